@@ -1,12 +1,32 @@
 package dto
 
+import (
+	"github.com/kiosanim/pismo-code-assessment/internal/core/pii"
+	"log/slog"
+)
+
 type CreateAccountRequest struct {
+	DocumentType   string `json:"document_type,omitempty"` // CPF, CNPJ, PASSPORT or FOREIGN_TAX_ID, CPF or CNPJ by length when empty
 	DocumentNumber string `json:"document_number" binding:"required"`
+	Currency       string `json:"currency,omitempty"` // ISO 4217 code, the configured default currency when empty
+}
+
+// LogValue logs a CreateAccountRequest with its document number masked
+func (r CreateAccountRequest) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("document_type", r.DocumentType),
+		slog.String("document_number", pii.Mask(r.DocumentNumber)),
+		slog.String("currency", r.Currency),
+	)
 }
 
 type CreateAccountResponse struct {
 	AccountID      int64  `json:"account_id"`
+	DocumentType   string `json:"document_type"`
 	DocumentNumber string `json:"document_number"`
+	Currency       string `json:"currency"`
+	Status         string `json:"status"`
+	Version        int64  `json:"version"` // Version sent as the ETag of the account
 }
 
 type FindAccountByIdRequest struct {
@@ -15,7 +35,11 @@ type FindAccountByIdRequest struct {
 
 type FindAccountByIdResponse struct {
 	AccountID      int64  `json:"account_id"`
+	DocumentType   string `json:"document_type"`
 	DocumentNumber string `json:"document_number"`
+	Currency       string `json:"currency"`
+	Status         string `json:"status"`
+	Version        int64  `json:"version"` // Version sent as the ETag of the account
 }
 
 type ListAccountsRequest struct {
@@ -31,5 +55,23 @@ type ListAccountsResponse struct {
 
 type AccountDTO struct {
 	AccountID      int64  `json:"account_id"`
+	DocumentType   string `json:"document_type"`
+	DocumentNumber string `json:"document_number"`
+	Currency       string `json:"currency"`
+	Status         string `json:"status"`
+	Version        int64  `json:"version"` // Version sent as the ETag of the account
+}
+
+type BlockAccountRequest struct {
+	AccountID int64 `uri:"account_id" binding:"required,gt=0"`
+	Version   int64 `json:"-"` // Version the account must be at, from the If-Match header, the current version when zero
+}
+
+type BlockAccountResponse struct {
+	AccountID      int64  `json:"account_id"`
+	DocumentType   string `json:"document_type"`
 	DocumentNumber string `json:"document_number"`
+	Currency       string `json:"currency"`
+	Status         string `json:"status"`
+	Version        int64  `json:"version"` // Version sent as the ETag of the account
 }
@@ -7,21 +7,42 @@ import (
 
 func CreateDTOToEntity(req dto.CreateAccountRequest) *account.Account {
 	return &account.Account{
+		DocumentType:   account.DocumentType(req.DocumentType),
 		DocumentNumber: req.DocumentNumber,
+		Currency:       req.Currency,
 	}
 }
 
 func CreateEntityToResponse(entity *account.Account) *dto.CreateAccountResponse {
 	return &dto.CreateAccountResponse{
 		AccountID:      entity.AccountID,
+		DocumentType:   string(entity.DocumentType),
 		DocumentNumber: entity.DocumentNumber,
+		Currency:       entity.Currency,
+		Status:         entity.Status,
+		Version:        entity.Version,
 	}
 }
 
 func FindEntityToResponse(entity *account.Account) *dto.FindAccountByIdResponse {
 	return &dto.FindAccountByIdResponse{
 		AccountID:      entity.AccountID,
+		DocumentType:   string(entity.DocumentType),
 		DocumentNumber: entity.DocumentNumber,
+		Currency:       entity.Currency,
+		Status:         entity.Status,
+		Version:        entity.Version,
+	}
+}
+
+func BlockEntityToResponse(entity *account.Account) *dto.BlockAccountResponse {
+	return &dto.BlockAccountResponse{
+		AccountID:      entity.AccountID,
+		DocumentType:   string(entity.DocumentType),
+		DocumentNumber: entity.DocumentNumber,
+		Currency:       entity.Currency,
+		Status:         entity.Status,
+		Version:        entity.Version,
 	}
 }
 
@@ -31,7 +52,11 @@ func ListAccountsToResponse(entities []account.Account, limit int64, cursor int6
 	for _, entity := range entities {
 		accountDTO := dto.AccountDTO{
 			AccountID:      entity.AccountID,
+			DocumentType:   string(entity.DocumentType),
 			DocumentNumber: entity.DocumentNumber,
+			Currency:       entity.Currency,
+			Status:         entity.Status,
+			Version:        entity.Version,
 		}
 		accountsDTO = append(accountsDTO, accountDTO)
 	}
@@ -7,9 +7,9 @@ import (
 	"github.com/kiosanim/pismo-code-assessment/application/account/mapper"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/cache"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/contextutils"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/currency"
 	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/factory"
-	"github.com/kiosanim/pismo-code-assessment/internal/core/lock"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
 	"github.com/kiosanim/pismo-code-assessment/internal/domains/account"
 )
@@ -17,19 +17,26 @@ import (
 type AccountService struct {
 	accountRepository account.AccountRepository
 	cache             cache.CacheRepository
+	accountCache      *cache.EntityCache[account.Account]
+	defaultCurrency   string
 	componentName     string
-	locker            lock.DistributedLockManager
 	log               logger.Logger
 }
 
 func NewAccountService(factory factory.Factory) *AccountService {
-	return &AccountService{
+	cacheRepository := factory.CacheRepository()
+	service := &AccountService{
 		componentName:     "AccountService",
 		accountRepository: factory.AccountRepository(),
-		cache:             factory.CacheRepository(),
-		locker:            factory.DistributedLockManager(),
+		cache:             cacheRepository,
+		accountCache:      cache.NewEntityCache[account.Account](cache.AccountEntity, cacheRepository, factory.Configuration().Cache.Accounts, factory.Log()).WithCodec(account.NewCacheCodec(factory.FieldCipher())),
+		defaultCurrency:   currency.DefaultCode,
 		log:               factory.Log(),
 	}
+	if defaultCurrency, err := currency.Normalize(factory.Configuration().Currency.Default); err == nil {
+		service.defaultCurrency = defaultCurrency
+	}
+	return service
 }
 
 func (a *AccountService) FindByID(ctx context.Context, request dto.FindAccountByIdRequest) (*dto.FindAccountByIdResponse, error) {
@@ -40,7 +47,9 @@ func (a *AccountService) FindByID(ctx context.Context, request dto.FindAccountBy
 		a.log.Warn(a.componentName+".FindByID", "error", err, "x_trace_id", traceID)
 		return nil, err
 	}
-	output, err := a.accountRepository.FindByID(ctx, request.AccountID)
+	output, err := a.accountCache.Get(ctx, request.AccountID, func(ctx context.Context) (*account.Account, error) {
+		return a.accountRepository.FindByID(ctx, request.AccountID)
+	})
 	if err != nil {
 		a.log.Warn(a.componentName+".FindByID", "error", err, "x_trace_id", traceID)
 		return nil, err
@@ -57,12 +66,21 @@ func (a *AccountService) FindByID(ctx context.Context, request dto.FindAccountBy
 func (a *AccountService) Create(ctx context.Context, request dto.CreateAccountRequest) (*dto.CreateAccountResponse, error) {
 	traceID := contextutils.GetTraceID(ctx)
 	a.log.Debug(a.componentName+".Create", "request", request, "x_trace_id", traceID)
-	if request.DocumentNumber == "" || account.IsValidDocumentNumber(request.DocumentNumber) != nil {
-		err := coreerr.InvalidParametersError
+	documentType, documentNumber, err := a.normalizeDocument(request)
+	if err != nil {
 		a.log.Warn(a.componentName+".Create", "error", err, "x_trace_id", traceID)
 		return nil, err
 	}
-	accountByDocumentNumber, err := a.accountRepository.FindByDocumentNumber(ctx, request.DocumentNumber)
+	accountCurrency := a.defaultCurrency
+	if request.Currency != "" {
+		normalized, err := currency.Normalize(request.Currency)
+		if err != nil {
+			a.log.Warn(a.componentName+".Create", "error", err, "x_trace_id", traceID)
+			return nil, err
+		}
+		accountCurrency = normalized
+	}
+	accountByDocumentNumber, err := a.accountRepository.FindByDocumentNumber(ctx, documentNumber)
 	if err != nil && !errors.Is(err, coreerr.AccountNotFoundError) {
 		a.log.Warn(a.componentName+".Create", "error", err, "x_trace_id", traceID)
 		return nil, err
@@ -73,23 +91,63 @@ func (a *AccountService) Create(ctx context.Context, request dto.CreateAccountRe
 		return nil, err
 	}
 	accountRequest := mapper.CreateDTOToEntity(request)
-	lck, err := a.locker.WaitToLockUsingDefaultTimeConfiguration(ctx, lock.AccountCreationLockKey)
+	accountRequest.DocumentType = documentType
+	accountRequest.DocumentNumber = documentNumber
+	accountRequest.Currency = accountCurrency
+	// The lookup above only finds accounts already committed: concurrent requests for the same document are rejected
+	// by the unique document number index of the repository with AccountAlreadyExistsForDocumentNumberError
+	output, err := a.accountRepository.Save(ctx, accountRequest)
 	if err != nil {
 		a.log.Warn(a.componentName+".Create", "error", err, "x_trace_id", traceID)
 		return nil, err
 	}
-	output, err := a.accountRepository.Save(ctx, accountRequest)
-	if err != nil {
-		a.log.Warn(a.componentName+".Create", "error", err, "x_trace_id", traceID)
+	a.accountCache.Invalidate(ctx, output.AccountID)
+	response := mapper.CreateEntityToResponse(output)
+	return response, nil
+}
+
+// Block blocks an account, so it rejects new transactions. Blocking a blocked account has no effect. The account must
+// be at the request version, or at the version read before blocking it when the request has none.
+func (a *AccountService) Block(ctx context.Context, request dto.BlockAccountRequest) (*dto.BlockAccountResponse, error) {
+	traceID := contextutils.GetTraceID(ctx)
+	a.log.Debug(a.componentName+".Block", "request", request, "x_trace_id", traceID)
+	if request.AccountID <= 0 || request.Version < 0 {
+		err := coreerr.InvalidParametersError
+		a.log.Warn(a.componentName+".Block", "error", err, "x_trace_id", traceID)
 		return nil, err
 	}
-	err = a.locker.Unlock(ctx, lck)
+	expectedVersion := request.Version
+	if expectedVersion == 0 {
+		current, err := a.accountRepository.FindByID(ctx, request.AccountID)
+		if err != nil {
+			a.log.Warn(a.componentName+".Block", "error", err, "x_trace_id", traceID)
+			return nil, err
+		}
+		expectedVersion = current.Version
+	}
+	output, err := a.accountRepository.Block(ctx, request.AccountID, expectedVersion)
 	if err != nil {
-		a.log.Warn(a.componentName+".Create", "error", err, "x_trace_id", traceID)
+		a.log.Warn(a.componentName+".Block", "error", err, "x_trace_id", traceID)
 		return nil, err
 	}
-	response := mapper.CreateEntityToResponse(output)
-	return response, nil
+	a.accountCache.Invalidate(ctx, output.AccountID)
+	a.log.Info(a.componentName+".Block", "account", output, "x_trace_id", traceID)
+	return mapper.BlockEntityToResponse(output), nil
+}
+
+// normalizeDocument validates the request document and returns it in the normalized form accounts are stored with
+func (a *AccountService) normalizeDocument(request dto.CreateAccountRequest) (account.DocumentType, string, error) {
+	if request.DocumentNumber == "" {
+		return "", "", coreerr.InvalidParametersError
+	}
+	var documentType account.DocumentType
+	if request.DocumentType != "" {
+		var err error
+		if documentType, err = account.ParseDocumentType(request.DocumentType); err != nil {
+			return "", "", err
+		}
+	}
+	return account.NormalizeDocument(documentType, request.DocumentNumber)
 }
 
 func (a *AccountService) List(ctx context.Context, request dto.ListAccountsRequest) (*dto.ListAccountsResponse, error) {
@@ -2,26 +2,33 @@ package service
 
 import (
 	"context"
+	"encoding/base64"
 	"github.com/kiosanim/pismo-code-assessment/application/account/dto"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/cache"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/config"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/currency"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/errors"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/factory"
-	"github.com/kiosanim/pismo-code-assessment/internal/core/lock"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
 	"github.com/kiosanim/pismo-code-assessment/internal/domains/account"
+	infrapii "github.com/kiosanim/pismo-code-assessment/internal/infra/pii"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/mock/gomock"
+	"strings"
 	"testing"
+	"time"
 )
 
 type AccountServiceTestSuite struct {
 	suite.Suite
-	repository *account.AccountRepositoryMock
-	cache      cache.CacheRepository
-	ctx        context.Context
-	log        *logger.LoggerMock
-	factory    *factory.FactoryMock
-	locker     *lock.DistributedLockManagerMock
+	repository    *account.AccountRepositoryMock
+	cache         *cache.CacheRepositoryMock
+	ctx           context.Context
+	log           *logger.LoggerMock
+	factory       *factory.FactoryMock
+	configuration *config.Configuration
+	fieldCipher   *infrapii.AESFieldCipher
 }
 
 func (s *AccountServiceTestSuite) SetupTest() {
@@ -31,20 +38,25 @@ func (s *AccountServiceTestSuite) SetupTest() {
 	s.repository = account.NewAccountRepositoryMock()
 	s.cache = cache.NewCacheRepositoryMock(ctrl)
 	s.log = logger.NewLoggerMock(ctrl)
-	s.locker = lock.NewDistributedLockManagerMock(ctrl)
+	s.configuration = &config.Configuration{}
+	fieldCipher, err := infrapii.NewAESFieldCipher(config.PIIConfig{
+		EncryptionKey: base64.StdEncoding.EncodeToString([]byte(strings.Repeat("e", 32))),
+		BlindIndexKey: base64.StdEncoding.EncodeToString([]byte(strings.Repeat("b", 32))),
+	})
+	s.Require().NoError(err)
+	s.fieldCipher = fieldCipher
 
 	// Allow any number of these calls
 	s.log.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
 	s.log.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
 	s.log.EXPECT().Warn(gomock.Any(), gomock.Any()).AnyTimes()
-	s.locker.EXPECT().WaitToLockUsingDefaultTimeConfiguration(gomock.Any(), gomock.Any()).AnyTimes()
-	s.locker.EXPECT().Unlock(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 
 	// Factory returns same mocks
 	s.factory = factory.NewFactoryMock(ctrl)
 	s.factory.EXPECT().AccountRepository().Return(s.repository).AnyTimes()
 	s.factory.EXPECT().CacheRepository().Return(s.cache).AnyTimes()
-	s.factory.EXPECT().DistributedLockManager().Return(s.locker).AnyTimes()
+	s.factory.EXPECT().Configuration().Return(s.configuration).AnyTimes()
+	s.factory.EXPECT().FieldCipher().Return(s.fieldCipher).AnyTimes()
 	s.factory.EXPECT().Log().Return(s.log).AnyTimes()
 }
 
@@ -64,11 +76,15 @@ func (s *AccountServiceTestSuite) TestCreateAccountSuccess() {
 		"Save",
 		s.ctx,
 		&account.Account{
+			DocumentType:   account.DocumentTypeCPF,
 			DocumentNumber: documentNumber,
+			Currency:       currency.DefaultCode,
 		}).Return(
 		&account.Account{
 			AccountID:      accountID,
+			DocumentType:   account.DocumentTypeCPF,
 			DocumentNumber: documentNumber,
+			Currency:       currency.DefaultCode,
 		}, nil)
 
 	s.repository.On("FindByDocumentNumber", s.ctx, documentNumber).Return(
@@ -82,6 +98,60 @@ func (s *AccountServiceTestSuite) TestCreateAccountSuccess() {
 	s.Greater(output.AccountID, accountIDToCompare, "account ID should be greater than zero")
 }
 
+func (s *AccountServiceTestSuite) TestCreateAccountWithCurrency() {
+	as := NewAccountService(s.factory)
+	documentNumber := "11987408098"
+	s.repository.On("FindByDocumentNumber", s.ctx, documentNumber).Return(nil, errors.AccountNotFoundError)
+	s.repository.On("Save", s.ctx, &account.Account{DocumentType: account.DocumentTypeCPF, DocumentNumber: documentNumber, Currency: "USD"}).Return(
+		&account.Account{AccountID: 1, DocumentNumber: documentNumber, Currency: "USD"}, nil)
+	output, err := as.Create(s.ctx, dto.CreateAccountRequest{DocumentNumber: documentNumber, Currency: "usd"})
+	s.NoError(err, "create account should return no error")
+	s.Equal("USD", output.Currency, "currency should be normalized")
+}
+
+func (s *AccountServiceTestSuite) TestCreateAccountConcurrentDuplicate() {
+	as := NewAccountService(s.factory)
+	documentNumber := "11987408098"
+	s.repository.On("FindByDocumentNumber", s.ctx, documentNumber).Return(nil, errors.AccountNotFoundError)
+	s.repository.On("Save", s.ctx, mock.Anything).Return(nil, errors.AccountAlreadyExistsForDocumentNumberError)
+	_, err := as.Create(s.ctx, dto.CreateAccountRequest{DocumentNumber: documentNumber})
+	s.ErrorIs(err, errors.AccountAlreadyExistsForDocumentNumberError, "an account created after the lookup should be rejected by the repository")
+}
+
+func (s *AccountServiceTestSuite) TestCreateAccountInvalidCurrency() {
+	as := NewAccountService(s.factory)
+	_, err := as.Create(s.ctx, dto.CreateAccountRequest{DocumentNumber: "11987408098", Currency: "US"})
+	s.ErrorIs(err, errors.CurrencyInvalidError)
+	s.repository.AssertNotCalled(s.T(), "Save")
+}
+
+func (s *AccountServiceTestSuite) TestCreateAccountStoresNormalizedDocument() {
+	as := NewAccountService(s.factory)
+	s.repository.On("FindByDocumentNumber", s.ctx, "12345678909").Return(
+		&account.Account{AccountID: 1, DocumentType: account.DocumentTypeCPF, DocumentNumber: "12345678909"}, nil)
+	_, err := as.Create(s.ctx, dto.CreateAccountRequest{DocumentNumber: "123.456.789-09"})
+	s.ErrorIs(err, errors.AccountAlreadyExistsForDocumentNumberError, "a formatted document should match the normalized one")
+	s.repository.AssertNotCalled(s.T(), "Save")
+}
+
+func (s *AccountServiceTestSuite) TestCreateAccountWithPassport() {
+	as := NewAccountService(s.factory)
+	s.repository.On("FindByDocumentNumber", s.ctx, "AB1234567").Return(nil, errors.AccountNotFoundError)
+	s.repository.On("Save", s.ctx, &account.Account{DocumentType: account.DocumentTypePassport, DocumentNumber: "AB1234567", Currency: currency.DefaultCode}).Return(
+		&account.Account{AccountID: 1, DocumentType: account.DocumentTypePassport, DocumentNumber: "AB1234567", Currency: currency.DefaultCode}, nil)
+	output, err := as.Create(s.ctx, dto.CreateAccountRequest{DocumentType: "passport", DocumentNumber: "ab 123-4567"})
+	s.NoError(err, "create account should return no error")
+	s.Equal("PASSPORT", output.DocumentType)
+	s.Equal("AB1234567", output.DocumentNumber)
+}
+
+func (s *AccountServiceTestSuite) TestCreateAccountInvalidDocumentType() {
+	as := NewAccountService(s.factory)
+	_, err := as.Create(s.ctx, dto.CreateAccountRequest{DocumentType: "DRIVER_LICENSE", DocumentNumber: "123456"})
+	s.ErrorIs(err, errors.DocumentTypeInvalidError)
+	s.repository.AssertNotCalled(s.T(), "Save")
+}
+
 func (s *AccountServiceTestSuite) TestCreateAccountInvalidParameters() {
 	service := NewAccountService(s.factory)
 	var accountID int64 = 0
@@ -154,6 +224,93 @@ func (s *AccountServiceTestSuite) TestFindByIDInvalidParameters() {
 	s.Nil(output, "find account by ID should return nil because no Account was found")
 }
 
+func (s *AccountServiceTestSuite) TestFindByIDCacheHit() {
+	s.configuration.Cache.Accounts = config.CacheEntityConfig{Enabled: true, TTLMs: 60000}
+	service := NewAccountService(s.factory)
+	s.cache.EXPECT().Get(s.ctx, "cache:account:1").Return(`{"AccountID":1,"DocumentNumber":"11987408098"}`, nil)
+	output, err := service.FindByID(s.ctx, dto.FindAccountByIdRequest{AccountID: 1})
+	s.NoError(err, "find account by ID should return no error")
+	s.Equal("11987408098", output.DocumentNumber, "document number should come from the cache")
+	s.repository.AssertNotCalled(s.T(), "FindByID", s.ctx, int64(1))
+}
+
+func (s *AccountServiceTestSuite) TestFindByIDCacheMiss() {
+	s.configuration.Cache.Accounts = config.CacheEntityConfig{Enabled: true, TTLMs: 60000}
+	service := NewAccountService(s.factory)
+	var accountID int64 = 1
+	var payload string
+	s.cache.EXPECT().Get(s.ctx, "cache:account:1").Return("", errors.CacheNotFoundError)
+	s.cache.EXPECT().Set(s.ctx, "cache:account:1", gomock.Any(), time.Minute).DoAndReturn(
+		func(ctx context.Context, key string, value string, ttl time.Duration) error {
+			payload = value
+			return nil
+		})
+	s.repository.On("FindByID", s.ctx, accountID).Return(
+		&account.Account{AccountID: accountID, DocumentType: account.DocumentTypeCPF, DocumentNumber: "11987408098", Currency: "BRL", Status: account.StatusActive, Version: 1}, nil)
+	output, err := service.FindByID(s.ctx, dto.FindAccountByIdRequest{AccountID: accountID})
+	s.NoError(err, "find account by ID should return no error")
+	s.Equal(accountID, output.AccountID, "account should be loaded from the repository")
+	s.Equal("11987408098", output.DocumentNumber)
+	s.repository.AssertNumberOfCalls(s.T(), "FindByID", 1)
+	s.Contains(payload, `"AccountID":1`)
+	s.NotContains(payload, "11987408098", "the cached account should not hold the document number in clear text")
+
+	s.cache.EXPECT().Get(s.ctx, "cache:account:1").Return(payload, nil)
+	output, err = service.FindByID(s.ctx, dto.FindAccountByIdRequest{AccountID: accountID})
+	s.NoError(err, "find account by ID should return no error")
+	s.Equal("11987408098", output.DocumentNumber, "the document number of the cached account should be decrypted")
+	s.repository.AssertNumberOfCalls(s.T(), "FindByID", 1)
+}
+
+func (s *AccountServiceTestSuite) TestBlockSuccess() {
+	s.configuration.Cache.Accounts = config.CacheEntityConfig{Enabled: true, TTLMs: 60000}
+	service := NewAccountService(s.factory)
+	var accountID int64 = 1
+	s.repository.On("Block", s.ctx, accountID, int64(3)).Return(
+		&account.Account{AccountID: accountID, DocumentType: account.DocumentTypeCPF, DocumentNumber: "11987408098", Currency: "BRL", Status: account.StatusBlocked, Version: 4}, nil)
+	s.cache.EXPECT().Del(s.ctx, "cache:account:1").Return(nil)
+	output, err := service.Block(s.ctx, dto.BlockAccountRequest{AccountID: accountID, Version: 3})
+	s.NoError(err, "block account should return no error")
+	s.Equal(account.StatusBlocked, output.Status, "account should be blocked")
+	s.Equal(int64(4), output.Version, "block account should return the new version")
+	s.repository.AssertNumberOfCalls(s.T(), "FindByID", 0)
+}
+
+func (s *AccountServiceTestSuite) TestBlockCurrentVersion() {
+	service := NewAccountService(s.factory)
+	var accountID int64 = 1
+	s.repository.On("FindByID", s.ctx, accountID).Return(&account.Account{AccountID: accountID, Status: account.StatusActive, Version: 2}, nil)
+	s.repository.On("Block", s.ctx, accountID, int64(2)).Return(&account.Account{AccountID: accountID, Status: account.StatusBlocked, Version: 3}, nil)
+	output, err := service.Block(s.ctx, dto.BlockAccountRequest{AccountID: accountID})
+	s.NoError(err, "block account without a version should block the version read")
+	s.Equal(int64(3), output.Version)
+}
+
+func (s *AccountServiceTestSuite) TestBlockVersionConflict() {
+	service := NewAccountService(s.factory)
+	s.repository.On("Block", s.ctx, int64(1), int64(2)).Return(nil, errors.AccountVersionConflictError)
+	output, err := service.Block(s.ctx, dto.BlockAccountRequest{AccountID: 1, Version: 2})
+	s.ErrorIs(err, errors.AccountVersionConflictError, "block account should fail when the account is at another version")
+	s.Nil(output, "block account should return no account")
+}
+
+func (s *AccountServiceTestSuite) TestBlockNotFound() {
+	service := NewAccountService(s.factory)
+	s.repository.On("FindByID", s.ctx, int64(2)).Return(nil, errors.AccountNotFoundError)
+	output, err := service.Block(s.ctx, dto.BlockAccountRequest{AccountID: 2})
+	s.ErrorIs(err, errors.AccountNotFoundError, "block account should return the repository error")
+	s.Nil(output, "block account should return no account")
+	s.repository.AssertNumberOfCalls(s.T(), "Block", 0)
+}
+
+func (s *AccountServiceTestSuite) TestBlockInvalidParameters() {
+	service := NewAccountService(s.factory)
+	output, err := service.Block(s.ctx, dto.BlockAccountRequest{AccountID: 0})
+	s.ErrorIs(err, errors.InvalidParametersError, "block account should reject an invalid account ID")
+	s.Nil(output, "block account should return no account")
+	s.repository.AssertNumberOfCalls(s.T(), "Block", 0)
+}
+
 func TestCreateAccountTestSuite(t *testing.T) {
 	suite.Run(t, new(AccountServiceTestSuite))
 }
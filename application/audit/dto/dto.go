@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	DefaultListLimit int64 = 50
+	MaxListLimit     int64 = 500
+)
+
+// ListAuditLogRequest selects the entries of an entity type, or of a single entity, created in the [From, To) interval
+type ListAuditLogRequest struct {
+	EntityType string
+	EntityID   int64
+	From       time.Time
+	To         time.Time
+	Cursor     int64 // Last audit ID of the previous page
+	Limit      int64
+}
+
+type ListAuditLogResponse struct {
+	Entries []AuditEntryDTO `json:"entries"`
+	Limit   int64           `json:"limit"`
+	Cursor  int64           `json:"cursor"` // Cursor of the next page, zero on the last page
+}
+
+type AuditEntryDTO struct {
+	AuditID      int64           `json:"audit_id"`
+	Principal    string          `json:"principal"`
+	ClientIP     string          `json:"client_ip"`
+	TraceID      string          `json:"trace_id"`
+	Action       string          `json:"action"`
+	EntityType   string          `json:"entity_type"`
+	EntityID     int64           `json:"entity_id"`
+	Before       json.RawMessage `json:"before" swaggertype:"object"`
+	After        json.RawMessage `json:"after" swaggertype:"object"`
+	CreatedAt    time.Time       `json:"created_at"`
+	PreviousHash string          `json:"previous_hash"`
+	Hash         string          `json:"hash"`
+}
+
+type VerifyAuditLogResponse struct {
+	Valid          bool  `json:"valid"`
+	Checked        int64 `json:"checked"`                    // Number of entries verified
+	FirstInvalidID int64 `json:"first_invalid_id,omitempty"` // First entry that does not match the hash chain
+}
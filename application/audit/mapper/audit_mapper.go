@@ -0,0 +1,47 @@
+package mapper
+
+import (
+	"github.com/kiosanim/pismo-code-assessment/application/audit/dto"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/audit"
+	"strings"
+)
+
+func ListRequestToFilter(request dto.ListAuditLogRequest) audit.Filter {
+	return audit.Filter{
+		EntityType: strings.ToLower(strings.TrimSpace(request.EntityType)),
+		EntityID:   request.EntityID,
+		From:       request.From,
+		To:         request.To,
+		Cursor:     request.Cursor,
+		Limit:      request.Limit,
+	}
+}
+
+func EntityToDTO(entry *audit.Entry) *dto.AuditEntryDTO {
+	return &dto.AuditEntryDTO{
+		AuditID:      entry.AuditID,
+		Principal:    entry.Principal,
+		ClientIP:     entry.ClientIP,
+		TraceID:      entry.TraceID,
+		Action:       entry.Action,
+		EntityType:   entry.EntityType,
+		EntityID:     entry.EntityID,
+		Before:       entry.Before,
+		After:        entry.After,
+		CreatedAt:    entry.CreatedAt.UTC(),
+		PreviousHash: entry.PreviousHash,
+		Hash:         entry.Hash,
+	}
+}
+
+func EntitiesToListResponse(entries []*audit.Entry, limit int64, cursor int64) *dto.ListAuditLogResponse {
+	response := &dto.ListAuditLogResponse{
+		Entries: make([]dto.AuditEntryDTO, 0, len(entries)),
+		Limit:   limit,
+		Cursor:  cursor,
+	}
+	for _, entry := range entries {
+		response.Entries = append(response.Entries, *EntityToDTO(entry))
+	}
+	return response
+}
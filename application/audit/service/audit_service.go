@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"github.com/kiosanim/pismo-code-assessment/application/audit/dto"
+	"github.com/kiosanim/pismo-code-assessment/application/audit/mapper"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/contextutils"
+	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/factory"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/audit"
+)
+
+type AuditService struct {
+	auditLogRepository audit.AuditLogRepository
+	componentName      string
+	log                logger.Logger
+}
+
+func NewAuditService(factory factory.Factory) *AuditService {
+	return &AuditService{
+		componentName:      "AuditService",
+		auditLogRepository: factory.AuditLogRepository(),
+		log:                factory.Log(),
+	}
+}
+
+// List returns a page of the audit log ordered by audit ID
+func (a *AuditService) List(ctx context.Context, request dto.ListAuditLogRequest) (*dto.ListAuditLogResponse, error) {
+	traceID := contextutils.GetTraceID(ctx)
+	a.log.Debug(a.componentName+".List", "request", request, "x_trace_id", traceID)
+	filter := mapper.ListRequestToFilter(request)
+	if err := validateFilter(filter); err != nil {
+		a.log.Warn(a.componentName+".List", "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
+	if filter.Limit == 0 {
+		filter.Limit = dto.DefaultListLimit
+	}
+	filter.Limit = min(filter.Limit, dto.MaxListLimit)
+	entries, err := a.auditLogRepository.Search(ctx, filter)
+	if err != nil {
+		a.log.Warn(a.componentName+".List", "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
+	nextCursor := int64(0)
+	if int64(len(entries)) == filter.Limit {
+		nextCursor = entries[len(entries)-1].AuditID
+	}
+	return mapper.EntitiesToListResponse(entries, filter.Limit, nextCursor), nil
+}
+
+// Verify checks the whole audit log against its hash chain, stopping at the first entry that does not match
+func (a *AuditService) Verify(ctx context.Context) (*dto.VerifyAuditLogResponse, error) {
+	traceID := contextutils.GetTraceID(ctx)
+	a.log.Debug(a.componentName+".Verify", "x_trace_id", traceID)
+	verifier := audit.NewChainVerifier()
+	err := a.auditLogRepository.StreamEntries(ctx, func(entry *audit.Entry) error {
+		if !verifier.Verify(entry) {
+			return coreerr.AuditLogTamperedError
+		}
+		return nil
+	})
+	if err != nil && err != coreerr.AuditLogTamperedError {
+		a.log.Warn(a.componentName+".Verify", "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
+	response := &dto.VerifyAuditLogResponse{
+		Valid:          verifier.FirstInvalidID() == 0,
+		Checked:        verifier.Checked(),
+		FirstInvalidID: verifier.FirstInvalidID(),
+	}
+	if !response.Valid {
+		a.log.Error(a.componentName+".Verify", "error", coreerr.AuditLogTamperedError, "audit_id", response.FirstInvalidID, "x_trace_id", traceID)
+	}
+	return response, nil
+}
+
+func validateFilter(filter audit.Filter) error {
+	switch filter.EntityType {
+	case audit.EntityAccount, audit.EntityAccountHolder, audit.EntityTransaction:
+	case "":
+		if filter.EntityID != 0 {
+			return coreerr.InvalidParametersError
+		}
+	default:
+		return coreerr.InvalidParametersError
+	}
+	if filter.EntityID < 0 || filter.Cursor < 0 || filter.Limit < 0 ||
+		(!filter.From.IsZero() && !filter.To.IsZero() && !filter.To.After(filter.From)) {
+		return coreerr.InvalidParametersError
+	}
+	return nil
+}
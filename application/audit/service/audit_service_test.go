@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/kiosanim/pismo-code-assessment/application/audit/dto"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/factory"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/audit"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+type AuditServiceTestSuite struct {
+	suite.Suite
+	repository *audit.AuditLogRepositoryMock
+	ctx        context.Context
+	log        *logger.LoggerMock
+	factory    *factory.FactoryMock
+}
+
+func (s *AuditServiceTestSuite) SetupTest() {
+	ctrl := gomock.NewController(s.T())
+	s.ctx = context.Background()
+	s.repository = audit.NewAuditLogRepositoryMock()
+	s.log = logger.NewLoggerMock(ctrl)
+	s.log.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	s.log.EXPECT().Warn(gomock.Any(), gomock.Any()).AnyTimes()
+	s.log.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+	s.factory = factory.NewFactoryMock(ctrl)
+	s.factory.EXPECT().AuditLogRepository().Return(s.repository).AnyTimes()
+	s.factory.EXPECT().Log().Return(s.log).AnyTimes()
+}
+
+func entries(count int) []*audit.Entry {
+	result := make([]*audit.Entry, 0, count)
+	for i := 1; i <= count; i++ {
+		result = append(result, &audit.Entry{
+			AuditID:    int64(i),
+			Action:     audit.ActionTransactionCreate,
+			EntityType: audit.EntityTransaction,
+			EntityID:   int64(i),
+			After:      json.RawMessage(`{"TransactionID":1}`),
+			CreatedAt:  time.Date(2026, 10, 19, 12, 0, i, 0, time.UTC),
+		})
+	}
+	audit.Chain(audit.GenesisHash, result...)
+	return result
+}
+
+func (s *AuditServiceTestSuite) TestListUsesDefaultLimit() {
+	service := NewAuditService(s.factory)
+	s.repository.On("Search", s.ctx, audit.Filter{EntityType: audit.EntityAccount, EntityID: 1, Limit: dto.DefaultListLimit}).Return(entries(2), nil)
+	response, err := service.List(s.ctx, dto.ListAuditLogRequest{EntityType: " Account ", EntityID: 1})
+	s.NoError(err)
+	s.Len(response.Entries, 2)
+	s.Equal(int64(0), response.Cursor, "a partial page is the last one")
+	s.JSONEq(`{"TransactionID":1}`, string(response.Entries[0].After))
+}
+
+func (s *AuditServiceTestSuite) TestListReturnsNextCursorOnFullPage() {
+	service := NewAuditService(s.factory)
+	s.repository.On("Search", s.ctx, audit.Filter{Cursor: 10, Limit: 2}).Return(entries(2), nil)
+	response, err := service.List(s.ctx, dto.ListAuditLogRequest{Cursor: 10, Limit: 2})
+	s.NoError(err)
+	s.Equal(int64(2), response.Cursor, "the cursor should be the last audit ID of the page")
+}
+
+func (s *AuditServiceTestSuite) TestListInvalidFilters() {
+	service := NewAuditService(s.factory)
+	from := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	for name, request := range map[string]dto.ListAuditLogRequest{
+		"unknown entity type":    {EntityType: "card"},
+		"entity ID without type": {EntityID: 1},
+		"negative limit":         {Limit: -1},
+		"to not after from":      {From: from, To: from},
+		"negative entity ID":     {EntityType: audit.EntityAccount, EntityID: -1},
+		"negative cursor":        {Cursor: -1},
+		"plural entity type":     {EntityType: "accounts"},
+	} {
+		_, err := service.List(s.ctx, request)
+		s.ErrorIs(err, errors.InvalidParametersError, name)
+	}
+	s.repository.AssertNotCalled(s.T(), "Search", mock.Anything, mock.Anything)
+}
+
+func (s *AuditServiceTestSuite) TestVerifyValidChain() {
+	service := NewAuditService(s.factory)
+	s.repository.On("StreamEntries", s.ctx, mock.Anything).Return(entries(3), nil)
+	response, err := service.Verify(s.ctx)
+	s.NoError(err)
+	s.Equal(&dto.VerifyAuditLogResponse{Valid: true, Checked: 3}, response)
+}
+
+func (s *AuditServiceTestSuite) TestVerifyTamperedChain() {
+	service := NewAuditService(s.factory)
+	tampered := entries(3)
+	tampered[1].Principal = "someone-else"
+	s.repository.On("StreamEntries", s.ctx, mock.Anything).Return(tampered, nil)
+	response, err := service.Verify(s.ctx)
+	s.NoError(err)
+	s.Equal(&dto.VerifyAuditLogResponse{Valid: false, Checked: 2, FirstInvalidID: 2}, response)
+}
+
+func (s *AuditServiceTestSuite) TestVerifyRepositoryError() {
+	service := NewAuditService(s.factory)
+	s.repository.On("StreamEntries", s.ctx, mock.Anything).Return(nil, errors.DatabaseQueryError)
+	_, err := service.Verify(s.ctx)
+	s.ErrorIs(err, errors.DatabaseQueryError)
+}
+
+func TestAuditServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(AuditServiceTestSuite))
+}
@@ -0,0 +1,2 @@
+// Package service has the audit log service used by the api
+package service
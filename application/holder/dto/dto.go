@@ -0,0 +1,78 @@
+package dto
+
+import (
+	"log/slog"
+	"time"
+)
+
+type AddressDTO struct {
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2"`
+	City       string `json:"city"`
+	State      string `json:"state"`
+	PostalCode string `json:"postal_code"`
+	Country    string `json:"country"` // ISO 3166-1 alpha-2 code
+}
+
+type HolderResponse struct {
+	AccountID int64      `json:"account_id"`
+	Name      string     `json:"name"`
+	Email     string     `json:"email"`
+	Phone     string     `json:"phone"`      // E.164 phone number
+	BirthDate string     `json:"birth_date"` // YYYY-MM-DD birth date of people or incorporation date of companies
+	Address   AddressDTO `json:"address"`
+	Version   int64      `json:"version"` // Version sent as the ETag of the holder, 0 until it is first changed
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+type FindHolderRequest struct {
+	AccountID int64 `uri:"account_id" binding:"required,gt=0"`
+}
+
+// AddressPatchDTO sets the address values sent, an empty value clearing it
+type AddressPatchDTO struct {
+	Line1      *string `json:"line1,omitempty"`
+	Line2      *string `json:"line2,omitempty"`
+	City       *string `json:"city,omitempty"`
+	State      *string `json:"state,omitempty"`
+	PostalCode *string `json:"postal_code,omitempty"`
+	Country    *string `json:"country,omitempty"`
+}
+
+// UpdateHolderRequest sets the holder values sent, an empty value clearing it. The holder is only changed when its
+// version is still Version, taken from the If-Match header.
+type UpdateHolderRequest struct {
+	AccountID int64            `json:"-"`
+	Version   int64            `json:"-"`
+	Name      *string          `json:"name,omitempty"`
+	Email     *string          `json:"email,omitempty"`
+	Phone     *string          `json:"phone,omitempty"`
+	BirthDate *string          `json:"birth_date,omitempty"`
+	Address   *AddressPatchDTO `json:"address,omitempty"`
+}
+
+// LogValue logs an UpdateHolderRequest without its personal data
+func (r UpdateHolderRequest) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Int64("account_id", r.AccountID),
+		slog.Int64("version", r.Version),
+	)
+}
+
+type HolderHistoryRequest struct {
+	AccountID int64 `uri:"account_id" binding:"required,gt=0"`
+}
+
+type HolderChangeDTO struct {
+	Version       int64          `json:"version"`
+	ChangedFields []string       `json:"changed_fields"` // e.g. "email" or "address.city"
+	Principal     string         `json:"principal"`
+	TraceID       string         `json:"trace_id"`
+	ChangedAt     time.Time      `json:"changed_at"`
+	Holder        HolderResponse `json:"holder"` // State of the holder after the change
+}
+
+type HolderHistoryResponse struct {
+	AccountID int64             `json:"account_id"`
+	Changes   []HolderChangeDTO `json:"changes"` // Newest first
+}
@@ -0,0 +1,68 @@
+package mapper
+
+import (
+	"github.com/kiosanim/pismo-code-assessment/application/holder/dto"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/holder"
+)
+
+func UpdateDTOToPatch(req dto.UpdateHolderRequest) holder.Patch {
+	patch := holder.Patch{
+		Name:      req.Name,
+		Email:     req.Email,
+		Phone:     req.Phone,
+		BirthDate: req.BirthDate,
+	}
+	if req.Address != nil {
+		patch.Address = &holder.AddressPatch{
+			Line1:      req.Address.Line1,
+			Line2:      req.Address.Line2,
+			City:       req.Address.City,
+			State:      req.Address.State,
+			PostalCode: req.Address.PostalCode,
+			Country:    req.Address.Country,
+		}
+	}
+	return patch
+}
+
+func EntityToResponse(entity *holder.Holder) *dto.HolderResponse {
+	response := &dto.HolderResponse{
+		AccountID: entity.AccountID,
+		Name:      entity.Name,
+		Email:     entity.Email,
+		Phone:     entity.Phone,
+		BirthDate: entity.BirthDate,
+		Address: dto.AddressDTO{
+			Line1:      entity.Address.Line1,
+			Line2:      entity.Address.Line2,
+			City:       entity.Address.City,
+			State:      entity.Address.State,
+			PostalCode: entity.Address.PostalCode,
+			Country:    entity.Address.Country,
+		},
+		Version: entity.Version,
+	}
+	if !entity.UpdatedAt.IsZero() {
+		updatedAt := entity.UpdatedAt
+		response.UpdatedAt = &updatedAt
+	}
+	return response
+}
+
+func ChangesToHistoryResponse(accountID int64, changes []holder.Change) *dto.HolderHistoryResponse {
+	response := &dto.HolderHistoryResponse{
+		AccountID: accountID,
+		Changes:   make([]dto.HolderChangeDTO, 0, len(changes)),
+	}
+	for _, change := range changes {
+		response.Changes = append(response.Changes, dto.HolderChangeDTO{
+			Version:       change.Version,
+			ChangedFields: change.ChangedFields,
+			Principal:     change.Principal,
+			TraceID:       change.TraceID,
+			ChangedAt:     change.ChangedAt,
+			Holder:        *EntityToResponse(&change.Holder),
+		})
+	}
+	return response
+}
@@ -0,0 +1,2 @@
+// Package service has the account holder service used by the api
+package service
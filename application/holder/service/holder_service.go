@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/kiosanim/pismo-code-assessment/application/holder/dto"
+	"github.com/kiosanim/pismo-code-assessment/application/holder/mapper"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/clock"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/contextutils"
+	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/factory"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/account"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/holder"
+)
+
+type HolderService struct {
+	accountRepository account.AccountRepository
+	holderRepository  holder.HolderRepository
+	clock             clock.Clock
+	componentName     string
+	log               logger.Logger
+}
+
+func NewHolderService(factory factory.Factory) *HolderService {
+	return &HolderService{
+		componentName:     "HolderService",
+		accountRepository: factory.AccountRepository(),
+		holderRepository:  factory.HolderRepository(),
+		clock:             factory.Clock(),
+		log:               factory.Log(),
+	}
+}
+
+func (h *HolderService) Find(ctx context.Context, request dto.FindHolderRequest) (*dto.HolderResponse, error) {
+	traceID := contextutils.GetTraceID(ctx)
+	h.log.Debug(h.componentName+".Find", "request", request, "x_trace_id", traceID)
+	if request.AccountID <= 0 {
+		err := coreerr.InvalidParametersError
+		h.log.Warn(h.componentName+".Find", "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
+	current, err := h.current(ctx, request.AccountID)
+	if err != nil {
+		h.log.Warn(h.componentName+".Find", "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
+	return mapper.EntityToResponse(current), nil
+}
+
+// Update changes the holder values sent when the holder is still at the request version. Requests that change no
+// value return the current holder without creating a new version.
+func (h *HolderService) Update(ctx context.Context, request dto.UpdateHolderRequest) (*dto.HolderResponse, error) {
+	traceID := contextutils.GetTraceID(ctx)
+	h.log.Debug(h.componentName+".Update", "request", request, "x_trace_id", traceID)
+	if request.AccountID <= 0 || request.Version < 0 {
+		err := coreerr.InvalidParametersError
+		h.log.Warn(h.componentName+".Update", "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
+	current, err := h.current(ctx, request.AccountID)
+	if err != nil {
+		h.log.Warn(h.componentName+".Update", "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
+	if current.Version != request.Version {
+		err := coreerr.AccountHolderVersionMismatchError
+		h.log.Warn(h.componentName+".Update", "error", err, "version", current.Version, "x_trace_id", traceID)
+		return nil, err
+	}
+	now := h.clock.Now()
+	patched, changedFields := current.Apply(mapper.UpdateDTOToPatch(request))
+	if err = holder.Validate(patched, now); err != nil {
+		h.log.Warn(h.componentName+".Update", "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
+	if len(changedFields) == 0 {
+		return mapper.EntityToResponse(current), nil
+	}
+	patched.UpdatedAt = now
+	// The repository checks the version again, rejecting changes saved since the holder was read
+	output, err := h.holderRepository.Save(ctx, &patched, request.Version, changedFields)
+	if err != nil {
+		h.log.Warn(h.componentName+".Update", "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
+	h.log.Info(h.componentName+".Update", "holder", output, "changed_fields", changedFields, "x_trace_id", traceID)
+	return mapper.EntityToResponse(output), nil
+}
+
+func (h *HolderService) History(ctx context.Context, request dto.HolderHistoryRequest) (*dto.HolderHistoryResponse, error) {
+	traceID := contextutils.GetTraceID(ctx)
+	h.log.Debug(h.componentName+".History", "request", request, "x_trace_id", traceID)
+	if request.AccountID <= 0 {
+		err := coreerr.InvalidParametersError
+		h.log.Warn(h.componentName+".History", "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
+	if _, err := h.accountRepository.FindByID(ctx, request.AccountID); err != nil {
+		h.log.Warn(h.componentName+".History", "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
+	changes, err := h.holderRepository.History(ctx, request.AccountID)
+	if err != nil {
+		h.log.Warn(h.componentName+".History", "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
+	return mapper.ChangesToHistoryResponse(request.AccountID, changes), nil
+}
+
+// current returns the holder of an existing account, the empty holder at version 0 when it was never changed
+func (h *HolderService) current(ctx context.Context, accountID int64) (*holder.Holder, error) {
+	if _, err := h.accountRepository.FindByID(ctx, accountID); err != nil {
+		return nil, err
+	}
+	current, err := h.holderRepository.FindByAccountID(ctx, accountID)
+	if errors.Is(err, coreerr.AccountHolderNotFoundError) {
+		return &holder.Holder{AccountID: accountID}, nil
+	}
+	return current, err
+}
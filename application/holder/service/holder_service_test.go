@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"github.com/kiosanim/pismo-code-assessment/application/holder/dto"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/clock"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/factory"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/account"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/holder"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+type HolderServiceTestSuite struct {
+	suite.Suite
+	accountRepository *account.AccountRepositoryMock
+	holderRepository  *holder.HolderRepositoryMock
+	ctx               context.Context
+	log               *logger.LoggerMock
+	factory           *factory.FactoryMock
+}
+
+func (s *HolderServiceTestSuite) SetupTest() {
+	ctrl := gomock.NewController(s.T())
+	s.ctx = context.Background()
+	s.accountRepository = account.NewAccountRepositoryMock()
+	s.holderRepository = holder.NewHolderRepositoryMock()
+	s.log = logger.NewLoggerMock(ctrl)
+	s.log.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+	s.log.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	s.log.EXPECT().Warn(gomock.Any(), gomock.Any()).AnyTimes()
+	s.factory = factory.NewFactoryMock(ctrl)
+	s.factory.EXPECT().AccountRepository().Return(s.accountRepository).AnyTimes()
+	s.factory.EXPECT().HolderRepository().Return(s.holderRepository).AnyTimes()
+	s.factory.EXPECT().Clock().Return(clock.NewFakeClock(time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC))).AnyTimes()
+	s.factory.EXPECT().Log().Return(s.log).AnyTimes()
+}
+
+func ptr(value string) *string {
+	return &value
+}
+
+func (s *HolderServiceTestSuite) TestFindNeverChanged() {
+	hs := NewHolderService(s.factory)
+	s.accountRepository.On("FindByID", s.ctx, int64(1)).Return(&account.Account{AccountID: 1}, nil)
+	s.holderRepository.On("FindByAccountID", s.ctx, int64(1)).Return(nil, errors.AccountHolderNotFoundError)
+	output, err := hs.Find(s.ctx, dto.FindHolderRequest{AccountID: 1})
+	s.NoError(err)
+	s.Equal(&dto.HolderResponse{AccountID: 1}, output, "a holder never changed should be empty at version 0")
+}
+
+func (s *HolderServiceTestSuite) TestFindAccountNotFound() {
+	hs := NewHolderService(s.factory)
+	s.accountRepository.On("FindByID", s.ctx, int64(1)).Return(nil, errors.AccountNotFoundError)
+	_, err := hs.Find(s.ctx, dto.FindHolderRequest{AccountID: 1})
+	s.ErrorIs(err, errors.AccountNotFoundError)
+	s.holderRepository.AssertNumberOfCalls(s.T(), "FindByAccountID", 0)
+}
+
+func (s *HolderServiceTestSuite) TestUpdate() {
+	hs := NewHolderService(s.factory)
+	current := &holder.Holder{AccountID: 1, Name: "Ana Silva", Version: 2}
+	s.accountRepository.On("FindByID", s.ctx, int64(1)).Return(&account.Account{AccountID: 1}, nil)
+	s.holderRepository.On("FindByAccountID", s.ctx, int64(1)).Return(current, nil)
+	patched := &holder.Holder{AccountID: 1, Name: "Ana Silva", Email: "ana@example.com", Version: 2,
+		UpdatedAt: time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)}
+	saved := *patched
+	saved.Version = 3
+	s.holderRepository.On("Save", s.ctx, patched, int64(2), []string{"email"}).Return(&saved, nil)
+	output, err := hs.Update(s.ctx, dto.UpdateHolderRequest{AccountID: 1, Version: 2, Name: ptr("Ana Silva"), Email: ptr("Ana@Example.com")})
+	s.NoError(err)
+	s.Equal(int64(3), output.Version)
+	s.Equal("ana@example.com", output.Email)
+}
+
+func (s *HolderServiceTestSuite) TestUpdateVersionMismatch() {
+	hs := NewHolderService(s.factory)
+	s.accountRepository.On("FindByID", s.ctx, int64(1)).Return(&account.Account{AccountID: 1}, nil)
+	s.holderRepository.On("FindByAccountID", s.ctx, int64(1)).Return(&holder.Holder{AccountID: 1, Version: 3}, nil)
+	_, err := hs.Update(s.ctx, dto.UpdateHolderRequest{AccountID: 1, Version: 2, Email: ptr("ana@example.com")})
+	s.ErrorIs(err, errors.AccountHolderVersionMismatchError)
+	s.holderRepository.AssertNumberOfCalls(s.T(), "Save", 0)
+}
+
+func (s *HolderServiceTestSuite) TestUpdateConcurrentChange() {
+	hs := NewHolderService(s.factory)
+	s.accountRepository.On("FindByID", s.ctx, int64(1)).Return(&account.Account{AccountID: 1}, nil)
+	s.holderRepository.On("FindByAccountID", s.ctx, int64(1)).Return(nil, errors.AccountHolderNotFoundError)
+	s.holderRepository.On("Save", s.ctx, mock.Anything, int64(0), []string{"email"}).Return(nil, errors.AccountHolderVersionMismatchError)
+	_, err := hs.Update(s.ctx, dto.UpdateHolderRequest{AccountID: 1, Email: ptr("ana@example.com")})
+	s.ErrorIs(err, errors.AccountHolderVersionMismatchError, "a change saved after the holder was read should be rejected")
+}
+
+func (s *HolderServiceTestSuite) TestUpdateWithoutChanges() {
+	hs := NewHolderService(s.factory)
+	s.accountRepository.On("FindByID", s.ctx, int64(1)).Return(&account.Account{AccountID: 1}, nil)
+	s.holderRepository.On("FindByAccountID", s.ctx, int64(1)).Return(&holder.Holder{AccountID: 1, Email: "ana@example.com", Version: 1}, nil)
+	output, err := hs.Update(s.ctx, dto.UpdateHolderRequest{AccountID: 1, Version: 1, Email: ptr(" ana@example.com ")})
+	s.NoError(err)
+	s.Equal(int64(1), output.Version, "no new version should be created")
+	s.holderRepository.AssertNumberOfCalls(s.T(), "Save", 0)
+}
+
+func (s *HolderServiceTestSuite) TestUpdateInvalidHolder() {
+	hs := NewHolderService(s.factory)
+	s.accountRepository.On("FindByID", s.ctx, int64(1)).Return(&account.Account{AccountID: 1}, nil)
+	s.holderRepository.On("FindByAccountID", s.ctx, int64(1)).Return(nil, errors.AccountHolderNotFoundError)
+	_, err := hs.Update(s.ctx, dto.UpdateHolderRequest{AccountID: 1, BirthDate: ptr("2026-10-20")})
+	s.ErrorIs(err, errors.AccountHolderInvalidError)
+	s.holderRepository.AssertNumberOfCalls(s.T(), "Save", 0)
+}
+
+func (s *HolderServiceTestSuite) TestUpdateInvalidParameters() {
+	hs := NewHolderService(s.factory)
+	_, err := hs.Update(s.ctx, dto.UpdateHolderRequest{AccountID: 0})
+	s.ErrorIs(err, errors.InvalidParametersError)
+	_, err = hs.Update(s.ctx, dto.UpdateHolderRequest{AccountID: 1, Version: -1})
+	s.ErrorIs(err, errors.InvalidParametersError)
+}
+
+func (s *HolderServiceTestSuite) TestHistory() {
+	hs := NewHolderService(s.factory)
+	s.accountRepository.On("FindByID", s.ctx, int64(1)).Return(&account.Account{AccountID: 1}, nil)
+	s.holderRepository.On("History", s.ctx, int64(1)).Return([]holder.Change{
+		{AccountID: 1, Version: 2, ChangedFields: []string{"phone"}, Holder: holder.Holder{AccountID: 1, Phone: "+5511987654321", Version: 2}},
+		{AccountID: 1, Version: 1, ChangedFields: []string{"email"}, Holder: holder.Holder{AccountID: 1, Version: 1}},
+	}, nil)
+	output, err := hs.History(s.ctx, dto.HolderHistoryRequest{AccountID: 1})
+	s.NoError(err)
+	s.Len(output.Changes, 2)
+	s.Equal([]string{"phone"}, output.Changes[0].ChangedFields)
+	s.Equal("+5511987654321", output.Changes[0].Holder.Phone)
+}
+
+func TestHolderServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(HolderServiceTestSuite))
+}
@@ -0,0 +1,3 @@
+// Package loadtest drives the API with concurrent clients following an operation mix and reports its throughput,
+// latency percentiles and errors by code
+package loadtest
@@ -0,0 +1,304 @@
+package loadtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/kiosanim/pismo-code-assessment/application/seed"
+	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/transaction"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Operations of the mix
+const (
+	OperationCreateAccount     = "create_account"
+	OperationCreateTransaction = "create_transaction"
+	OperationGetAccount        = "get_account"
+	OperationGetTransaction    = "get_transaction"
+	OperationListTransactions  = "list_transactions"
+)
+
+// DefaultMix is mostly transaction creation, the operation serialized by the transaction lock
+const DefaultMix = "create_transaction=70,get_account=10,get_transaction=10,list_transactions=10"
+
+// Error codes of failures without an error of internal/core/errors
+const (
+	CodeTransport  = "TRANSPORT_ERROR" // The request was not sent or its response not read
+	httpCodePrefix = "HTTP_"           // Followed by the status of error responses without a known error
+)
+
+var operations = []string{OperationCreateAccount, OperationCreateTransaction, OperationGetAccount, OperationGetTransaction, OperationListTransactions}
+
+// Options sets the load. A run stops after Duration or Requests requests, whichever comes first, zero values
+// setting no limit.
+type Options struct {
+	Concurrency int
+	Duration    time.Duration
+	Requests    int
+	Accounts    int            // Accounts created before the run, the fewer accounts the more transactions contend for them
+	Mix         map[string]int // Relative weight of each operation
+	Seed        uint64         // Seed of the document numbers, operations and amounts
+}
+
+// ParseMix parses an operation mix, comma separated OPERATION=WEIGHT pairs, e.g. DefaultMix
+func ParseMix(value string) (map[string]int, error) {
+	mix := make(map[string]int)
+	for _, pair := range strings.Split(value, ",") {
+		operation, weight, found := strings.Cut(strings.TrimSpace(pair), "=")
+		weightValue, err := strconv.Atoi(strings.TrimSpace(weight))
+		if !found || err != nil {
+			return nil, fmt.Errorf("%w: invalid mix entry %q, expected OPERATION=WEIGHT", coreerr.InvalidParametersError, pair)
+		}
+		mix[strings.TrimSpace(operation)] = weightValue
+	}
+	return mix, nil
+}
+
+// Validate checks the options
+func (o Options) Validate() error {
+	if o.Concurrency <= 0 || o.Accounts <= 0 {
+		return fmt.Errorf("%w: concurrency and accounts must be greater than zero", coreerr.InvalidParametersError)
+	}
+	if o.Duration <= 0 && o.Requests <= 0 {
+		return fmt.Errorf("%w: the duration or the number of requests must be informed", coreerr.InvalidParametersError)
+	}
+	total := 0
+	for operation, weight := range o.Mix {
+		if !isAnOperation(operation) || weight < 0 {
+			return fmt.Errorf("%w: invalid mix weight %d for operation %q", coreerr.InvalidParametersError, weight, operation)
+		}
+		total += weight
+	}
+	if total == 0 {
+		return fmt.Errorf("%w: the mix must have a positive weight", coreerr.InvalidParametersError)
+	}
+	return nil
+}
+
+func isAnOperation(operation string) bool {
+	for _, known := range operations {
+		if operation == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Runner drives the API at baseURL with Options.Concurrency clients, each sending a request as soon as the previous
+// one is answered
+type Runner struct {
+	client     *http.Client
+	baseURL    string
+	headers    map[string]string // Headers sent with every request, e.g. Authorization
+	options    Options
+	mix        []string // Operations of the mix, sorted
+	weights    []int    // Cumulative weights of mix
+	accountIDs []int64
+	sent       atomic.Int64
+	mu         sync.Mutex
+	created    []int64 // IDs of the transactions created during the run
+}
+
+func NewRunner(client *http.Client, baseURL string, headers map[string]string, options Options) (*Runner, error) {
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+	runner := &Runner{
+		client:  client,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		headers: headers,
+		options: options,
+	}
+	for operation, weight := range options.Mix {
+		if weight > 0 {
+			runner.mix = append(runner.mix, operation)
+		}
+	}
+	sort.Strings(runner.mix)
+	total := 0
+	for _, operation := range runner.mix {
+		total += options.Mix[operation]
+		runner.weights = append(runner.weights, total)
+	}
+	return runner, nil
+}
+
+// Run creates the accounts, then sends requests until the duration ends, the requests are sent or ctx is done.
+// Requests interrupted by the end of the run are not reported.
+func (r *Runner) Run(ctx context.Context) (*Report, error) {
+	random := rand.New(rand.NewPCG(r.options.Seed, 0))
+	for range r.options.Accounts {
+		accountID, sample := r.createAccount(ctx, random)
+		if sample.Failed() {
+			return nil, fmt.Errorf("failed to create the accounts of the load test: %s (status %d)", sample.Code, sample.Status)
+		}
+		r.accountIDs = append(r.accountIDs, accountID)
+	}
+	runCtx := ctx
+	if r.options.Duration > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, r.options.Duration)
+		defer cancel()
+	}
+	results := make([][]Sample, r.options.Concurrency)
+	var workers sync.WaitGroup
+	start := time.Now()
+	for worker := range r.options.Concurrency {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			results[worker] = r.work(runCtx, rand.New(rand.NewPCG(r.options.Seed, uint64(worker+1))))
+		}()
+	}
+	workers.Wait()
+	elapsed := time.Since(start)
+	var samples []Sample
+	for _, workerSamples := range results {
+		samples = append(samples, workerSamples...)
+	}
+	return NewReport(samples, elapsed, r.options.Concurrency), nil
+}
+
+func (r *Runner) work(ctx context.Context, random *rand.Rand) []Sample {
+	var samples []Sample
+	for ctx.Err() == nil {
+		if r.options.Requests > 0 && r.sent.Add(1) > int64(r.options.Requests) {
+			break
+		}
+		sample := r.send(ctx, random)
+		if ctx.Err() != nil && sample.Code == CodeTransport {
+			break
+		}
+		samples = append(samples, sample)
+	}
+	return samples
+}
+
+// send sends a request of an operation drawn from the mix
+func (r *Runner) send(ctx context.Context, random *rand.Rand) Sample {
+	operation := r.mix[sort.SearchInts(r.weights, random.IntN(r.weights[len(r.weights)-1])+1)]
+	accountID := r.accountIDs[random.IntN(len(r.accountIDs))]
+	switch operation {
+	case OperationCreateAccount:
+		_, sample := r.createAccount(ctx, random)
+		return sample
+	case OperationGetAccount:
+		sample, _ := r.do(ctx, operation, http.MethodGet, fmt.Sprintf("/accounts/%d", accountID), nil)
+		return sample
+	case OperationGetTransaction:
+		if transactionID, ok := r.createdTransaction(random); ok {
+			sample, _ := r.do(ctx, operation, http.MethodGet, fmt.Sprintf("/transactions/%d", transactionID), nil)
+			return sample
+		}
+	case OperationListTransactions:
+		sample, _ := r.do(ctx, operation, http.MethodGet, fmt.Sprintf("/transactions?account_id=%d&limit=20", accountID), nil)
+		return sample
+	}
+	// Transactions are also created while there is none to get
+	operationTypeID := transaction.Purchase + random.IntN(transaction.Payment)
+	request := map[string]any{
+		"account_id":        accountID,
+		"operation_type_id": operationTypeID,
+		"amount":            float64(100+random.IntN(50000)) / 100,
+	}
+	sample, body := r.do(ctx, OperationCreateTransaction, http.MethodPost, "/transactions", request)
+	var response struct {
+		Transaction struct {
+			TransactionID int64 `json:"transaction_id"`
+		} `json:"transaction"`
+	}
+	if !sample.Failed() && json.Unmarshal(body, &response) == nil && response.Transaction.TransactionID > 0 {
+		r.mu.Lock()
+		r.created = append(r.created, response.Transaction.TransactionID)
+		r.mu.Unlock()
+	}
+	return sample
+}
+
+func (r *Runner) createAccount(ctx context.Context, random *rand.Rand) (int64, Sample) {
+	documentNumber := seed.CPF(random)
+	sample, body := r.do(ctx, OperationCreateAccount, http.MethodPost, "/accounts", map[string]any{"document_number": documentNumber})
+	var response struct {
+		AccountID int64 `json:"account_id"`
+	}
+	if !sample.Failed() {
+		_ = json.Unmarshal(body, &response)
+	}
+	return response.AccountID, sample
+}
+
+func (r *Runner) createdTransaction(random *rand.Rand) (int64, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.created) == 0 {
+		return 0, false
+	}
+	return r.created[random.IntN(len(r.created))], true
+}
+
+// do sends a request, returning its sample and the response body
+func (r *Runner) do(ctx context.Context, operation string, method string, path string, payload any) (Sample, []byte) {
+	sample := Sample{Operation: operation}
+	var body io.Reader
+	if payload != nil {
+		content, err := json.Marshal(payload)
+		if err != nil {
+			sample.Code = CodeTransport
+			return sample, nil
+		}
+		body = bytes.NewReader(content)
+	}
+	request, err := http.NewRequestWithContext(ctx, method, r.baseURL+path, body)
+	if err != nil {
+		sample.Code = CodeTransport
+		return sample, nil
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	for name, value := range r.headers {
+		request.Header.Set(name, value)
+	}
+	start := time.Now()
+	response, err := r.client.Do(request)
+	if err != nil {
+		sample.Latency = time.Since(start)
+		sample.Code = CodeTransport
+		return sample, nil
+	}
+	defer response.Body.Close()
+	content, err := io.ReadAll(response.Body)
+	sample.Latency = time.Since(start)
+	sample.Status = response.StatusCode
+	switch {
+	case err != nil:
+		sample.Code = CodeTransport
+	case response.StatusCode >= http.StatusBadRequest:
+		sample.Code = errorCode(response.StatusCode, content)
+	}
+	return sample, content
+}
+
+// errorCode returns the code of the error of an error response, see coreerr.Code, or HTTP_ followed by its status
+// when the error is unknown
+func errorCode(status int, body []byte) string {
+	var response struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(body, &response) == nil {
+		if code := coreerr.Code(coreerr.FromMessage(response.Error)); code != "" {
+			return code
+		}
+	}
+	return httpCodePrefix + strconv.Itoa(status)
+}
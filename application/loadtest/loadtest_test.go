@@ -0,0 +1,156 @@
+package loadtest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kiosanim/pismo-code-assessment/interfaces/http/router"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/config"
+	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/factory"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/logger/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newInProcessAPI serves the API on the memory repositories
+func newInProcessAPI(t *testing.T) *httptest.Server {
+	t.Helper()
+	gin.SetMode(gin.ReleaseMode)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	configuration := &config.Configuration{
+		DistributedLock: config.DistributedLock{TTL: 1000, RetryInterval: 1, WaitingTime: 2000},
+		Currency:        config.CurrencyConfig{Default: "BRL"},
+	}
+	log := mock.NewMockLogger()
+	memoryFactory, err := factory.NewMemoryFactory(ctx, configuration, log)
+	require.NoError(t, err)
+	server := httptest.NewServer(router.NewRouter(memoryFactory, nil, log))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestParseMix(t *testing.T) {
+	mix, err := ParseMix(DefaultMix)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{
+		OperationCreateTransaction: 70,
+		OperationGetAccount:        10,
+		OperationGetTransaction:    10,
+		OperationListTransactions:  10,
+	}, mix)
+
+	_, err = ParseMix("create_transaction")
+	assert.ErrorIs(t, err, coreerr.InvalidParametersError)
+	_, err = ParseMix("create_transaction=many")
+	assert.ErrorIs(t, err, coreerr.InvalidParametersError)
+}
+
+func TestOptionsValidate(t *testing.T) {
+	valid := Options{Concurrency: 1, Requests: 1, Accounts: 1, Mix: map[string]int{OperationGetAccount: 1}}
+	assert.NoError(t, valid.Validate())
+
+	tests := map[string]func(o *Options){
+		"no concurrency":      func(o *Options) { o.Concurrency = 0 },
+		"no accounts":         func(o *Options) { o.Accounts = 0 },
+		"no limit":            func(o *Options) { o.Requests = 0 },
+		"unknown operation":   func(o *Options) { o.Mix = map[string]int{"delete_account": 1} },
+		"negative weight":     func(o *Options) { o.Mix = map[string]int{OperationGetAccount: -1} },
+		"no positive weights": func(o *Options) { o.Mix = map[string]int{OperationGetAccount: 0} },
+	}
+	for name, change := range tests {
+		t.Run(name, func(t *testing.T) {
+			options := valid
+			change(&options)
+			assert.ErrorIs(t, options.Validate(), coreerr.InvalidParametersError)
+		})
+	}
+}
+
+func TestRunInProcess(t *testing.T) {
+	server := newInProcessAPI(t)
+	mix, err := ParseMix(DefaultMix)
+	require.NoError(t, err)
+	runner, err := NewRunner(server.Client(), server.URL, nil, Options{Concurrency: 4, Requests: 200, Accounts: 5, Mix: mix, Seed: 7})
+	require.NoError(t, err)
+
+	report, err := runner.Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 200, report.Requests)
+	assert.Equal(t, 4, report.Concurrency)
+	assert.Positive(t, report.Throughput)
+	assert.LessOrEqual(t, report.Latency.P50, report.Latency.P99)
+	operations := make(map[string]int)
+	for _, operation := range report.Operations {
+		operations[operation.Operation] = operation.Requests
+	}
+	assert.Positive(t, operations[OperationCreateTransaction])
+	assert.Positive(t, operations[OperationGetAccount])
+	assert.Positive(t, operations[OperationListTransactions])
+	for _, errorCount := range report.Errors {
+		assert.NotEqual(t, CodeTransport, errorCount.Code)
+	}
+}
+
+func TestRunStopsAfterDuration(t *testing.T) {
+	server := newInProcessAPI(t)
+	runner, err := NewRunner(server.Client(), server.URL, nil, Options{
+		Concurrency: 2,
+		Duration:    200 * time.Millisecond,
+		Accounts:    1,
+		Mix:         map[string]int{OperationGetAccount: 1},
+	})
+	require.NoError(t, err)
+
+	start := time.Now()
+	report, err := runner.Run(context.Background())
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 2*time.Second)
+	assert.Positive(t, report.Requests)
+	assert.Zero(t, report.Failed)
+}
+
+func TestErrorCodes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/accounts/1":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"account not found"}`))
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+			_, _ = w.Write([]byte(`bad gateway`))
+		}
+	}))
+	defer server.Close()
+	runner, err := NewRunner(server.Client(), server.URL, nil, Options{Concurrency: 1, Requests: 1, Accounts: 1, Mix: map[string]int{OperationGetAccount: 1}})
+	require.NoError(t, err)
+
+	sample, _ := runner.do(context.Background(), OperationGetAccount, http.MethodGet, "/accounts/1", nil)
+	assert.Equal(t, "AccountNotFoundError", sample.Code)
+	assert.Equal(t, http.StatusNotFound, sample.Status)
+	sample, _ = runner.do(context.Background(), OperationGetAccount, http.MethodGet, "/accounts/2", nil)
+	assert.Equal(t, "HTTP_502", sample.Code)
+
+	server.Close()
+	sample, _ = runner.do(context.Background(), OperationGetAccount, http.MethodGet, "/accounts/1", nil)
+	assert.Equal(t, CodeTransport, sample.Code)
+	assert.Zero(t, sample.Status)
+}
+
+func TestRunFailsWhenTheAccountsAreNotCreated(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":"invalid parameters"}`))
+	}))
+	defer server.Close()
+	runner, err := NewRunner(server.Client(), server.URL, nil, Options{Concurrency: 1, Requests: 1, Accounts: 1, Mix: map[string]int{OperationGetAccount: 1}})
+	require.NoError(t, err)
+
+	_, err = runner.Run(context.Background())
+	assert.ErrorContains(t, err, "InvalidParametersError")
+}
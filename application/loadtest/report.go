@@ -0,0 +1,160 @@
+package loadtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// Sample is the outcome of a request. Status is zero when the request could not be sent.
+type Sample struct {
+	Operation string
+	Latency   time.Duration
+	Status    int
+	Code      string // Error code of failed requests, see errorCode
+}
+
+// Failed checks if the request was not sent or the API answered it with an error status
+func (s Sample) Failed() bool {
+	return s.Code != ""
+}
+
+// Latency holds latency percentiles in milliseconds
+type Latency struct {
+	P50 float64 `json:"p50_ms"`
+	P95 float64 `json:"p95_ms"`
+	P99 float64 `json:"p99_ms"`
+	Max float64 `json:"max_ms"`
+}
+
+// OperationReport summarizes the requests of an operation
+type OperationReport struct {
+	Operation  string  `json:"operation"`
+	Requests   int     `json:"requests"`
+	Failed     int     `json:"failed"`
+	Throughput float64 `json:"throughput_rps"`
+	Latency    Latency `json:"latency"`
+}
+
+// ErrorCount is the number of failed requests of an error code
+type ErrorCount struct {
+	Code  string `json:"code"`
+	Count int    `json:"count"`
+}
+
+// Report summarizes a load test
+type Report struct {
+	Concurrency int               `json:"concurrency"`
+	Duration    float64           `json:"duration_seconds"`
+	Requests    int               `json:"requests"`
+	Failed      int               `json:"failed"`
+	Throughput  float64           `json:"throughput_rps"`
+	Latency     Latency           `json:"latency"`
+	Operations  []OperationReport `json:"operations"` // Ordered by operation name
+	Errors      []ErrorCount      `json:"errors"`     // The most frequent first
+}
+
+// NewReport summarizes the samples of a load test that ran for elapsed
+func NewReport(samples []Sample, elapsed time.Duration, concurrency int) *Report {
+	report := &Report{
+		Concurrency: concurrency,
+		Duration:    elapsed.Seconds(),
+		Operations:  []OperationReport{},
+		Errors:      []ErrorCount{},
+	}
+	byOperation := make(map[string][]Sample)
+	errors := make(map[string]int)
+	for _, sample := range samples {
+		byOperation[sample.Operation] = append(byOperation[sample.Operation], sample)
+		if sample.Failed() {
+			errors[sample.Code]++
+		}
+	}
+	report.Requests, report.Failed, report.Throughput, report.Latency = summarize(samples, elapsed)
+	for operation, operationSamples := range byOperation {
+		operationReport := OperationReport{Operation: operation}
+		operationReport.Requests, operationReport.Failed, operationReport.Throughput, operationReport.Latency = summarize(operationSamples, elapsed)
+		report.Operations = append(report.Operations, operationReport)
+	}
+	sort.Slice(report.Operations, func(i, j int) bool { return report.Operations[i].Operation < report.Operations[j].Operation })
+	for code, count := range errors {
+		report.Errors = append(report.Errors, ErrorCount{Code: code, Count: count})
+	}
+	sort.Slice(report.Errors, func(i, j int) bool {
+		if report.Errors[i].Count != report.Errors[j].Count {
+			return report.Errors[i].Count > report.Errors[j].Count
+		}
+		return report.Errors[i].Code < report.Errors[j].Code
+	})
+	return report
+}
+
+func summarize(samples []Sample, elapsed time.Duration) (requests int, failed int, throughput float64, latency Latency) {
+	latencies := make([]time.Duration, 0, len(samples))
+	for _, sample := range samples {
+		latencies = append(latencies, sample.Latency)
+		if sample.Failed() {
+			failed++
+		}
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	if elapsed > 0 {
+		throughput = float64(len(samples)) / elapsed.Seconds()
+	}
+	latency = Latency{
+		P50: milliseconds(percentile(latencies, 50)),
+		P95: milliseconds(percentile(latencies, 95)),
+		P99: milliseconds(percentile(latencies, 99)),
+	}
+	if len(latencies) > 0 {
+		latency.Max = milliseconds(latencies[len(latencies)-1])
+	}
+	return len(samples), failed, throughput, latency
+}
+
+// percentile returns the nearest rank percentile of sorted latencies, zero when there are none
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[min(max(rank, 1), len(sorted))-1]
+}
+
+func milliseconds(d time.Duration) float64 {
+	return math.Round(float64(d)/float64(time.Millisecond)*1000) / 1000
+}
+
+// WriteJSON writes the report as an indented JSON object
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WriteText writes the report as aligned tables
+func (r *Report) WriteText(w io.Writer) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(table, "duration\t%.1fs, %d clients\n", r.Duration, r.Concurrency)
+	fmt.Fprintf(table, "requests\t%d, %d failed\n", r.Requests, r.Failed)
+	fmt.Fprintf(table, "throughput\t%.1f requests/s\n", r.Throughput)
+	fmt.Fprintf(table, "latency\tp50 %.2fms, p95 %.2fms, p99 %.2fms, max %.2fms\n", r.Latency.P50, r.Latency.P95, r.Latency.P99, r.Latency.Max)
+	fmt.Fprintln(table)
+	fmt.Fprintln(table, "OPERATION\tREQUESTS\tFAILED\tREQUESTS/S\tP50 MS\tP95 MS\tP99 MS\tMAX MS")
+	for _, operation := range r.Operations {
+		fmt.Fprintf(table, "%s\t%d\t%d\t%.1f\t%.2f\t%.2f\t%.2f\t%.2f\n", operation.Operation, operation.Requests, operation.Failed,
+			operation.Throughput, operation.Latency.P50, operation.Latency.P95, operation.Latency.P99, operation.Latency.Max)
+	}
+	if len(r.Errors) > 0 {
+		fmt.Fprintln(table)
+		fmt.Fprintln(table, "ERROR\tCOUNT")
+		for _, errorCount := range r.Errors {
+			fmt.Fprintf(table, "%s\t%d\n", errorCount.Code, errorCount.Count)
+		}
+	}
+	return table.Flush()
+}
@@ -0,0 +1,76 @@
+package loadtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPercentile(t *testing.T) {
+	latencies := make([]time.Duration, 0, 100)
+	for i := 1; i <= 100; i++ {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+	assert.Equal(t, 50*time.Millisecond, percentile(latencies, 50))
+	assert.Equal(t, 95*time.Millisecond, percentile(latencies, 95))
+	assert.Equal(t, 99*time.Millisecond, percentile(latencies, 99))
+	assert.Equal(t, 100*time.Millisecond, percentile(latencies, 100))
+	assert.Equal(t, time.Duration(0), percentile(nil, 50))
+	assert.Equal(t, 7*time.Millisecond, percentile([]time.Duration{7 * time.Millisecond}, 99))
+}
+
+func TestNewReport(t *testing.T) {
+	samples := []Sample{
+		{Operation: OperationCreateTransaction, Latency: 10 * time.Millisecond, Status: 201},
+		{Operation: OperationCreateTransaction, Latency: 30 * time.Millisecond, Status: 404, Code: "AccountNotFoundError"},
+		{Operation: OperationGetAccount, Latency: 20 * time.Millisecond, Status: 200},
+		{Operation: OperationGetAccount, Latency: 40 * time.Millisecond, Status: 404, Code: "AccountNotFoundError"},
+		{Operation: OperationGetAccount, Code: CodeTransport},
+	}
+	report := NewReport(samples, 2*time.Second, 3)
+	assert.Equal(t, 3, report.Concurrency)
+	assert.Equal(t, 5, report.Requests)
+	assert.Equal(t, 3, report.Failed)
+	assert.Equal(t, 2.5, report.Throughput)
+	assert.Equal(t, 40.0, report.Latency.Max)
+	assert.Equal(t, 20.0, report.Latency.P50)
+	require.Len(t, report.Operations, 2)
+	assert.Equal(t, OperationCreateTransaction, report.Operations[0].Operation)
+	assert.Equal(t, 2, report.Operations[0].Requests)
+	assert.Equal(t, 1, report.Operations[0].Failed)
+	assert.Equal(t, 30.0, report.Operations[0].Latency.P99)
+	assert.Equal(t, OperationGetAccount, report.Operations[1].Operation)
+	assert.Equal(t, []ErrorCount{{Code: "AccountNotFoundError", Count: 2}, {Code: CodeTransport, Count: 1}}, report.Errors)
+}
+
+func TestReportWriters(t *testing.T) {
+	report := NewReport([]Sample{
+		{Operation: OperationGetTransaction, Latency: 5 * time.Millisecond, Status: 404, Code: "TransactionNotFoundError"},
+	}, time.Second, 1)
+
+	var text bytes.Buffer
+	require.NoError(t, report.WriteText(&text))
+	assert.Contains(t, text.String(), "requests    1, 1 failed")
+	assert.Contains(t, text.String(), OperationGetTransaction)
+	assert.Contains(t, text.String(), "TransactionNotFoundError  1")
+
+	var content bytes.Buffer
+	require.NoError(t, report.WriteJSON(&content))
+	var decoded Report
+	require.NoError(t, json.Unmarshal(content.Bytes(), &decoded))
+	assert.Equal(t, *report, decoded)
+}
+
+func TestReportWithoutSamples(t *testing.T) {
+	report := NewReport(nil, 0, 1)
+	assert.Zero(t, report.Requests)
+	assert.Zero(t, report.Throughput)
+	assert.Empty(t, report.Operations)
+	var content bytes.Buffer
+	require.NoError(t, report.WriteJSON(&content))
+	assert.Contains(t, content.String(), `"operations": []`)
+}
@@ -0,0 +1,3 @@
+// Package seed generates deterministic synthetic accounts and transactions for demo and performance environments,
+// and replays request logs against a running API
+package seed
@@ -0,0 +1,71 @@
+package seed
+
+import (
+	"math/rand/v2"
+	"strings"
+)
+
+var (
+	cnpjFirstWeights  = []int{5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}
+	cnpjSecondWeights = []int{6, 5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}
+)
+
+// CPF returns a random valid CPF, digits only
+func CPF(random *rand.Rand) string {
+	digits := randomDigits(random, 9)
+	digits = append(digits, checkDigit(digits, descendingWeights(10)))
+	digits = append(digits, checkDigit(digits, descendingWeights(11)))
+	return join(digits)
+}
+
+// CNPJ returns a random valid CNPJ of a head office (branch 0001), digits only
+func CNPJ(random *rand.Rand) string {
+	digits := append(randomDigits(random, 8), 0, 0, 0, 1)
+	digits = append(digits, checkDigit(digits, cnpjFirstWeights))
+	digits = append(digits, checkDigit(digits, cnpjSecondWeights))
+	return join(digits)
+}
+
+// randomDigits returns n random digits that are not all the same, which the validators reject
+func randomDigits(random *rand.Rand, n int) []int {
+	digits := make([]int, n, n+2)
+	for {
+		repeated := true
+		for i := range digits {
+			digits[i] = random.IntN(10)
+			repeated = repeated && digits[i] == digits[0]
+		}
+		if !repeated {
+			return digits
+		}
+	}
+}
+
+// checkDigit returns the modulo 11 check digit of digits
+func checkDigit(digits []int, weights []int) int {
+	sum := 0
+	for i, weight := range weights {
+		sum += digits[i] * weight
+	}
+	if rest := sum % 11; rest >= 2 {
+		return 11 - rest
+	}
+	return 0
+}
+
+// descendingWeights returns the weights from first down to 2
+func descendingWeights(first int) []int {
+	weights := make([]int, 0, first-1)
+	for weight := first; weight >= 2; weight-- {
+		weights = append(weights, weight)
+	}
+	return weights
+}
+
+func join(digits []int) string {
+	var builder strings.Builder
+	for _, digit := range digits {
+		builder.WriteByte(byte('0' + digit))
+	}
+	return builder.String()
+}
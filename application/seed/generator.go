@@ -0,0 +1,249 @@
+package seed
+
+import (
+	"fmt"
+	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/account"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/transaction"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Distributions of the event dates between Options.From and Options.To
+const (
+	DistributionUniform = "uniform" // Every instant is equally likely
+	DistributionRecent  = "recent"  // Exponentially more transactions close to Options.To
+)
+
+// recentRate is the rate of the exponential distribution of DistributionRecent, about 95% of the transactions
+// happen in the last third of the interval
+const recentRate = 9.0
+
+// DefaultMix is the operation type mix of a card holder: mostly purchases and a few payments
+const DefaultMix = "1=60,2=10,3=10,4=20"
+
+// amountRange is the interval, in cents, the amount of an operation type is drawn from
+type amountRange struct {
+	min, max int64
+	step     int64 // Amounts are multiples of step
+}
+
+var amountRanges = map[int]amountRange{
+	transaction.Purchase:            {min: 500, max: 50000, step: 1},
+	transaction.InstallmentPurchase: {min: 10000, max: 300000, step: 1},
+	transaction.Withdrawal:          {min: 2000, max: 100000, step: 1000},
+	transaction.Payment:             {min: 5000, max: 200000, step: 100},
+}
+
+// merchants are the merchants of the generated purchases
+var merchants = []transaction.Merchant{
+	{Name: "Padaria Pao Dourado", CategoryCode: "5462", Country: "BR"},
+	{Name: "Supermercado Boa Compra", CategoryCode: "5411", Country: "BR"},
+	{Name: "Auto Posto Estrada Real", CategoryCode: "5541", Country: "BR"},
+	{Name: "Drogaria Vida Saudavel", CategoryCode: "5912", Country: "BR"},
+	{Name: "Restaurante Sabor Caseiro", CategoryCode: "5812", Country: "BR"},
+	{Name: "Livraria Pagina Nova", CategoryCode: "5942", Country: "BR"},
+	{Name: "Loja de Eletronicos Conecta", CategoryCode: "5732", Country: "BR"},
+	{Name: "Corner Coffee Shop", CategoryCode: "5814", Country: "US"},
+	{Name: "City Transit Rides", CategoryCode: "4121", Country: "US"},
+	{Name: "Mercado Central Lisboa", CategoryCode: "5411", Country: "PT"},
+}
+
+// Options sets what a Generator generates. The same options always generate the same accounts and transactions.
+type Options struct {
+	Seed                   uint64
+	Accounts               int
+	TransactionsPerAccount int
+	CNPJShare              float64     // Share of the accounts identified by a CNPJ, the others by a CPF
+	Mix                    map[int]int // Relative weight of each operation type
+	From                   time.Time   // Event dates are in the [From, To) interval
+	To                     time.Time
+	Distribution           string // DistributionUniform or DistributionRecent
+	Currency               string // ISO 4217 code of the accounts and transactions currency
+}
+
+// ParseMix parses an operation type mix, comma separated OPERATION_TYPE=WEIGHT pairs, e.g. DefaultMix
+func ParseMix(value string) (map[int]int, error) {
+	mix := make(map[int]int)
+	for _, pair := range strings.Split(value, ",") {
+		operation, weight, found := strings.Cut(strings.TrimSpace(pair), "=")
+		operationTypeID, operationErr := strconv.Atoi(strings.TrimSpace(operation))
+		weightValue, weightErr := strconv.Atoi(strings.TrimSpace(weight))
+		if !found || operationErr != nil || weightErr != nil {
+			return nil, fmt.Errorf("%w: invalid mix entry %q, expected OPERATION_TYPE=WEIGHT", coreerr.InvalidParametersError, pair)
+		}
+		mix[operationTypeID] = weightValue
+	}
+	return mix, nil
+}
+
+// Validate checks the options
+func (o Options) Validate() error {
+	if o.Accounts <= 0 || o.TransactionsPerAccount < 0 {
+		return fmt.Errorf("%w: accounts must be greater than zero and transactions per account must not be negative", coreerr.InvalidParametersError)
+	}
+	if o.CNPJShare < 0 || o.CNPJShare > 1 {
+		return fmt.Errorf("%w: the CNPJ share must be between 0 and 1", coreerr.InvalidParametersError)
+	}
+	total := 0
+	for operationTypeID, weight := range o.Mix {
+		if _, ok := amountRanges[operationTypeID]; !ok || weight < 0 {
+			return fmt.Errorf("%w: invalid mix weight %d for operation type %d", coreerr.InvalidParametersError, weight, operationTypeID)
+		}
+		total += weight
+	}
+	if total == 0 {
+		return fmt.Errorf("%w: the mix must have a positive weight", coreerr.InvalidParametersError)
+	}
+	if !o.From.Before(o.To) {
+		return fmt.Errorf("%w: from must be before to", coreerr.InvalidParametersError)
+	}
+	if o.Distribution != DistributionUniform && o.Distribution != DistributionRecent {
+		return fmt.Errorf("%w: unknown distribution %q", coreerr.InvalidParametersError, o.Distribution)
+	}
+	if len(o.Currency) != 3 {
+		return fmt.Errorf("%w: invalid currency %q", coreerr.InvalidParametersError, o.Currency)
+	}
+	return nil
+}
+
+// Generator generates accounts with valid CPF or CNPJ document numbers and their posted transactions, drawing
+// every value from a random source seeded by Options.Seed
+type Generator struct {
+	options    Options
+	random     *rand.Rand
+	operations []int // Operation types of the mix, sorted
+	weights    []int // Cumulative weights of operations
+	documents  map[string]bool
+	generated  int // Accounts generated so far
+}
+
+func NewGenerator(options Options) (*Generator, error) {
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+	generator := &Generator{
+		options:   options,
+		random:    rand.New(rand.NewPCG(options.Seed, options.Seed^0x9e3779b97f4a7c15)),
+		documents: make(map[string]bool, options.Accounts),
+	}
+	for operationTypeID, weight := range options.Mix {
+		if weight > 0 {
+			generator.operations = append(generator.operations, operationTypeID)
+		}
+	}
+	sort.Ints(generator.operations)
+	total := 0
+	for _, operationTypeID := range generator.operations {
+		total += options.Mix[operationTypeID]
+		generator.weights = append(generator.weights, total)
+	}
+	return generator, nil
+}
+
+// Next returns the next account, not saved yet, and its transactions ordered by event date. ok is false once
+// every account was generated.
+func (g *Generator) Next() (newAccount *account.Account, newTransactions []*transaction.Transaction, ok bool) {
+	if g.generated >= g.options.Accounts {
+		return nil, nil, false
+	}
+	g.generated++
+	newAccount = g.account()
+	newTransactions = make([]*transaction.Transaction, 0, g.options.TransactionsPerAccount)
+	for range g.options.TransactionsPerAccount {
+		newTransactions = append(newTransactions, g.transaction())
+	}
+	sort.SliceStable(newTransactions, func(i, j int) bool {
+		return newTransactions[i].EventDate.Before(newTransactions[j].EventDate)
+	})
+	return newAccount, newTransactions, true
+}
+
+// account returns an account with a document number not generated before
+func (g *Generator) account() *account.Account {
+	documentType := account.DocumentTypeCPF
+	if g.random.Float64() < g.options.CNPJShare {
+		documentType = account.DocumentTypeCNPJ
+	}
+	for {
+		documentNumber := CPF(g.random)
+		if documentType == account.DocumentTypeCNPJ {
+			documentNumber = CNPJ(g.random)
+		}
+		if !g.documents[documentNumber] {
+			g.documents[documentNumber] = true
+			return &account.Account{DocumentType: documentType, DocumentNumber: documentNumber, Currency: g.options.Currency}
+		}
+	}
+}
+
+// transaction returns a transaction posted at its event date, its amount signed as stored
+func (g *Generator) transaction() *transaction.Transaction {
+	operationTypeID := g.operationType()
+	amount := g.amount(operationTypeID)
+	eventDate := g.eventDate()
+	newTransaction := &transaction.Transaction{
+		OperationTypeID:  operationTypeID,
+		Amount:           -amount,
+		Currency:         g.options.Currency,
+		OriginalAmount:   amount,
+		OriginalCurrency: g.options.Currency,
+		ExchangeRate:     1,
+		EventDate:        eventDate,
+		Status:           transaction.StatusPosted,
+		EffectiveDate:    eventDate,
+		PostedAt:         eventDate,
+		CreatedAt:        eventDate,
+	}
+	switch operationTypeID {
+	case transaction.Payment:
+		newTransaction.Amount = amount
+		newTransaction.Description = "Invoice payment"
+	case transaction.Withdrawal:
+		newTransaction.Description = "ATM withdrawal"
+	case transaction.Purchase, transaction.InstallmentPurchase:
+		newTransaction.Merchant = merchants[g.random.IntN(len(merchants))]
+		if operationTypeID == transaction.InstallmentPurchase {
+			newTransaction.Metadata = map[string]string{"installments": strconv.Itoa(2 + g.random.IntN(11))}
+		}
+	}
+	return newTransaction
+}
+
+func (g *Generator) operationType() int {
+	pick := g.random.IntN(g.weights[len(g.weights)-1])
+	return g.operations[sort.SearchInts(g.weights, pick+1)]
+}
+
+// amount returns a positive amount of the operation type, rounded to cents
+func (g *Generator) amount(operationTypeID int) float64 {
+	bounds := amountRanges[operationTypeID]
+	steps := (bounds.max - bounds.min) / bounds.step
+	cents := bounds.min + g.random.Int64N(steps+1)*bounds.step
+	return float64(cents) / 100
+}
+
+// eventDate returns a date between From and To, truncated to the second, following the distribution
+func (g *Generator) eventDate() time.Time {
+	span := g.options.To.Sub(g.options.From)
+	var offset time.Duration
+	switch g.options.Distribution {
+	case DistributionRecent:
+		back := math.Min(g.random.ExpFloat64()/recentRate, 1)
+		offset = span - time.Duration(back*float64(span))
+	default:
+		offset = time.Duration(g.random.Int64N(int64(span)))
+	}
+	eventDate := g.options.From.Add(offset).Truncate(time.Second)
+	if eventDate.Before(g.options.From) {
+		eventDate = g.options.From
+	}
+	if !eventDate.Before(g.options.To) {
+		eventDate = g.options.To.Add(-time.Second).Truncate(time.Second)
+	}
+	return eventDate.UTC()
+}
@@ -0,0 +1,186 @@
+package seed
+
+import (
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/account"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/transaction"
+	"github.com/paemuri/brdoc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math"
+	"math/rand/v2"
+	"testing"
+	"time"
+)
+
+var (
+	testFrom = time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	testTo   = time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func testOptions() Options {
+	mix, _ := ParseMix(DefaultMix)
+	return Options{
+		Seed:                   42,
+		Accounts:               20,
+		TransactionsPerAccount: 50,
+		CNPJShare:              0.3,
+		Mix:                    mix,
+		From:                   testFrom,
+		To:                     testTo,
+		Distribution:           DistributionUniform,
+		Currency:               "BRL",
+	}
+}
+
+func generateAll(t *testing.T, options Options) ([]*account.Account, []*transaction.Transaction) {
+	t.Helper()
+	generator, err := NewGenerator(options)
+	require.NoError(t, err)
+	var accounts []*account.Account
+	var transactions []*transaction.Transaction
+	for {
+		newAccount, newTransactions, ok := generator.Next()
+		if !ok {
+			return accounts, transactions
+		}
+		accounts = append(accounts, newAccount)
+		transactions = append(transactions, newTransactions...)
+	}
+}
+
+func TestDocuments(t *testing.T) {
+	random := rand.New(rand.NewPCG(1, 2))
+	for range 1000 {
+		cpf := CPF(random)
+		assert.Len(t, cpf, 11)
+		assert.True(t, brdoc.IsCPF(cpf), "%s should be a valid CPF", cpf)
+		cnpj := CNPJ(random)
+		assert.Len(t, cnpj, 14)
+		assert.True(t, brdoc.IsCNPJ(cnpj), "%s should be a valid CNPJ", cnpj)
+	}
+}
+
+func TestGeneratorIsDeterministic(t *testing.T) {
+	firstAccounts, firstTransactions := generateAll(t, testOptions())
+	secondAccounts, secondTransactions := generateAll(t, testOptions())
+	assert.Equal(t, firstAccounts, secondAccounts)
+	assert.Equal(t, firstTransactions, secondTransactions)
+
+	options := testOptions()
+	options.Seed = 43
+	otherAccounts, _ := generateAll(t, options)
+	assert.NotEqual(t, firstAccounts, otherAccounts, "another seed should generate other accounts")
+}
+
+func TestGeneratorAccounts(t *testing.T) {
+	accounts, transactions := generateAll(t, testOptions())
+	require.Len(t, accounts, 20)
+	assert.Len(t, transactions, 20*50)
+	documents := make(map[string]bool)
+	cnpjs := 0
+	for _, generated := range accounts {
+		documentType, documentNumber, err := account.NormalizeDocument(generated.DocumentType, generated.DocumentNumber)
+		assert.NoError(t, err)
+		assert.Equal(t, generated.DocumentType, documentType)
+		assert.Equal(t, generated.DocumentNumber, documentNumber, "document numbers should be normalized")
+		assert.False(t, documents[generated.DocumentNumber], "document numbers should be unique")
+		documents[generated.DocumentNumber] = true
+		assert.Equal(t, "BRL", generated.Currency)
+		if generated.DocumentType == account.DocumentTypeCNPJ {
+			cnpjs++
+		}
+	}
+	assert.Positive(t, cnpjs)
+	assert.Less(t, cnpjs, len(accounts))
+}
+
+func TestGeneratorTransactions(t *testing.T) {
+	options := testOptions()
+	options.Accounts = 40
+	options.TransactionsPerAccount = 250
+	_, transactions := generateAll(t, options)
+	operations := make(map[int]int)
+	for _, generated := range transactions {
+		operations[generated.OperationTypeID]++
+		assert.Equal(t, transaction.StatusPosted, generated.Status)
+		assert.False(t, generated.EventDate.Before(testFrom))
+		assert.True(t, generated.EventDate.Before(testTo))
+		assert.Equal(t, generated.EventDate, generated.PostedAt)
+		assert.Equal(t, generated.EventDate, generated.CreatedAt)
+		assert.Equal(t, math.Round(generated.OriginalAmount*100)/100, generated.OriginalAmount, "amounts should be rounded to cents")
+		if generated.OperationTypeID == transaction.Payment {
+			assert.Positive(t, generated.Amount, "payments should be credits")
+		} else {
+			assert.Negative(t, generated.Amount, "purchases and withdrawals should be debts")
+		}
+		if generated.OperationTypeID == transaction.Purchase {
+			assert.NoError(t, transaction.ValidateMerchant(generated.Merchant))
+		}
+	}
+	total := float64(len(transactions))
+	assert.InDelta(t, 0.6, float64(operations[transaction.Purchase])/total, 0.03)
+	assert.InDelta(t, 0.1, float64(operations[transaction.InstallmentPurchase])/total, 0.03)
+	assert.InDelta(t, 0.1, float64(operations[transaction.Withdrawal])/total, 0.03)
+	assert.InDelta(t, 0.2, float64(operations[transaction.Payment])/total, 0.03)
+}
+
+func TestGeneratorTransactionsOrderedByEventDate(t *testing.T) {
+	generator, err := NewGenerator(testOptions())
+	require.NoError(t, err)
+	_, transactions, ok := generator.Next()
+	require.True(t, ok)
+	for i := 1; i < len(transactions); i++ {
+		assert.False(t, transactions[i].EventDate.Before(transactions[i-1].EventDate))
+	}
+}
+
+func TestGeneratorRecentDistribution(t *testing.T) {
+	options := testOptions()
+	options.Distribution = DistributionRecent
+	_, transactions := generateAll(t, options)
+	lastThird := testTo.Add(-testTo.Sub(testFrom) / 3)
+	recent := 0
+	for _, generated := range transactions {
+		assert.False(t, generated.EventDate.Before(testFrom))
+		assert.True(t, generated.EventDate.Before(testTo))
+		if !generated.EventDate.Before(lastThird) {
+			recent++
+		}
+	}
+	assert.Greater(t, float64(recent)/float64(len(transactions)), 0.9, "most transactions should be recent")
+}
+
+func TestParseMix(t *testing.T) {
+	mix, err := ParseMix(" 1=70, 4=30 ")
+	require.NoError(t, err)
+	assert.Equal(t, map[int]int{1: 70, 4: 30}, mix)
+	for _, invalid := range []string{"", "1", "1=a", "purchase=10"} {
+		_, err = ParseMix(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(*Options)
+	}{
+		{name: "no accounts", change: func(o *Options) { o.Accounts = 0 }},
+		{name: "negative transactions", change: func(o *Options) { o.TransactionsPerAccount = -1 }},
+		{name: "cnpj share", change: func(o *Options) { o.CNPJShare = 1.5 }},
+		{name: "unknown operation type", change: func(o *Options) { o.Mix = map[int]int{9: 1} }},
+		{name: "zero weights", change: func(o *Options) { o.Mix = map[int]int{1: 0} }},
+		{name: "empty interval", change: func(o *Options) { o.To = o.From }},
+		{name: "distribution", change: func(o *Options) { o.Distribution = "normal" }},
+		{name: "currency", change: func(o *Options) { o.Currency = "" }},
+	}
+	assert.NoError(t, testOptions().Validate())
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			options := testOptions()
+			test.change(&options)
+			_, err := NewGenerator(options)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package seed
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/contextkeys"
+	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxReplayLineSize is the longest request log line read, bodies included
+const maxReplayLineSize = 1024 * 1024
+
+// ReplayRequest is a line of a request log, a JSON object per line. RequestID, when informed, is sent as the
+// x-trace-id header so the API logs of the replayed requests can be found.
+type ReplayRequest struct {
+	RequestID string            `json:"request_id,omitempty"`
+	Method    string            `json:"method"`
+	Path      string            `json:"path"` // Path and query of the request, e.g. /accounts/1
+	Headers   map[string]string `json:"headers,omitempty"`
+	Body      json.RawMessage   `json:"body,omitempty"`
+}
+
+// ReplayResult is the outcome of a replayed request. Status is zero when the request could not be sent.
+type ReplayResult struct {
+	Line      int           `json:"line"`
+	RequestID string        `json:"request_id,omitempty"`
+	Method    string        `json:"method"`
+	Path      string        `json:"path"`
+	Status    int           `json:"status"`
+	Duration  time.Duration `json:"duration_ns"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// Failed checks if the request was not sent or the API answered it with an error status
+func (r ReplayResult) Failed() bool {
+	return r.Status == 0 || r.Status >= http.StatusBadRequest
+}
+
+// ReplaySummary counts the replayed requests
+type ReplaySummary struct {
+	Requests int
+	Failed   int
+	Statuses map[int]int // Responses by status code, zero for requests not sent
+}
+
+// String returns the summary and the responses by status code, e.g. "3 requests, 1 failed (201: 2, 422: 1)"
+func (s ReplaySummary) String() string {
+	statuses := make([]int, 0, len(s.Statuses))
+	for status := range s.Statuses {
+		statuses = append(statuses, status)
+	}
+	sort.Ints(statuses)
+	counts := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		counts = append(counts, fmt.Sprintf("%d: %d", status, s.Statuses[status]))
+	}
+	return fmt.Sprintf("%d requests, %d failed (%s)", s.Requests, s.Failed, strings.Join(counts, ", "))
+}
+
+// Replayer sends the requests of a request log to a running API, one at a time in the order they were logged
+type Replayer struct {
+	client  *http.Client
+	baseURL string
+	headers map[string]string // Headers sent with every request, e.g. Authorization, overridden by the logged ones
+}
+
+func NewReplayer(client *http.Client, baseURL string, headers map[string]string) *Replayer {
+	return &Replayer{client: client, baseURL: strings.TrimRight(baseURL, "/"), headers: headers}
+}
+
+// Replay sends every request of the log, skipping blank lines, and calls report with the result of each one.
+// Invalid lines and failed requests are reported and counted, replay only stops when reading the log or report fails.
+func (r *Replayer) Replay(ctx context.Context, requestLog io.Reader, report func(ReplayResult) error) (ReplaySummary, error) {
+	summary := ReplaySummary{Statuses: make(map[int]int)}
+	scanner := bufio.NewScanner(requestLog)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxReplayLineSize)
+	line := 0
+	for scanner.Scan() {
+		line++
+		content := bytes.TrimSpace(scanner.Bytes())
+		if len(content) == 0 {
+			continue
+		}
+		result := r.send(ctx, line, content)
+		summary.Requests++
+		summary.Statuses[result.Status]++
+		if result.Failed() {
+			summary.Failed++
+		}
+		if err := report(result); err != nil {
+			return summary, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return summary, fmt.Errorf("failed to read the request log after line %d: %w", line, err)
+	}
+	return summary, nil
+}
+
+func (r *Replayer) send(ctx context.Context, line int, content []byte) ReplayResult {
+	var logged ReplayRequest
+	if err := json.Unmarshal(content, &logged); err != nil || logged.Path == "" {
+		return ReplayResult{Line: line, Error: coreerr.InvalidParametersError.Error()}
+	}
+	result := ReplayResult{Line: line, RequestID: logged.RequestID, Method: strings.ToUpper(logged.Method), Path: logged.Path}
+	if result.Method == "" {
+		result.Method = http.MethodGet
+		if len(logged.Body) > 0 {
+			result.Method = http.MethodPost
+		}
+	}
+	var body io.Reader
+	if len(logged.Body) > 0 {
+		body = bytes.NewReader(logged.Body)
+	}
+	request, err := http.NewRequestWithContext(ctx, result.Method, r.baseURL+"/"+strings.TrimLeft(logged.Path, "/"), body)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	for name, value := range r.headers {
+		request.Header.Set(name, value)
+	}
+	for name, value := range logged.Headers {
+		request.Header.Set(name, value)
+	}
+	if logged.RequestID != "" {
+		request.Header.Set(contextkeys.TraceIDKey, logged.RequestID)
+	}
+	start := time.Now()
+	response, err := r.client.Do(request)
+	result.Duration = time.Since(start)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, response.Body)
+	result.Status = response.StatusCode
+	return result
+}
@@ -0,0 +1,118 @@
+package seed
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kiosanim/pismo-code-assessment/internal/core/contextkeys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// receivedRequest is a request seen by the test API
+type receivedRequest struct {
+	method        string
+	path          string
+	body          string
+	traceID       string
+	authorization string
+	contentType   string
+}
+
+func newTestAPI(t *testing.T) (*httptest.Server, *[]receivedRequest) {
+	t.Helper()
+	var mu sync.Mutex
+	var received []receivedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		received = append(received, receivedRequest{
+			method:        r.Method,
+			path:          r.URL.RequestURI(),
+			body:          string(body),
+			traceID:       r.Header.Get(contextkeys.TraceIDKey),
+			authorization: r.Header.Get("Authorization"),
+			contentType:   r.Header.Get("Content-Type"),
+		})
+		mu.Unlock()
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/accounts":
+			w.WriteHeader(http.StatusCreated)
+		case r.URL.Path == "/accounts/404":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &received
+}
+
+const testRequestLog = `{"request_id": "req-1", "method": "POST", "path": "/accounts", "body": {"document_number": "12345678909"}}
+
+{"path": "/accounts/1?fields=currency", "headers": {"Authorization": "Bearer other"}}
+not json
+{"method": "get", "path": "accounts/404"}
+`
+
+func TestReplayerReplay(t *testing.T) {
+	server, received := newTestAPI(t)
+	replayer := NewReplayer(server.Client(), server.URL+"/", map[string]string{"Authorization": "Bearer token"})
+	var results []ReplayResult
+	summary, err := replayer.Replay(context.Background(), strings.NewReader(testRequestLog), func(result ReplayResult) error {
+		results = append(results, result)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ReplaySummary{Requests: 4, Failed: 2, Statuses: map[int]int{0: 1, 200: 1, 201: 1, 404: 1}}, summary)
+	assert.Equal(t, "4 requests, 2 failed (0: 1, 200: 1, 201: 1, 404: 1)", summary.String())
+
+	require.Len(t, results, 4)
+	assert.Equal(t, []int{1, 3, 4, 5}, []int{results[0].Line, results[1].Line, results[2].Line, results[3].Line}, "blank lines should be skipped")
+	assert.Equal(t, http.StatusCreated, results[0].Status)
+	assert.Equal(t, "req-1", results[0].RequestID)
+	assert.False(t, results[0].Failed())
+	assert.NotEmpty(t, results[2].Error, "invalid lines should be reported")
+	assert.True(t, results[2].Failed())
+	assert.Equal(t, http.StatusNotFound, results[3].Status)
+	assert.True(t, results[3].Failed())
+
+	require.Len(t, *received, 3, "invalid lines should not be sent")
+	assert.Equal(t, receivedRequest{method: http.MethodPost, path: "/accounts", body: `{"document_number": "12345678909"}`,
+		traceID: "req-1", authorization: "Bearer token", contentType: "application/json"}, (*received)[0])
+	assert.Equal(t, receivedRequest{method: http.MethodGet, path: "/accounts/1?fields=currency", authorization: "Bearer other"}, (*received)[1],
+		"logged headers should override the replayer ones")
+	assert.Equal(t, http.MethodGet, (*received)[2].method)
+	assert.Equal(t, "/accounts/404", (*received)[2].path)
+}
+
+func TestReplayerReplayStopsWhenReportFails(t *testing.T) {
+	server, received := newTestAPI(t)
+	replayer := NewReplayer(server.Client(), server.URL, nil)
+	reportErr := errors.New("disk full")
+	summary, err := replayer.Replay(context.Background(), strings.NewReader(testRequestLog), func(result ReplayResult) error {
+		return reportErr
+	})
+	assert.ErrorIs(t, err, reportErr)
+	assert.Equal(t, 1, summary.Requests)
+	assert.Len(t, *received, 1)
+}
+
+func TestReplayerReplayUnreachableAPI(t *testing.T) {
+	server, _ := newTestAPI(t)
+	server.Close()
+	replayer := NewReplayer(server.Client(), server.URL, nil)
+	summary, err := replayer.Replay(context.Background(), strings.NewReader(`{"path": "/accounts/1"}`), func(result ReplayResult) error {
+		assert.Zero(t, result.Status)
+		assert.NotEmpty(t, result.Error)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary.Failed)
+}
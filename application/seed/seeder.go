@@ -0,0 +1,115 @@
+package seed
+
+import (
+	"context"
+	"fmt"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/config"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/contextutils"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/account"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/ledger"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/transaction"
+)
+
+// TransactionWriter stores posted transactions along with their ledger postings
+type TransactionWriter interface {
+	WriteTransactions(ctx context.Context, newTransactions []*transaction.Transaction) error
+}
+
+// RepositoryTransactionWriter writes transactions with TransactionRepository.SaveBatch, so their creation is
+// recorded in the audit log like the transactions created by the API
+type RepositoryTransactionWriter struct {
+	repository transaction.TransactionRepository
+}
+
+func NewRepositoryTransactionWriter(repository transaction.TransactionRepository) *RepositoryTransactionWriter {
+	return &RepositoryTransactionWriter{repository: repository}
+}
+
+func (r *RepositoryTransactionWriter) WriteTransactions(ctx context.Context, newTransactions []*transaction.Transaction) error {
+	_, err := r.repository.SaveBatch(ctx, newTransactions)
+	return err
+}
+
+// Summary counts what a Seeder stored
+type Summary struct {
+	Accounts     int
+	Transactions int
+}
+
+// Seeder saves the accounts of a Generator and writes their transactions, batchSize at a time
+type Seeder struct {
+	generator     *Generator
+	accounts      account.AccountRepository
+	writer        TransactionWriter
+	fees          map[int]float64 // Ledger fee by operation type
+	batchSize     int
+	componentName string
+	log           logger.Logger
+}
+
+func NewSeeder(generator *Generator, accounts account.AccountRepository, writer TransactionWriter, ledgerConfig config.LedgerConfig,
+	batchSize int, log logger.Logger) *Seeder {
+	seeder := &Seeder{
+		generator:     generator,
+		accounts:      accounts,
+		writer:        writer,
+		fees:          make(map[int]float64),
+		batchSize:     max(batchSize, 1),
+		componentName: "Seeder",
+		log:           log,
+	}
+	for _, fee := range ledgerConfig.Fees {
+		seeder.fees[fee.OperationTypeID] = fee.Amount
+	}
+	return seeder
+}
+
+// Run stores every generated account and transaction. Accounts are saved one by one, so a document number already
+// in the database stops the run with the accounts saved so far; seed an empty database or use another seed.
+func (s *Seeder) Run(ctx context.Context) (Summary, error) {
+	traceID := contextutils.GetTraceID(ctx)
+	var summary Summary
+	batch := make([]*transaction.Transaction, 0, s.batchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := s.writer.WriteTransactions(ctx, batch); err != nil {
+			return fmt.Errorf("failed to write %d transactions: %w", len(batch), err)
+		}
+		summary.Transactions += len(batch)
+		s.log.Debug(s.componentName+".Run", "accounts", summary.Accounts, "transactions", summary.Transactions, "x_trace_id", traceID)
+		batch = batch[:0]
+		return nil
+	}
+	for {
+		newAccount, newTransactions, ok := s.generator.Next()
+		if !ok {
+			break
+		}
+		savedAccount, err := s.accounts.Save(ctx, newAccount)
+		if err != nil {
+			return summary, fmt.Errorf("failed to save account %d: %w", summary.Accounts+1, err)
+		}
+		summary.Accounts++
+		for _, newTransaction := range newTransactions {
+			newTransaction.AccountID = savedAccount.AccountID
+			newTransaction.Postings, err = ledger.Post(savedAccount.AccountID, newTransaction.Amount, s.fees[newTransaction.OperationTypeID], newTransaction.Currency)
+			if err != nil {
+				return summary, err
+			}
+			batch = append(batch, newTransaction)
+			if len(batch) == s.batchSize {
+				if err = flush(); err != nil {
+					return summary, err
+				}
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return summary, err
+	}
+	s.log.Info(s.componentName+".Run", "accounts", summary.Accounts, "transactions", summary.Transactions, "x_trace_id", traceID)
+	return summary, nil
+}
@@ -0,0 +1,115 @@
+package seed
+
+import (
+	"context"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/config"
+	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/account"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/ledger"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/transaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+// recordingWriter keeps the batches it is asked to write
+type recordingWriter struct {
+	batches [][]*transaction.Transaction
+	err     error
+}
+
+func (r *recordingWriter) WriteTransactions(ctx context.Context, newTransactions []*transaction.Transaction) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.batches = append(r.batches, append([]*transaction.Transaction(nil), newTransactions...))
+	return nil
+}
+
+func newTestSeeder(t *testing.T, options Options, accounts account.AccountRepository, writer TransactionWriter) *Seeder {
+	t.Helper()
+	generator, err := NewGenerator(options)
+	require.NoError(t, err)
+	log := logger.NewLoggerMock(gomock.NewController(t))
+	log.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	log.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+	ledgerConfig := config.LedgerConfig{Fees: []config.LedgerFeeConfig{{OperationTypeID: transaction.Withdrawal, Amount: 2.5}}}
+	return NewSeeder(generator, accounts, writer, ledgerConfig, 7, log)
+}
+
+// memoryAccounts saves accounts in memory, assigning sequential IDs
+type memoryAccounts struct {
+	account.AccountRepository
+	saved []*account.Account
+}
+
+func (m *memoryAccounts) Save(ctx context.Context, newAccount *account.Account) (*account.Account, error) {
+	saved := *newAccount
+	saved.AccountID = int64(len(m.saved) + 1)
+	m.saved = append(m.saved, &saved)
+	return &saved, nil
+}
+
+func TestSeederRun(t *testing.T) {
+	options := testOptions()
+	options.Accounts = 3
+	options.TransactionsPerAccount = 5
+	writer := &recordingWriter{}
+	accounts := &memoryAccounts{}
+	summary, err := newTestSeeder(t, options, accounts, writer).Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, Summary{Accounts: 3, Transactions: 15}, summary)
+	assert.Len(t, accounts.saved, 3)
+
+	require.Len(t, writer.batches, 3, "15 transactions should be written in batches of 7")
+	assert.Len(t, writer.batches[0], 7)
+	assert.Len(t, writer.batches[2], 1)
+	perAccount := make(map[int64]int)
+	for _, batch := range writer.batches {
+		for _, written := range batch {
+			perAccount[written.AccountID]++
+			assert.True(t, ledger.Balanced(written.Postings), "transactions should be posted to the ledger")
+			assert.Contains(t, []string{written.Postings[0].LedgerAccount, written.Postings[1].LedgerAccount}, ledger.CustomerAccount(written.AccountID),
+				"postings should belong to the saved account")
+			if written.OperationTypeID == transaction.Withdrawal {
+				assert.Len(t, written.Postings, 4, "withdrawals should be charged the configured fee")
+			} else {
+				assert.Len(t, written.Postings, 2)
+			}
+		}
+	}
+	assert.Equal(t, map[int64]int{1: 5, 2: 5, 3: 5}, perAccount)
+}
+
+func TestSeederRunStopsOnAccountError(t *testing.T) {
+	accounts := account.NewAccountRepositoryMock()
+	accounts.On("Save", mock.Anything, mock.Anything).Return(nil, coreerr.DatabaseInsertionError)
+	writer := &recordingWriter{}
+	summary, err := newTestSeeder(t, testOptions(), accounts, writer).Run(context.Background())
+	assert.ErrorIs(t, err, coreerr.DatabaseInsertionError)
+	assert.Zero(t, summary.Accounts)
+	assert.Empty(t, writer.batches)
+}
+
+func TestSeederRunStopsOnWriteError(t *testing.T) {
+	writer := &recordingWriter{err: coreerr.DatabaseInsertionError}
+	summary, err := newTestSeeder(t, testOptions(), &memoryAccounts{}, writer).Run(context.Background())
+	assert.ErrorIs(t, err, coreerr.DatabaseInsertionError)
+	assert.Equal(t, 1, summary.Accounts, "the seeder should stop at the first failed batch")
+	assert.Zero(t, summary.Transactions)
+}
+
+func TestRepositoryTransactionWriter(t *testing.T) {
+	ctx := context.Background()
+	batch := []*transaction.Transaction{{AccountID: 1}, {AccountID: 2}}
+	repository := transaction.NewTransactionRepositoryMock()
+	repository.On("SaveBatch", ctx, batch).Return(batch, nil).Once()
+	repository.On("SaveBatch", ctx, batch[:1]).Return(nil, coreerr.DatabaseInsertionError).Once()
+	writer := NewRepositoryTransactionWriter(repository)
+	assert.NoError(t, writer.WriteTransactions(ctx, batch))
+	assert.ErrorIs(t, writer.WriteTransactions(ctx, batch[:1]), coreerr.DatabaseInsertionError)
+	repository.AssertExpectations(t)
+}
@@ -1,28 +1,70 @@
 package dto
 
+import "time"
+
 type TransactionDTO struct {
-	TransactionID   int64   `json:"transaction_id"`
-	AccountID       int64   `json:"account_id"`
-	OperationTypeID int     `json:"operation_type_id"`
-	Amount          float64 `json:"amount"`
+	TransactionID    int64             `json:"transaction_id"`
+	AccountID        int64             `json:"account_id"`
+	OperationTypeID  int               `json:"operation_type_id"`
+	Amount           float64           `json:"amount"`            // Amount in the account currency
+	Currency         string            `json:"currency"`          // Account currency
+	OriginalAmount   float64           `json:"original_amount"`   // Amount informed on creation
+	OriginalCurrency string            `json:"original_currency"` // Currency informed on creation
+	ExchangeRate     float64           `json:"exchange_rate"`     // Units of currency worth one unit of original_currency
+	MerchantName     string            `json:"merchant_name,omitempty"`
+	MCC              string            `json:"mcc,omitempty"`
+	MerchantCountry  string            `json:"merchant_country,omitempty"`
+	Description      string            `json:"description,omitempty"`
+	Metadata         map[string]string `json:"metadata,omitempty"`
+	EventDate        time.Time         `json:"event_date"`     // Date the transaction happened, in UTC
+	LocalDate        string            `json:"local_date"`     // Day of event_date in the requested time zone, UTC by default
+	Status           string            `json:"status"`         // PENDING, POSTED or CANCELLED
+	EffectiveDate    time.Time         `json:"effective_date"` // Date the transaction is, or is scheduled to be, posted
 }
 type CreateTransactionRequest struct {
-	AccountID       int64   `json:"account_id"`
-	OperationTypeID int     `json:"operation_type_id"`
-	Amount          float64 `json:"amount"`
+	AccountID       int64             `json:"account_id"`
+	OperationTypeID int               `json:"operation_type_id"`
+	Amount          float64           `json:"amount"`
+	Currency        string            `json:"currency,omitempty"`         // ISO 4217 code, the account currency when empty
+	MerchantName    string            `json:"merchant_name,omitempty"`    // Up to 120 characters
+	MCC             string            `json:"mcc,omitempty"`              // 4 digits ISO 18245 merchant category code
+	MerchantCountry string            `json:"merchant_country,omitempty"` // ISO 3166-1 alpha-2 code
+	Description     string            `json:"description,omitempty"`      // Up to 255 characters
+	Metadata        map[string]string `json:"metadata,omitempty"`         // Up to 20 entries, keys with letters, digits, '_', '.' or '-'
+	EffectiveDate   *time.Time        `json:"effective_date,omitempty"`   // RFC 3339, a future date schedules the transaction as PENDING
+	EventDate       *time.Time        `json:"event_date,omitempty"`       // RFC 3339, backdates the transaction within the configured window
 }
 
 type CreateTransactionResponse struct {
 	Transaction TransactionDTO `json:"transaction"`
 }
 
+const (
+	DefaultListLimit = 50
+	MaxListLimit     = 500
+)
+
+// ListTransactionsRequest searches transactions. Empty criteria are ignored, MerchantName and Description match
+// case-insensitive substrings and every Metadata entry must be present in the transaction metadata.
 type ListTransactionsRequest struct {
-	Page int64 `json:"page" validate:"min=0"`
-	Size int64 `json:"size" validate:"min=1"`
+	AccountID       int64
+	Status          string
+	MerchantName    string
+	MCC             string
+	MerchantCountry string
+	Description     string
+	Metadata        map[string]string
+	From            time.Time
+	To              time.Time
+	Location        *time.Location // Time zone of local_date, UTC when nil
+	Cursor          int64          // Last transaction ID of the previous page
+	Limit           int64
 }
 
 type ListTransactionsResponse struct {
 	Transactions []TransactionDTO `json:"transactions"`
+	Limit        int64            `json:"limit"`
+	Cursor       int64            `json:"cursor"` // Cursor of the next page, zero on the last page
 }
 
 type FindTransactionByIdRequest struct {
@@ -30,5 +72,87 @@ type FindTransactionByIdRequest struct {
 }
 
 type FindTransactionByIdResponse struct {
+	Transaction       TransactionDTO        `json:"transaction"`
+	StatusTransitions []StatusTransitionDTO `json:"status_transitions"`
+}
+
+type StatusTransitionDTO struct {
+	Status string    `json:"status"`
+	At     time.Time `json:"at"`
+}
+
+type CancelTransactionRequest struct {
+	TransactionID int64 `uri:"transaction_id" binding:"required,gt=0"`
+}
+
+type CancelTransactionResponse struct {
 	Transaction TransactionDTO `json:"transaction"`
 }
+
+type ReverseTransactionRequest struct {
+	TransactionID int64 `uri:"transaction_id" binding:"required,gt=0"`
+}
+
+// ReverseTransactionResponse carries the reversal, a new transaction
+type ReverseTransactionResponse struct {
+	Transaction TransactionDTO `json:"transaction"`
+}
+
+const (
+	BatchRowCreated = "created"
+	BatchRowFailed  = "failed"
+)
+
+// BatchTransactionRow is a row of a bulk import. Rows that could not be parsed carry the parse Error.
+type BatchTransactionRow struct {
+	Row     int
+	Request CreateTransactionRequest
+	Error   error
+}
+
+type BatchTransactionResult struct {
+	Row         int             `json:"row"`
+	Status      string          `json:"status"`
+	Transaction *TransactionDTO `json:"transaction,omitempty"`
+	Error       string          `json:"error,omitempty"`
+}
+
+type CreateTransactionBatchResponse struct {
+	Total   int                      `json:"total"`
+	Created int                      `json:"created"`
+	Failed  int                      `json:"failed"`
+	Results []BatchTransactionResult `json:"results"`
+}
+
+const (
+	DirectionCredit = "credit"
+	DirectionDebit  = "debit"
+)
+
+// ExportTransactionsRequest selects the transactions of an account, or of every account when AccountID is zero,
+// whose event date is in the [From, To) interval
+type ExportTransactionsRequest struct {
+	AccountID int64
+	From      time.Time
+	To        time.Time
+	Location  *time.Location // Time zone of local_date, UTC when nil
+}
+
+// ExportTransactionRow is a transaction with its amount presented as informed by the client and its direction
+type ExportTransactionRow struct {
+	TransactionID    int64     `json:"transaction_id"`
+	AccountID        int64     `json:"account_id"`
+	OperationTypeID  int       `json:"operation_type_id"`
+	Direction        string    `json:"direction"`
+	Amount           float64   `json:"amount"`
+	EventDate        time.Time `json:"event_date"`
+	Currency         string    `json:"currency"`
+	OriginalAmount   float64   `json:"original_amount"`
+	OriginalCurrency string    `json:"original_currency"`
+	ExchangeRate     float64   `json:"exchange_rate"`
+	MerchantName     string    `json:"merchant_name,omitempty"`
+	MCC              string    `json:"mcc,omitempty"`
+	MerchantCountry  string    `json:"merchant_country,omitempty"`
+	Description      string    `json:"description,omitempty"`
+	LocalDate        string    `json:"local_date"` // Day of event_date in the requested time zone
+}
@@ -0,0 +1,183 @@
+package exporter
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/kiosanim/pismo-code-assessment/application/transaction/dto"
+	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // ?tz= zones must not depend on the zoneinfo of the host
+)
+
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+	FormatOFX    = "ofx"
+)
+
+// csvHeader is the column layout of CSV exports. New columns must only be appended.
+var csvHeader = []string{"transaction_id", "account_id", "operation_type_id", "direction", "amount", "event_date",
+	"currency", "original_amount", "original_currency", "exchange_rate",
+	"merchant_name", "merchant_category_code", "merchant_country", "description", "local_date"}
+
+var contentTypes = map[string]string{
+	FormatCSV:    "text/csv; charset=utf-8",
+	FormatNDJSON: "application/x-ndjson",
+	FormatOFX:    "application/x-ofx",
+}
+
+// Metadata describes an export. GeneratedAt is taken from the clock of the caller, it dates OFX statements without a
+// To bound. Location is the time zone of the statement dates, UTC when nil.
+type Metadata struct {
+	AccountID   int64
+	From        time.Time
+	To          time.Time
+	GeneratedAt time.Time
+	Location    *time.Location
+}
+
+// RowWriter writes exported transactions. Nothing is written to the underlying writer before the first row
+// or Close, so callers can still report errors that happen before the export starts.
+type RowWriter interface {
+	Write(row dto.ExportTransactionRow) error
+	Close() error
+}
+
+// NewWriter returns the RowWriter for a format
+func NewWriter(format string, w io.Writer, metadata Metadata) (RowWriter, error) {
+	switch format {
+	case FormatCSV:
+		return &csvWriter{writer: csv.NewWriter(w)}, nil
+	case FormatNDJSON:
+		buffered := bufio.NewWriter(w)
+		return &ndjsonWriter{buffered: buffered, encoder: json.NewEncoder(buffered)}, nil
+	case FormatOFX:
+		return &ofxWriter{writer: bufio.NewWriter(w), metadata: metadata}, nil
+	}
+	return nil, fmt.Errorf("%w: unsupported format %q", coreerr.InvalidParametersError, format)
+}
+
+func (m Metadata) location() *time.Location {
+	if m.Location == nil {
+		return time.UTC
+	}
+	return m.Location
+}
+
+// ContentType returns the media type of a format
+func ContentType(format string) string {
+	return contentTypes[format]
+}
+
+// FileName returns the name of the exported file, e.g. transactions-account-1-20260101-20260201.csv
+func FileName(format string, metadata Metadata) string {
+	parts := []string{"transactions"}
+	if metadata.AccountID > 0 {
+		parts = append(parts, "account", strconv.FormatInt(metadata.AccountID, 10))
+	}
+	if !metadata.From.IsZero() {
+		parts = append(parts, metadata.From.In(metadata.location()).Format("20060102"))
+	}
+	if !metadata.To.IsZero() {
+		parts = append(parts, metadata.To.In(metadata.location()).Format("20060102"))
+	}
+	return strings.Join(parts, "-") + "." + format
+}
+
+type csvWriter struct {
+	writer        *csv.Writer
+	headerWritten bool
+}
+
+func (c *csvWriter) Write(row dto.ExportTransactionRow) error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	return c.writer.Write([]string{
+		strconv.FormatInt(row.TransactionID, 10),
+		strconv.FormatInt(row.AccountID, 10),
+		strconv.Itoa(row.OperationTypeID),
+		row.Direction,
+		strconv.FormatFloat(row.Amount, 'f', -1, 64),
+		row.EventDate.UTC().Format(time.RFC3339),
+		row.Currency,
+		strconv.FormatFloat(row.OriginalAmount, 'f', -1, 64),
+		row.OriginalCurrency,
+		strconv.FormatFloat(row.ExchangeRate, 'f', -1, 64),
+		row.MerchantName,
+		row.MCC,
+		row.MerchantCountry,
+		row.Description,
+		row.LocalDate,
+	})
+}
+
+func (c *csvWriter) Close() error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+func (c *csvWriter) writeHeader() error {
+	if c.headerWritten {
+		return nil
+	}
+	c.headerWritten = true
+	return c.writer.Write(csvHeader)
+}
+
+type ndjsonWriter struct {
+	buffered *bufio.Writer
+	encoder  *json.Encoder
+}
+
+func (n *ndjsonWriter) Write(row dto.ExportTransactionRow) error {
+	return n.encoder.Encode(row)
+}
+
+func (n *ndjsonWriter) Close() error {
+	return n.buffered.Flush()
+}
+
+// ParseBound parses an export interval bound given as RFC 3339 or as a date (YYYY-MM-DD, in UTC).
+// Dates used as the upper bound include the whole day. Empty values return the zero time.
+func ParseBound(value string, upper bool) (time.Time, error) {
+	return ParseBoundIn(value, upper, time.UTC)
+}
+
+// ParseBoundIn parses a bound like ParseBound, taking dates as local days of location
+func ParseBoundIn(value string, upper bool, location *time.Location) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation(time.DateOnly, value, location)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: invalid date %q", coreerr.InvalidParametersError, value)
+	}
+	if upper {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// ParseLocation returns the IANA time zone named by the tz query parameter, UTC when empty
+func ParseLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil || name == "Local" {
+		return nil, fmt.Errorf("%w: invalid time zone %q", coreerr.InvalidParametersError, name)
+	}
+	return location, nil
+}
@@ -0,0 +1,166 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/kiosanim/pismo-code-assessment/application/transaction/dto"
+	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+var eventDate = time.Date(2026, 3, 10, 12, 30, 0, 0, time.UTC)
+
+var rows = []dto.ExportTransactionRow{
+	{TransactionID: 1, AccountID: 1, OperationTypeID: 1, Direction: dto.DirectionDebit, Amount: 50.5, EventDate: eventDate,
+		Currency: "BRL", OriginalAmount: 10, OriginalCurrency: "USD", ExchangeRate: 5.05, LocalDate: "2026-03-10"},
+	{TransactionID: 2, AccountID: 1, OperationTypeID: 4, Direction: dto.DirectionCredit, Amount: 100, EventDate: eventDate,
+		Currency: "BRL", OriginalAmount: 100, OriginalCurrency: "BRL", ExchangeRate: 1, LocalDate: "2026-03-10"},
+}
+
+func export(t *testing.T, format string, metadata Metadata, rows []dto.ExportTransactionRow) string {
+	var buffer bytes.Buffer
+	writer, err := NewWriter(format, &buffer, metadata)
+	require.NoError(t, err)
+	for _, row := range rows {
+		require.NoError(t, writer.Write(row))
+	}
+	require.NoError(t, writer.Close())
+	return buffer.String()
+}
+
+func TestNewWriter_NothingWrittenBeforeFirstRow(t *testing.T) {
+	for _, format := range []string{FormatCSV, FormatNDJSON, FormatOFX} {
+		var buffer bytes.Buffer
+		_, err := NewWriter(format, &buffer, Metadata{AccountID: 1})
+		require.NoError(t, err)
+		assert.Zero(t, buffer.Len(), format)
+	}
+}
+
+func TestNewWriter_UnsupportedFormat(t *testing.T) {
+	_, err := NewWriter("xlsx", &bytes.Buffer{}, Metadata{})
+	assert.ErrorIs(t, err, coreerr.InvalidParametersError)
+}
+
+func TestCSVWriter(t *testing.T) {
+	output := export(t, FormatCSV, Metadata{AccountID: 1}, rows)
+	assert.Equal(t, "transaction_id,account_id,operation_type_id,direction,amount,event_date,currency,original_amount,original_currency,exchange_rate,"+
+		"merchant_name,merchant_category_code,merchant_country,description,local_date\n"+
+		"1,1,1,debit,50.5,2026-03-10T12:30:00Z,BRL,10,USD,5.05,,,,,2026-03-10\n"+
+		"2,1,4,credit,100,2026-03-10T12:30:00Z,BRL,100,BRL,1,,,,,2026-03-10\n", output)
+}
+
+func TestCSVWriter_EmptyExportHasHeader(t *testing.T) {
+	output := export(t, FormatCSV, Metadata{AccountID: 1}, nil)
+	assert.Equal(t, "transaction_id,account_id,operation_type_id,direction,amount,event_date,currency,original_amount,original_currency,exchange_rate,merchant_name,merchant_category_code,merchant_country,description,local_date\n", output)
+}
+
+func TestNDJSONWriter(t *testing.T) {
+	output := export(t, FormatNDJSON, Metadata{AccountID: 1}, rows)
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	require.Len(t, lines, 2)
+	var row dto.ExportTransactionRow
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &row))
+	assert.Equal(t, rows[0], row)
+}
+
+func TestOFXWriter(t *testing.T) {
+	metadata := Metadata{AccountID: 1, From: eventDate.Add(-time.Hour), To: eventDate.Add(time.Hour), GeneratedAt: eventDate}
+	output := export(t, FormatOFX, metadata, rows)
+	assert.True(t, strings.HasPrefix(output, "<?xml"))
+	assert.Contains(t, output, "<CURDEF>BRL</CURDEF>")
+	assert.Contains(t, output, "<ACCTID>1</ACCTID>")
+	assert.Contains(t, output, "<DTSTART>20260310113000.000[0:GMT]</DTSTART><DTEND>20260310133000.000[0:GMT]</DTEND>")
+	assert.Contains(t, output, "<TRNTYPE>DEBIT</TRNTYPE><DTPOSTED>20260310123000.000[0:GMT]</DTPOSTED><TRNAMT>-50.50</TRNAMT><FITID>1</FITID><NAME>PURCHASE</NAME>")
+	assert.Contains(t, output, "<TRNTYPE>CREDIT</TRNTYPE><DTPOSTED>20260310123000.000[0:GMT]</DTPOSTED><TRNAMT>100.00</TRNAMT><FITID>2</FITID><NAME>PAYMENT</NAME>")
+	assert.Contains(t, output, "<BALAMT>49.50</BALAMT>")
+	assert.True(t, strings.HasSuffix(output, "</OFX>\n"))
+}
+
+func TestOFXWriter_LocalTimeZone(t *testing.T) {
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	require.NoError(t, err)
+	from := time.Date(2026, 3, 10, 0, 0, 0, 0, saoPaulo)
+	metadata := Metadata{AccountID: 1, From: from, To: from.AddDate(0, 0, 1), GeneratedAt: eventDate, Location: saoPaulo}
+	output := export(t, FormatOFX, metadata, rows[:1])
+	assert.Contains(t, output, "<DTSTART>20260310000000.000[-3]</DTSTART><DTEND>20260311000000.000[-3]</DTEND>")
+	assert.Contains(t, output, "<DTPOSTED>20260310093000.000[-3]</DTPOSTED>")
+	assert.Equal(t, "transactions-account-1-20260310-20260311.ofx", FileName(FormatOFX, metadata))
+}
+
+func TestOFXWriter_MerchantDetails(t *testing.T) {
+	row := dto.ExportTransactionRow{TransactionID: 1, AccountID: 1, OperationTypeID: 1, Direction: dto.DirectionDebit, Amount: 10, EventDate: eventDate,
+		MerchantName: "A Very Long Merchant Name & Sons Trading Company", Description: "Order <42>"}
+	output := export(t, FormatOFX, Metadata{AccountID: 1, GeneratedAt: eventDate}, []dto.ExportTransactionRow{row})
+	assert.Contains(t, output, "<NAME>A Very Long Merchant Name &amp; Sons</NAME><MEMO>Order &lt;42&gt;</MEMO>")
+}
+
+func TestOFXWriter_OneStatementPerAccount(t *testing.T) {
+	allAccounts := append(rows, dto.ExportTransactionRow{TransactionID: 3, AccountID: 2, OperationTypeID: 3, Direction: dto.DirectionDebit, Amount: 10, EventDate: eventDate})
+	output := export(t, FormatOFX, Metadata{GeneratedAt: eventDate}, allAccounts)
+	assert.Equal(t, 2, strings.Count(output, "<STMTRS>"))
+	assert.Equal(t, 2, strings.Count(output, "</STMTTRNRS>"))
+	assert.Contains(t, output, "<ACCTID>2</ACCTID>")
+}
+
+func TestOFXWriter_EmptyAccountStatement(t *testing.T) {
+	output := export(t, FormatOFX, Metadata{AccountID: 7, GeneratedAt: eventDate}, nil)
+	assert.Contains(t, output, "<ACCTID>7</ACCTID>")
+	assert.Contains(t, output, "<BALAMT>0.00</BALAMT>")
+	generatedAt := eventDate.UTC().Format(ofxDateLayout) + "[0:GMT]"
+	assert.Contains(t, output, "<DTEND>"+generatedAt+"</DTEND>", "a statement without a To bound should end when the export was generated")
+}
+
+func TestFileName(t *testing.T) {
+	assert.Equal(t, "transactions.ndjson", FileName(FormatNDJSON, Metadata{}))
+	metadata := Metadata{AccountID: 3, From: eventDate, To: eventDate.AddDate(0, 1, 0)}
+	assert.Equal(t, "transactions-account-3-20260310-20260410.csv", FileName(FormatCSV, metadata))
+}
+
+func TestParseBound(t *testing.T) {
+	from, err := ParseBound("2026-03-10", false)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC), from)
+	to, err := ParseBound("2026-03-10", true)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC), to)
+	exact, err := ParseBound("2026-03-10T12:30:00Z", true)
+	require.NoError(t, err)
+	assert.Equal(t, eventDate, exact)
+	empty, err := ParseBound("", true)
+	require.NoError(t, err)
+	assert.True(t, empty.IsZero())
+	_, err = ParseBound("10/03/2026", false)
+	assert.ErrorIs(t, err, coreerr.InvalidParametersError)
+}
+
+func TestParseBoundIn(t *testing.T) {
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	require.NoError(t, err)
+	from, err := ParseBoundIn("2026-03-10", false, saoPaulo)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 3, 10, 3, 0, 0, 0, time.UTC), from.UTC(), "dates should start at the local midnight")
+	to, err := ParseBoundIn("2026-03-10", true, saoPaulo)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 3, 11, 3, 0, 0, 0, time.UTC), to.UTC())
+	exact, err := ParseBoundIn("2026-03-10T12:30:00Z", false, saoPaulo)
+	require.NoError(t, err)
+	assert.Equal(t, eventDate, exact, "RFC 3339 bounds should keep their own offset")
+}
+
+func TestParseLocation(t *testing.T) {
+	location, err := ParseLocation("")
+	require.NoError(t, err)
+	assert.Equal(t, time.UTC, location)
+	location, err = ParseLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	assert.Equal(t, "Asia/Tokyo", location.String())
+	for _, name := range []string{"Mars/Olympus_Mons", "Local"} {
+		_, err = ParseLocation(name)
+		assert.ErrorIs(t, err, coreerr.InvalidParametersError, name)
+	}
+}
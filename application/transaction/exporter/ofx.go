@@ -0,0 +1,145 @@
+package exporter
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/kiosanim/pismo-code-assessment/application/transaction/dto"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/currency"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const ofxDateLayout = "20060102150405.000"
+
+// ofxNameLength is the maximum length of the NAME of a statement transaction
+const ofxNameLength = 32
+
+// operationTypeNames are the descriptions of the operation types seeded by the migrations
+var operationTypeNames = map[int]string{
+	1: "PURCHASE",
+	2: "INSTALLMENT PURCHASE",
+	3: "WITHDRAWAL",
+	4: "PAYMENT",
+}
+
+// ofxWriter writes an OFX 2.2 document with one bank statement response per account.
+// The ledger balance of a statement is the sum of its exported transactions.
+type ofxWriter struct {
+	writer        *bufio.Writer
+	metadata      Metadata
+	headerWritten bool
+	accountID     int64
+	inStatement   bool
+	balance       float64
+}
+
+func (o *ofxWriter) Write(row dto.ExportTransactionRow) error {
+	o.writeHeader()
+	if !o.inStatement || row.AccountID != o.accountID {
+		o.closeStatement()
+		o.openStatement(row.AccountID, row.Currency, row.EventDate)
+	}
+	amount := row.Amount
+	transactionType := "CREDIT"
+	if row.Direction == dto.DirectionDebit {
+		amount = -amount
+		transactionType = "DEBIT"
+	}
+	o.balance += amount
+	name := operationTypeNames[row.OperationTypeID]
+	if name == "" {
+		name = "OPERATION TYPE " + strconv.Itoa(row.OperationTypeID)
+	}
+	if row.MerchantName != "" {
+		name = truncate(row.MerchantName, ofxNameLength)
+	}
+	memo := ""
+	if row.Description != "" {
+		memo = "<MEMO>" + html.EscapeString(row.Description) + "</MEMO>"
+	}
+	fmt.Fprintf(o.writer, "<STMTTRN><TRNTYPE>%s</TRNTYPE><DTPOSTED>%s</DTPOSTED><TRNAMT>%s</TRNAMT><FITID>%d</FITID><NAME>%s</NAME>%s</STMTTRN>\n",
+		transactionType, o.date(row.EventDate), strconv.FormatFloat(amount, 'f', 2, 64), row.TransactionID, html.EscapeString(name), memo)
+	return nil
+}
+
+func (o *ofxWriter) Close() error {
+	o.writeHeader()
+	if !o.inStatement && o.metadata.AccountID > 0 {
+		o.openStatement(o.metadata.AccountID, "", o.metadata.From)
+	}
+	o.closeStatement()
+	o.writer.WriteString("</BANKMSGSRSV1>\n</OFX>\n")
+	return o.writer.Flush()
+}
+
+func (o *ofxWriter) writeHeader() {
+	if o.headerWritten {
+		return
+	}
+	o.headerWritten = true
+	o.writer.WriteString("<?xml version=\"1.0\" encoding=\"UTF-8\" standalone=\"no\"?>\n")
+	o.writer.WriteString("<?OFX OFXHEADER=\"200\" VERSION=\"220\" SECURITY=\"NONE\" OLDFILEUID=\"NONE\" NEWFILEUID=\"NONE\"?>\n")
+	o.writer.WriteString("<OFX>\n")
+	fmt.Fprintf(o.writer, "<SIGNONMSGSRSV1><SONRS><STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS><DTSERVER>%s</DTSERVER><LANGUAGE>ENG</LANGUAGE></SONRS></SIGNONMSGSRSV1>\n",
+		o.date(o.metadata.GeneratedAt))
+	o.writer.WriteString("<BANKMSGSRSV1>\n")
+}
+
+func (o *ofxWriter) openStatement(accountID int64, accountCurrency string, firstEventDate time.Time) {
+	if accountCurrency == "" {
+		accountCurrency = currency.DefaultCode
+	}
+	start := o.metadata.From
+	if start.IsZero() {
+		start = firstEventDate
+	}
+	o.accountID = accountID
+	o.inStatement = true
+	o.balance = 0
+	fmt.Fprintf(o.writer, "<STMTTRNRS><TRNUID>%d</TRNUID><STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>\n", accountID)
+	fmt.Fprintf(o.writer, "<STMTRS><CURDEF>%s</CURDEF><BANKACCTFROM><BANKID>0</BANKID><ACCTID>%d</ACCTID><ACCTTYPE>CHECKING</ACCTTYPE></BANKACCTFROM>\n",
+		accountCurrency, accountID)
+	fmt.Fprintf(o.writer, "<BANKTRANLIST><DTSTART>%s</DTSTART><DTEND>%s</DTEND>\n", o.date(start), o.date(o.end()))
+}
+
+func (o *ofxWriter) closeStatement() {
+	if !o.inStatement {
+		return
+	}
+	o.inStatement = false
+	fmt.Fprintf(o.writer, "</BANKTRANLIST><LEDGERBAL><BALAMT>%s</BALAMT><DTASOF>%s</DTASOF></LEDGERBAL></STMTRS></STMTTRNRS>\n",
+		strconv.FormatFloat(o.balance, 'f', 2, 64), o.date(o.end()))
+}
+
+func (o *ofxWriter) end() time.Time {
+	if !o.metadata.To.IsZero() {
+		return o.metadata.To
+	}
+	return o.metadata.GeneratedAt
+}
+
+// date formats t in the time zone of the export, e.g. 20261019090000.000[-3:BRT]. The zone name is left out when
+// the time zone database only has a numeric abbreviation for it.
+func (o *ofxWriter) date(t time.Time) string {
+	local := t.In(o.metadata.location())
+	name, offset := local.Zone()
+	if offset == 0 && (name == "UTC" || name == "GMT") {
+		return local.Format(ofxDateLayout) + "[0:GMT]"
+	}
+	zone := strconv.FormatFloat(float64(offset)/3600, 'f', -1, 64)
+	if strings.Trim(name, "+-0123456789") == name {
+		zone += ":" + name
+	}
+	return local.Format(ofxDateLayout) + "[" + zone + "]"
+}
+
+// truncate cuts value to at most length runes
+func truncate(value string, length int) string {
+	runes := []rune(value)
+	if len(runes) <= length {
+		return value
+	}
+	return string(runes[:length])
+}
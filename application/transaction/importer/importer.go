@@ -0,0 +1,252 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/kiosanim/pismo-code-assessment/application/transaction/dto"
+	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FormatJSON   = "json"
+	FormatNDJSON = "ndjson"
+	FormatCSV    = "csv"
+)
+
+// maxLineSize is the longest NDJSON line accepted
+const maxLineSize = 1024 * 1024
+
+// csvColumns are the columns required in the CSV header, in any order
+var csvColumns = []string{"account_id", "operation_type_id", "amount"}
+
+// RowReader reads the transactions of a bulk import one row at a time.
+// Rows that cannot be parsed are returned with their Error set so the import can go on,
+// while errors returned by Next mean the input cannot be read any further. Next returns io.EOF at the end.
+type RowReader interface {
+	Next() (dto.BatchTransactionRow, error)
+}
+
+// NewReader returns the RowReader for a format
+func NewReader(format string, r io.Reader) (RowReader, error) {
+	switch strings.ToLower(format) {
+	case FormatJSON:
+		return NewJSONArrayReader(r), nil
+	case FormatNDJSON:
+		return NewNDJSONReader(r), nil
+	case FormatCSV:
+		return NewCSVReader(r), nil
+	}
+	return nil, fmt.Errorf("%w: unsupported format %q", coreerr.BatchInvalidFormatError, format)
+}
+
+// ReadAll reads every row, failing with BatchTooLargeError when there are more than maxRows rows
+func ReadAll(reader RowReader, maxRows int) ([]dto.BatchTransactionRow, error) {
+	var rows []dto.BatchTransactionRow
+	for {
+		row, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if maxRows > 0 && len(rows) == maxRows {
+			return nil, coreerr.BatchTooLargeError
+		}
+		rows = append(rows, row)
+	}
+}
+
+// JSONArrayReader reads a JSON array of transactions
+type JSONArrayReader struct {
+	decoder *json.Decoder
+	started bool
+	row     int
+}
+
+func NewJSONArrayReader(r io.Reader) *JSONArrayReader {
+	return &JSONArrayReader{decoder: json.NewDecoder(r)}
+}
+
+func (j *JSONArrayReader) Next() (dto.BatchTransactionRow, error) {
+	if !j.started {
+		token, err := j.decoder.Token()
+		if err != nil || token != json.Delim('[') {
+			return dto.BatchTransactionRow{}, fmt.Errorf("%w: expected a JSON array", coreerr.BatchInvalidFormatError)
+		}
+		j.started = true
+	}
+	if !j.decoder.More() {
+		if _, err := j.decoder.Token(); err != nil {
+			return dto.BatchTransactionRow{}, fmt.Errorf("%w: %v", coreerr.BatchInvalidFormatError, err)
+		}
+		return dto.BatchTransactionRow{}, io.EOF
+	}
+	j.row++
+	row := dto.BatchTransactionRow{Row: j.row}
+	if err := j.decoder.Decode(&row.Request); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if !errors.As(err, &typeErr) {
+			return dto.BatchTransactionRow{}, fmt.Errorf("%w: %v", coreerr.BatchInvalidFormatError, err)
+		}
+		row.Error = coreerr.InvalidParametersError
+	}
+	return row, nil
+}
+
+// NDJSONReader reads one JSON transaction per line, skipping blank lines
+type NDJSONReader struct {
+	scanner *bufio.Scanner
+	row     int
+}
+
+func NewNDJSONReader(r io.Reader) *NDJSONReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	return &NDJSONReader{scanner: scanner}
+}
+
+func (n *NDJSONReader) Next() (dto.BatchTransactionRow, error) {
+	for n.scanner.Scan() {
+		line := bytes.TrimSpace(n.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		n.row++
+		row := dto.BatchTransactionRow{Row: n.row}
+		if err := json.Unmarshal(line, &row.Request); err != nil {
+			row.Error = coreerr.InvalidParametersError
+		}
+		return row, nil
+	}
+	if err := n.scanner.Err(); err != nil {
+		return dto.BatchTransactionRow{}, fmt.Errorf("%w: %v", coreerr.BatchInvalidFormatError, err)
+	}
+	return dto.BatchTransactionRow{}, io.EOF
+}
+
+// CSVReader reads a CSV file whose header names the account_id, operation_type_id and amount columns
+// and optionally the currency, merchant_name, mcc, merchant_country, description, effective_date and event_date
+// (RFC 3339) columns
+type CSVReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+	row     int
+}
+
+func NewCSVReader(r io.Reader) *CSVReader {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	return &CSVReader{reader: reader}
+}
+
+func (c *CSVReader) Next() (dto.BatchTransactionRow, error) {
+	if c.columns == nil {
+		if err := c.readHeader(); err != nil {
+			return dto.BatchTransactionRow{}, err
+		}
+	}
+	record, err := c.reader.Read()
+	if errors.Is(err, io.EOF) {
+		return dto.BatchTransactionRow{}, io.EOF
+	}
+	c.row++
+	row := dto.BatchTransactionRow{Row: c.row}
+	if err != nil {
+		var parseErr *csv.ParseError
+		if !errors.As(err, &parseErr) {
+			return dto.BatchTransactionRow{}, fmt.Errorf("%w: %v", coreerr.BatchInvalidFormatError, err)
+		}
+		row.Error = coreerr.InvalidParametersError
+		return row, nil
+	}
+	row.Request, row.Error = c.parseRecord(record)
+	return row, nil
+}
+
+func (c *CSVReader) readHeader() error {
+	header, err := c.reader.Read()
+	if err != nil {
+		return fmt.Errorf("%w: missing CSV header", coreerr.BatchInvalidFormatError)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range csvColumns {
+		if _, ok := columns[name]; !ok {
+			return fmt.Errorf("%w: missing CSV column %q", coreerr.BatchInvalidFormatError, name)
+		}
+	}
+	c.columns = columns
+	return nil
+}
+
+func (c *CSVReader) parseRecord(record []string) (dto.CreateTransactionRequest, error) {
+	var request dto.CreateTransactionRequest
+	field := func(name string) (string, bool) {
+		index := c.columns[name]
+		if index >= len(record) {
+			return "", false
+		}
+		return strings.TrimSpace(record[index]), true
+	}
+	accountID, ok := field("account_id")
+	if !ok {
+		return request, coreerr.InvalidParametersError
+	}
+	operationTypeID, ok := field("operation_type_id")
+	if !ok {
+		return request, coreerr.InvalidParametersError
+	}
+	amount, ok := field("amount")
+	if !ok {
+		return request, coreerr.InvalidParametersError
+	}
+	var err error
+	if request.AccountID, err = strconv.ParseInt(accountID, 10, 64); err != nil {
+		return request, coreerr.InvalidParametersError
+	}
+	if request.OperationTypeID, err = strconv.Atoi(operationTypeID); err != nil {
+		return request, coreerr.InvalidParametersError
+	}
+	if request.Amount, err = strconv.ParseFloat(amount, 64); err != nil {
+		return request, coreerr.InvalidParametersError
+	}
+	optional := func(name string) string {
+		if _, ok := c.columns[name]; !ok {
+			return ""
+		}
+		value, _ := field(name)
+		return value
+	}
+	request.Currency = optional("currency")
+	request.MerchantName = optional("merchant_name")
+	request.MCC = optional("mcc")
+	request.MerchantCountry = optional("merchant_country")
+	request.Description = optional("description")
+	if effectiveDate := optional("effective_date"); effectiveDate != "" {
+		parsed, err := time.Parse(time.RFC3339, effectiveDate)
+		if err != nil {
+			return request, coreerr.InvalidParametersError
+		}
+		request.EffectiveDate = &parsed
+	}
+	if eventDate := optional("event_date"); eventDate != "" {
+		parsed, err := time.Parse(time.RFC3339, eventDate)
+		if err != nil {
+			return request, coreerr.InvalidParametersError
+		}
+		request.EventDate = &parsed
+	}
+	return request, nil
+}
@@ -0,0 +1,104 @@
+package importer
+
+import (
+	"github.com/kiosanim/pismo-code-assessment/application/transaction/dto"
+	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadAll_JSONArray(t *testing.T) {
+	input := `[{"account_id":1,"operation_type_id":4,"amount":10.5},{"account_id":"x","operation_type_id":4,"amount":1},{"account_id":2,"operation_type_id":1,"amount":3}]`
+	rows, err := ReadAll(NewJSONArrayReader(strings.NewReader(input)), 0)
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, dto.BatchTransactionRow{Row: 1, Request: dto.CreateTransactionRequest{AccountID: 1, OperationTypeID: 4, Amount: 10.5}}, rows[0])
+	assert.Equal(t, 2, rows[1].Row)
+	assert.ErrorIs(t, rows[1].Error, coreerr.InvalidParametersError)
+	assert.Equal(t, int64(2), rows[2].Request.AccountID)
+}
+
+func TestReadAll_JSONNotAnArray(t *testing.T) {
+	_, err := ReadAll(NewJSONArrayReader(strings.NewReader(`{"account_id":1}`)), 0)
+	assert.ErrorIs(t, err, coreerr.BatchInvalidFormatError)
+}
+
+func TestReadAll_NDJSON(t *testing.T) {
+	input := "{\"account_id\":1,\"operation_type_id\":4,\"amount\":10}\n\nnot json\n{\"account_id\":3,\"operation_type_id\":2,\"amount\":7}\n"
+	rows, err := ReadAll(NewNDJSONReader(strings.NewReader(input)), 0)
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.NoError(t, rows[0].Error)
+	assert.Equal(t, 2, rows[1].Row)
+	assert.ErrorIs(t, rows[1].Error, coreerr.InvalidParametersError)
+	assert.Equal(t, dto.CreateTransactionRequest{AccountID: 3, OperationTypeID: 2, Amount: 7}, rows[2].Request)
+}
+
+func TestReadAll_CSV(t *testing.T) {
+	input := "amount,account_id,operation_type_id\n10.25,1,4\nabc,1,4\n5,2\n"
+	rows, err := ReadAll(NewCSVReader(strings.NewReader(input)), 0)
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, dto.BatchTransactionRow{Row: 1, Request: dto.CreateTransactionRequest{AccountID: 1, OperationTypeID: 4, Amount: 10.25}}, rows[0])
+	assert.ErrorIs(t, rows[1].Error, coreerr.InvalidParametersError)
+	assert.ErrorIs(t, rows[2].Error, coreerr.InvalidParametersError)
+}
+
+func TestReadAll_CSVWithCurrency(t *testing.T) {
+	rows, err := ReadAll(NewCSVReader(strings.NewReader("account_id,operation_type_id,amount,currency\n1,4,10,USD\n2,4,5,\n")), 0)
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, "USD", rows[0].Request.Currency)
+	assert.Empty(t, rows[1].Request.Currency)
+}
+
+func TestReadAll_CSVWithMerchantDetails(t *testing.T) {
+	input := "account_id,operation_type_id,amount,merchant_name,mcc,merchant_country,description\n1,1,10,Coffee Shop,5814,BR,Espresso\n"
+	rows, err := ReadAll(NewCSVReader(strings.NewReader(input)), 0)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, "Coffee Shop", rows[0].Request.MerchantName)
+	assert.Equal(t, "5814", rows[0].Request.MCC)
+	assert.Equal(t, "BR", rows[0].Request.MerchantCountry)
+	assert.Equal(t, "Espresso", rows[0].Request.Description)
+}
+
+func TestReadAll_CSVWithEffectiveDate(t *testing.T) {
+	input := "account_id,operation_type_id,amount,effective_date\n1,1,10,2026-11-01T09:00:00-03:00\n1,1,10,\n1,1,10,tomorrow\n"
+	rows, err := ReadAll(NewCSVReader(strings.NewReader(input)), 0)
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	require.NotNil(t, rows[0].Request.EffectiveDate)
+	assert.True(t, rows[0].Request.EffectiveDate.Equal(time.Date(2026, 11, 1, 12, 0, 0, 0, time.UTC)))
+	assert.Nil(t, rows[1].Request.EffectiveDate, "an empty effective date posts the transaction at once")
+	assert.ErrorIs(t, rows[2].Error, coreerr.InvalidParametersError)
+}
+
+func TestReadAll_CSVWithEventDate(t *testing.T) {
+	input := "account_id,operation_type_id,amount,event_date\n1,1,10,2026-10-18T21:30:00-03:00\n1,1,10,yesterday\n"
+	rows, err := ReadAll(NewCSVReader(strings.NewReader(input)), 0)
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	require.NotNil(t, rows[0].Request.EventDate)
+	assert.True(t, rows[0].Request.EventDate.Equal(time.Date(2026, 10, 19, 0, 30, 0, 0, time.UTC)))
+	assert.ErrorIs(t, rows[1].Error, coreerr.InvalidParametersError)
+}
+
+func TestReadAll_CSVMissingColumn(t *testing.T) {
+	_, err := ReadAll(NewCSVReader(strings.NewReader("account_id,amount\n1,10\n")), 0)
+	assert.ErrorIs(t, err, coreerr.BatchInvalidFormatError)
+}
+
+func TestReadAll_TooManyRows(t *testing.T) {
+	input := "{\"account_id\":1}\n{\"account_id\":2}\n{\"account_id\":3}\n"
+	_, err := ReadAll(NewNDJSONReader(strings.NewReader(input)), 2)
+	assert.ErrorIs(t, err, coreerr.BatchTooLargeError)
+}
+
+func TestNewReader_UnsupportedFormat(t *testing.T) {
+	_, err := NewReader("xml", strings.NewReader(""))
+	assert.ErrorIs(t, err, coreerr.BatchInvalidFormatError)
+}
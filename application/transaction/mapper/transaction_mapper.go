@@ -3,32 +3,140 @@ package mapper
 import (
 	"github.com/kiosanim/pismo-code-assessment/application/transaction/dto"
 	"github.com/kiosanim/pismo-code-assessment/internal/domains/transaction"
+	"strings"
+	"time"
 )
 
 func CreateDTOToEntity(req dto.CreateTransactionRequest) *transaction.Transaction {
-	return &transaction.Transaction{
-		AccountID:       req.AccountID,
-		Amount:          req.Amount,
-		OperationTypeID: req.OperationTypeID,
+	entity := &transaction.Transaction{
+		AccountID:        req.AccountID,
+		Amount:           req.Amount,
+		OperationTypeID:  req.OperationTypeID,
+		OriginalAmount:   req.Amount,
+		OriginalCurrency: req.Currency,
+		Merchant: transaction.NormalizeMerchant(transaction.Merchant{
+			Name:         req.MerchantName,
+			CategoryCode: req.MCC,
+			Country:      req.MerchantCountry,
+		}),
+		Description: strings.TrimSpace(req.Description),
+		Metadata:    req.Metadata,
 	}
+	if req.EffectiveDate != nil {
+		entity.EffectiveDate = req.EffectiveDate.UTC()
+	}
+	if req.EventDate != nil {
+		entity.EventDate = req.EventDate.UTC()
+	}
+	return entity
 }
 
-func EntityToResponse(entity *transaction.Transaction) *dto.CreateTransactionResponse {
-	transactionDTO := &dto.TransactionDTO{
-		TransactionID:   entity.TransactionID,
-		AccountID:       entity.AccountID,
-		OperationTypeID: entity.OperationTypeID,
-		Amount:          entity.Amount,
+func EntityToDTO(entity *transaction.Transaction) *dto.TransactionDTO {
+	return entityToDTOIn(entity, time.UTC)
+}
+
+// entityToDTOIn maps a transaction presenting its local date in location
+func entityToDTOIn(entity *transaction.Transaction, location *time.Location) *dto.TransactionDTO {
+	return &dto.TransactionDTO{
+		TransactionID:    entity.TransactionID,
+		AccountID:        entity.AccountID,
+		OperationTypeID:  entity.OperationTypeID,
+		Amount:           entity.Amount,
+		Currency:         entity.Currency,
+		OriginalAmount:   entity.OriginalAmount,
+		OriginalCurrency: entity.OriginalCurrency,
+		ExchangeRate:     entity.ExchangeRate,
+		MerchantName:     entity.Merchant.Name,
+		MCC:              entity.Merchant.CategoryCode,
+		MerchantCountry:  entity.Merchant.Country,
+		Description:      entity.Description,
+		Metadata:         entity.Metadata,
+		EventDate:        entity.EventDate.UTC(),
+		LocalDate:        localDate(entity.EventDate, location),
+		Status:           entity.Status,
+		EffectiveDate:    entity.EffectiveDate.UTC(),
 	}
+}
+
+func EntityToResponse(entity *transaction.Transaction) *dto.CreateTransactionResponse {
+	transactionDTO := EntityToDTO(entity)
 	return &dto.CreateTransactionResponse{Transaction: *transactionDTO}
 }
 
 func EntityByIdToResponseById(entity *transaction.Transaction) *dto.FindTransactionByIdResponse {
-	transactionDTO := &dto.TransactionDTO{
-		TransactionID:   entity.TransactionID,
-		AccountID:       entity.AccountID,
-		OperationTypeID: entity.OperationTypeID,
-		Amount:          entity.Amount,
+	transactionDTO := EntityToDTO(entity)
+	transitions := make([]dto.StatusTransitionDTO, 0, 3)
+	for _, transition := range entity.Transitions() {
+		transitions = append(transitions, dto.StatusTransitionDTO{Status: transition.Status, At: transition.At.UTC()})
+	}
+	return &dto.FindTransactionByIdResponse{Transaction: *transactionDTO, StatusTransitions: transitions}
+}
+
+func EntityToCancelResponse(entity *transaction.Transaction) *dto.CancelTransactionResponse {
+	transactionDTO := EntityToDTO(entity)
+	return &dto.CancelTransactionResponse{Transaction: *transactionDTO}
+}
+
+func EntityToReverseResponse(entity *transaction.Transaction) *dto.ReverseTransactionResponse {
+	transactionDTO := EntityToDTO(entity)
+	return &dto.ReverseTransactionResponse{Transaction: *transactionDTO}
+}
+
+// EntityToExportRow maps a transaction to an export row, presenting its local date in location (UTC when nil)
+func EntityToExportRow(entity *transaction.Transaction, direction string, location *time.Location) dto.ExportTransactionRow {
+	return dto.ExportTransactionRow{
+		TransactionID:    entity.TransactionID,
+		AccountID:        entity.AccountID,
+		OperationTypeID:  entity.OperationTypeID,
+		Direction:        direction,
+		Amount:           entity.Amount,
+		EventDate:        entity.EventDate.UTC(),
+		Currency:         entity.Currency,
+		OriginalAmount:   entity.OriginalAmount,
+		OriginalCurrency: entity.OriginalCurrency,
+		ExchangeRate:     entity.ExchangeRate,
+		MerchantName:     entity.Merchant.Name,
+		MCC:              entity.Merchant.CategoryCode,
+		MerchantCountry:  entity.Merchant.Country,
+		Description:      entity.Description,
+		LocalDate:        localDate(entity.EventDate, location),
+	}
+}
+
+func ListRequestToSearch(req dto.ListTransactionsRequest) transaction.TransactionSearch {
+	return transaction.TransactionSearch{
+		TransactionFilter:    transaction.TransactionFilter{AccountID: req.AccountID, From: req.From, To: req.To},
+		Status:               strings.ToUpper(strings.TrimSpace(req.Status)),
+		MerchantName:         strings.TrimSpace(req.MerchantName),
+		MerchantCategoryCode: strings.TrimSpace(req.MCC),
+		MerchantCountry:      strings.ToUpper(strings.TrimSpace(req.MerchantCountry)),
+		Description:          strings.TrimSpace(req.Description),
+		Metadata:             req.Metadata,
+		Cursor:               req.Cursor,
+		Limit:                req.Limit,
+	}
+}
+
+// EntitiesToListResponse maps a page of transactions, presenting their local dates in location (UTC when nil)
+func EntitiesToListResponse(entities []*transaction.Transaction, limit int64, cursor int64, location *time.Location) *dto.ListTransactionsResponse {
+	transactions := make([]dto.TransactionDTO, 0, len(entities))
+	for _, entity := range entities {
+		transactions = append(transactions, *entityToDTOIn(entity, location))
+	}
+	return &dto.ListTransactionsResponse{
+		Transactions: transactions,
+		Limit:        limit,
+		Cursor:       cursor,
+	}
+}
+
+// localDate returns the day of t in location, formatted as YYYY-MM-DD
+func localDate(t time.Time, location *time.Location) string {
+	if t.IsZero() {
+		return ""
+	}
+	if location == nil {
+		location = time.UTC
 	}
-	return &dto.FindTransactionByIdResponse{Transaction: *transactionDTO}
+	return t.In(location).Format(time.DateOnly)
 }
@@ -282,3 +282,75 @@ func TestEntityToResponse_NegativeAndPositiveAmounts(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateDTOToEntity_MerchantDetails(t *testing.T) {
+	input := dto.CreateTransactionRequest{
+		AccountID:       1,
+		OperationTypeID: transaction.Purchase,
+		Amount:          10,
+		MerchantName:    " Coffee Shop ",
+		MCC:             "5814",
+		MerchantCountry: "br",
+		Description:     " Espresso ",
+		Metadata:        map[string]string{"order_id": "42"},
+	}
+	result := CreateDTOToEntity(input)
+
+	assert.Equal(t, transaction.Merchant{Name: "Coffee Shop", CategoryCode: "5814", Country: "BR"}, result.Merchant, "merchant should be normalized")
+	assert.Equal(t, "Espresso", result.Description, "description should be trimmed")
+	assert.Equal(t, map[string]string{"order_id": "42"}, result.Metadata, "metadata should match")
+
+	response := EntityToDTO(result)
+	assert.Equal(t, "Coffee Shop", response.MerchantName, "merchant name should match")
+	assert.Equal(t, "5814", response.MCC, "mcc should match")
+	assert.Equal(t, "BR", response.MerchantCountry, "merchant country should match")
+}
+
+func TestEntityByIdToResponseById_StatusTransitions(t *testing.T) {
+	created := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	later := created.Add(24 * time.Hour)
+	tests := []struct {
+		name     string
+		entity   *transaction.Transaction
+		expected []dto.StatusTransitionDTO
+	}{
+		{
+			name:     "posted when created",
+			entity:   &transaction.Transaction{Status: transaction.StatusPosted, CreatedAt: created, PostedAt: created},
+			expected: []dto.StatusTransitionDTO{{Status: transaction.StatusPosted, At: created}},
+		},
+		{
+			name:     "backdated",
+			entity:   &transaction.Transaction{Status: transaction.StatusPosted, EventDate: created.Add(-48 * time.Hour), CreatedAt: created, PostedAt: created},
+			expected: []dto.StatusTransitionDTO{{Status: transaction.StatusPosted, At: created}},
+		},
+		{
+			name:     "pending",
+			entity:   &transaction.Transaction{Status: transaction.StatusPending, CreatedAt: created},
+			expected: []dto.StatusTransitionDTO{{Status: transaction.StatusPending, At: created}},
+		},
+		{
+			name:   "posted by the scheduler",
+			entity: &transaction.Transaction{Status: transaction.StatusPosted, CreatedAt: created, PostedAt: later},
+			expected: []dto.StatusTransitionDTO{
+				{Status: transaction.StatusPending, At: created},
+				{Status: transaction.StatusPosted, At: later},
+			},
+		},
+		{
+			name:   "cancelled",
+			entity: &transaction.Transaction{Status: transaction.StatusCancelled, CreatedAt: created, CancelledAt: later},
+			expected: []dto.StatusTransitionDTO{
+				{Status: transaction.StatusPending, At: created},
+				{Status: transaction.StatusCancelled, At: later},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := EntityByIdToResponseById(tt.entity)
+			assert.Equal(t, tt.expected, response.StatusTransitions)
+			assert.Equal(t, tt.entity.Status, response.Transaction.Status)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/lock"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
+	"time"
+)
+
+// Poster posts the pending transactions whose effective date has come, returning how many were posted
+type Poster interface {
+	PostDue(ctx context.Context, limit int64) (int, error)
+}
+
+// Options sets how often the scheduler runs. Lease is the ttl of the leader lock and must be longer than Interval,
+// so the leader refreshes it before it expires.
+type Options struct {
+	Interval  time.Duration
+	Lease     time.Duration
+	BatchSize int64
+}
+
+// Scheduler posts due transactions on every instance elected leader. The leader holds lock.SchedulerLeaderLockKey,
+// refreshing it on every run, and the other instances take it over when it expires.
+type Scheduler struct {
+	poster        Poster
+	locker        lock.DistributedLockManager
+	options       Options
+	leader        *lock.Lock // Lock held while this instance is the leader
+	componentName string
+	log           logger.Logger
+}
+
+func NewScheduler(poster Poster, locker lock.DistributedLockManager, options Options, log logger.Logger) *Scheduler {
+	return &Scheduler{
+		poster:        poster,
+		locker:        locker,
+		options:       options,
+		componentName: "Scheduler",
+		log:           log,
+	}
+}
+
+// Run calls Tick every interval until ctx is done, then gives up the leadership
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.options.Interval)
+	defer ticker.Stop()
+	defer s.resign()
+	for {
+		_, _ = s.Tick(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick takes or keeps the leadership and, when leader, posts the due transactions one batch at a time until a batch
+// is not full. Followers post nothing.
+func (s *Scheduler) Tick(ctx context.Context) (int, error) {
+	if !s.lead(ctx) {
+		return 0, nil
+	}
+	total := 0
+	for ctx.Err() == nil {
+		posted, err := s.poster.PostDue(ctx, s.options.BatchSize)
+		total += posted
+		if err != nil {
+			s.log.Warn(s.componentName+".Tick", "error", err, "posted", total)
+			return total, err
+		}
+		if int64(posted) < s.options.BatchSize {
+			break
+		}
+	}
+	return total, nil
+}
+
+// IsLeader tells if this instance held the leadership on its last run
+func (s *Scheduler) IsLeader() bool {
+	return s.leader != nil
+}
+
+// lead refreshes the leader lock, or tries to acquire it when this instance is not the leader
+func (s *Scheduler) lead(ctx context.Context) bool {
+	if s.leader != nil {
+		err := s.locker.Refresh(ctx, s.leader, s.options.Lease)
+		if err == nil {
+			return true
+		}
+		s.log.Warn(s.componentName+".lead", "status", "leadership lost", "error", err)
+		s.leader = nil
+	}
+	leader, err := s.locker.Lock(ctx, lock.SchedulerLeaderLockKey, s.options.Lease)
+	if err != nil {
+		if !errors.Is(err, coreerr.DistributedLockFailToAcquire) {
+			s.log.Warn(s.componentName+".lead", "error", err)
+		}
+		return false
+	}
+	s.log.Info(s.componentName+".lead", "status", "elected leader")
+	s.leader = leader
+	return true
+}
+
+// resign releases the leader lock so another instance takes over without waiting for it to expire
+func (s *Scheduler) resign() {
+	if s.leader == nil {
+		return
+	}
+	if err := s.locker.Unlock(context.Background(), s.leader); err != nil {
+		s.log.Warn(s.componentName+".resign", "error", err)
+	}
+	s.leader = nil
+}
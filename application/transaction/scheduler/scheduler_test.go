@@ -0,0 +1,109 @@
+package scheduler
+
+import (
+	"context"
+	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/lock"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/transaction"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+type SchedulerTestSuite struct {
+	suite.Suite
+	ctx       context.Context
+	locker    *lock.DistributedLockManagerMock
+	poster    *transaction.TransactionServiceMock
+	scheduler *Scheduler
+}
+
+var options = Options{Interval: time.Second, Lease: 5 * time.Second, BatchSize: 2}
+
+func (s *SchedulerTestSuite) SetupTest() {
+	ctrl := gomock.NewController(s.T())
+	s.ctx = context.Background()
+	s.locker = lock.NewDistributedLockManagerMock(ctrl)
+	s.poster = transaction.NewTransactionServiceMock()
+	log := logger.NewLoggerMock(ctrl)
+	log.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+	log.EXPECT().Warn(gomock.Any(), gomock.Any()).AnyTimes()
+	s.scheduler = NewScheduler(s.poster, s.locker, options, log)
+}
+
+func (s *SchedulerTestSuite) TestTick_FollowerPostsNothing() {
+	s.locker.EXPECT().Lock(s.ctx, lock.SchedulerLeaderLockKey, options.Lease).Return(nil, coreerr.DistributedLockFailToAcquire)
+	posted, err := s.scheduler.Tick(s.ctx)
+	s.NoError(err)
+	s.Zero(posted)
+	s.False(s.scheduler.IsLeader())
+	s.poster.AssertNotCalled(s.T(), "PostDue")
+}
+
+func (s *SchedulerTestSuite) TestTick_LeaderPostsUntilBatchIsNotFull() {
+	leader := &lock.Lock{Key: lock.SchedulerLeaderLockKey, Value: "leader"}
+	s.locker.EXPECT().Lock(s.ctx, lock.SchedulerLeaderLockKey, options.Lease).Return(leader, nil)
+	s.poster.On("PostDue", s.ctx, options.BatchSize).Return(2, nil).Once()
+	s.poster.On("PostDue", s.ctx, options.BatchSize).Return(1, nil).Once()
+	posted, err := s.scheduler.Tick(s.ctx)
+	s.NoError(err)
+	s.Equal(3, posted, "every due transaction should be posted")
+	s.True(s.scheduler.IsLeader())
+	s.poster.AssertNumberOfCalls(s.T(), "PostDue", 2)
+}
+
+func (s *SchedulerTestSuite) TestTick_LeaderRefreshesItsLease() {
+	leader := &lock.Lock{Key: lock.SchedulerLeaderLockKey, Value: "leader"}
+	s.locker.EXPECT().Lock(s.ctx, lock.SchedulerLeaderLockKey, options.Lease).Return(leader, nil).Times(1)
+	s.locker.EXPECT().Refresh(s.ctx, leader, options.Lease).Return(nil)
+	s.poster.On("PostDue", s.ctx, options.BatchSize).Return(0, nil)
+	_, err := s.scheduler.Tick(s.ctx)
+	s.NoError(err)
+	_, err = s.scheduler.Tick(s.ctx)
+	s.NoError(err)
+	s.poster.AssertNumberOfCalls(s.T(), "PostDue", 2)
+}
+
+func (s *SchedulerTestSuite) TestTick_LostLeadershipStopsPosting() {
+	leader := &lock.Lock{Key: lock.SchedulerLeaderLockKey, Value: "leader"}
+	gomock.InOrder(
+		s.locker.EXPECT().Lock(s.ctx, lock.SchedulerLeaderLockKey, options.Lease).Return(leader, nil),
+		s.locker.EXPECT().Refresh(s.ctx, leader, options.Lease).Return(coreerr.DistributedLockNotHeldError),
+		s.locker.EXPECT().Lock(s.ctx, lock.SchedulerLeaderLockKey, options.Lease).Return(nil, coreerr.DistributedLockFailToAcquire),
+	)
+	s.poster.On("PostDue", s.ctx, options.BatchSize).Return(0, nil)
+	_, err := s.scheduler.Tick(s.ctx)
+	s.NoError(err)
+	posted, err := s.scheduler.Tick(s.ctx)
+	s.NoError(err)
+	s.Zero(posted)
+	s.False(s.scheduler.IsLeader(), "the lease was taken by another instance")
+	s.poster.AssertNumberOfCalls(s.T(), "PostDue", 1)
+}
+
+func (s *SchedulerTestSuite) TestTick_PostingError() {
+	leader := &lock.Lock{Key: lock.SchedulerLeaderLockKey, Value: "leader"}
+	s.locker.EXPECT().Lock(s.ctx, lock.SchedulerLeaderLockKey, options.Lease).Return(leader, nil)
+	s.poster.On("PostDue", s.ctx, options.BatchSize).Return(1, coreerr.DatabaseQueryError)
+	posted, err := s.scheduler.Tick(s.ctx)
+	s.ErrorIs(err, coreerr.DatabaseQueryError)
+	s.Equal(1, posted)
+	s.True(s.scheduler.IsLeader(), "a posting error should not give up the leadership")
+}
+
+func (s *SchedulerTestSuite) TestRun_ResignsWhenDone() {
+	ctx, cancel := context.WithCancel(s.ctx)
+	leader := &lock.Lock{Key: lock.SchedulerLeaderLockKey, Value: "leader"}
+	s.locker.EXPECT().Lock(ctx, lock.SchedulerLeaderLockKey, options.Lease).Return(leader, nil)
+	s.locker.EXPECT().Unlock(gomock.Any(), leader).Return(nil)
+	s.poster.On("PostDue", ctx, options.BatchSize).Return(0, nil).Run(func(_ mock.Arguments) { cancel() })
+	s.scheduler.Run(ctx)
+	s.False(s.scheduler.IsLeader(), "the leader lock should be released")
+}
+
+func TestSchedulerTestSuite(t *testing.T) {
+	suite.Run(t, new(SchedulerTestSuite))
+}
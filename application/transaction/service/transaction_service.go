@@ -2,39 +2,93 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"github.com/kiosanim/pismo-code-assessment/application/transaction/dto"
 	"github.com/kiosanim/pismo-code-assessment/application/transaction/mapper"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/adapter"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/cache"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/clock"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/contextutils"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/currency"
 	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/factory"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/lock"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/ratelimit"
 	"github.com/kiosanim/pismo-code-assessment/internal/domains/account"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/ledger"
 	"github.com/kiosanim/pismo-code-assessment/internal/domains/transaction"
+	"strconv"
+	"strings"
 	"time"
 )
 
 const purchaseOperationCode = 4
 
+// batchChunkSize is the number of transactions inserted per statement by CreateBatch
+const batchChunkSize = 500
+
 type TransactionService struct {
 	accountRepository     account.AccountRepository
 	transactionRepository transaction.TransactionRepository
+	unitOfWork            adapter.UnitOfWork
+	isolation             sql.IsolationLevel // Isolation level of the database transaction of Create
 	cache                 cache.CacheRepository
+	accountCache          *cache.EntityCache[account.Account]
+	transactionCache      *cache.EntityCache[transaction.Transaction]
+	operationTypeCache    *cache.EntityCache[transaction.OperationType]
 	componentName         string
 	locker                lock.DistributedLockManager
+	rateLimiter           ratelimit.RateLimiter
+	velocityLimit         ratelimit.Limit
+	rateProvider          currency.RateProvider
+	defaultCurrency       string
+	fees                  map[int]float64 // Ledger fee by operation type
+	backdatingWindow      time.Duration   // How far in the past clients may date their transactions
+	clock                 clock.Clock
 	log                   logger.Logger
 }
 
 func NewTransactionService(factory factory.Factory) *TransactionService {
-	return &TransactionService{
+	cacheRepository := factory.CacheRepository()
+	cacheConfig := factory.Configuration().Cache
+	service := &TransactionService{
 		componentName:         "TransactionService",
 		accountRepository:     factory.AccountRepository(),
 		transactionRepository: factory.TransactionRepository(),
-		cache:                 factory.CacheRepository(),
+		unitOfWork:            factory.UnitOfWork(),
+		cache:                 cacheRepository,
+		accountCache:          cache.NewEntityCache[account.Account](cache.AccountEntity, cacheRepository, cacheConfig.Accounts, factory.Log()).WithCodec(account.NewCacheCodec(factory.FieldCipher())),
+		transactionCache:      cache.NewEntityCache[transaction.Transaction](cache.TransactionEntity, cacheRepository, cacheConfig.Transactions, factory.Log()),
+		operationTypeCache:    cache.NewEntityCache[transaction.OperationType](cache.OperationTypeEntity, cacheRepository, cacheConfig.OperationTypes, factory.Log()),
 		locker:                factory.DistributedLockManager(),
+		rateLimiter:           factory.RateLimiter(),
+		rateProvider:          factory.RateProvider(),
+		defaultCurrency:       currency.DefaultCode,
+		fees:                  make(map[int]float64),
+		backdatingWindow:      time.Duration(factory.Configuration().Transactions.BackdatingWindowMs) * time.Millisecond,
+		clock:                 factory.Clock(),
 		log:                   factory.Log(),
 	}
+	if defaultCurrency, err := currency.Normalize(factory.Configuration().Currency.Default); err == nil {
+		service.defaultCurrency = defaultCurrency
+	}
+	if isolation, err := adapter.ParseIsolationLevel(factory.Configuration().Transactions.IsolationLevel); err == nil {
+		service.isolation = isolation
+	} else {
+		service.log.Warn(service.componentName+".NewTransactionService", "error", err)
+	}
+	for _, fee := range factory.Configuration().Ledger.Fees {
+		if fee.Amount > 0 {
+			service.fees[fee.OperationTypeID] = fee.Amount
+		}
+	}
+	if rateLimitConfig := factory.Configuration().RateLimit; rateLimitConfig.Enabled {
+		service.velocityLimit = ratelimit.Limit{Requests: rateLimitConfig.AccountTransactionsPerMinute, Window: time.Minute}
+	}
+	return service
 }
 
 func (t *TransactionService) Create(ctx context.Context, request dto.CreateTransactionRequest) (*dto.CreateTransactionResponse, error) {
@@ -45,38 +99,295 @@ func (t *TransactionService) Create(ctx context.Context, request dto.CreateTrans
 		t.log.Warn(t.componentName+".Create", "error", err, "x_trace_id", traceID)
 		return nil, err
 	}
-	_, err = t.accountRepository.FindByID(ctx, request.AccountID)
+	acc, err := t.accountCache.Get(ctx, request.AccountID, func(ctx context.Context) (*account.Account, error) {
+		return t.accountRepository.FindByID(ctx, request.AccountID)
+	})
 	if err != nil {
 		t.log.Warn(t.componentName+".Create", "error", err, "x_trace_id", traceID)
 		return nil, err
 	}
+	if acc.Blocked() {
+		err := coreerr.AccountBlockedError
+		t.log.Warn(t.componentName+".Create", "error", err, "accountID", request.AccountID, "x_trace_id", traceID)
+		return nil, err
+	}
+	err = t.checkVelocityLimit(ctx, request.AccountID)
+	if err != nil {
+		t.log.Warn(t.componentName+".Create", "error", err, "accountID", request.AccountID, "x_trace_id", traceID)
+		return nil, err
+	}
 	newTransaction := mapper.CreateDTOToEntity(request)
-	newTransaction.Amount = t.reverseAmountSign(newTransaction) //Change the amount sign for debt operations
-	newTransaction.EventDate = time.Now()
-	lck, err := t.locker.WaitToLockUsingDefaultTimeConfiguration(ctx, lock.TransactionCreationLockKey)
+	err = t.applyExchangeRate(ctx, newTransaction, acc)
 	if err != nil {
 		t.log.Warn(t.componentName+".Create", "error", err, "x_trace_id", traceID)
 		return nil, err
 	}
-	response, err := t.transactionRepository.Save(ctx, newTransaction)
+	newTransaction.Amount = t.reverseAmountSign(newTransaction) //Change the amount sign for debt operations
+	err = t.schedule(newTransaction, t.clock.Now())
 	if err != nil {
 		t.log.Warn(t.componentName+".Create", "error", err, "x_trace_id", traceID)
 		return nil, err
 	}
-	err = t.locker.Unlock(ctx, lck)
+	var response *transaction.Transaction
+	err = t.lockedUnitOfWork(ctx, ".Create", func(ctx context.Context) error {
+		response, err = t.saveChecked(ctx, newTransaction)
+		return err
+	})
 	if err != nil {
 		t.log.Warn(t.componentName+".Create", "error", err, "x_trace_id", traceID)
 		return nil, err
 	}
+	t.transactionCache.Invalidate(ctx, response.TransactionID)
 	response.Amount = t.reverseAmountSign(newTransaction) //Returning value sign only for user presentation
 	return mapper.EntityToResponse(response), nil
 }
 
+// lockedUnitOfWork runs fn in a unit of work holding the transaction creation lock. The lock is taken in the unit of
+// work, so a Postgres advisory lock is held by its transaction, and released after its commit, so a Redis lock covers it.
+func (t *TransactionService) lockedUnitOfWork(ctx context.Context, method string, fn func(ctx context.Context) error) error {
+	traceID := contextutils.GetTraceID(ctx)
+	var lck *lock.Lock
+	unlock := func() {
+		if lck == nil {
+			return
+		}
+		if unlockErr := t.locker.Unlock(ctx, lck); unlockErr != nil {
+			t.log.Warn(t.componentName+method, "error", unlockErr, "x_trace_id", traceID)
+		}
+		lck = nil
+	}
+	defer unlock()
+	return t.unitOfWork.Do(ctx, t.isolation, func(ctx context.Context) error {
+		unlock() // Taken again when the unit of work runs again, a Postgres lock was released by the rolled back run
+		var err error
+		lck, err = t.locker.WaitToLockUsingDefaultTimeConfiguration(ctx, lock.TransactionCreationLockKey)
+		if err != nil {
+			return err
+		}
+		return fn(ctx)
+	})
+}
+
+// saveChecked saves a transaction after reading its account and operation type again from the repositories, which
+// Create checked in the cache to fail fast. Run in a unit of work, the insert is based on the state read with it: the
+// account is read for share, so it is not blocked before the transaction is committed.
+func (t *TransactionService) saveChecked(ctx context.Context, newTransaction *transaction.Transaction) (*transaction.Transaction, error) {
+	acc, err := t.accountRepository.FindByIDForShare(ctx, newTransaction.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	if acc.Blocked() {
+		return nil, coreerr.AccountBlockedError
+	}
+	if _, err = t.transactionRepository.FindOperationTypeByID(ctx, newTransaction.OperationTypeID); err != nil {
+		if errors.Is(err, coreerr.OperationTypeNotFoundError) {
+			return nil, coreerr.TransactionInvalidOperationTypeError
+		}
+		return nil, err
+	}
+	return t.transactionRepository.Save(ctx, newTransaction)
+}
+
+// CreateBatch validates every row with the same rules of Create and inserts the valid ones in chunks, each one in a
+// unit of work checking its accounts again as Create does. Invalid rows, rows of accounts blocked meanwhile and rows of
+// a chunk that failed to be inserted are reported without aborting the batch.
+// The per account velocity limit is not applied to batches.
+func (t *TransactionService) CreateBatch(ctx context.Context, rows []dto.BatchTransactionRow) (*dto.CreateTransactionBatchResponse, error) {
+	traceID := contextutils.GetTraceID(ctx)
+	t.log.Debug(t.componentName+".CreateBatch", "rows", len(rows), "x_trace_id", traceID)
+	response := &dto.CreateTransactionBatchResponse{
+		Total:   len(rows),
+		Results: make([]dto.BatchTransactionResult, len(rows)),
+	}
+	operationTypes := make(map[int]bool)
+	accounts := make(map[int64]batchAccount)
+	var pending []int
+	var newTransactions []*transaction.Transaction
+	for i, row := range rows {
+		response.Results[i].Row = row.Row
+		err := row.Error
+		var acc *account.Account
+		if err == nil {
+			acc, err = t.validateBatchRow(ctx, row.Request, operationTypes, accounts)
+		}
+		newTransaction := mapper.CreateDTOToEntity(row.Request)
+		if err == nil {
+			err = t.applyExchangeRate(ctx, newTransaction, acc)
+		}
+		if err != nil {
+			response.Results[i].Status = dto.BatchRowFailed
+			response.Results[i].Error = err.Error()
+			continue
+		}
+		newTransaction.Amount = t.reverseAmountSign(newTransaction) //Change the amount sign for debt operations
+		if err = t.schedule(newTransaction, t.clock.Now()); err != nil {
+			response.Results[i].Status = dto.BatchRowFailed
+			response.Results[i].Error = err.Error()
+			continue
+		}
+		pending = append(pending, i)
+		newTransactions = append(newTransactions, newTransaction)
+	}
+	for start := 0; start < len(newTransactions); start += batchChunkSize {
+		end := min(start+batchChunkSize, len(newTransactions))
+		saved, rowErrs, err := t.saveBatchChunk(ctx, newTransactions[start:end])
+		for j, i := range pending[start:end] {
+			rowErr := err
+			if rowErr == nil {
+				rowErr = rowErrs[j]
+			}
+			if rowErr != nil {
+				response.Results[i].Status = dto.BatchRowFailed
+				response.Results[i].Error = rowErr.Error()
+				continue
+			}
+			saved[j].Amount = t.reverseAmountSign(saved[j]) //Returning value sign only for user presentation
+			response.Results[i].Status = dto.BatchRowCreated
+			response.Results[i].Transaction = mapper.EntityToDTO(saved[j])
+		}
+	}
+	for _, result := range response.Results {
+		if result.Status == dto.BatchRowCreated {
+			response.Created++
+		} else {
+			response.Failed++
+		}
+	}
+	t.log.Info(t.componentName+".CreateBatch", "total", response.Total, "created", response.Created, "failed", response.Failed, "x_trace_id", traceID)
+	return response, nil
+}
+
+// batchAccount is the result of an account lookup remembered during a batch
+type batchAccount struct {
+	account *account.Account
+	err     error
+}
+
+// validateBatchRow applies the rules of Create, remembering operation types and accounts already checked in the batch
+func (t *TransactionService) validateBatchRow(ctx context.Context, request dto.CreateTransactionRequest, operationTypes map[int]bool, accounts map[int64]batchAccount) (*account.Account, error) {
+	err := t.validateRequest(request, func(operationTypeID int) bool {
+		valid, checked := operationTypes[operationTypeID]
+		if !checked {
+			valid = t.isAValidOperationType(ctx, operationTypeID)
+			operationTypes[operationTypeID] = valid
+		}
+		return valid
+	})
+	if err != nil {
+		return nil, err
+	}
+	lookup, checked := accounts[request.AccountID]
+	if !checked {
+		lookup.account, lookup.err = t.accountCache.Get(ctx, request.AccountID, func(ctx context.Context) (*account.Account, error) {
+			return t.accountRepository.FindByID(ctx, request.AccountID)
+		})
+		accounts[request.AccountID] = lookup
+	}
+	if lookup.err == nil && lookup.account.Blocked() {
+		return nil, coreerr.AccountBlockedError
+	}
+	return lookup.account, lookup.err
+}
+
+// applyExchangeRate converts the amount informed in the transaction original currency, the account currency when not informed,
+// to the account currency
+func (t *TransactionService) applyExchangeRate(ctx context.Context, newTransaction *transaction.Transaction, acc *account.Account) error {
+	accountCurrency := acc.Currency
+	if accountCurrency == "" {
+		accountCurrency = t.defaultCurrency
+	}
+	originalCurrency := accountCurrency
+	if newTransaction.OriginalCurrency != "" {
+		normalized, err := currency.Normalize(newTransaction.OriginalCurrency)
+		if err != nil {
+			return err
+		}
+		originalCurrency = normalized
+	}
+	rate := 1.0
+	if originalCurrency != accountCurrency {
+		var err error
+		rate, err = t.rateProvider.Rate(ctx, originalCurrency, accountCurrency)
+		if err != nil {
+			return err
+		}
+	}
+	newTransaction.Currency = accountCurrency
+	newTransaction.OriginalCurrency = originalCurrency
+	newTransaction.ExchangeRate = rate
+	newTransaction.Amount = currency.Convert(newTransaction.OriginalAmount, rate)
+	if newTransaction.Amount <= 0 {
+		return coreerr.TransactionInvalidAmountNegativeError
+	}
+	return nil
+}
+
+// saveBatchChunk inserts a chunk of transactions in a unit of work, as Create does: the accounts of the chunk are read
+// again for share, so no transaction is inserted for an account blocked after validateBatchRow read it from the cache.
+// Transactions of blocked accounts are left out with their error in rowErrs, the saved ones are at the index of the
+// new ones.
+func (t *TransactionService) saveBatchChunk(ctx context.Context, newTransactions []*transaction.Transaction) ([]*transaction.Transaction, []error, error) {
+	traceID := contextutils.GetTraceID(ctx)
+	var saved []*transaction.Transaction
+	var rowErrs []error
+	err := t.lockedUnitOfWork(ctx, ".saveBatchChunk", func(ctx context.Context) error {
+		saved, rowErrs = make([]*transaction.Transaction, len(newTransactions)), make([]error, len(newTransactions))
+		blocked, err := t.blockedAccounts(ctx, newTransactions)
+		if err != nil {
+			return err
+		}
+		var accepted []*transaction.Transaction
+		var positions []int
+		for j, newTransaction := range newTransactions {
+			if blocked[newTransaction.AccountID] {
+				rowErrs[j] = coreerr.AccountBlockedError
+				continue
+			}
+			accepted = append(accepted, newTransaction)
+			positions = append(positions, j)
+		}
+		if len(accepted) == 0 {
+			return nil
+		}
+		inserted, err := t.transactionRepository.SaveBatch(ctx, accepted)
+		if err != nil {
+			return err
+		}
+		for k, j := range positions {
+			saved[j] = inserted[k]
+		}
+		return nil
+	})
+	if err != nil {
+		t.log.Warn(t.componentName+".saveBatchChunk", "error", err, "rows", len(newTransactions), "x_trace_id", traceID)
+		return nil, nil, err
+	}
+	return saved, rowErrs, nil
+}
+
+// blockedAccounts reads the accounts of the transactions for share, telling which ones are blocked
+func (t *TransactionService) blockedAccounts(ctx context.Context, newTransactions []*transaction.Transaction) (map[int64]bool, error) {
+	blocked := make(map[int64]bool)
+	for _, newTransaction := range newTransactions {
+		if _, read := blocked[newTransaction.AccountID]; read {
+			continue
+		}
+		acc, err := t.accountRepository.FindByIDForShare(ctx, newTransaction.AccountID)
+		if err != nil {
+			return nil, err
+		}
+		blocked[newTransaction.AccountID] = acc.Blocked()
+	}
+	return blocked, nil
+}
+
 func (t *TransactionService) isAValidOperationType(ctx context.Context, operationTypeID int) bool {
 	if operationTypeID <= 0 {
 		return false
 	}
-	output, err := t.transactionRepository.FindOperationTypeByID(ctx, operationTypeID)
+	output, err := t.operationTypeCache.Get(ctx, int64(operationTypeID), func(ctx context.Context) (*transaction.OperationType, error) {
+		return t.transactionRepository.FindOperationTypeByID(ctx, operationTypeID)
+	})
 	if err != nil {
 		return false
 	}
@@ -94,7 +405,9 @@ func (t *TransactionService) FindByID(ctx context.Context, request dto.FindTrans
 		t.log.Warn(t.componentName+".FindByID", "error", err, "x_trace_id", traceID)
 		return nil, err
 	}
-	output, err := t.transactionRepository.FindTransactionByID(ctx, request.TransactionID)
+	output, err := t.transactionCache.Get(ctx, request.TransactionID, func(ctx context.Context) (*transaction.Transaction, error) {
+		return t.transactionRepository.FindTransactionByID(ctx, request.TransactionID)
+	})
 	if err != nil {
 		t.log.Warn(t.componentName+".FindByID", "error", err, "x_trace_id", traceID)
 		return nil, err
@@ -108,19 +421,285 @@ func (t *TransactionService) FindByID(ctx context.Context, request dto.FindTrans
 	return response, nil
 }
 
+// Cancel cancels a pending transaction, which is then never posted
+func (t *TransactionService) Cancel(ctx context.Context, request dto.CancelTransactionRequest) (*dto.CancelTransactionResponse, error) {
+	traceID := contextutils.GetTraceID(ctx)
+	t.log.Debug(t.componentName+".Cancel", "request", request, "x_trace_id", traceID)
+	if request.TransactionID <= 0 {
+		err := coreerr.InvalidParametersError
+		t.log.Warn(t.componentName+".Cancel", "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
+	cancelled, err := t.transactionRepository.Cancel(ctx, request.TransactionID, t.clock.Now())
+	if err != nil {
+		t.log.Warn(t.componentName+".Cancel", "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
+	t.transactionCache.Invalidate(ctx, cancelled.TransactionID)
+	t.log.Info(t.componentName+".Cancel", "transactionID", cancelled.TransactionID, "x_trace_id", traceID)
+	return mapper.EntityToCancelResponse(cancelled), nil
+}
+
+// Reverse creates a posted transaction cancelling out a posted transaction, see transaction.Reverse. A transaction
+// is reversed at most once and reversals are not reversible.
+func (t *TransactionService) Reverse(ctx context.Context, request dto.ReverseTransactionRequest) (*dto.ReverseTransactionResponse, error) {
+	traceID := contextutils.GetTraceID(ctx)
+	t.log.Debug(t.componentName+".Reverse", "request", request, "x_trace_id", traceID)
+	if request.TransactionID <= 0 {
+		err := coreerr.InvalidParametersError
+		t.log.Warn(t.componentName+".Reverse", "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
+	// The creation lock keeps two reversals of the same transaction from being saved concurrently
+	lck, err := t.locker.WaitToLockUsingDefaultTimeConfiguration(ctx, lock.TransactionCreationLockKey)
+	if err != nil {
+		t.log.Warn(t.componentName+".Reverse", "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
+	defer func() {
+		if unlockErr := t.locker.Unlock(ctx, lck); unlockErr != nil {
+			t.log.Warn(t.componentName+".Reverse", "error", unlockErr, "x_trace_id", traceID)
+		}
+	}()
+	original, err := t.transactionRepository.FindTransactionByID(ctx, request.TransactionID)
+	if err != nil {
+		t.log.Warn(t.componentName+".Reverse", "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
+	if original.Status != transaction.StatusPosted || original.IsReversal() {
+		err := coreerr.TransactionNotReversibleError
+		t.log.Warn(t.componentName+".Reverse", "error", err, "transactionID", original.TransactionID, "x_trace_id", traceID)
+		return nil, err
+	}
+	reversals, err := t.transactionRepository.Search(ctx, transaction.TransactionSearch{
+		TransactionFilter: transaction.TransactionFilter{AccountID: original.AccountID},
+		Metadata:          map[string]string{transaction.ReversalOfMetadataKey: strconv.FormatInt(original.TransactionID, 10)},
+		Limit:             1,
+	})
+	if err != nil {
+		t.log.Warn(t.componentName+".Reverse", "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
+	if len(reversals) > 0 {
+		err := coreerr.TransactionAlreadyReversedError
+		t.log.Warn(t.componentName+".Reverse", "error", err, "transactionID", original.TransactionID, "x_trace_id", traceID)
+		return nil, err
+	}
+	reversal, err := t.transactionRepository.Save(ctx, transaction.Reverse(original, t.clock.Now()))
+	if err != nil {
+		t.log.Warn(t.componentName+".Reverse", "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
+	t.transactionCache.Invalidate(ctx, reversal.TransactionID)
+	t.log.Info(t.componentName+".Reverse", "transactionID", original.TransactionID, "reversalID", reversal.TransactionID, "x_trace_id", traceID)
+	return mapper.EntityToReverseResponse(reversal), nil
+}
+
+// PostDue posts up to limit pending transactions whose effective date has come, returning how many were posted.
+// Transactions cancelled in the meantime are skipped.
+func (t *TransactionService) PostDue(ctx context.Context, limit int64) (int, error) {
+	traceID := contextutils.GetTraceID(ctx)
+	if limit <= 0 {
+		err := coreerr.InvalidParametersError
+		t.log.Warn(t.componentName+".PostDue", "error", err, "x_trace_id", traceID)
+		return 0, err
+	}
+	due, err := t.transactionRepository.FindDue(ctx, t.clock.Now(), limit)
+	if err != nil {
+		t.log.Warn(t.componentName+".PostDue", "error", err, "x_trace_id", traceID)
+		return 0, err
+	}
+	posted := 0
+	for _, dueTransaction := range due {
+		dueTransaction.Status = transaction.StatusPosted
+		dueTransaction.PostedAt = t.clock.Now()
+		if err = t.post(dueTransaction); err != nil {
+			t.log.Warn(t.componentName+".PostDue", "error", err, "transactionID", dueTransaction.TransactionID, "x_trace_id", traceID)
+			continue
+		}
+		_, err = t.transactionRepository.Post(ctx, dueTransaction)
+		if errors.Is(err, coreerr.TransactionNotPendingError) {
+			continue
+		} else if err != nil {
+			t.log.Warn(t.componentName+".PostDue", "error", err, "transactionID", dueTransaction.TransactionID, "x_trace_id", traceID)
+			return posted, err
+		}
+		t.transactionCache.Invalidate(ctx, dueTransaction.TransactionID)
+		posted++
+	}
+	if posted > 0 {
+		t.log.Info(t.componentName+".PostDue", "posted", posted, "x_trace_id", traceID)
+	}
+	return posted, nil
+}
+
+// List returns a page of the transactions matching the request, with amounts signed as stored like FindByID
+func (t *TransactionService) List(ctx context.Context, request dto.ListTransactionsRequest) (*dto.ListTransactionsResponse, error) {
+	traceID := contextutils.GetTraceID(ctx)
+	t.log.Debug(t.componentName+".List", "request", request, "x_trace_id", traceID)
+	if request.AccountID < 0 || request.Cursor < 0 || request.Limit < 0 ||
+		(!request.From.IsZero() && !request.To.IsZero() && !request.To.After(request.From)) {
+		err := coreerr.InvalidParametersError
+		t.log.Warn(t.componentName+".List", "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
+	if request.Limit == 0 {
+		request.Limit = dto.DefaultListLimit
+	}
+	request.Limit = min(request.Limit, dto.MaxListLimit)
+	search := mapper.ListRequestToSearch(request)
+	if !isAValidStatus(search.Status) {
+		err := coreerr.InvalidParametersError
+		t.log.Warn(t.componentName+".List", "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
+	if err := transaction.ValidateDetails("", search.Metadata); err != nil {
+		t.log.Warn(t.componentName+".List", "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
+	transactions, err := t.transactionRepository.Search(ctx, search)
+	if err != nil {
+		t.log.Warn(t.componentName+".List", "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
+	nextCursor := int64(0)
+	if int64(len(transactions)) == request.Limit {
+		nextCursor = transactions[len(transactions)-1].TransactionID
+	}
+	return mapper.EntitiesToListResponse(transactions, request.Limit, nextCursor, request.Location), nil
+}
+
+// Export streams the transactions selected by request to write, presenting the amounts as Create does
+func (t *TransactionService) Export(ctx context.Context, request dto.ExportTransactionsRequest, write func(row dto.ExportTransactionRow) error) error {
+	traceID := contextutils.GetTraceID(ctx)
+	t.log.Debug(t.componentName+".Export", "request", request, "x_trace_id", traceID)
+	if request.AccountID < 0 || (!request.From.IsZero() && !request.To.IsZero() && !request.To.After(request.From)) {
+		err := coreerr.InvalidParametersError
+		t.log.Warn(t.componentName+".Export", "error", err, "x_trace_id", traceID)
+		return err
+	}
+	if request.AccountID > 0 {
+		_, err := t.accountCache.Get(ctx, request.AccountID, func(ctx context.Context) (*account.Account, error) {
+			return t.accountRepository.FindByID(ctx, request.AccountID)
+		})
+		if err != nil {
+			t.log.Warn(t.componentName+".Export", "error", err, "x_trace_id", traceID)
+			return err
+		}
+	}
+	filter := transaction.TransactionFilter{AccountID: request.AccountID, From: request.From, To: request.To}
+	exported := 0
+	err := t.transactionRepository.StreamTransactions(ctx, filter, func(entity *transaction.Transaction) error {
+		direction := dto.DirectionCredit
+		if entity.OperationTypeID != purchaseOperationCode {
+			direction = dto.DirectionDebit
+		}
+		entity.Amount = t.reverseAmountSign(entity) //Returning value sign only for user presentation
+		exported++
+		return write(mapper.EntityToExportRow(entity, direction, request.Location))
+	})
+	if err != nil {
+		t.log.Warn(t.componentName+".Export", "error", err, "exported", exported, "x_trace_id", traceID)
+		return err
+	}
+	t.log.Info(t.componentName+".Export", "accountID", request.AccountID, "exported", exported, "x_trace_id", traceID)
+	return nil
+}
+
 func (t *TransactionService) validateRequestParameters(ctx context.Context, request dto.CreateTransactionRequest) error {
+	return t.validateRequest(request, func(operationTypeID int) bool {
+		return t.isAValidOperationType(ctx, operationTypeID)
+	})
+}
+
+func (t *TransactionService) validateRequest(request dto.CreateTransactionRequest, isAValidOperationType func(operationTypeID int) bool) error {
 	if request.AccountID <= 0 {
 		return coreerr.TransactionInvalidAccountIDError
 	}
 	if request.Amount <= 0 {
 		return coreerr.TransactionInvalidAmountNegativeError
 	}
-	if !t.isAValidOperationType(ctx, request.OperationTypeID) {
+	now := t.clock.Now()
+	if request.EffectiveDate != nil && !request.EffectiveDate.After(now) {
+		return coreerr.TransactionInvalidEffectiveDateError
+	}
+	if request.EventDate != nil && (request.EventDate.After(now) || request.EventDate.Before(now.Add(-t.backdatingWindow))) {
+		return coreerr.TransactionInvalidEventDateError
+	}
+	if request.Currency != "" {
+		if _, err := currency.Normalize(request.Currency); err != nil {
+			return err
+		}
+	}
+	if err := transaction.ValidateMerchant(transaction.NormalizeMerchant(transaction.Merchant{
+		Name:         request.MerchantName,
+		CategoryCode: request.MCC,
+		Country:      request.MerchantCountry,
+	})); err != nil {
+		return err
+	}
+	if err := transaction.ValidateDetails(strings.TrimSpace(request.Description), request.Metadata); err != nil {
+		return err
+	}
+	if !isAValidOperationType(request.OperationTypeID) {
 		return coreerr.TransactionInvalidOperationTypeError
 	}
 	return nil
 }
 
+// checkVelocityLimit limits the number of transactions created for an account per minute.
+// Transactions are allowed when the rate limiter is unavailable.
+func (t *TransactionService) checkVelocityLimit(ctx context.Context, accountID int64) error {
+	if t.velocityLimit.Requests <= 0 {
+		return nil
+	}
+	key := fmt.Sprintf("%s:account:%d", ratelimit.VelocityKeyPrefix, accountID)
+	result, err := t.rateLimiter.Allow(ctx, key, t.velocityLimit)
+	if err != nil {
+		t.log.Warn(t.componentName+".checkVelocityLimit", "error", err, "x_trace_id", contextutils.GetTraceID(ctx))
+		return nil
+	}
+	if !result.Allowed {
+		return &ratelimit.ExceededError{Result: result}
+	}
+	return nil
+}
+
+// schedule sets the status of a transaction created at now: transactions effective in the future stay pending
+// and the others are posted at once. Transactions not backdated by the client happen at now.
+func (t *TransactionService) schedule(newTransaction *transaction.Transaction, now time.Time) error {
+	newTransaction.CreatedAt = now
+	if newTransaction.EventDate.IsZero() {
+		newTransaction.EventDate = now
+	}
+	if newTransaction.EffectiveDate.After(now) {
+		newTransaction.Status = transaction.StatusPending
+		return nil
+	}
+	newTransaction.Status = transaction.StatusPosted
+	newTransaction.EffectiveDate = now
+	newTransaction.PostedAt = now
+	return t.post(newTransaction)
+}
+
+func isAValidStatus(status string) bool {
+	switch status {
+	case "", transaction.StatusPending, transaction.StatusPosted, transaction.StatusCancelled:
+		return true
+	}
+	return false
+}
+
+// post sets the ledger postings of a new transaction, whose amount must already be signed as stored
+func (t *TransactionService) post(newTransaction *transaction.Transaction) error {
+	postings, err := ledger.Post(newTransaction.AccountID, newTransaction.Amount, t.fees[newTransaction.OperationTypeID], newTransaction.Currency)
+	if err != nil {
+		return err
+	}
+	newTransaction.Postings = postings
+	return nil
+}
+
 // reverseAmountSign Change the amount sign for debt operations
 func (t *TransactionService) reverseAmountSign(newTransaction *transaction.Transaction) float64 {
 	if newTransaction.OperationTypeID != purchaseOperationCode {
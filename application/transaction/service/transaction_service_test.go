@@ -2,19 +2,26 @@ package service
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"github.com/kiosanim/pismo-code-assessment/application/transaction/dto"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/cache"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/clock"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/config"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/currency"
 	tranerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/factory"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/lock"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/ratelimit"
 	"github.com/kiosanim/pismo-code-assessment/internal/domains/account"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/ledger"
 	"github.com/kiosanim/pismo-code-assessment/internal/domains/transaction"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/mock/gomock"
 	"testing"
+	"time"
 )
 
 type TransactionServiceTestSuite struct {
@@ -26,6 +33,11 @@ type TransactionServiceTestSuite struct {
 	log                   *logger.LoggerMock
 	factory               *factory.FactoryMock
 	locker                *lock.DistributedLockManagerMock
+	unitOfWork            *recordingUnitOfWork
+	rateLimiter           *ratelimit.RateLimiterMock
+	rateProvider          *currency.RateProviderMock
+	configuration         *config.Configuration
+	clock                 *clock.FakeClock
 }
 
 func (s *TransactionServiceTestSuite) SetupTest() {
@@ -36,6 +48,11 @@ func (s *TransactionServiceTestSuite) SetupTest() {
 	s.cache = cache.NewCacheRepositoryMock(ctrl)
 	s.log = logger.NewLoggerMock(ctrl)
 	s.locker = lock.NewDistributedLockManagerMock(ctrl)
+	s.rateLimiter = ratelimit.NewRateLimiterMock(ctrl)
+	s.rateProvider = currency.NewRateProviderMock(ctrl)
+	s.configuration = &config.Configuration{}
+	s.clock = clock.NewFakeClock(fixedNow)
+	s.unitOfWork = &recordingUnitOfWork{}
 	// Allow any number of these calls
 	s.log.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
 	s.log.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
@@ -47,11 +64,52 @@ func (s *TransactionServiceTestSuite) SetupTest() {
 	s.factory = factory.NewFactoryMock(ctrl)
 	s.factory.EXPECT().TransactionRepository().Return(s.transactionRepository).AnyTimes()
 	s.factory.EXPECT().AccountRepository().Return(s.accountRepository).AnyTimes()
+	s.factory.EXPECT().UnitOfWork().Return(s.unitOfWork).AnyTimes()
 	s.factory.EXPECT().CacheRepository().Return(s.cache).AnyTimes()
 	s.factory.EXPECT().DistributedLockManager().Return(s.locker).AnyTimes()
+	s.factory.EXPECT().RateLimiter().Return(s.rateLimiter).AnyTimes()
+	s.factory.EXPECT().RateProvider().Return(s.rateProvider).AnyTimes()
+	s.factory.EXPECT().Configuration().Return(s.configuration).AnyTimes()
+	s.factory.EXPECT().Clock().Return(s.clock).AnyTimes()
+	s.factory.EXPECT().FieldCipher().Return(nil).AnyTimes() // The account cache is disabled
 	s.factory.EXPECT().Log().Return(s.log).AnyTimes()
 }
 
+// recordingUnitOfWork runs the functions given to Do with the same context, recording the isolation level of each call
+type recordingUnitOfWork struct {
+	isolations []sql.IsolationLevel
+	running    bool // Tells that a function given to Do is running
+	conflicts  int  // Runs of the next functions given to Do that conflict with a concurrent transaction and run again
+}
+
+func (r *recordingUnitOfWork) Do(ctx context.Context, isolation sql.IsolationLevel, fn func(ctx context.Context) error) error {
+	r.isolations = append(r.isolations, isolation)
+	r.running = true
+	defer func() { r.running = false }()
+	for ; r.conflicts > 0; r.conflicts-- {
+		if err := fn(ctx); err != nil {
+			return err
+		}
+	}
+	return fn(ctx)
+}
+
+// mockValidTransactionRequest mocks an existing account and operation type
+func (s *TransactionServiceTestSuite) mockValidTransactionRequest(accountID int64, operationTypeID int) {
+	s.accountRepository.On("FindByID", s.ctx, accountID).Return(
+		&account.Account{AccountID: accountID, DocumentNumber: "12345678900"},
+		nil,
+	)
+	s.accountRepository.On("FindByIDForShare", s.ctx, accountID).Return(
+		&account.Account{AccountID: accountID, DocumentNumber: "12345678900"},
+		nil,
+	)
+	s.transactionRepository.On("FindOperationTypeByID", s.ctx, operationTypeID).Return(
+		&transaction.OperationType{OperationTypeID: int64(operationTypeID), Description: "PAYMENT"},
+		nil,
+	)
+}
+
 func (s *TransactionServiceTestSuite) TestNewTransactionService() {
 	service := NewTransactionService(s.factory)
 	s.NotNil(service, "transaction service should not be nil")
@@ -68,6 +126,10 @@ func (s *TransactionServiceTestSuite) TestCreateTransactionSuccess_Purchase() {
 		&account.Account{AccountID: accountID, DocumentNumber: "12345678900"},
 		nil,
 	)
+	s.accountRepository.On("FindByIDForShare", s.ctx, accountID).Return(
+		&account.Account{AccountID: accountID, DocumentNumber: "12345678900"},
+		nil,
+	)
 	// Mock operation type exists
 	s.transactionRepository.On("FindOperationTypeByID", s.ctx, operationTypeID).Return(
 		&transaction.OperationType{OperationTypeID: int64(operationTypeID), Description: "PURCHASE"},
@@ -77,7 +139,8 @@ func (s *TransactionServiceTestSuite) TestCreateTransactionSuccess_Purchase() {
 	s.transactionRepository.On("Save", s.ctx, mock.MatchedBy(func(tx *transaction.Transaction) bool {
 		return tx.AccountID == accountID &&
 			tx.OperationTypeID == operationTypeID &&
-			tx.Amount == -amount
+			tx.Amount == -amount &&
+			tx.EventDate.Equal(fixedNow)
 	})).Return(
 		&transaction.Transaction{
 			TransactionID:   transactionID,
@@ -113,6 +176,10 @@ func (s *TransactionServiceTestSuite) TestCreateTransactionSuccess_Payment() {
 		&account.Account{AccountID: accountID, DocumentNumber: "12345678900"},
 		nil,
 	)
+	s.accountRepository.On("FindByIDForShare", s.ctx, accountID).Return(
+		&account.Account{AccountID: accountID, DocumentNumber: "12345678900"},
+		nil,
+	)
 	// Mock operation type exists
 	s.transactionRepository.On("FindOperationTypeByID", s.ctx, operationTypeID).Return(
 		&transaction.OperationType{OperationTypeID: int64(operationTypeID), Description: "PAYMENT"},
@@ -255,6 +322,10 @@ func (s *TransactionServiceTestSuite) TestCreateTransactionError_RepositorySaveE
 		&account.Account{AccountID: accountID, DocumentNumber: "12345678900"},
 		nil,
 	)
+	s.accountRepository.On("FindByIDForShare", s.ctx, accountID).Return(
+		&account.Account{AccountID: accountID, DocumentNumber: "12345678900"},
+		nil,
+	)
 	// Mock operation type exists
 	s.transactionRepository.On("FindOperationTypeByID", s.ctx, operationTypeID).Return(
 		&transaction.OperationType{OperationTypeID: int64(operationTypeID), Description: "PURCHASE"},
@@ -265,7 +336,8 @@ func (s *TransactionServiceTestSuite) TestCreateTransactionError_RepositorySaveE
 	s.transactionRepository.On("Save", s.ctx, mock.MatchedBy(func(tx *transaction.Transaction) bool {
 		return tx.AccountID == accountID &&
 			tx.OperationTypeID == operationTypeID &&
-			tx.Amount == -amount
+			tx.Amount == -amount &&
+			tx.EventDate.Equal(fixedNow)
 	})).Return(nil, repositoryError)
 	input := dto.CreateTransactionRequest{
 		AccountID:       accountID,
@@ -278,6 +350,51 @@ func (s *TransactionServiceTestSuite) TestCreateTransactionError_RepositorySaveE
 	s.Nil(output, "output should be nil")
 }
 
+func (s *TransactionServiceTestSuite) TestCreateTransactionError_VelocityLimitExceeded() {
+	s.configuration.RateLimit = config.RateLimitConfig{Enabled: true, AccountTransactionsPerMinute: 10}
+	service := NewTransactionService(s.factory)
+	var accountID int64 = 1
+	s.mockValidTransactionRequest(accountID, transaction.Payment)
+	expectedLimit := ratelimit.Limit{Requests: 10, Window: time.Minute}
+	s.rateLimiter.EXPECT().Allow(s.ctx, "velocity:account:1", expectedLimit).Return(
+		&ratelimit.Result{Allowed: false, Limit: 10, Remaining: 0, RetryAfter: 20 * time.Second},
+		nil,
+	)
+	input := dto.CreateTransactionRequest{
+		AccountID:       accountID,
+		OperationTypeID: transaction.Payment,
+		Amount:          10.0,
+	}
+	output, err := service.Create(s.ctx, input)
+	s.ErrorIs(err, tranerr.RateLimitExceededError)
+	var exceeded *ratelimit.ExceededError
+	s.ErrorAs(err, &exceeded)
+	s.Equal(20*time.Second, exceeded.Result.RetryAfter, "retry after should be propagated")
+	s.Nil(output, "output should be nil")
+	s.transactionRepository.AssertNotCalled(s.T(), "Save", mock.Anything, mock.Anything)
+}
+
+func (s *TransactionServiceTestSuite) TestCreateTransactionSuccess_VelocityLimiterUnavailable() {
+	s.configuration.RateLimit = config.RateLimitConfig{Enabled: true, AccountTransactionsPerMinute: 10}
+	service := NewTransactionService(s.factory)
+	var accountID int64 = 1
+	amount := 10.0
+	s.mockValidTransactionRequest(accountID, transaction.Payment)
+	s.rateLimiter.EXPECT().Allow(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("redis unavailable"))
+	s.transactionRepository.On("Save", s.ctx, mock.Anything).Return(
+		&transaction.Transaction{TransactionID: 1, AccountID: accountID, OperationTypeID: transaction.Payment, Amount: amount},
+		nil,
+	)
+	input := dto.CreateTransactionRequest{
+		AccountID:       accountID,
+		OperationTypeID: transaction.Payment,
+		Amount:          amount,
+	}
+	output, err := service.Create(s.ctx, input)
+	s.NoError(err, "transactions should be allowed when the limiter is unavailable")
+	s.NotNil(output, "output should not be nil")
+}
+
 func (s *TransactionServiceTestSuite) TestIsAValidOperationType_Valid() {
 	service := NewTransactionService(s.factory)
 	operationTypeID := transaction.Purchase
@@ -368,6 +485,634 @@ func (s *TransactionServiceTestSuite) TestReverseAmountSign_Payment() {
 	s.Equal(500.0, tx.Amount, "transaction amount should not be modified for payment")
 }
 
+func (s *TransactionServiceTestSuite) TestCreateBatch_PartialFailure() {
+	service := NewTransactionService(s.factory)
+	s.mockValidTransactionRequest(1, transaction.Payment)
+	s.transactionRepository.On("FindOperationTypeByID", s.ctx, 99).Return(nil, tranerr.OperationTypeNotFoundError)
+	s.accountRepository.On("FindByID", s.ctx, int64(2)).Return(nil, tranerr.AccountNotFoundError)
+	s.transactionRepository.On("SaveBatch", s.ctx, mock.MatchedBy(func(txs []*transaction.Transaction) bool {
+		return len(txs) == 2 && txs[0].Amount == 10 && txs[1].Amount == 20 &&
+			txs[0].EventDate.Equal(fixedNow) && txs[1].EventDate.Equal(fixedNow)
+	})).Return([]*transaction.Transaction{
+		{TransactionID: 1, AccountID: 1, OperationTypeID: transaction.Payment, Amount: 10},
+		{TransactionID: 2, AccountID: 1, OperationTypeID: transaction.Payment, Amount: 20},
+	}, nil)
+	rows := []dto.BatchTransactionRow{
+		{Row: 1, Request: dto.CreateTransactionRequest{AccountID: 1, OperationTypeID: transaction.Payment, Amount: 10}},
+		{Row: 2, Request: dto.CreateTransactionRequest{AccountID: 1, OperationTypeID: transaction.Payment, Amount: -5}},
+		{Row: 3, Request: dto.CreateTransactionRequest{AccountID: 1, OperationTypeID: 99, Amount: 5}},
+		{Row: 4, Request: dto.CreateTransactionRequest{AccountID: 2, OperationTypeID: transaction.Payment, Amount: 5}},
+		{Row: 5, Error: tranerr.InvalidParametersError},
+		{Row: 6, Request: dto.CreateTransactionRequest{AccountID: 1, OperationTypeID: transaction.Payment, Amount: 20}},
+	}
+	output, err := service.CreateBatch(s.ctx, rows)
+	s.NoError(err)
+	s.Equal(6, output.Total)
+	s.Equal(2, output.Created)
+	s.Equal(4, output.Failed)
+	s.Equal(dto.BatchRowCreated, output.Results[0].Status)
+	s.Equal(int64(1), output.Results[0].Transaction.TransactionID)
+	s.Equal(tranerr.TransactionInvalidAmountNegativeError.Error(), output.Results[1].Error)
+	s.Equal(tranerr.TransactionInvalidOperationTypeError.Error(), output.Results[2].Error)
+	s.Equal(tranerr.AccountNotFoundError.Error(), output.Results[3].Error)
+	s.Equal(tranerr.InvalidParametersError.Error(), output.Results[4].Error)
+	s.Equal(6, output.Results[5].Row)
+	s.Equal(20.0, output.Results[5].Transaction.Amount)
+	// accounts and operation types are looked up once per batch
+	s.accountRepository.AssertNumberOfCalls(s.T(), "FindByID", 2)
+	s.transactionRepository.AssertNumberOfCalls(s.T(), "FindOperationTypeByID", 2)
+}
+
+func (s *TransactionServiceTestSuite) TestCreateBatch_AccountBlockedAfterValidation() {
+	service := NewTransactionService(s.factory)
+	s.mockValidTransactionRequest(1, transaction.Payment)
+	s.accountRepository.On("FindByID", s.ctx, int64(2)).Return(&account.Account{AccountID: 2, Status: account.StatusActive}, nil)
+	s.accountRepository.On("FindByIDForShare", s.ctx, int64(2)).Return(&account.Account{AccountID: 2, Status: account.StatusBlocked}, nil).Run(
+		func(mock.Arguments) {
+			s.True(s.unitOfWork.running, "the accounts should be read again in the unit of work")
+		})
+	s.transactionRepository.On("SaveBatch", s.ctx, mock.MatchedBy(func(txs []*transaction.Transaction) bool {
+		return len(txs) == 2 && txs[0].AccountID == 1 && txs[1].AccountID == 1
+	})).Return([]*transaction.Transaction{
+		{TransactionID: 1, AccountID: 1, OperationTypeID: transaction.Payment, Amount: 10},
+		{TransactionID: 2, AccountID: 1, OperationTypeID: transaction.Payment, Amount: 30},
+	}, nil)
+	rows := []dto.BatchTransactionRow{
+		{Row: 1, Request: dto.CreateTransactionRequest{AccountID: 1, OperationTypeID: transaction.Payment, Amount: 10}},
+		{Row: 2, Request: dto.CreateTransactionRequest{AccountID: 2, OperationTypeID: transaction.Payment, Amount: 20}},
+		{Row: 3, Request: dto.CreateTransactionRequest{AccountID: 1, OperationTypeID: transaction.Payment, Amount: 30}},
+	}
+	output, err := service.CreateBatch(s.ctx, rows)
+	s.NoError(err)
+	s.Equal(2, output.Created)
+	s.Equal(1, output.Failed)
+	s.Equal(int64(1), output.Results[0].Transaction.TransactionID)
+	s.Equal(dto.BatchRowFailed, output.Results[1].Status)
+	s.Equal(tranerr.AccountBlockedError.Error(), output.Results[1].Error)
+	s.Equal(int64(2), output.Results[2].Transaction.TransactionID)
+	s.Equal([]sql.IsolationLevel{service.isolation}, s.unitOfWork.isolations, "the chunk should be inserted in a unit of work")
+}
+
+func (s *TransactionServiceTestSuite) TestCreateBatch_InsertionFailureFailsChunkRows() {
+	service := NewTransactionService(s.factory)
+	s.mockValidTransactionRequest(1, transaction.Purchase)
+	s.transactionRepository.On("SaveBatch", s.ctx, mock.Anything).Return(nil, tranerr.DatabaseInsertionError)
+	rows := []dto.BatchTransactionRow{
+		{Row: 1, Request: dto.CreateTransactionRequest{AccountID: 1, OperationTypeID: transaction.Purchase, Amount: 10}},
+		{Row: 2, Request: dto.CreateTransactionRequest{AccountID: 1, OperationTypeID: transaction.Purchase, Amount: 20}},
+	}
+	output, err := service.CreateBatch(s.ctx, rows)
+	s.NoError(err)
+	s.Equal(0, output.Created)
+	s.Equal(2, output.Failed)
+	for _, result := range output.Results {
+		s.Equal(dto.BatchRowFailed, result.Status)
+		s.Equal(tranerr.DatabaseInsertionError.Error(), result.Error)
+	}
+}
+
+func (s *TransactionServiceTestSuite) TestCreateTransaction_ForeignCurrency() {
+	service := NewTransactionService(s.factory)
+	s.accountRepository.On("FindByID", s.ctx, int64(1)).Return(&account.Account{AccountID: 1, Currency: "BRL"}, nil)
+	s.accountRepository.On("FindByIDForShare", s.ctx, int64(1)).Return(&account.Account{AccountID: 1, Currency: "BRL"}, nil)
+	s.transactionRepository.On("FindOperationTypeByID", s.ctx, transaction.Purchase).Return(
+		&transaction.OperationType{OperationTypeID: int64(transaction.Purchase), Description: "PURCHASE"}, nil)
+	s.rateProvider.EXPECT().Rate(gomock.Any(), "USD", "BRL").Return(5.4321, nil)
+	var saved *transaction.Transaction
+	s.transactionRepository.On("Save", s.ctx, mock.MatchedBy(func(tx *transaction.Transaction) bool {
+		copied := *tx
+		saved = &copied
+		return true
+	})).Return(&transaction.Transaction{TransactionID: 1, AccountID: 1, OperationTypeID: transaction.Purchase}, nil)
+	_, err := service.Create(s.ctx, dto.CreateTransactionRequest{AccountID: 1, OperationTypeID: transaction.Purchase, Amount: 10, Currency: "usd"})
+	s.NoError(err)
+	s.Equal(-54.32, saved.Amount, "converted amount should be rounded to cents and stored as a debt")
+	s.Equal("BRL", saved.Currency)
+	s.Equal(10.0, saved.OriginalAmount)
+	s.Equal("USD", saved.OriginalCurrency)
+	s.Equal(5.4321, saved.ExchangeRate)
+}
+
+func (s *TransactionServiceTestSuite) TestCreateTransaction_PostsLedgerEntries() {
+	s.configuration.Ledger.Fees = []config.LedgerFeeConfig{{OperationTypeID: transaction.Withdrawal, Amount: 1.5}}
+	service := NewTransactionService(s.factory)
+	s.mockValidTransactionRequest(1, transaction.Withdrawal)
+	var saved *transaction.Transaction
+	s.transactionRepository.On("Save", s.ctx, mock.MatchedBy(func(tx *transaction.Transaction) bool {
+		copied := *tx
+		saved = &copied
+		return true
+	})).Return(&transaction.Transaction{TransactionID: 1, AccountID: 1, OperationTypeID: transaction.Withdrawal}, nil)
+	_, err := service.Create(s.ctx, dto.CreateTransactionRequest{AccountID: 1, OperationTypeID: transaction.Withdrawal, Amount: 100})
+	s.NoError(err)
+	s.Equal([]ledger.Posting{
+		{LedgerAccount: "customer:1", Direction: ledger.Debit, Amount: 100, Currency: "BRL"},
+		{LedgerAccount: ledger.SettlementAccount, Direction: ledger.Credit, Amount: 100, Currency: "BRL"},
+		{LedgerAccount: "customer:1", Direction: ledger.Debit, Amount: 1.5, Currency: "BRL"},
+		{LedgerAccount: ledger.FeesAccount, Direction: ledger.Credit, Amount: 1.5, Currency: "BRL"},
+	}, saved.Postings, "the withdrawal and its fee should be posted")
+}
+
+func (s *TransactionServiceTestSuite) TestCreateTransaction_PaymentPostingsWithoutFee() {
+	s.configuration.Ledger.Fees = []config.LedgerFeeConfig{{OperationTypeID: transaction.Withdrawal, Amount: 1.5}}
+	service := NewTransactionService(s.factory)
+	s.mockValidTransactionRequest(1, transaction.Payment)
+	var saved *transaction.Transaction
+	s.transactionRepository.On("Save", s.ctx, mock.MatchedBy(func(tx *transaction.Transaction) bool {
+		copied := *tx
+		saved = &copied
+		return true
+	})).Return(&transaction.Transaction{TransactionID: 1, AccountID: 1, OperationTypeID: transaction.Payment}, nil)
+	_, err := service.Create(s.ctx, dto.CreateTransactionRequest{AccountID: 1, OperationTypeID: transaction.Payment, Amount: 60})
+	s.NoError(err)
+	s.Equal([]ledger.Posting{
+		{LedgerAccount: ledger.SettlementAccount, Direction: ledger.Debit, Amount: 60, Currency: "BRL"},
+		{LedgerAccount: "customer:1", Direction: ledger.Credit, Amount: 60, Currency: "BRL"},
+	}, saved.Postings, "payments without a configured fee should only be posted to settlement")
+}
+
+func (s *TransactionServiceTestSuite) TestCreateTransaction_AccountCurrencyByDefault() {
+	service := NewTransactionService(s.factory)
+	s.mockValidTransactionRequest(1, transaction.Payment)
+	var saved *transaction.Transaction
+	s.transactionRepository.On("Save", s.ctx, mock.MatchedBy(func(tx *transaction.Transaction) bool {
+		copied := *tx
+		saved = &copied
+		return true
+	})).Return(&transaction.Transaction{TransactionID: 1, AccountID: 1, OperationTypeID: transaction.Payment}, nil)
+	_, err := service.Create(s.ctx, dto.CreateTransactionRequest{AccountID: 1, OperationTypeID: transaction.Payment, Amount: 10})
+	s.NoError(err)
+	s.Equal(10.0, saved.Amount)
+	s.Equal(currency.DefaultCode, saved.Currency)
+	s.Equal(currency.DefaultCode, saved.OriginalCurrency)
+	s.Equal(1.0, saved.ExchangeRate)
+}
+
+func (s *TransactionServiceTestSuite) TestCreateTransaction_RateNotFound() {
+	service := NewTransactionService(s.factory)
+	s.mockValidTransactionRequest(1, transaction.Payment)
+	s.rateProvider.EXPECT().Rate(gomock.Any(), "JPY", currency.DefaultCode).Return(0.0, tranerr.CurrencyRateNotFoundError)
+	_, err := service.Create(s.ctx, dto.CreateTransactionRequest{AccountID: 1, OperationTypeID: transaction.Payment, Amount: 10, Currency: "JPY"})
+	s.ErrorIs(err, tranerr.CurrencyRateNotFoundError)
+	s.transactionRepository.AssertNotCalled(s.T(), "Save", mock.Anything, mock.Anything)
+}
+
+func (s *TransactionServiceTestSuite) TestCreateTransaction_InvalidCurrency() {
+	service := NewTransactionService(s.factory)
+	_, err := service.Create(s.ctx, dto.CreateTransactionRequest{AccountID: 1, OperationTypeID: transaction.Payment, Amount: 10, Currency: "dollar"})
+	s.ErrorIs(err, tranerr.CurrencyInvalidError)
+}
+
+func (s *TransactionServiceTestSuite) TestExport_PresentsAmountsAsInformed() {
+	service := NewTransactionService(s.factory)
+	eventDate := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	s.accountRepository.On("FindByID", s.ctx, int64(1)).Return(&account.Account{AccountID: 1}, nil)
+	s.transactionRepository.On("StreamTransactions", s.ctx, transaction.TransactionFilter{AccountID: 1, From: eventDate}, mock.Anything).Return(
+		[]*transaction.Transaction{
+			{TransactionID: 1, AccountID: 1, OperationTypeID: transaction.Purchase, Amount: -50, EventDate: eventDate},
+			{TransactionID: 2, AccountID: 1, OperationTypeID: transaction.Payment, Amount: 80, EventDate: eventDate},
+		}, nil)
+	var exported []dto.ExportTransactionRow
+	err := service.Export(s.ctx, dto.ExportTransactionsRequest{AccountID: 1, From: eventDate}, func(row dto.ExportTransactionRow) error {
+		exported = append(exported, row)
+		return nil
+	})
+	s.NoError(err)
+	s.Require().Len(exported, 2)
+	s.Equal(50.0, exported[0].Amount)
+	s.Equal(dto.DirectionDebit, exported[0].Direction)
+	s.Equal(80.0, exported[1].Amount)
+	s.Equal(dto.DirectionCredit, exported[1].Direction)
+}
+
+func (s *TransactionServiceTestSuite) TestExport_AccountNotFound() {
+	service := NewTransactionService(s.factory)
+	s.accountRepository.On("FindByID", s.ctx, int64(9)).Return(nil, tranerr.AccountNotFoundError)
+	err := service.Export(s.ctx, dto.ExportTransactionsRequest{AccountID: 9}, func(row dto.ExportTransactionRow) error {
+		return nil
+	})
+	s.ErrorIs(err, tranerr.AccountNotFoundError)
+	s.transactionRepository.AssertNotCalled(s.T(), "StreamTransactions", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *TransactionServiceTestSuite) TestExport_InvalidInterval() {
+	service := NewTransactionService(s.factory)
+	now := time.Now()
+	err := service.Export(s.ctx, dto.ExportTransactionsRequest{AccountID: 1, From: now, To: now.Add(-time.Hour)}, func(row dto.ExportTransactionRow) error {
+		return nil
+	})
+	s.ErrorIs(err, tranerr.InvalidParametersError)
+}
+
+func (s *TransactionServiceTestSuite) TestExport_WriteErrorStopsStream() {
+	service := NewTransactionService(s.factory)
+	writeErr := errors.New("client gone")
+	s.transactionRepository.On("StreamTransactions", s.ctx, transaction.TransactionFilter{}, mock.Anything).Return(
+		[]*transaction.Transaction{
+			{TransactionID: 1, AccountID: 1, OperationTypeID: transaction.Payment, Amount: 10},
+			{TransactionID: 2, AccountID: 2, OperationTypeID: transaction.Payment, Amount: 10},
+		}, nil)
+	calls := 0
+	err := service.Export(s.ctx, dto.ExportTransactionsRequest{}, func(row dto.ExportTransactionRow) error {
+		calls++
+		return writeErr
+	})
+	s.ErrorIs(err, writeErr)
+	s.Equal(1, calls)
+}
+
+func (s *TransactionServiceTestSuite) TestCreateTransaction_MerchantDetails() {
+	service := NewTransactionService(s.factory)
+	s.mockValidTransactionRequest(1, transaction.Purchase)
+	var saved *transaction.Transaction
+	s.transactionRepository.On("Save", s.ctx, mock.MatchedBy(func(tx *transaction.Transaction) bool {
+		copied := *tx
+		saved = &copied
+		return true
+	})).Return(&transaction.Transaction{TransactionID: 1, AccountID: 1, OperationTypeID: transaction.Purchase}, nil)
+	_, err := service.Create(s.ctx, dto.CreateTransactionRequest{AccountID: 1, OperationTypeID: transaction.Purchase, Amount: 10,
+		MerchantName: "Coffee Shop", MCC: "5814", MerchantCountry: "br", Description: "Espresso", Metadata: map[string]string{"order_id": "42"}})
+	s.NoError(err)
+	s.Equal(transaction.Merchant{Name: "Coffee Shop", CategoryCode: "5814", Country: "BR"}, saved.Merchant)
+	s.Equal("Espresso", saved.Description)
+	s.Equal(map[string]string{"order_id": "42"}, saved.Metadata)
+}
+
+func (s *TransactionServiceTestSuite) TestCreateTransaction_InvalidMerchantDetails() {
+	service := NewTransactionService(s.factory)
+	_, err := service.Create(s.ctx, dto.CreateTransactionRequest{AccountID: 1, OperationTypeID: transaction.Purchase, Amount: 10, MCC: "58"})
+	s.ErrorIs(err, tranerr.TransactionInvalidDetailsError)
+	_, err = service.Create(s.ctx, dto.CreateTransactionRequest{AccountID: 1, OperationTypeID: transaction.Purchase, Amount: 10,
+		Metadata: map[string]string{"order id": "42"}})
+	s.ErrorIs(err, tranerr.TransactionInvalidDetailsError)
+	s.transactionRepository.AssertNotCalled(s.T(), "Save", mock.Anything, mock.Anything)
+}
+
+func (s *TransactionServiceTestSuite) TestList_DefaultLimitAndNextCursor() {
+	service := NewTransactionService(s.factory)
+	transactions := make([]*transaction.Transaction, dto.DefaultListLimit)
+	for i := range transactions {
+		transactions[i] = &transaction.Transaction{TransactionID: int64(i + 11), AccountID: 1, OperationTypeID: transaction.Purchase, Amount: -10}
+	}
+	s.transactionRepository.On("Search", s.ctx, transaction.TransactionSearch{
+		TransactionFilter:    transaction.TransactionFilter{AccountID: 1},
+		MerchantName:         "coffee",
+		MerchantCategoryCode: "5814",
+		MerchantCountry:      "BR",
+		Metadata:             map[string]string{"order_id": "42"},
+		Cursor:               10,
+		Limit:                dto.DefaultListLimit,
+	}).Return(transactions, nil)
+	response, err := service.List(s.ctx, dto.ListTransactionsRequest{AccountID: 1, MerchantName: " coffee ", MCC: "5814", MerchantCountry: "br",
+		Metadata: map[string]string{"order_id": "42"}, Cursor: 10})
+	s.NoError(err)
+	s.Len(response.Transactions, dto.DefaultListLimit)
+	s.Equal(-10.0, response.Transactions[0].Amount, "amounts should be signed as stored")
+	s.Equal(int64(60), response.Cursor)
+	s.Equal(int64(dto.DefaultListLimit), response.Limit)
+}
+
+func (s *TransactionServiceTestSuite) TestList_LastPage() {
+	service := NewTransactionService(s.factory)
+	s.transactionRepository.On("Search", s.ctx, transaction.TransactionSearch{Limit: dto.MaxListLimit}).Return(
+		[]*transaction.Transaction{{TransactionID: 1}}, nil)
+	response, err := service.List(s.ctx, dto.ListTransactionsRequest{Limit: 10000})
+	s.NoError(err)
+	s.Len(response.Transactions, 1)
+	s.Zero(response.Cursor, "the last page should have no next cursor")
+	s.Equal(int64(dto.MaxListLimit), response.Limit, "limit should be capped")
+}
+
+func (s *TransactionServiceTestSuite) TestList_InvalidParameters() {
+	service := NewTransactionService(s.factory)
+	_, err := service.List(s.ctx, dto.ListTransactionsRequest{Cursor: -1})
+	s.ErrorIs(err, tranerr.InvalidParametersError)
+	_, err = service.List(s.ctx, dto.ListTransactionsRequest{Metadata: map[string]string{"bad key": "1"}})
+	s.ErrorIs(err, tranerr.TransactionInvalidDetailsError)
+	s.transactionRepository.AssertNotCalled(s.T(), "Search", mock.Anything, mock.Anything)
+}
+
 func TestTransactionServiceTestSuite(t *testing.T) {
 	suite.Run(t, new(TransactionServiceTestSuite))
 }
+
+// fixedNow is the time of the fake clock of the tests
+var fixedNow = time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+func (s *TransactionServiceTestSuite) TestCreateTransaction_PostedAtOnce() {
+	service := NewTransactionService(s.factory)
+	s.mockValidTransactionRequest(1, transaction.Purchase)
+	var saved *transaction.Transaction
+	s.transactionRepository.On("Save", s.ctx, mock.MatchedBy(func(tx *transaction.Transaction) bool {
+		copied := *tx
+		saved = &copied
+		return true
+	})).Return(&transaction.Transaction{TransactionID: 1, AccountID: 1, OperationTypeID: transaction.Purchase, Status: transaction.StatusPosted}, nil)
+	output, err := service.Create(s.ctx, dto.CreateTransactionRequest{AccountID: 1, OperationTypeID: transaction.Purchase, Amount: 10})
+	s.NoError(err)
+	s.Equal(transaction.StatusPosted, saved.Status)
+	s.Equal(fixedNow, saved.EventDate)
+	s.Equal(fixedNow, saved.EffectiveDate)
+	s.Equal(fixedNow, saved.PostedAt)
+	s.True(ledger.Balanced(saved.Postings), "posted transactions should carry their postings")
+	s.Equal(transaction.StatusPosted, output.Transaction.Status)
+}
+
+func (s *TransactionServiceTestSuite) TestCreateTransaction_ScheduledIsPending() {
+	service := NewTransactionService(s.factory)
+	s.mockValidTransactionRequest(1, transaction.Purchase)
+	effectiveDate := fixedNow.Add(48 * time.Hour).In(time.FixedZone("BRT", -3*60*60))
+	var saved *transaction.Transaction
+	s.transactionRepository.On("Save", s.ctx, mock.MatchedBy(func(tx *transaction.Transaction) bool {
+		copied := *tx
+		saved = &copied
+		return true
+	})).Return(&transaction.Transaction{TransactionID: 1, AccountID: 1, OperationTypeID: transaction.Purchase, Status: transaction.StatusPending}, nil)
+	output, err := service.Create(s.ctx, dto.CreateTransactionRequest{AccountID: 1, OperationTypeID: transaction.Purchase, Amount: 10, EffectiveDate: &effectiveDate})
+	s.NoError(err)
+	s.Equal(transaction.StatusPending, saved.Status)
+	s.Equal(fixedNow, saved.EventDate)
+	s.Equal(fixedNow.Add(48*time.Hour), saved.EffectiveDate, "the effective date should be kept in UTC")
+	s.True(saved.PostedAt.IsZero())
+	s.Empty(saved.Postings, "pending transactions are posted to the ledger by the scheduler")
+	s.Equal(transaction.StatusPending, output.Transaction.Status)
+}
+
+func (s *TransactionServiceTestSuite) TestCreateTransaction_PastEffectiveDate() {
+	service := NewTransactionService(s.factory)
+	for _, effectiveDate := range []time.Time{fixedNow, fixedNow.Add(-time.Minute)} {
+		_, err := service.Create(s.ctx, dto.CreateTransactionRequest{AccountID: 1, OperationTypeID: transaction.Purchase, Amount: 10, EffectiveDate: &effectiveDate})
+		s.ErrorIs(err, tranerr.TransactionInvalidEffectiveDateError)
+	}
+	s.transactionRepository.AssertNotCalled(s.T(), "Save", mock.Anything, mock.Anything)
+}
+
+func (s *TransactionServiceTestSuite) TestCancel() {
+	service := NewTransactionService(s.factory)
+	s.transactionRepository.On("Cancel", s.ctx, int64(5), fixedNow).Return(
+		&transaction.Transaction{TransactionID: 5, AccountID: 1, Status: transaction.StatusCancelled, CancelledAt: fixedNow}, nil)
+	output, err := service.Cancel(s.ctx, dto.CancelTransactionRequest{TransactionID: 5})
+	s.NoError(err)
+	s.Equal(transaction.StatusCancelled, output.Transaction.Status)
+}
+
+func (s *TransactionServiceTestSuite) TestCancel_NotPending() {
+	service := NewTransactionService(s.factory)
+	s.transactionRepository.On("Cancel", s.ctx, int64(5), fixedNow).Return(nil, tranerr.TransactionNotPendingError)
+	output, err := service.Cancel(s.ctx, dto.CancelTransactionRequest{TransactionID: 5})
+	s.ErrorIs(err, tranerr.TransactionNotPendingError)
+	s.Nil(output)
+}
+
+func (s *TransactionServiceTestSuite) TestCreateTransactionError_AccountBlocked() {
+	service := NewTransactionService(s.factory)
+	s.accountRepository.On("FindByID", s.ctx, int64(1)).Return(
+		&account.Account{AccountID: 1, DocumentNumber: "12345678900", Status: account.StatusBlocked}, nil)
+	s.transactionRepository.On("FindOperationTypeByID", s.ctx, transaction.Payment).Return(
+		&transaction.OperationType{OperationTypeID: int64(transaction.Payment), Description: "PAYMENT"}, nil)
+	output, err := service.Create(s.ctx, dto.CreateTransactionRequest{AccountID: 1, OperationTypeID: transaction.Payment, Amount: 10})
+	s.ErrorIs(err, tranerr.AccountBlockedError)
+	s.Nil(output)
+	s.transactionRepository.AssertNotCalled(s.T(), "Save", mock.Anything, mock.Anything)
+}
+
+func (s *TransactionServiceTestSuite) TestCreateTransaction_UnitOfWork() {
+	s.configuration.Transactions.IsolationLevel = "serializable"
+	service := NewTransactionService(s.factory)
+	s.mockValidTransactionRequest(1, transaction.Payment)
+	s.transactionRepository.On("Save", s.ctx, mock.Anything).Return(&transaction.Transaction{TransactionID: 10, AccountID: 1}, nil)
+	_, err := service.Create(s.ctx, dto.CreateTransactionRequest{AccountID: 1, OperationTypeID: transaction.Payment, Amount: 10})
+	s.NoError(err)
+	s.Equal([]sql.IsolationLevel{sql.LevelSerializable}, s.unitOfWork.isolations, "the transaction should be saved in one unit of work")
+}
+
+func (s *TransactionServiceTestSuite) TestCreateTransactionError_AccountBlockedInUnitOfWork() {
+	service := NewTransactionService(s.factory)
+	s.accountRepository.On("FindByID", s.ctx, int64(1)).Return(&account.Account{AccountID: 1, Status: account.StatusActive}, nil)
+	s.accountRepository.On("FindByIDForShare", s.ctx, int64(1)).Return(&account.Account{AccountID: 1, Status: account.StatusBlocked}, nil)
+	s.transactionRepository.On("FindOperationTypeByID", s.ctx, transaction.Payment).Return(
+		&transaction.OperationType{OperationTypeID: int64(transaction.Payment), Description: "PAYMENT"}, nil)
+	_, err := service.Create(s.ctx, dto.CreateTransactionRequest{AccountID: 1, OperationTypeID: transaction.Payment, Amount: 10})
+	s.ErrorIs(err, tranerr.AccountBlockedError, "an account blocked after it was first read should be found in the unit of work")
+	s.transactionRepository.AssertNumberOfCalls(s.T(), "Save", 0)
+}
+
+func (s *TransactionServiceTestSuite) TestCreateTransactionError_UnlocksWhenUnitOfWorkFails() {
+	service := NewTransactionService(s.factory)
+	locker := lock.NewDistributedLockManagerMock(gomock.NewController(s.T()))
+	creationLock := &lock.Lock{Key: lock.TransactionCreationLockKey}
+	locker.EXPECT().WaitToLockUsingDefaultTimeConfiguration(gomock.Any(), lock.TransactionCreationLockKey).DoAndReturn(
+		func(ctx context.Context, key string) (*lock.Lock, error) {
+			s.True(s.unitOfWork.running, "the creation lock should be taken in the unit of work")
+			return creationLock, nil
+		})
+	locker.EXPECT().Unlock(gomock.Any(), creationLock).DoAndReturn(func(ctx context.Context, acquiredLock *lock.Lock) error {
+		s.False(s.unitOfWork.running, "the creation lock should be released once the unit of work ended")
+		return nil
+	}).Times(1)
+	service.locker = locker
+	s.accountRepository.On("FindByID", s.ctx, int64(1)).Return(&account.Account{AccountID: 1, Status: account.StatusActive}, nil)
+	s.accountRepository.On("FindByIDForShare", s.ctx, int64(1)).Return(&account.Account{AccountID: 1, Status: account.StatusBlocked}, nil)
+	s.transactionRepository.On("FindOperationTypeByID", s.ctx, transaction.Payment).Return(
+		&transaction.OperationType{OperationTypeID: int64(transaction.Payment), Description: "PAYMENT"}, nil)
+	_, err := service.Create(s.ctx, dto.CreateTransactionRequest{AccountID: 1, OperationTypeID: transaction.Payment, Amount: 10})
+	s.ErrorIs(err, tranerr.AccountBlockedError)
+}
+
+func (s *TransactionServiceTestSuite) TestCreateTransaction_RelocksWhenUnitOfWorkRunsAgain() {
+	service := NewTransactionService(s.factory)
+	locker := lock.NewDistributedLockManagerMock(gomock.NewController(s.T()))
+	conflictingLock := &lock.Lock{Key: lock.TransactionCreationLockKey, Value: "conflicting"}
+	creationLock := &lock.Lock{Key: lock.TransactionCreationLockKey, Value: "creation"}
+	gomock.InOrder(
+		locker.EXPECT().WaitToLockUsingDefaultTimeConfiguration(gomock.Any(), lock.TransactionCreationLockKey).Return(conflictingLock, nil),
+		locker.EXPECT().Unlock(gomock.Any(), conflictingLock).DoAndReturn(func(ctx context.Context, acquiredLock *lock.Lock) error {
+			s.True(s.unitOfWork.running, "the lock of the conflicting run should be released before running again")
+			return nil
+		}),
+		locker.EXPECT().WaitToLockUsingDefaultTimeConfiguration(gomock.Any(), lock.TransactionCreationLockKey).Return(creationLock, nil),
+		locker.EXPECT().Unlock(gomock.Any(), creationLock).Return(nil),
+	)
+	service.locker = locker
+	s.unitOfWork.conflicts = 1
+	s.mockValidTransactionRequest(1, transaction.Payment)
+	s.transactionRepository.On("Save", s.ctx, mock.Anything).Return(&transaction.Transaction{TransactionID: 1, AccountID: 1}, nil)
+	_, err := service.Create(s.ctx, dto.CreateTransactionRequest{AccountID: 1, OperationTypeID: transaction.Payment, Amount: 10})
+	s.NoError(err)
+	s.transactionRepository.AssertNumberOfCalls(s.T(), "Save", 2)
+}
+
+func (s *TransactionServiceTestSuite) TestNewTransactionService_InvalidIsolationLevel() {
+	s.configuration.Transactions.IsolationLevel = "snapshot"
+	service := NewTransactionService(s.factory)
+	s.Equal(sql.LevelDefault, service.isolation, "an unknown isolation level should fall back to the database default")
+}
+
+func (s *TransactionServiceTestSuite) TestReverse() {
+	service := NewTransactionService(s.factory)
+	original := &transaction.Transaction{TransactionID: 5, AccountID: 1, OperationTypeID: transaction.Withdrawal, Amount: -20,
+		Currency: "BRL", OriginalAmount: 20, OriginalCurrency: "BRL", ExchangeRate: 1, Status: transaction.StatusPosted,
+		Postings: []ledger.Posting{
+			{EntryID: 1, TransactionID: 5, LedgerAccount: "customer:1", Direction: ledger.Debit, Amount: 20, Currency: "BRL"},
+			{EntryID: 2, TransactionID: 5, LedgerAccount: ledger.SettlementAccount, Direction: ledger.Credit, Amount: 20, Currency: "BRL"},
+		}}
+	s.transactionRepository.On("FindTransactionByID", s.ctx, int64(5)).Return(original, nil)
+	s.transactionRepository.On("Search", s.ctx, mock.MatchedBy(func(search transaction.TransactionSearch) bool {
+		return search.AccountID == 1 && search.Metadata[transaction.ReversalOfMetadataKey] == "5"
+	})).Return([]*transaction.Transaction{}, nil)
+	s.transactionRepository.On("Save", s.ctx, mock.MatchedBy(func(tx *transaction.Transaction) bool {
+		return tx.AccountID == 1 && tx.Amount == 20 && tx.Status == transaction.StatusPosted && tx.PostedAt.Equal(fixedNow) &&
+			tx.Metadata[transaction.ReversalOfMetadataKey] == "5" && ledger.Balanced(tx.Postings) &&
+			tx.Postings[0].Direction == ledger.Credit && tx.Postings[0].LedgerAccount == "customer:1"
+	})).Return(&transaction.Transaction{TransactionID: 6, AccountID: 1, OperationTypeID: transaction.Withdrawal, Amount: 20,
+		Status: transaction.StatusPosted, Metadata: map[string]string{transaction.ReversalOfMetadataKey: "5"}}, nil)
+	output, err := service.Reverse(s.ctx, dto.ReverseTransactionRequest{TransactionID: 5})
+	s.NoError(err)
+	s.Equal(int64(6), output.Transaction.TransactionID)
+	s.Equal(20.0, output.Transaction.Amount, "the reversal amount should have the opposite sign")
+}
+
+func (s *TransactionServiceTestSuite) TestReverse_AlreadyReversed() {
+	service := NewTransactionService(s.factory)
+	s.transactionRepository.On("FindTransactionByID", s.ctx, int64(5)).Return(
+		&transaction.Transaction{TransactionID: 5, AccountID: 1, Amount: -20, Status: transaction.StatusPosted}, nil)
+	s.transactionRepository.On("Search", s.ctx, mock.Anything).Return([]*transaction.Transaction{{TransactionID: 6}}, nil)
+	output, err := service.Reverse(s.ctx, dto.ReverseTransactionRequest{TransactionID: 5})
+	s.ErrorIs(err, tranerr.TransactionAlreadyReversedError)
+	s.Nil(output)
+	s.transactionRepository.AssertNotCalled(s.T(), "Save", mock.Anything, mock.Anything)
+}
+
+func (s *TransactionServiceTestSuite) TestReverse_NotReversible() {
+	service := NewTransactionService(s.factory)
+	s.transactionRepository.On("FindTransactionByID", s.ctx, int64(5)).Return(
+		&transaction.Transaction{TransactionID: 5, AccountID: 1, Amount: -20, Status: transaction.StatusPending}, nil)
+	s.transactionRepository.On("FindTransactionByID", s.ctx, int64(6)).Return(
+		&transaction.Transaction{TransactionID: 6, AccountID: 1, Amount: 20, Status: transaction.StatusPosted,
+			Metadata: map[string]string{transaction.ReversalOfMetadataKey: "4"}}, nil)
+	for _, transactionID := range []int64{5, 6} {
+		output, err := service.Reverse(s.ctx, dto.ReverseTransactionRequest{TransactionID: transactionID})
+		s.ErrorIs(err, tranerr.TransactionNotReversibleError, "transaction %d should not be reversible", transactionID)
+		s.Nil(output)
+	}
+	s.transactionRepository.AssertNotCalled(s.T(), "Save", mock.Anything, mock.Anything)
+}
+
+func (s *TransactionServiceTestSuite) TestReverse_NotFound() {
+	service := NewTransactionService(s.factory)
+	s.transactionRepository.On("FindTransactionByID", s.ctx, int64(5)).Return(nil, tranerr.TransactionNotFoundError)
+	output, err := service.Reverse(s.ctx, dto.ReverseTransactionRequest{TransactionID: 5})
+	s.ErrorIs(err, tranerr.TransactionNotFoundError)
+	s.Nil(output)
+}
+
+func (s *TransactionServiceTestSuite) TestPostDue() {
+	s.configuration.Ledger.Fees = []config.LedgerFeeConfig{{OperationTypeID: transaction.Withdrawal, Amount: 1}}
+	service := NewTransactionService(s.factory)
+	due := []*transaction.Transaction{
+		{TransactionID: 1, AccountID: 1, OperationTypeID: transaction.Withdrawal, Amount: -20, Currency: "BRL", Status: transaction.StatusPending},
+		{TransactionID: 2, AccountID: 1, OperationTypeID: transaction.Purchase, Amount: -30, Currency: "BRL", Status: transaction.StatusPending},
+	}
+	s.clock.Advance(time.Hour)
+	s.transactionRepository.On("FindDue", s.ctx, fixedNow.Add(time.Hour), int64(10)).Return(due, nil)
+	var posted *transaction.Transaction
+	s.transactionRepository.On("Post", s.ctx, mock.MatchedBy(func(tx *transaction.Transaction) bool {
+		return tx.TransactionID == 1
+	})).Run(func(args mock.Arguments) {
+		posted = args.Get(1).(*transaction.Transaction)
+	}).Return(due[0], nil)
+	s.transactionRepository.On("Post", s.ctx, mock.MatchedBy(func(tx *transaction.Transaction) bool {
+		return tx.TransactionID == 2
+	})).Return(nil, tranerr.TransactionNotPendingError)
+	count, err := service.PostDue(s.ctx, 10)
+	s.NoError(err)
+	s.Equal(1, count, "transactions cancelled in the meantime should be skipped")
+	s.Equal(transaction.StatusPosted, posted.Status)
+	s.Equal(fixedNow.Add(time.Hour), posted.PostedAt, "transactions should be posted at the time of the clock")
+	s.Len(posted.Postings, 4, "the configured fee should be posted with the transaction")
+	s.True(ledger.Balanced(posted.Postings))
+}
+
+func (s *TransactionServiceTestSuite) TestPostDue_RepositoryError() {
+	service := NewTransactionService(s.factory)
+	s.transactionRepository.On("FindDue", s.ctx, fixedNow, int64(10)).Return(nil, tranerr.DatabaseQueryError)
+	count, err := service.PostDue(s.ctx, 10)
+	s.ErrorIs(err, tranerr.DatabaseQueryError)
+	s.Zero(count)
+}
+
+func (s *TransactionServiceTestSuite) TestList_InvalidStatus() {
+	service := NewTransactionService(s.factory)
+	_, err := service.List(s.ctx, dto.ListTransactionsRequest{Status: "SETTLED"})
+	s.ErrorIs(err, tranerr.InvalidParametersError)
+	s.transactionRepository.AssertNotCalled(s.T(), "Search", mock.Anything, mock.Anything)
+}
+
+func (s *TransactionServiceTestSuite) TestCreateTransaction_BackdatedEventDate() {
+	s.configuration.Transactions.BackdatingWindowMs = (72 * time.Hour).Milliseconds()
+	service := NewTransactionService(s.factory)
+	s.mockValidTransactionRequest(1, transaction.Purchase)
+	eventDate := fixedNow.Add(-48 * time.Hour).In(time.FixedZone("BRT", -3*60*60))
+	var saved *transaction.Transaction
+	s.transactionRepository.On("Save", s.ctx, mock.MatchedBy(func(tx *transaction.Transaction) bool {
+		copied := *tx
+		saved = &copied
+		return true
+	})).Return(&transaction.Transaction{TransactionID: 1, AccountID: 1, OperationTypeID: transaction.Purchase,
+		Status: transaction.StatusPosted, EventDate: fixedNow.Add(-48 * time.Hour)}, nil)
+	output, err := service.Create(s.ctx, dto.CreateTransactionRequest{AccountID: 1, OperationTypeID: transaction.Purchase, Amount: 10, EventDate: &eventDate})
+	s.NoError(err)
+	s.Equal(fixedNow.Add(-48*time.Hour), saved.EventDate, "the event date should be kept in UTC")
+	s.Equal(fixedNow, saved.CreatedAt)
+	s.Equal(fixedNow, saved.PostedAt, "backdated transactions are posted when recorded")
+	s.Equal(fixedNow.Add(-48*time.Hour), output.Transaction.EventDate)
+	s.Equal(time.UTC, output.Transaction.EventDate.Location())
+}
+
+func (s *TransactionServiceTestSuite) TestCreateTransaction_InvalidEventDate() {
+	s.configuration.Transactions.BackdatingWindowMs = (72 * time.Hour).Milliseconds()
+	service := NewTransactionService(s.factory)
+	for _, eventDate := range []time.Time{fixedNow.Add(time.Minute), fixedNow.Add(-73 * time.Hour)} {
+		_, err := service.Create(s.ctx, dto.CreateTransactionRequest{AccountID: 1, OperationTypeID: transaction.Purchase, Amount: 10, EventDate: &eventDate})
+		s.ErrorIs(err, tranerr.TransactionInvalidEventDateError)
+	}
+	s.transactionRepository.AssertNotCalled(s.T(), "Save", mock.Anything, mock.Anything)
+}
+
+func (s *TransactionServiceTestSuite) TestCreateTransaction_BackdatingDisabled() {
+	service := NewTransactionService(s.factory)
+	eventDate := fixedNow.Add(-time.Second)
+	_, err := service.Create(s.ctx, dto.CreateTransactionRequest{AccountID: 1, OperationTypeID: transaction.Purchase, Amount: 10, EventDate: &eventDate})
+	s.ErrorIs(err, tranerr.TransactionInvalidEventDateError, "a zero window should reject client event dates")
+}
+
+func (s *TransactionServiceTestSuite) TestList_LocalDates() {
+	service := NewTransactionService(s.factory)
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	s.Require().NoError(err)
+	lateEvening := time.Date(2026, 10, 19, 20, 0, 0, 0, time.UTC)
+	s.transactionRepository.On("Search", s.ctx, transaction.TransactionSearch{Limit: dto.DefaultListLimit}).Return(
+		[]*transaction.Transaction{{TransactionID: 1, EventDate: lateEvening}}, nil)
+	response, err := service.List(s.ctx, dto.ListTransactionsRequest{Location: tokyo})
+	s.NoError(err)
+	s.Require().Len(response.Transactions, 1)
+	s.Equal(lateEvening, response.Transactions[0].EventDate)
+	s.Equal("2026-10-20", response.Transactions[0].LocalDate, "the local date should be the day in Tokyo")
+}
+
+func (s *TransactionServiceTestSuite) TestExport_LocalDates() {
+	service := NewTransactionService(s.factory)
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	s.Require().NoError(err)
+	earlyMorning := time.Date(2026, 10, 19, 1, 0, 0, 0, time.UTC)
+	s.transactionRepository.On("StreamTransactions", s.ctx, transaction.TransactionFilter{}, mock.Anything).Return(
+		[]*transaction.Transaction{{TransactionID: 1, OperationTypeID: transaction.Payment, Amount: 10, EventDate: earlyMorning}}, nil)
+	var exported []dto.ExportTransactionRow
+	err = service.Export(s.ctx, dto.ExportTransactionsRequest{Location: saoPaulo}, func(row dto.ExportTransactionRow) error {
+		exported = append(exported, row)
+		return nil
+	})
+	s.NoError(err)
+	s.Require().Len(exported, 1)
+	s.Equal("2026-10-18", exported[0].LocalDate, "the local date should be the day in Sao Paulo")
+}
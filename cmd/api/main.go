@@ -27,10 +27,23 @@ import (
 
 // @host      localhost:8080
 // @BasePath  /
+
+// @securityDefinitions.apikey  BearerAuth
+// @in                          header
+// @name                        Authorization
+// @description                 JWT bearer token: "Bearer <token>"
+
+// @securityDefinitions.apikey  ApiKeyAuth
+// @in                          header
+// @name                        X-API-Key
+// @description                 API key for machine clients
 func main() {
-	ctx := context.Background()
+	ctx, stopWatching := context.WithCancel(context.Background())
+	defer stopWatching()
 	appFactory := factory.NewAppFactory(ctx)
 	sLogger := logger.NewSlogLogger(ctx, appFactory.Configuration())
+	appFactory.ConfigWatcher().Register(sLogger)
+	appFactory.ConfigWatcher().Watch(ctx)
 	r := router.NewRouterFactory(appFactory, sLogger)
 	server := &http.Server{
 		Addr:    appFactory.Configuration().App.Address,
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/kiosanim/pismo-code-assessment/application/transaction/dto"
+	"github.com/kiosanim/pismo-code-assessment/application/transaction/exporter"
+	"github.com/kiosanim/pismo-code-assessment/application/transaction/service"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/factory"
+	"github.com/spf13/cobra"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+type options struct {
+	accountID int64
+	format    string
+	from      string
+	to        string
+	tz        string
+	output    string
+}
+
+// run streams the selected transactions to the output file
+func run(ctx context.Context, opts options) error {
+	location, err := exporter.ParseLocation(opts.tz)
+	if err != nil {
+		return err
+	}
+	from, err := exporter.ParseBoundIn(opts.from, false, location)
+	if err != nil {
+		return err
+	}
+	to, err := exporter.ParseBoundIn(opts.to, true, location)
+	if err != nil {
+		return err
+	}
+	appFactory := factory.NewAppFactory(ctx)
+	metadata := exporter.Metadata{AccountID: opts.accountID, From: from, To: to, GeneratedAt: appFactory.Clock().Now(), Location: location}
+	output, err := openOutput(opts.output)
+	if err != nil {
+		return err
+	}
+	defer output.Close()
+	writer, err := exporter.NewWriter(opts.format, output, metadata)
+	if err != nil {
+		return err
+	}
+	transactionService := service.NewTransactionService(&appFactory)
+	request := dto.ExportTransactionsRequest{AccountID: opts.accountID, From: from, To: to, Location: location}
+	if err = transactionService.Export(ctx, request, writer.Write); err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+func openOutput(file string) (io.WriteCloser, error) {
+	if file == "" || file == "-" {
+		return stdout{os.Stdout}, nil
+	}
+	return os.Create(file)
+}
+
+// stdout keeps the standard output open when the export is closed
+type stdout struct {
+	io.Writer
+}
+
+func (stdout) Close() error {
+	return nil
+}
+
+func main() {
+	var opts options
+	rootCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export transactions as CSV, NDJSON or OFX",
+		Long: "Export the transactions of an account, or of every account when --account is not informed, streaming them from the database.\n" +
+			"--from and --to accept RFC 3339 or YYYY-MM-DD; --from is inclusive and --to is exclusive, dates used as --to include the whole day.\n" +
+			"Dates are days of the --tz time zone, which also sets the local_date column and the OFX dates.",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+			defer stop()
+			if err := run(ctx, opts); err != nil {
+				return err
+			}
+			if opts.output != "" && opts.output != "-" {
+				fmt.Fprintf(os.Stderr, "exported to %s\n", opts.output)
+			}
+			return nil
+		},
+	}
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.Flags().Int64Var(&opts.accountID, "account", 0, "account ID, every account when not informed")
+	rootCmd.Flags().StringVar(&opts.format, "format", exporter.FormatCSV, "csv, ndjson or ofx")
+	rootCmd.Flags().StringVar(&opts.from, "from", "", "first event date")
+	rootCmd.Flags().StringVar(&opts.to, "to", "", "last event date")
+	rootCmd.Flags().StringVar(&opts.tz, "tz", "", "IANA time zone of the statement days, e.g. America/Sao_Paulo (default UTC)")
+	rootCmd.Flags().StringVarP(&opts.output, "output", "o", "-", "output file, - writes to stdout")
+	if err := rootCmd.Execute(); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/kiosanim/pismo-code-assessment/application/transaction/dto"
+	"github.com/kiosanim/pismo-code-assessment/application/transaction/importer"
+	"github.com/kiosanim/pismo-code-assessment/application/transaction/service"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/factory"
+	"github.com/spf13/cobra"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+type options struct {
+	file      string
+	format    string
+	chunkSize int
+	report    string
+}
+
+// run imports the file chunk by chunk, writing one NDJSON result per row to the report
+func run(ctx context.Context, opts options) (*dto.CreateTransactionBatchResponse, error) {
+	input, err := openInput(opts.file)
+	if err != nil {
+		return nil, err
+	}
+	defer input.Close()
+	format := opts.format
+	if format == "" {
+		format = strings.TrimPrefix(filepath.Ext(opts.file), ".")
+	}
+	reader, err := importer.NewReader(format, input)
+	if err != nil {
+		return nil, err
+	}
+	output, err := openReport(opts.report)
+	if err != nil {
+		return nil, err
+	}
+	defer output.Close()
+	appFactory := factory.NewAppFactory(ctx)
+	transactionService := service.NewTransactionService(&appFactory)
+	encoder := json.NewEncoder(output)
+	summary := &dto.CreateTransactionBatchResponse{}
+	rows := make([]dto.BatchTransactionRow, 0, opts.chunkSize)
+	flush := func() error {
+		if len(rows) == 0 {
+			return nil
+		}
+		response, err := transactionService.CreateBatch(ctx, rows)
+		if err != nil {
+			return err
+		}
+		summary.Total += response.Total
+		summary.Created += response.Created
+		summary.Failed += response.Failed
+		for _, result := range response.Results {
+			if err = encoder.Encode(result); err != nil {
+				return err
+			}
+		}
+		rows = rows[:0]
+		return nil
+	}
+	for {
+		row, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return summary, err
+		}
+		rows = append(rows, row)
+		if len(rows) == opts.chunkSize {
+			if err = flush(); err != nil {
+				return summary, err
+			}
+		}
+	}
+	return summary, flush()
+}
+
+func openInput(file string) (io.ReadCloser, error) {
+	if file == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	return os.Open(file)
+}
+
+func openReport(file string) (io.WriteCloser, error) {
+	if file == "" || file == "-" {
+		return stdout{os.Stdout}, nil
+	}
+	return os.Create(file)
+}
+
+// stdout keeps the standard output open when the report is closed
+type stdout struct {
+	io.Writer
+}
+
+func (stdout) Close() error {
+	return nil
+}
+
+func main() {
+	var opts options
+	rootCmd := &cobra.Command{
+		Use:   "import",
+		Short: "Import transactions in bulk from a CSV or NDJSON file",
+		Long: "Import transactions in bulk from a CSV file (header with account_id, operation_type_id and amount) or an NDJSON file.\n" +
+			"Every row is validated like a transaction created through the API and reported on its own as NDJSON.\n" +
+			"The command exits with status 1 when a row failed.",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.chunkSize <= 0 {
+				return fmt.Errorf("--chunk-size must be greater than zero")
+			}
+			ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+			defer stop()
+			summary, err := run(ctx, opts)
+			if summary != nil {
+				fmt.Fprintf(os.Stderr, "total: %d, created: %d, failed: %d\n", summary.Total, summary.Created, summary.Failed)
+			}
+			if err != nil {
+				return err
+			}
+			if summary.Failed > 0 {
+				os.Exit(1)
+			}
+			return nil
+		},
+	}
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.Flags().StringVarP(&opts.file, "file", "f", "", "file to import, - reads from stdin")
+	rootCmd.Flags().StringVar(&opts.format, "format", "", "csv or ndjson (default: file extension)")
+	rootCmd.Flags().IntVar(&opts.chunkSize, "chunk-size", 1000, "rows sent to the service at a time")
+	rootCmd.Flags().StringVarP(&opts.report, "report", "r", "-", "file receiving the per row NDJSON report, - writes to stdout")
+	_ = rootCmd.MarkFlagRequired("file")
+	if err := rootCmd.Execute(); err != nil {
+		log.Fatal(err)
+	}
+}
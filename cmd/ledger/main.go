@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/ledger"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/factory"
+	"github.com/spf13/cobra"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// checkResult is the JSON output of the check command
+type checkResult struct {
+	Valid                    bool             `json:"valid"`
+	Totals                   []currencyTotals `json:"totals"`
+	UnbalancedTransactionIDs []int64          `json:"unbalanced_transaction_ids"`
+	UnpostedTransactionIDs   []int64          `json:"unposted_transaction_ids"`
+}
+
+type currencyTotals struct {
+	Currency string  `json:"currency"`
+	Debits   float64 `json:"debits"`
+	Credits  float64 `json:"credits"`
+}
+
+func check(output *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "check",
+		Short: "Verify that every posting of the ledger sums to zero",
+		Long: "Verify that the debits and credits of every currency and of every transaction are equal and that every transaction was posted.\n" +
+			"Up to 100 transactions are listed for each violation. The command exits with status 1 when an invariant is violated.",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if *output != "text" && *output != "json" {
+				return fmt.Errorf("--output must be text or json")
+			}
+			ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+			defer stop()
+			appFactory := factory.NewAppFactory(ctx)
+			report, err := appFactory.LedgerRepository().CheckInvariants(ctx)
+			if err != nil {
+				return err
+			}
+			if *output == "json" {
+				err = writeJSON(os.Stdout, report)
+			} else {
+				err = writeText(os.Stdout, report)
+			}
+			if err != nil {
+				return err
+			}
+			if !report.Valid() {
+				os.Exit(1)
+			}
+			return nil
+		},
+	}
+}
+
+func writeJSON(w io.Writer, report *ledger.InvariantReport) error {
+	result := checkResult{
+		Valid:                    report.Valid(),
+		Totals:                   make([]currencyTotals, 0, len(report.Totals)),
+		UnbalancedTransactionIDs: append([]int64{}, report.UnbalancedTransactionIDs...),
+		UnpostedTransactionIDs:   append([]int64{}, report.UnpostedTransactionIDs...),
+	}
+	for _, totals := range report.Totals {
+		result.Totals = append(result.Totals, currencyTotals{Currency: totals.Currency, Debits: totals.Debits, Credits: totals.Credits})
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
+
+func writeText(w io.Writer, report *ledger.InvariantReport) error {
+	fmt.Fprintf(w, "%-8s %20s %20s\n", "CURRENCY", "DEBITS", "CREDITS")
+	for _, totals := range report.Totals {
+		fmt.Fprintf(w, "%-8s %20.2f %20.2f\n", totals.Currency, totals.Debits, totals.Credits)
+	}
+	if len(report.UnbalancedTransactionIDs) > 0 {
+		fmt.Fprintf(w, "unbalanced transactions: %v\n", report.UnbalancedTransactionIDs)
+	}
+	if len(report.UnpostedTransactionIDs) > 0 {
+		fmt.Fprintf(w, "transactions without postings: %v\n", report.UnpostedTransactionIDs)
+	}
+	status := "ledger is balanced"
+	if !report.Valid() {
+		status = "ledger invariants violated"
+	}
+	_, err := fmt.Fprintln(w, status)
+	return err
+}
+
+func main() {
+	var output string
+	rootCmd := &cobra.Command{
+		Use:   "ledger",
+		Short: "Ledger maintenance commands",
+	}
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.PersistentFlags().StringVarP(&output, "output", "o", "text", "text or json")
+	rootCmd.AddCommand(check(&output))
+	if err := rootCmd.Execute(); err != nil {
+		log.Fatal(err)
+	}
+}
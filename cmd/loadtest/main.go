@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/kiosanim/pismo-code-assessment/application/loadtest"
+	"github.com/kiosanim/pismo-code-assessment/interfaces/http/router"
+	infraconfig "github.com/kiosanim/pismo-code-assessment/internal/infra/config"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/factory"
+	infralogger "github.com/kiosanim/pismo-code-assessment/internal/infra/logger"
+	"github.com/spf13/cobra"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+type options struct {
+	url         string
+	concurrency int
+	duration    time.Duration
+	requests    int
+	accounts    int
+	mix         string
+	seed        uint64
+	headers     []string
+	output      string
+	logLevel    string
+	timeout     time.Duration
+}
+
+// run drives the API at --url, or an in-process API on the memory repositories when it is empty
+func run(ctx context.Context, opts options) (*loadtest.Report, error) {
+	if opts.output != outputText && opts.output != outputJSON {
+		return nil, fmt.Errorf("unknown output %q, expected %s or %s", opts.output, outputText, outputJSON)
+	}
+	mix, err := loadtest.ParseMix(opts.mix)
+	if err != nil {
+		return nil, err
+	}
+	headers := make(map[string]string, len(opts.headers))
+	for _, header := range opts.headers {
+		name, value, found := strings.Cut(header, ":")
+		if !found || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid header %q, expected NAME: VALUE", header)
+		}
+		headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	client := &http.Client{Timeout: opts.timeout}
+	baseURL := opts.url
+	if baseURL == "" {
+		server, err := startInProcessAPI(ctx, opts.logLevel)
+		if err != nil {
+			return nil, err
+		}
+		defer server.Close()
+		baseURL = server.URL
+		client.Transport = &http.Transport{MaxIdleConnsPerHost: opts.concurrency}
+	}
+	runner, err := loadtest.NewRunner(client, baseURL, headers, loadtest.Options{
+		Concurrency: opts.concurrency,
+		Duration:    opts.duration,
+		Requests:    opts.requests,
+		Accounts:    opts.accounts,
+		Mix:         mix,
+		Seed:        opts.seed,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return runner.Run(ctx)
+}
+
+// startInProcessAPI serves the API with the configuration of the working directory on the memory repositories and
+// an in-process Redis, without authentication
+func startInProcessAPI(ctx context.Context, logLevel string) (*httptest.Server, error) {
+	path, _ := os.Getwd()
+	configuration, err := infraconfig.LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	configuration.App.LogLevel = logLevel
+	sLogger := infralogger.NewSlogLogger(ctx, configuration)
+	memoryFactory, err := factory.NewMemoryFactory(ctx, configuration, sLogger)
+	if err != nil {
+		return nil, err
+	}
+	gin.SetMode(gin.ReleaseMode)
+	gin.DefaultWriter = io.Discard
+	return httptest.NewServer(router.NewRouter(memoryFactory, nil, sLogger)), nil
+}
+
+func main() {
+	var opts options
+	rootCmd := &cobra.Command{
+		Use:   "loadtest",
+		Short: "Load test the API",
+		Long: "Create --accounts accounts, then send requests from --concurrency clients until --duration ends or --requests are sent.\n" +
+			"Without --url the requests go to an in-process API on memory repositories and an in-process Redis, configured by config.yaml.\n" +
+			"The report has the throughput, the p50, p95 and p99 latencies of each operation and the failed requests by error code.",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+			defer stop()
+			report, err := run(ctx, opts)
+			if err != nil {
+				return err
+			}
+			if opts.output == outputJSON {
+				return report.WriteJSON(os.Stdout)
+			}
+			return report.WriteText(os.Stdout)
+		},
+	}
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.Flags().StringVar(&opts.url, "url", "", "base URL of the API, empty runs the API in-process")
+	rootCmd.Flags().IntVarP(&opts.concurrency, "concurrency", "c", 10, "clients sending requests at the same time")
+	rootCmd.Flags().DurationVarP(&opts.duration, "duration", "d", 10*time.Second, "duration of the test, 0 sends --requests requests")
+	rootCmd.Flags().IntVarP(&opts.requests, "requests", "n", 0, "requests sent, 0 sends requests until --duration ends")
+	rootCmd.Flags().IntVar(&opts.accounts, "accounts", 10, "accounts created before the test")
+	rootCmd.Flags().StringVar(&opts.mix, "mix", loadtest.DefaultMix, "weight of each operation, OPERATION=WEIGHT pairs of create_account, create_transaction, get_account, get_transaction and list_transactions")
+	rootCmd.Flags().Uint64Var(&opts.seed, "seed", 1, "seed of the document numbers, operations and amounts")
+	rootCmd.Flags().StringArrayVarP(&opts.headers, "header", "H", nil, "header sent with every request, e.g. \"Authorization: Bearer TOKEN\"")
+	rootCmd.Flags().StringVarP(&opts.output, "output", "o", outputText, "report format, text or json")
+	rootCmd.Flags().StringVar(&opts.logLevel, "log-level", "error", "log level of the in-process API")
+	rootCmd.Flags().DurationVar(&opts.timeout, "timeout", 10*time.Second, "timeout of each request")
+	if err := rootCmd.Execute(); err != nil {
+		log.Fatal(err)
+	}
+}
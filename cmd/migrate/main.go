@@ -5,65 +5,305 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/adapter"
+	coreconfig "github.com/kiosanim/pismo-code-assessment/internal/core/config"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/clock"
 	"github.com/kiosanim/pismo-code-assessment/internal/infra/config"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/database/connection"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/database/migrations"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/database/repository"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/logger"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/pii"
 	_ "github.com/lib/pq"
 	"github.com/pressly/goose/v3"
 	"github.com/spf13/cobra"
 	"log"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 )
 
-const MigrationsFolder = "internal/infra/database/migrations"
+// dryRun prints the SQL of the migrations a command would run instead of running them
+var dryRun bool
+
+// source is the migrations of database.driver
+var source migrations.Source
 
 func up(ctx context.Context, db *sql.DB) *cobra.Command {
 	upCmd := &cobra.Command{
 		Use:   "up",
-		Short: "Run up migration",
+		Short: "Apply every pending migration",
 		Run: func(cmd *cobra.Command, args []string) {
-			err := goose.UpContext(ctx, db, MigrationsFolder)
-			if err != nil {
-				log.Fatalf("failed to run migrations: %v\n", err)
+			if dryRun {
+				last, err := migrations.Last(source.FS)
+				exitOnError(err)
+				exitOnError(printPlan(ctx, db, last))
+				return
 			}
+			exitOnError(goose.UpContext(ctx, db, migrations.Dir))
 		},
 	}
 	return upCmd
 }
 
-func destroy(ctx context.Context, db *sql.DB) *cobra.Command {
+func upTo(ctx context.Context, db *sql.DB) *cobra.Command {
+	upToCmd := &cobra.Command{
+		Use:   "up-to VERSION",
+		Short: "Apply the pending migrations up to VERSION",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			version, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil || version <= 0 {
+				log.Fatalf("invalid version %q\n", args[0])
+			}
+			if dryRun {
+				exitOnError(printPlan(ctx, db, version))
+				return
+			}
+			exitOnError(goose.UpToContext(ctx, db, migrations.Dir, version))
+		},
+	}
+	return upToCmd
+}
+
+func down(ctx context.Context, db *sql.DB) *cobra.Command {
 	downCmd := &cobra.Command{
 		Use:   "down",
-		Short: "Rollback All Migrations",
+		Short: "Roll back the last applied migration",
 		Run: func(cmd *cobra.Command, args []string) {
-			reader := bufio.NewReader(os.Stdin)
-			fmt.Print("⚠️ Are you sure you want to destroy every table? (y/N): ")
-			answer, _ := reader.ReadString('\n')
-			answer = strings.TrimSpace(strings.ToLower(answer))
-			if answer != "y" && answer != "yes" {
-				fmt.Println("❌ Aborted.")
+			if dryRun {
+				current, err := currentVersion(ctx, db)
+				exitOnError(err)
+				previous, err := migrations.Previous(source.FS, current)
+				exitOnError(err)
+				exitOnError(printPlan(ctx, db, previous))
 				return
 			}
-			err := goose.DownToContext(ctx, db, MigrationsFolder, 0)
-			if err != nil {
-				log.Fatalf("failed to run migrations: %v\n", err)
-			}
+			exitOnError(goose.DownContext(ctx, db, migrations.Dir))
 		},
 	}
 	return downCmd
 }
 
+func redo(ctx context.Context, db *sql.DB) *cobra.Command {
+	redoCmd := &cobra.Command{
+		Use:   "redo",
+		Short: "Roll back and apply again the last applied migration",
+		Run: func(cmd *cobra.Command, args []string) {
+			if dryRun {
+				current, err := currentVersion(ctx, db)
+				exitOnError(err)
+				previous, err := migrations.Previous(source.FS, current)
+				exitOnError(err)
+				exitOnError(printSteps(current, previous))
+				if current > 0 {
+					exitOnError(printSteps(previous, current))
+				}
+				return
+			}
+			exitOnError(goose.RedoContext(ctx, db, migrations.Dir))
+		},
+	}
+	return redoCmd
+}
+
+func destroy(ctx context.Context, db *sql.DB) *cobra.Command {
+	var yes bool
+	destroyCmd := &cobra.Command{
+		Use:   "destroy",
+		Short: "Roll back every migration, dropping every table",
+		Run: func(cmd *cobra.Command, args []string) {
+			if dryRun {
+				exitOnError(printPlan(ctx, db, 0))
+				return
+			}
+			if !yes && !confirm("⚠️ Are you sure you want to destroy every table? (y/N): ") {
+				fmt.Println("❌ Aborted.")
+				return
+			}
+			exitOnError(goose.DownToContext(ctx, db, migrations.Dir, 0))
+		},
+	}
+	destroyCmd.Flags().BoolVarP(&yes, "yes", "y", false, "destroy without asking for confirmation")
+	return destroyCmd
+}
+
 func status(ctx context.Context, db *sql.DB) *cobra.Command {
 	statusCmd := &cobra.Command{
 		Use:   "status",
 		Short: "Status Migrations",
 		Run: func(cmd *cobra.Command, args []string) {
-			err := goose.StatusContext(ctx, db, MigrationsFolder)
+			exitOnError(goose.StatusContext(ctx, db, migrations.Dir))
+		},
+	}
+	return statusCmd
+}
+
+func version(ctx context.Context, db *sql.DB) *cobra.Command {
+	versionCmd := &cobra.Command{
+		Use:   "version",
+		Short: "Print the version of the last applied migration",
+		Run: func(cmd *cobra.Command, args []string) {
+			current, err := currentVersion(ctx, db)
+			exitOnError(err)
+			last, err := migrations.Last(source.FS)
+			exitOnError(err)
+			fmt.Printf("database version %d, latest migration %d\n", current, last)
+		},
+	}
+	return versionCmd
+}
+
+// create writes an empty migration numbered after the existing ones to the source directory, to be embedded by the
+// next build
+func create() *cobra.Command {
+	var dir string
+	createCmd := &cobra.Command{
+		Use:   "create NAME",
+		Short: "Create an empty SQL migration",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			fileName, err := migrations.FileName(os.DirFS(dir), args[0])
+			exitOnError(err)
+			file := filepath.Join(dir, fileName)
+			f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+			exitOnError(err)
+			defer f.Close()
+			_, err = f.WriteString(migrations.Template)
+			exitOnError(err)
+			fmt.Println("Created", file)
+		},
+	}
+	createCmd.Flags().StringVar(&dir, "dir", source.SourceDir, "directory of the migration files")
+	return createCmd
+}
+
+// currentVersion returns the version of the last applied migration without creating the goose version table,
+// zero when the table does not exist
+func currentVersion(ctx context.Context, db *sql.DB) (int64, error) {
+	var current sql.NullInt64
+	err := db.QueryRowContext(ctx, "SELECT max(version_id) FROM "+goose.TableName()+" WHERE is_applied").Scan(&current)
+	if err != nil {
+		exists := "SELECT to_regclass($1) IS NOT NULL"
+		if source.Dialect == goose.DialectSQLite3 {
+			exists = "SELECT count(*) > 0 FROM sqlite_master WHERE type = 'table' AND name = $1"
+		}
+		var tableExists bool
+		if existsErr := db.QueryRowContext(ctx, exists, goose.TableName()).Scan(&tableExists); existsErr == nil && !tableExists {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return current.Int64, nil
+}
+
+// printPlan prints the SQL that moves the database from its version to target
+func printPlan(ctx context.Context, db *sql.DB, target int64) error {
+	current, err := currentVersion(ctx, db)
+	if err != nil {
+		return err
+	}
+	return printSteps(current, target)
+}
+
+func printSteps(current, target int64) error {
+	steps, err := migrations.Plan(source.FS, current, target)
+	if err != nil {
+		return err
+	}
+	if len(steps) == 0 {
+		fmt.Printf("-- nothing to run, database version %d\n", current)
+		return nil
+	}
+	for _, step := range steps {
+		statements, err := migrations.SQL(source.FS, step)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("-- %s (%s)\n%s\n\n", step.Source, step.Direction, statements)
+	}
+	return nil
+}
+
+func confirm(question string) bool {
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Print(question)
+	answer, _ := reader.ReadString('\n')
+	answer = strings.TrimSpace(strings.ToLower(answer))
+	return answer == "y" || answer == "yes"
+}
+
+func exitOnError(err error) {
+	if err != nil {
+		log.Fatalf("failed to run migrations: %v\n", err)
+	}
+}
+
+// documentReport prints the accounts normalized or merged by the document type migration
+func documentReport(ctx context.Context, db *sql.DB) *cobra.Command {
+	reportCmd := &cobra.Command{
+		Use:   "document-report",
+		Short: "Report the accounts normalized or merged by the document type migration",
+		Run: func(cmd *cobra.Command, args []string) {
+			rows, err := db.QueryContext(ctx, "SELECT account_id, kept_account_id, original_document_number, document_number, cardinality(moved_transaction_ids) FROM account_document_migration_report ORDER BY kept_account_id, account_id")
 			if err != nil {
-				log.Fatalf("failed to run migrations: %v\n", err)
+				log.Fatalf("failed to read the document migration report: %v\n", err)
+			}
+			defer rows.Close()
+			normalized, merged := 0, 0
+			fmt.Printf("%-12s %-12s %-24s %-24s %s\n", "ACCOUNT", "KEPT", "ORIGINAL", "NORMALIZED", "MOVED TRANSACTIONS")
+			for rows.Next() {
+				var accountID, keptAccountID, movedTransactions int64
+				var originalDocumentNumber, documentNumber string
+				if err = rows.Scan(&accountID, &keptAccountID, &originalDocumentNumber, &documentNumber, &movedTransactions); err != nil {
+					log.Fatalf("failed to read the document migration report: %v\n", err)
+				}
+				if accountID == keptAccountID {
+					normalized++
+				} else {
+					merged++
+				}
+				fmt.Printf("%-12d %-12d %-24s %-24s %d\n", accountID, keptAccountID, originalDocumentNumber, documentNumber, movedTransactions)
+			}
+			if err = rows.Err(); err != nil {
+				log.Fatalf("failed to read the document migration report: %v\n", err)
 			}
+			fmt.Printf("%d accounts normalized, %d duplicated accounts merged\n", normalized, merged)
 		},
 	}
-	return statusCmd
+	return reportCmd
+}
+
+// protectDocuments encrypts and indexes the document numbers stored before the encryption migration
+func protectDocuments(ctx context.Context, db *sql.DB, cfg *coreconfig.Configuration) *cobra.Command {
+	var batchSize int
+	protectCmd := &cobra.Command{
+		Use:   "protect-documents",
+		Short: "Encrypt and index the document numbers stored in plaintext",
+		Run: func(cmd *cobra.Command, args []string) {
+			if batchSize <= 0 {
+				log.Fatalf("batch size must be greater than zero\n")
+			}
+			fieldCipher, err := pii.NewAESFieldCipher(cfg.PII)
+			if err != nil {
+				log.Fatalf("failed to create the field cipher: %v\n", err)
+			}
+			accountRepository := repository.NewAccountPostgresRepository(
+				&adapter.DatabaseConnectionData{Db: db},
+				fieldCipher,
+				clock.NewSystemClock(),
+				logger.NewSlogLogger(ctx, cfg),
+			)
+			protected, err := accountRepository.ProtectDocumentNumbers(ctx, batchSize)
+			if err != nil {
+				log.Fatalf("failed to protect document numbers after %d accounts: %v\n", protected, err)
+			}
+			fmt.Printf("%d document numbers encrypted\n", protected)
+		},
+	}
+	protectCmd.Flags().IntVar(&batchSize, "batch-size", 500, "accounts encrypted per database transaction")
+	return protectCmd
 }
 
 func main() {
@@ -73,21 +313,36 @@ func main() {
 		log.Fatal(err)
 	}
 	ctx := context.Background()
-	db, err := sql.Open("postgres", cfg.Database.URL)
-
+	source, err = migrations.ForDriver(cfg.Database.Driver)
 	if err != nil {
 		log.Fatal(err)
 	}
-	goose.SetDialect("postgres")
-	fmt.Println("Connected to database")
-	migrationsFolder := "internal/infra/database/migrations"
-	fmt.Println("Migrations folder is", migrationsFolder)
+	var db *sql.DB
+	if source.Dialect == goose.DialectSQLite3 {
+		db, err = connection.OpenSQLite(cfg.Database.URL)
+	} else {
+		db, err = sql.Open("postgres", cfg.Database.URL)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	goose.SetBaseFS(source.FS)
+	if err = goose.SetDialect(string(source.Dialect)); err != nil {
+		log.Fatal(err)
+	}
 	var rootCmd = &cobra.Command{
 		Use:   "migration-tool",
 		Short: "Simple CLI to up/down migrations",
+		Long:  "Apply and roll back the migrations embedded in the binary. --dry-run prints the SQL of up, up-to, down, redo and destroy instead of running it.",
 	}
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
-	rootCmd.AddCommand(up(ctx, db), destroy(ctx, db), status(ctx, db))
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "print the SQL of the migrations instead of running them")
+	rootCmd.AddCommand(up(ctx, db), upTo(ctx, db), down(ctx, db), redo(ctx, db), destroy(ctx, db), status(ctx, db), version(ctx, db),
+		create())
+	// The document migrations only ran on Postgres, SQLite databases start with encrypted document numbers
+	if source.Dialect == goose.DialectPostgres {
+		rootCmd.AddCommand(documentReport(ctx, db), protectDocuments(ctx, db, cfg))
+	}
 	err = rootCmd.Execute()
 	if err != nil {
 		fmt.Println(err)
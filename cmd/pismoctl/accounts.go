@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/kiosanim/pismo-code-assessment/application/account/dto"
+	"github.com/kiosanim/pismo-code-assessment/application/account/service"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/factory"
+	"github.com/spf13/cobra"
+	"strconv"
+)
+
+var accountHeaders = []string{"ACCOUNT", "DOCUMENT TYPE", "DOCUMENT NUMBER", "CURRENCY", "STATUS", "VERSION"}
+
+func accountRow(accountID int64, documentType, documentNumber, currency, status string, version int64) []string {
+	return []string{strconv.FormatInt(accountID, 10), documentType, documentNumber, currency, status, strconv.FormatInt(version, 10)}
+}
+
+func accountCommand(opts *options) *cobra.Command {
+	accountCmd := &cobra.Command{
+		Use:   "account",
+		Short: "Get, create, list and block accounts",
+	}
+	accountCmd.AddCommand(accountGet(opts), accountCreate(opts), accountList(opts), accountBlock(opts))
+	return accountCmd
+}
+
+func accountGet(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get ACCOUNT_ID",
+		Short: "Show an account",
+		Args:  cobra.ExactArgs(1),
+		RunE: runWithApp(opts, func(ctx context.Context, appFactory *factory.AppFactory, out *printer, args []string) error {
+			accountID, err := parseID(args[0])
+			if err != nil {
+				return err
+			}
+			response, err := service.NewAccountService(appFactory).FindByID(ctx, dto.FindAccountByIdRequest{AccountID: accountID})
+			if err != nil {
+				return err
+			}
+			return out.print(response, accountHeaders, [][]string{
+				accountRow(response.AccountID, response.DocumentType, response.DocumentNumber, response.Currency, response.Status, response.Version),
+			})
+		}),
+	}
+}
+
+func accountCreate(opts *options) *cobra.Command {
+	var request dto.CreateAccountRequest
+	createCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create an account",
+		Args:  cobra.NoArgs,
+		RunE: runWithApp(opts, func(ctx context.Context, appFactory *factory.AppFactory, out *printer, args []string) error {
+			response, err := service.NewAccountService(appFactory).Create(ctx, request)
+			if err != nil {
+				return err
+			}
+			return out.print(response, accountHeaders, [][]string{
+				accountRow(response.AccountID, response.DocumentType, response.DocumentNumber, response.Currency, response.Status, response.Version),
+			})
+		}),
+	}
+	createCmd.Flags().StringVar(&request.DocumentNumber, "document-number", "", "document number of the account holder")
+	createCmd.Flags().StringVar(&request.DocumentType, "document-type", "", "CPF, CNPJ, PASSPORT or FOREIGN_TAX_ID, CPF or CNPJ by length when not informed")
+	createCmd.Flags().StringVar(&request.Currency, "currency", "", "ISO 4217 code of the account currency, the configured default when not informed")
+	_ = createCmd.MarkFlagRequired("document-number")
+	return createCmd
+}
+
+func accountList(opts *options) *cobra.Command {
+	var limit int64
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the first accounts, ordered by ID",
+		Args:  cobra.NoArgs,
+		RunE: runWithApp(opts, func(ctx context.Context, appFactory *factory.AppFactory, out *printer, args []string) error {
+			if limit <= 0 {
+				return fmt.Errorf("limit must be greater than zero")
+			}
+			// AccountService.List always starts from the first account, the cursor is only validated
+			response, err := service.NewAccountService(appFactory).List(ctx, dto.ListAccountsRequest{Cursor: 1, Limit: limit})
+			if err != nil {
+				return err
+			}
+			rows := make([][]string, 0, len(response.Accounts))
+			for _, account := range response.Accounts {
+				rows = append(rows, accountRow(account.AccountID, account.DocumentType, account.DocumentNumber, account.Currency, account.Status, account.Version))
+			}
+			return out.print(response, accountHeaders, rows)
+		}),
+	}
+	listCmd.Flags().Int64Var(&limit, "limit", 50, "maximum number of accounts")
+	return listCmd
+}
+
+func accountBlock(opts *options) *cobra.Command {
+	var expectedVersion int64
+	blockCmd := &cobra.Command{
+		Use:   "block ACCOUNT_ID",
+		Short: "Block an account, so it rejects new transactions",
+		Args:  cobra.ExactArgs(1),
+		RunE: runWithApp(opts, func(ctx context.Context, appFactory *factory.AppFactory, out *printer, args []string) error {
+			accountID, err := parseID(args[0])
+			if err != nil {
+				return err
+			}
+			response, err := service.NewAccountService(appFactory).Block(ctx, dto.BlockAccountRequest{AccountID: accountID, Version: expectedVersion})
+			if err != nil {
+				return err
+			}
+			return out.print(response, accountHeaders, [][]string{
+				accountRow(response.AccountID, response.DocumentType, response.DocumentNumber, response.Currency, response.Status, response.Version),
+			})
+		}),
+	}
+	blockCmd.Flags().Int64Var(&expectedVersion, "expected-version", 0, "fail unless the account is at this version, the current one by default")
+	return blockCmd
+}
+
+func parseID(value string) (int64, error) {
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid ID %q", value)
+	}
+	return id, nil
+}
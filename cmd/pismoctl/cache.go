@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/cache"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/factory"
+	"github.com/spf13/cobra"
+	"slices"
+	"strconv"
+)
+
+var cacheEntities = []string{cache.AccountEntity, cache.TransactionEntity, cache.OperationTypeEntity}
+
+func cacheCommand(opts *options) *cobra.Command {
+	cacheCmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Purge cached entities",
+	}
+	cacheCmd.AddCommand(cachePurge(opts))
+	return cacheCmd
+}
+
+func cachePurge(opts *options) *cobra.Command {
+	var entity string
+	var id int64
+	purgeCmd := &cobra.Command{
+		Use:   "purge",
+		Short: "Remove cached entities, read again from the database on their next lookup",
+		Long: "Remove every cached entity, the entities of --entity, or the single entity of --entity and --id.\n" +
+			"Rate limits and locks are not touched.",
+		Args: cobra.NoArgs,
+		RunE: runWithApp(opts, func(ctx context.Context, appFactory *factory.AppFactory, out *printer, args []string) error {
+			if entity != "" && !slices.Contains(cacheEntities, entity) {
+				return fmt.Errorf("invalid entity %q, use %v", entity, cacheEntities)
+			}
+			if id < 0 || (id > 0 && entity == "") {
+				return fmt.Errorf("--id must be greater than zero and requires --entity")
+			}
+			pattern := cache.KeyPattern(entity, id)
+			removed, err := appFactory.CachePurger().Purge(ctx, pattern)
+			if err != nil {
+				return err
+			}
+			return out.print(struct {
+				Pattern string `json:"pattern"`
+				Removed int64  `json:"removed"`
+			}{pattern, removed}, []string{"PATTERN", "REMOVED"}, [][]string{{pattern, strconv.FormatInt(removed, 10)}})
+		}),
+	}
+	purgeCmd.Flags().StringVar(&entity, "entity", "", fmt.Sprintf("kind of entity, one of %v", cacheEntities))
+	purgeCmd.Flags().Int64Var(&id, "id", 0, "ID of the entity")
+	return purgeCmd
+}
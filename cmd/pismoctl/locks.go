@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/lock"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/factory"
+	"github.com/spf13/cobra"
+	"time"
+)
+
+func lockCommand(opts *options) *cobra.Command {
+	lockCmd := &cobra.Command{
+		Use:   "lock",
+		Short: "List and force the release of distributed locks",
+	}
+	lockCmd.AddCommand(lockList(opts), lockForceRelease(opts))
+	return lockCmd
+}
+
+func lockList(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the held locks and their holders",
+		Args:  cobra.NoArgs,
+		RunE: runWithApp(opts, func(ctx context.Context, appFactory *factory.AppFactory, out *printer, args []string) error {
+			heldLocks, err := appFactory.LockAdministrator().List(ctx)
+			if err != nil {
+				return err
+			}
+			rows := make([][]string, 0, len(heldLocks))
+			for _, heldLock := range heldLocks {
+				ttl := "never expires"
+				if heldLock.TTL >= 0 {
+					ttl = heldLock.TTL.Round(time.Millisecond).String()
+				}
+				rows = append(rows, []string{heldLock.Key, heldLock.Value, ttl})
+			}
+			return out.print(struct {
+				Locks []lock.HeldLock `json:"locks"`
+			}{heldLocks}, []string{"KEY", "HOLDER", "EXPIRES IN"}, rows)
+		}),
+	}
+}
+
+func lockForceRelease(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "force-release KEY",
+		Short: "Release a lock whatever its holder",
+		Long: "Release a lock left behind by a crashed instance. The holder, if still running, is no longer protected by the lock,\n" +
+			"so check with lock list that it expires later than expected before releasing it. Only keys starting with " + lock.KeyPrefix + " are released.",
+		Args: cobra.ExactArgs(1),
+		RunE: runWithApp(opts, func(ctx context.Context, appFactory *factory.AppFactory, out *printer, args []string) error {
+			released, err := appFactory.LockAdministrator().ForceRelease(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			message := fmt.Sprintf("lock %s released", args[0])
+			if !released {
+				message = fmt.Sprintf("lock %s was not held", args[0])
+			}
+			return out.print(struct {
+				Key      string `json:"key"`
+				Released bool   `json:"released"`
+			}{args[0], released}, []string{"RESULT"}, [][]string{{message}})
+		}),
+	}
+}
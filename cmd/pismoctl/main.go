@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/config"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/factory"
+	infralogger "github.com/kiosanim/pismo-code-assessment/internal/infra/logger"
+	"github.com/spf13/cobra"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+type options struct {
+	output   string
+	logLevel string
+}
+
+// newAppFactory connects to the database and the cache of the configuration in the working directory, logging only
+// the messages of the --log-level level, so they do not mix with the command output
+func newAppFactory(ctx context.Context, opts *options) (*factory.AppFactory, error) {
+	if _, ok := infralogger.ParseLevel(opts.logLevel); !ok {
+		return nil, fmt.Errorf("invalid log level %q, use debug, info, warn or error", opts.logLevel)
+	}
+	appFactory := factory.NewAppFactory(ctx)
+	if reloadable, ok := appFactory.Log().(config.Reloadable); ok {
+		configuration := *appFactory.Configuration()
+		configuration.App.LogLevel = opts.logLevel
+		reloadable.ApplyConfiguration(&configuration)
+	}
+	return &appFactory, nil
+}
+
+// runWithApp returns a cobra RunE building the AppFactory only when the command runs, so help and argument errors
+// need no database
+func runWithApp(opts *options, run func(ctx context.Context, appFactory *factory.AppFactory, out *printer, args []string) error) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		out, err := newPrinter(cmd.OutOrStdout(), opts.output)
+		if err != nil {
+			return err
+		}
+		ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+		defer stop()
+		appFactory, err := newAppFactory(ctx, opts)
+		if err != nil {
+			return err
+		}
+		return run(ctx, appFactory, out, args)
+	}
+}
+
+func main() {
+	opts := &options{}
+	rootCmd := &cobra.Command{
+		Use:   "pismoctl",
+		Short: "Support tool for accounts, transactions, locks and the cache",
+		Long: "Inspect and fix accounts, transactions, distributed locks and cached entities through the service layer,\n" +
+			"with the validations, audit log and cache invalidation of the API. It uses the config.yaml of the working directory.",
+		SilenceUsage: true,
+	}
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.PersistentFlags().StringVarP(&opts.output, "output", "o", formatTable, "output format, table or json")
+	rootCmd.PersistentFlags().StringVar(&opts.logLevel, "log-level", "error", "level of the logs written along with the output")
+	rootCmd.AddCommand(accountCommand(opts), transactionCommand(opts), lockCommand(opts), cacheCommand(opts))
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+)
+
+// printer writes the result of a command as an aligned table or as indented JSON
+type printer struct {
+	w      io.Writer
+	format string
+}
+
+func newPrinter(w io.Writer, format string) (*printer, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format != formatTable && format != formatJSON {
+		return nil, fmt.Errorf("invalid output format %q, use table or json", format)
+	}
+	return &printer{w: w, format: format}, nil
+}
+
+// print writes value as JSON or the rows under the headers as a table
+func (p *printer) print(value any, headers []string, rows [][]string) error {
+	if p.format == formatJSON {
+		encoder := json.NewEncoder(p.w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	}
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// printf writes a message after the table, it is left out of the JSON output
+func (p *printer) printf(format string, args ...any) {
+	if p.format == formatTable {
+		fmt.Fprintf(p.w, format, args...)
+	}
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
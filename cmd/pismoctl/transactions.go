@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/kiosanim/pismo-code-assessment/application/transaction/dto"
+	"github.com/kiosanim/pismo-code-assessment/application/transaction/exporter"
+	"github.com/kiosanim/pismo-code-assessment/application/transaction/service"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/factory"
+	"github.com/spf13/cobra"
+	"strconv"
+	"strings"
+)
+
+var transactionHeaders = []string{"TRANSACTION", "ACCOUNT", "OPERATION", "AMOUNT", "CURRENCY", "STATUS", "EVENT DATE", "EFFECTIVE DATE", "DESCRIPTION"}
+
+func transactionRow(transaction dto.TransactionDTO) []string {
+	return []string{
+		strconv.FormatInt(transaction.TransactionID, 10),
+		strconv.FormatInt(transaction.AccountID, 10),
+		strconv.Itoa(transaction.OperationTypeID),
+		strconv.FormatFloat(transaction.Amount, 'f', 2, 64),
+		transaction.Currency,
+		transaction.Status,
+		formatTime(transaction.EventDate),
+		formatTime(transaction.EffectiveDate),
+		transaction.Description,
+	}
+}
+
+func transactionCommand(opts *options) *cobra.Command {
+	transactionCmd := &cobra.Command{
+		Use:   "transaction",
+		Short: "Get, list and reverse transactions",
+	}
+	transactionCmd.AddCommand(transactionGet(opts), transactionList(opts), transactionReverse(opts))
+	return transactionCmd
+}
+
+func transactionGet(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get TRANSACTION_ID",
+		Short: "Show a transaction and its status transitions",
+		Args:  cobra.ExactArgs(1),
+		RunE: runWithApp(opts, func(ctx context.Context, appFactory *factory.AppFactory, out *printer, args []string) error {
+			transactionID, err := parseID(args[0])
+			if err != nil {
+				return err
+			}
+			response, err := service.NewTransactionService(appFactory).FindByID(ctx, dto.FindTransactionByIdRequest{TransactionID: transactionID})
+			if err != nil {
+				return err
+			}
+			if err = out.print(response, transactionHeaders, [][]string{transactionRow(response.Transaction)}); err != nil {
+				return err
+			}
+			for _, transition := range response.StatusTransitions {
+				out.printf("%s at %s\n", transition.Status, formatTime(transition.At))
+			}
+			return nil
+		}),
+	}
+}
+
+func transactionList(opts *options) *cobra.Command {
+	var request dto.ListTransactionsRequest
+	var from, to, tz string
+	var metadata []string
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "Search transactions, a page at a time",
+		Long: "Search the transactions matching every informed filter. --from and --to accept RFC 3339 or YYYY-MM-DD;\n" +
+			"--from is inclusive and --to is exclusive, dates used as --to include the whole day.\n" +
+			"The cursor of the next page is printed after the table.",
+		Args: cobra.NoArgs,
+		RunE: runWithApp(opts, func(ctx context.Context, appFactory *factory.AppFactory, out *printer, args []string) error {
+			location, err := exporter.ParseLocation(tz)
+			if err != nil {
+				return err
+			}
+			if request.From, err = exporter.ParseBoundIn(from, false, location); err != nil {
+				return err
+			}
+			if request.To, err = exporter.ParseBoundIn(to, true, location); err != nil {
+				return err
+			}
+			if len(metadata) > 0 {
+				request.Metadata = make(map[string]string, len(metadata))
+				for _, entry := range metadata {
+					key, value, ok := strings.Cut(entry, "=")
+					if !ok {
+						return fmt.Errorf("invalid metadata %q, use key=value", entry)
+					}
+					request.Metadata[key] = value
+				}
+			}
+			request.Location = location
+			response, err := service.NewTransactionService(appFactory).List(ctx, request)
+			if err != nil {
+				return err
+			}
+			rows := make([][]string, 0, len(response.Transactions))
+			for _, transaction := range response.Transactions {
+				rows = append(rows, transactionRow(transaction))
+			}
+			if err = out.print(response, transactionHeaders, rows); err != nil {
+				return err
+			}
+			if response.Cursor > 0 {
+				out.printf("next page: --cursor %d\n", response.Cursor)
+			}
+			return nil
+		}),
+	}
+	listCmd.Flags().Int64Var(&request.AccountID, "account", 0, "account ID, every account when not informed")
+	listCmd.Flags().StringVar(&request.Status, "status", "", "PENDING, POSTED or CANCELLED")
+	listCmd.Flags().StringVar(&request.MerchantName, "merchant-name", "", "part of the merchant name")
+	listCmd.Flags().StringVar(&request.MCC, "mcc", "", "merchant category code")
+	listCmd.Flags().StringVar(&request.MerchantCountry, "merchant-country", "", "ISO 3166-1 alpha-2 merchant country")
+	listCmd.Flags().StringVar(&request.Description, "description", "", "part of the description")
+	listCmd.Flags().StringArrayVar(&metadata, "metadata", nil, "key=value metadata entry, repeatable")
+	listCmd.Flags().StringVar(&from, "from", "", "first event date")
+	listCmd.Flags().StringVar(&to, "to", "", "last event date")
+	listCmd.Flags().StringVar(&tz, "tz", "", "IANA time zone of the dates, e.g. America/Sao_Paulo (default UTC)")
+	listCmd.Flags().Int64Var(&request.Cursor, "cursor", 0, "last transaction ID of the previous page")
+	listCmd.Flags().Int64Var(&request.Limit, "limit", dto.DefaultListLimit, fmt.Sprintf("transactions per page, up to %d", dto.MaxListLimit))
+	return listCmd
+}
+
+func transactionReverse(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "reverse TRANSACTION_ID",
+		Short: "Create a transaction cancelling out a posted transaction",
+		Long: "Create a posted transaction with the opposite amount and mirrored ledger postings, fees included.\n" +
+			"The reversal has a reversal_of metadata entry with the reversed transaction ID; a transaction is reversed at most once.",
+		Args: cobra.ExactArgs(1),
+		RunE: runWithApp(opts, func(ctx context.Context, appFactory *factory.AppFactory, out *printer, args []string) error {
+			transactionID, err := parseID(args[0])
+			if err != nil {
+				return err
+			}
+			response, err := service.NewTransactionService(appFactory).Reverse(ctx, dto.ReverseTransactionRequest{TransactionID: transactionID})
+			if err != nil {
+				return err
+			}
+			return out.print(response, transactionHeaders, [][]string{transactionRow(response.Transaction)})
+		}),
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/kiosanim/pismo-code-assessment/application/transaction/scheduler"
+	"github.com/kiosanim/pismo-code-assessment/application/transaction/service"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/factory"
+	"github.com/spf13/cobra"
+	"log"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+func main() {
+	var opts scheduler.Options
+	rootCmd := &cobra.Command{
+		Use:   "scheduler",
+		Short: "Post scheduled transactions when their effective date comes",
+		Long: "Post the PENDING transactions whose effective date has come, every --interval.\n" +
+			"Any number of schedulers can run: only the one holding the leader lock posts, the others take over when its --lease expires.",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.Interval <= 0 || opts.BatchSize <= 0 {
+				return fmt.Errorf("--interval and --batch-size must be greater than zero")
+			}
+			if opts.Lease <= opts.Interval {
+				return fmt.Errorf("--lease must be longer than --interval")
+			}
+			ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+			defer stop()
+			appFactory := factory.NewAppFactory(ctx)
+			transactionService := service.NewTransactionService(&appFactory)
+			worker := scheduler.NewScheduler(transactionService, appFactory.DistributedLockManager(), opts, appFactory.Log())
+			appFactory.Log().Info("scheduler", "status", "started", "interval", opts.Interval, "lease", opts.Lease)
+			worker.Run(ctx)
+			appFactory.Log().Info("scheduler", "status", "stopped")
+			return nil
+		},
+	}
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.Flags().DurationVar(&opts.Interval, "interval", 5*time.Second, "time between runs")
+	rootCmd.Flags().DurationVar(&opts.Lease, "lease", 30*time.Second, "ttl of the leader lock, refreshed on every run")
+	rootCmd.Flags().Int64Var(&opts.BatchSize, "batch-size", 100, "transactions posted per database query")
+	if err := rootCmd.Execute(); err != nil {
+		log.Fatal(err)
+	}
+}
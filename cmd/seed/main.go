@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/kiosanim/pismo-code-assessment/application/seed"
+	"github.com/kiosanim/pismo-code-assessment/application/transaction/exporter"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/config"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/database/repository"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/factory"
+	"github.com/spf13/cobra"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	writerRepository = "repository"
+	writerCopy       = "copy"
+)
+
+// defaultDays is the length of the event date interval when --from is not informed
+const defaultDays = 90
+
+type generateOptions struct {
+	seed         uint64
+	accounts     int
+	transactions int
+	cnpjShare    float64
+	mix          string
+	from         string
+	to           string
+	distribution string
+	currency     string
+	writer       string
+	batchSize    int
+}
+
+// generate stores the generated accounts and transactions in the configured database
+func generate(ctx context.Context, opts generateOptions) (seed.Summary, error) {
+	mix, err := seed.ParseMix(opts.mix)
+	if err != nil {
+		return seed.Summary{}, err
+	}
+	appFactory := factory.NewAppFactory(ctx)
+	to := appFactory.Clock().Now().UTC().Truncate(24 * time.Hour)
+	if opts.to != "" {
+		if to, err = exporter.ParseBound(opts.to, true); err != nil {
+			return seed.Summary{}, err
+		}
+	}
+	from := to.AddDate(0, 0, -defaultDays)
+	if opts.from != "" {
+		if from, err = exporter.ParseBound(opts.from, false); err != nil {
+			return seed.Summary{}, err
+		}
+	}
+	currency := opts.currency
+	if currency == "" {
+		currency = appFactory.Configuration().Currency.Default
+	}
+	generator, err := seed.NewGenerator(seed.Options{
+		Seed:                   opts.seed,
+		Accounts:               opts.accounts,
+		TransactionsPerAccount: opts.transactions,
+		CNPJShare:              opts.cnpjShare,
+		Mix:                    mix,
+		From:                   from,
+		To:                     to,
+		Distribution:           opts.distribution,
+		Currency:               strings.ToUpper(currency),
+	})
+	if err != nil {
+		return seed.Summary{}, err
+	}
+	var writer seed.TransactionWriter
+	switch opts.writer {
+	case writerRepository:
+		writer = seed.NewRepositoryTransactionWriter(appFactory.TransactionRepository())
+	case writerCopy:
+		if appFactory.Configuration().Database.Driver == config.DatabaseDriverSQLite {
+			return seed.Summary{}, fmt.Errorf("writer %s needs database.driver %s", writerCopy, config.DatabaseDriverPostgres)
+		}
+		writer = repository.NewTransactionCopyWriter(appFactory.ConnectionData(), appFactory.Log())
+	default:
+		return seed.Summary{}, fmt.Errorf("unknown writer %q, expected %s or %s", opts.writer, writerRepository, writerCopy)
+	}
+	seeder := seed.NewSeeder(generator, appFactory.AccountRepository(), writer, appFactory.Configuration().Ledger, opts.batchSize, appFactory.Log())
+	return seeder.Run(ctx)
+}
+
+func generateCommand() *cobra.Command {
+	var opts generateOptions
+	generateCmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate accounts and transactions in the database",
+		Long: "Generate --accounts accounts with valid CPF or CNPJ numbers and --transactions posted transactions per account.\n" +
+			"The same --seed, options and dates generate the same data; --to defaults to the start of today and --from to 90 days before it.\n" +
+			"--writer repository inserts the transactions as the API does, auditing them; --writer copy bulk loads them with COPY, without audit entries, on Postgres only.",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+			defer stop()
+			start := time.Now()
+			summary, err := generate(ctx, opts)
+			fmt.Fprintf(os.Stderr, "%d accounts and %d transactions stored in %s\n", summary.Accounts, summary.Transactions, time.Since(start).Round(time.Millisecond))
+			return err
+		},
+	}
+	generateCmd.Flags().Uint64Var(&opts.seed, "seed", 1, "seed of the random values")
+	generateCmd.Flags().IntVar(&opts.accounts, "accounts", 100, "accounts generated")
+	generateCmd.Flags().IntVar(&opts.transactions, "transactions", 20, "transactions generated per account")
+	generateCmd.Flags().Float64Var(&opts.cnpjShare, "cnpj-share", 0.2, "share of the accounts identified by a CNPJ")
+	generateCmd.Flags().StringVar(&opts.mix, "mix", seed.DefaultMix, "weight of each operation type, OPERATION_TYPE=WEIGHT pairs")
+	generateCmd.Flags().StringVar(&opts.from, "from", "", "first event date, RFC 3339 or YYYY-MM-DD")
+	generateCmd.Flags().StringVar(&opts.to, "to", "", "last event date, RFC 3339 or YYYY-MM-DD")
+	generateCmd.Flags().StringVar(&opts.distribution, "distribution", seed.DistributionUniform, "uniform or recent, concentrating the event dates close to --to")
+	generateCmd.Flags().StringVar(&opts.currency, "currency", "", "currency of the accounts (default currency.default)")
+	generateCmd.Flags().StringVar(&opts.writer, "writer", writerRepository, "repository or copy")
+	generateCmd.Flags().IntVar(&opts.batchSize, "batch-size", 500, "transactions written at a time")
+	return generateCmd
+}
+
+type replayOptions struct {
+	file    string
+	url     string
+	headers []string
+	report  string
+	timeout time.Duration
+}
+
+// replay sends the requests of the log to the API, writing the result of each one to the report as NDJSON
+func replay(ctx context.Context, opts replayOptions) (seed.ReplaySummary, error) {
+	headers := make(map[string]string, len(opts.headers))
+	for _, header := range opts.headers {
+		name, value, found := strings.Cut(header, ":")
+		if !found || strings.TrimSpace(name) == "" {
+			return seed.ReplaySummary{}, fmt.Errorf("invalid header %q, expected NAME: VALUE", header)
+		}
+		headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	input, err := openInput(opts.file)
+	if err != nil {
+		return seed.ReplaySummary{}, err
+	}
+	defer input.Close()
+	output := io.Discard
+	if opts.report != "" {
+		reportFile, err := os.Create(opts.report)
+		if err != nil {
+			return seed.ReplaySummary{}, err
+		}
+		defer reportFile.Close()
+		output = reportFile
+	}
+	encoder := json.NewEncoder(output)
+	replayer := seed.NewReplayer(&http.Client{Timeout: opts.timeout}, opts.url, headers)
+	return replayer.Replay(ctx, input, func(result seed.ReplayResult) error {
+		return encoder.Encode(result)
+	})
+}
+
+func openInput(file string) (io.ReadCloser, error) {
+	if file == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	return os.Open(file)
+}
+
+func replayCommand() *cobra.Command {
+	var opts replayOptions
+	replayCmd := &cobra.Command{
+		Use:   "replay",
+		Short: "Replay a request log against a running API",
+		Long: "Send the requests of a JSON lines log to --url, one at a time in the order they were logged.\n" +
+			"Every line is an object with the method, path, headers and body of a request; its request_id is sent as the x-trace-id header.",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+			defer stop()
+			summary, err := replay(ctx, opts)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(os.Stderr, summary)
+			if summary.Failed > 0 {
+				return fmt.Errorf("%d requests failed", summary.Failed)
+			}
+			return nil
+		},
+	}
+	replayCmd.Flags().StringVarP(&opts.file, "file", "f", "-", "request log, - reads from stdin")
+	replayCmd.Flags().StringVar(&opts.url, "url", "http://localhost:8080", "base URL of the API")
+	replayCmd.Flags().StringArrayVarP(&opts.headers, "header", "H", nil, "header sent with every request, e.g. \"Authorization: Bearer TOKEN\"")
+	replayCmd.Flags().StringVar(&opts.report, "report", "", "file the result of every request is written to as NDJSON")
+	replayCmd.Flags().DurationVar(&opts.timeout, "timeout", 10*time.Second, "timeout of each request")
+	return replayCmd
+}
+
+func main() {
+	rootCmd := &cobra.Command{
+		Use:   "seed",
+		Short: "Generate demo data and replay request logs",
+	}
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.AddCommand(generateCommand(), replayCommand())
+	if err := rootCmd.Execute(); err != nil {
+		log.Fatal(err)
+	}
+}
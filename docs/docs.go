@@ -25,7 +25,15 @@ const docTemplate = `{
     "paths": {
         "/accounts": {
             "post": {
-                "description": "Creates a new account with a valid and not used document number",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a new account with a valid and not used document number of type CPF, CNPJ, PASSPORT or FOREIGN_TAX_ID.\nThe document number is stored normalized and encrypted, it is masked unless the caller has the accounts:pii scope.",
                 "consumes": [
                     "application/json"
                 ],
@@ -62,13 +70,39 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             }
         },
         "/accounts/list/{cursor}/{limit}": {
             "get": {
-                "description": "Returns a list of accounts",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns a list of accounts, document numbers are masked unless the caller has the accounts:pii scope",
                 "produces": [
                     "application/json"
                 ],
@@ -100,6 +134,24 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -112,30 +164,80 @@ const docTemplate = `{
                 }
             }
         },
-        "/accounts/{id}": {
+        "/accounts/{account_id}/holder": {
             "get": {
-                "description": "Returns an account by ID",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the profile and contact data of the holder of an account, with its version as the ETag header, ending with -m when personal data is masked.\nHolders never changed have empty values and version 0. Personal data is masked unless the caller has the accounts:pii scope.\nResponds 304 with no body when the If-None-Match header has the current ETag.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Accounts"
                 ],
-                "summary": "Get account by ID",
+                "summary": "Get the holder of an account",
                 "parameters": [
                     {
                         "type": "integer",
                         "description": "Account ID",
-                        "name": "id",
+                        "name": "account_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the holder already held by the caller",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.FindAccountByIdResponse"
+                            "$ref": "#/definitions/dto.HolderResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the holder, sent back in the If-Match header to change it"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "The holder was not changed since the ETag of the If-None-Match header"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "404": {
@@ -148,11 +250,17 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/transactions": {
-            "post": {
-                "description": "Creates a new transaction with valid account id and document number",
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Sets the holder values sent in the body, leaving the others as they are. An empty value clears it.\nThe If-Match header must carry the ETag of the holder, the update failing with 412 when the holder was changed since it was read.\nemail is stored in lower case, phone must be an E.164 number, birth_date is the YYYY-MM-DD birth or incorporation date and address.country an ISO 3166-1 alpha-2 code.",
                 "consumes": [
                     "application/json"
                 ],
@@ -160,25 +268,45 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Transactions"
+                    "Accounts"
                 ],
-                "summary": "Create a transaction",
+                "summary": "Update the holder of an account",
                 "parameters": [
                     {
-                        "description": "Transaction Data",
-                        "name": "account",
+                        "type": "integer",
+                        "description": "Account ID",
+                        "name": "account_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the holder",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Holder values to set",
+                        "name": "holder",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CreateTransactionRequest"
+                            "$ref": "#/definitions/dto.UpdateHolderRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.CreateTransactionResponse"
+                            "$ref": "#/definitions/dto.HolderResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the holder"
+                            }
                         }
                     },
                     "400": {
@@ -189,25 +317,78 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "412": {
+                        "description": "The holder was changed since it was read",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "428": {
+                        "description": "The If-Match header is missing",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             }
         },
-        "/transactions/{id}": {
+        "/accounts/{account_id}/holder/history": {
             "get": {
-                "description": "Returns a transaction by ID",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns every version of the holder of an account, newest first, with the fields changed, who changed them and the holder after the change.\nPersonal data is masked unless the caller has the accounts:pii scope.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Transactions"
+                    "Accounts"
                 ],
-                "summary": "Get transaction by ID",
+                "summary": "Get the change history of the holder of an account",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Transaction ID",
-                        "name": "id",
+                        "description": "Account ID",
+                        "name": "account_id",
                         "in": "path",
                         "required": true
                     }
@@ -216,7 +397,34 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.FindTransactionByIdResponse"
+                            "$ref": "#/definitions/dto.HolderHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "404": {
@@ -230,28 +438,927 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "dto.AccountDTO": {
-            "type": "object",
-            "properties": {
-                "account_id": {
-                    "type": "integer"
-                },
-                "document_number": {
-                    "type": "string"
+        },
+        "/accounts/{account_id}/transactions/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Streams the transactions of an account as CSV, NDJSON or OFX. Amounts are presented as informed on creation, with their direction.\nfrom and to accept RFC 3339 or YYYY-MM-DD; from is inclusive and to is exclusive, dates used as to include the whole day.\nDates are days of the tz time zone, which also sets local_date and the OFX dates.",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson",
+                    "application/x-ofx"
+                ],
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Export the transactions of an account",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Account ID",
+                        "name": "account_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "csv (default), ndjson or ofx",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "First event date",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last event date",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone, e.g. America/Sao_Paulo (default UTC)",
+                        "name": "tz",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
                 }
             }
         },
-        "dto.CreateAccountRequest": {
+        "/accounts/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns an account by ID, the document number is masked unless the caller has the accounts:pii scope.\nThe ETag header is the account version, ending with -m when the document number is masked, responding 304 with no body when the If-None-Match header has it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Accounts"
+                ],
+                "summary": "Get account by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the account already held by the caller",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.FindAccountByIdResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the account, sent back in the If-Match header of its updates"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "The account was not changed since the ETag of the If-None-Match header"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/audit-log": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns a page of the audit entries ordered by ID, recorded for every account and transaction created, every account holder change and every transaction posted or cancelled.\nentity_id requires entity_type and from/to accept RFC 3339 or YYYY-MM-DD. Pass the returned cursor to get the next page, it is zero on the last page.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Audit"
+                ],
+                "summary": "Search the audit log",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Entity type: account, account_holder or transaction",
+                        "name": "entity_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Entity ID",
+                        "name": "entity_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "First creation date",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last creation date",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Cursor returned by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 50 by default and up to 500",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListAuditLogResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/audit-log/verify": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Recomputes the hash of every audit entry in order. valid is false and first_invalid_id is set when an entry was changed, removed or inserted out of the chain.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Audit"
+                ],
+                "summary": "Verify the audit log hash chain",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.VerifyAuditLogResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns a page of transactions ordered by ID. merchant_name and description match case-insensitive substrings,\nmetadata[key]=value filters by metadata entries and from/to accept RFC 3339 or YYYY-MM-DD like the export.\nAmounts are signed as stored. Pass the returned cursor to get the next page, it is zero on the last page.\ntz is the time zone of the from/to dates and of local_date, which groups transactions by local day.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Search transactions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Account ID",
+                        "name": "account_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "PENDING, POSTED or CANCELLED",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Merchant name",
+                        "name": "merchant_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Merchant category code",
+                        "name": "mcc",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Merchant country (ISO 3166-1 alpha-2)",
+                        "name": "merchant_country",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Description",
+                        "name": "description",
+                        "in": "query"
+                    },
+                    {
+                        "type": "object",
+                        "description": "Metadata entries, e.g. metadata[order_id]=42",
+                        "name": "metadata",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "First event date",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last event date",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone, e.g. America/Sao_Paulo (default UTC)",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Cursor returned by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 50 by default and up to 500",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListTransactionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a new transaction with valid account id and document number",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Create a transaction",
+                "parameters": [
+                    {
+                        "description": "Transaction Data",
+                        "name": "account",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateTransactionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateTransactionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/batch": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates up to 10000 transactions from a JSON array or from NDJSON (Content-Type: application/x-ndjson).\nEvery row is validated like a single transaction and reported on its own: valid rows are created even when others fail.",
+                "consumes": [
+                    "application/json",
+                    "application/x-ndjson"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Create transactions in bulk",
+                "parameters": [
+                    {
+                        "description": "Transactions",
+                        "name": "transactions",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.CreateTransactionRequest"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Every row was created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateTransactionBatchResponse"
+                        }
+                    },
+                    "207": {
+                        "description": "Some rows failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateTransactionBatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns a transaction by ID with the statuses it went through",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Get transaction by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.FindTransactionByIdResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/{transaction_id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Cancels a PENDING transaction, created with a future effective date, so it is never posted",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Cancel a scheduled transaction",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Transaction ID",
+                        "name": "transaction_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CancelTransactionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "The transaction is not pending",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "dto.AccountDTO": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "document_number": {
+                    "type": "string"
+                },
+                "document_type": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version sent as the ETag of the account",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.AddressDTO": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string"
+                },
+                "country": {
+                    "description": "ISO 3166-1 alpha-2 code",
+                    "type": "string"
+                },
+                "line1": {
+                    "type": "string"
+                },
+                "line2": {
+                    "type": "string"
+                },
+                "postal_code": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AddressPatchDTO": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string"
+                },
+                "country": {
+                    "type": "string"
+                },
+                "line1": {
+                    "type": "string"
+                },
+                "line2": {
+                    "type": "string"
+                },
+                "postal_code": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AuditEntryDTO": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "after": {
+                    "type": "object"
+                },
+                "audit_id": {
+                    "type": "integer"
+                },
+                "before": {
+                    "type": "object"
+                },
+                "client_ip": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "entity_id": {
+                    "type": "integer"
+                },
+                "entity_type": {
+                    "type": "string"
+                },
+                "hash": {
+                    "type": "string"
+                },
+                "previous_hash": {
+                    "type": "string"
+                },
+                "principal": {
+                    "type": "string"
+                },
+                "trace_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.BatchTransactionResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "row": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "transaction": {
+                    "$ref": "#/definitions/dto.TransactionDTO"
+                }
+            }
+        },
+        "dto.CancelTransactionResponse": {
+            "type": "object",
+            "properties": {
+                "transaction": {
+                    "$ref": "#/definitions/dto.TransactionDTO"
+                }
+            }
+        },
+        "dto.CreateAccountRequest": {
             "type": "object",
             "required": [
                 "document_number"
             ],
             "properties": {
+                "currency": {
+                    "description": "ISO 4217 code, the configured default currency when empty",
+                    "type": "string"
+                },
                 "document_number": {
                     "type": "string"
+                },
+                "document_type": {
+                    "description": "CPF, CNPJ, PASSPORT or FOREIGN_TAX_ID, CPF or CNPJ by length when empty",
+                    "type": "string"
                 }
             }
         },
@@ -261,8 +1368,41 @@ const docTemplate = `{
                 "account_id": {
                     "type": "integer"
                 },
+                "currency": {
+                    "type": "string"
+                },
                 "document_number": {
                     "type": "string"
+                },
+                "document_type": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version sent as the ETag of the account",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.CreateTransactionBatchResponse": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.BatchTransactionResult"
+                    }
+                },
+                "total": {
+                    "type": "integer"
                 }
             }
         },
@@ -275,6 +1415,41 @@ const docTemplate = `{
                 "amount": {
                     "type": "number"
                 },
+                "currency": {
+                    "description": "ISO 4217 code, the account currency when empty",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "Up to 255 characters",
+                    "type": "string"
+                },
+                "effective_date": {
+                    "description": "RFC 3339, a future date schedules the transaction as PENDING",
+                    "type": "string"
+                },
+                "event_date": {
+                    "description": "RFC 3339, backdates the transaction within the configured window",
+                    "type": "string"
+                },
+                "mcc": {
+                    "description": "4 digits ISO 18245 merchant category code",
+                    "type": "string"
+                },
+                "merchant_country": {
+                    "description": "ISO 3166-1 alpha-2 code",
+                    "type": "string"
+                },
+                "merchant_name": {
+                    "description": "Up to 120 characters",
+                    "type": "string"
+                },
+                "metadata": {
+                    "description": "Up to 20 entries, keys with letters, digits, '_', '.' or '-'",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
                 "operation_type_id": {
                     "type": "integer"
                 }
@@ -294,19 +1469,164 @@ const docTemplate = `{
                 "account_id": {
                     "type": "integer"
                 },
+                "currency": {
+                    "type": "string"
+                },
                 "document_number": {
                     "type": "string"
+                },
+                "document_type": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version sent as the ETag of the account",
+                    "type": "integer"
                 }
             }
         },
         "dto.FindTransactionByIdResponse": {
             "type": "object",
             "properties": {
+                "status_transitions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.StatusTransitionDTO"
+                    }
+                },
                 "transaction": {
                     "$ref": "#/definitions/dto.TransactionDTO"
                 }
             }
         },
+        "dto.HolderChangeDTO": {
+            "type": "object",
+            "properties": {
+                "changed_at": {
+                    "type": "string"
+                },
+                "changed_fields": {
+                    "description": "e.g. \"email\" or \"address.city\"",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "holder": {
+                    "description": "State of the holder after the change",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.HolderResponse"
+                        }
+                    ]
+                },
+                "principal": {
+                    "type": "string"
+                },
+                "trace_id": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.HolderHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "changes": {
+                    "description": "Newest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.HolderChangeDTO"
+                    }
+                }
+            }
+        },
+        "dto.HolderResponse": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "address": {
+                    "$ref": "#/definitions/dto.AddressDTO"
+                },
+                "birth_date": {
+                    "description": "YYYY-MM-DD birth date of people or incorporation date of companies",
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "description": "E.164 phone number",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version sent as the ETag of the holder, 0 until it is first changed",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.ListAuditLogResponse": {
+            "type": "object",
+            "properties": {
+                "cursor": {
+                    "description": "Cursor of the next page, zero on the last page",
+                    "type": "integer"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AuditEntryDTO"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.ListTransactionsResponse": {
+            "type": "object",
+            "properties": {
+                "cursor": {
+                    "description": "Cursor of the next page, zero on the last page",
+                    "type": "integer"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "transactions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TransactionDTO"
+                    }
+                }
+            }
+        },
+        "dto.StatusTransitionDTO": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
         "dto.TransactionDTO": {
             "type": "object",
             "properties": {
@@ -314,15 +1634,116 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "amount": {
+                    "description": "Amount in the account currency",
+                    "type": "number"
+                },
+                "currency": {
+                    "description": "Account currency",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "effective_date": {
+                    "description": "Date the transaction is, or is scheduled to be, posted",
+                    "type": "string"
+                },
+                "event_date": {
+                    "description": "Date the transaction happened, in UTC",
+                    "type": "string"
+                },
+                "exchange_rate": {
+                    "description": "Units of currency worth one unit of original_currency",
                     "type": "number"
                 },
+                "local_date": {
+                    "description": "Day of event_date in the requested time zone, UTC by default",
+                    "type": "string"
+                },
+                "mcc": {
+                    "type": "string"
+                },
+                "merchant_country": {
+                    "type": "string"
+                },
+                "merchant_name": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
                 "operation_type_id": {
                     "type": "integer"
                 },
+                "original_amount": {
+                    "description": "Amount informed on creation",
+                    "type": "number"
+                },
+                "original_currency": {
+                    "description": "Currency informed on creation",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "PENDING, POSTED or CANCELLED",
+                    "type": "string"
+                },
                 "transaction_id": {
                     "type": "integer"
                 }
             }
+        },
+        "dto.UpdateHolderRequest": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "$ref": "#/definitions/dto.AddressPatchDTO"
+                },
+                "birth_date": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.VerifyAuditLogResponse": {
+            "type": "object",
+            "properties": {
+                "checked": {
+                    "description": "Number of entries verified",
+                    "type": "integer"
+                },
+                "first_invalid_id": {
+                    "description": "First entry that does not match the hash chain",
+                    "type": "integer"
+                },
+                "valid": {
+                    "type": "boolean"
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "ApiKeyAuth": {
+            "description": "API key for machine clients",
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        },
+        "BearerAuth": {
+            "description": "JWT bearer token: \"Bearer \u003ctoken\u003e\"",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
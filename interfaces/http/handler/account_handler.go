@@ -3,8 +3,11 @@ package handler
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/kiosanim/pismo-code-assessment/application/account/dto"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/auth"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/contextutils"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/errors"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/pii"
 	"github.com/kiosanim/pismo-code-assessment/internal/domains/account"
 	"net/http"
 	"strconv"
@@ -24,13 +27,18 @@ func NewAccountHandler(service account.Service, log logger.Logger) *AccountHandl
 
 // CreateAccount godoc
 // @Summary      Create an account
-// @Description  Creates a new account with a valid and not used document number
+// @Description  Creates a new account with a valid and not used document number of type CPF, CNPJ, PASSPORT or FOREIGN_TAX_ID.
+// @Description  The document number is stored normalized and encrypted, it is masked unless the caller has the accounts:pii scope.
 // @Tags         Accounts
 // @Accept       json
 // @Produce      json
 // @Param        account  body	dto.CreateAccountRequest  true  "Account Data"
 // @Success      201  {object}  dto.CreateAccountResponse
 // @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Router       /accounts [post]
 func (h *AccountHandler) CreateAccount(c *gin.Context) {
 	var req dto.CreateAccountRequest
@@ -43,17 +51,30 @@ func (h *AccountHandler) CreateAccount(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	masked := !canReadPII(c)
+	if masked {
+		res.DocumentNumber = pii.Mask(res.DocumentNumber)
+	}
+	c.Header("ETag", formatETag(res.Version, masked))
 	c.JSON(http.StatusCreated, res)
 }
 
 // GetAccountByID godoc
 // @Summary      Get account by ID
-// @Description  Returns an account by ID
+// @Description  Returns an account by ID, the document number is masked unless the caller has the accounts:pii scope.
+// @Description  The ETag header is the account version, ending with -m when the document number is masked, responding 304 with no body when the If-None-Match header has it.
 // @Tags         Accounts
-// @Param        id   path	int  true  "Account ID"
+// @Param        id             path    int     true   "Account ID"
+// @Param        If-None-Match  header  string  false  "ETag of the account already held by the caller"
 // @Produce      json
 // @Success      200  {object}  dto.FindAccountByIdResponse
+// @Header       200  {string}  ETag  "Version of the account, sent back in the If-Match header of its updates"
+// @Success      304  "The account was not changed since the ETag of the If-None-Match header"
 // @Failure      404  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Router       /accounts/{id} [get]
 func (h *AccountHandler) GetAccountByID(c *gin.Context) {
 	accountId := c.Param("account_id")
@@ -70,19 +91,32 @@ func (h *AccountHandler) GetAccountByID(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-
+	masked := !canReadPII(c)
+	etag := formatETag(res.Version, masked)
+	varyByCaller(c)
+	if notModified(c, etag) {
+		return
+	}
+	if masked {
+		res.DocumentNumber = pii.Mask(res.DocumentNumber)
+	}
+	c.Header("ETag", etag)
 	c.JSON(http.StatusOK, res)
 }
 
 // ListAccounts godoc
 // @Summary      List accounts with pagination
-// @Description  Returns a list of accounts
+// @Description  Returns a list of accounts, document numbers are masked unless the caller has the accounts:pii scope
 // @Tags         Accounts
 // @Param        cursor  path     int  false  "Pagination cursor AccountID"
 // @Param        limit   path     int     false  "Max number of accounts to return (default 10)"
 // @Produce      json
 // @Success      200  {object}  []dto.AccountDTO
 // @Failure      404  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Router       /accounts/list/{cursor}/{limit} [get]
 func (h *AccountHandler) ListAccounts(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -107,9 +141,19 @@ func (h *AccountHandler) ListAccounts(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	if !canReadPII(c) {
+		for i := range response.Accounts {
+			response.Accounts[i].DocumentNumber = pii.Mask(response.Accounts[i].DocumentNumber)
+		}
+	}
 	// Build response
 	resp := gin.H{
 		"accounts": response,
 	}
 	c.JSON(http.StatusOK, resp)
 }
+
+// canReadPII checks if the caller was granted the scope to read unmasked document numbers and holder data
+func canReadPII(c *gin.Context) bool {
+	return contextutils.GetPrincipal(c.Request.Context()).HasScopes(auth.ScopeAccountsPII)
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	stderrors "errors"
+	"github.com/gin-gonic/gin"
+	"github.com/kiosanim/pismo-code-assessment/application/audit/dto"
+	"github.com/kiosanim/pismo-code-assessment/application/transaction/exporter"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/audit"
+	"net/http"
+	"strconv"
+)
+
+type AuditHandler struct {
+	service audit.Service
+	log     logger.Logger
+}
+
+func NewAuditHandler(service audit.Service, log logger.Logger) *AuditHandler {
+	return &AuditHandler{
+		service: service,
+		log:     log,
+	}
+}
+
+// ListAuditLog godoc
+// @Summary      Search the audit log
+// @Description  Returns a page of the audit entries ordered by ID, recorded for every account and transaction created, every account holder change and every transaction posted or cancelled.
+// @Description  entity_id requires entity_type and from/to accept RFC 3339 or YYYY-MM-DD. Pass the returned cursor to get the next page, it is zero on the last page.
+// @Tags         Audit
+// @Param        entity_type  query  string  false  "Entity type: account, account_holder or transaction"
+// @Param        entity_id    query  int     false  "Entity ID"
+// @Param        from         query  string  false  "First creation date"
+// @Param        to           query  string  false  "Last creation date"
+// @Param        cursor       query  int     false  "Cursor returned by the previous page"
+// @Param        limit        query  int     false  "Page size, 50 by default and up to 500"
+// @Produce      json
+// @Success      200  {object}  dto.ListAuditLogResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /audit-log [get]
+func (h *AuditHandler) ListAuditLog(c *gin.Context) {
+	request := dto.ListAuditLogRequest{EntityType: c.Query("entity_type")}
+	var err error
+	for param, value := range map[string]*int64{"entity_id": &request.EntityID, "cursor": &request.Cursor, "limit": &request.Limit} {
+		if raw := c.Query(param); raw != "" {
+			if *value, err = strconv.ParseInt(raw, 10, 64); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": errors.InvalidParametersError.Error()})
+				return
+			}
+		}
+	}
+	if request.From, err = exporter.ParseBound(c.Query("from"), false); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if request.To, err = exporter.ParseBound(c.Query("to"), true); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	res, err := h.service.List(c.Request.Context(), request)
+	switch {
+	case stderrors.Is(err, errors.InvalidParametersError):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusOK, res)
+	}
+}
+
+// VerifyAuditLog godoc
+// @Summary      Verify the audit log hash chain
+// @Description  Recomputes the hash of every audit entry in order. valid is false and first_invalid_id is set when an entry was changed, removed or inserted out of the chain.
+// @Tags         Audit
+// @Produce      json
+// @Success      200  {object}  dto.VerifyAuditLogResponse
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /audit-log/verify [get]
+func (h *AuditHandler) VerifyAuditLog(c *gin.Context) {
+	res, err := h.service.Verify(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
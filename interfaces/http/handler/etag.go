@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kiosanim/pismo-code-assessment/interfaces/http/middleware"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// maskedETagSuffix ends the ETags of representations with masked personal data, which differ from the unmasked
+// representations of the same version
+const maskedETagSuffix = "-m"
+
+// formatETag returns the strong ETag of a resource version, marked with maskedETagSuffix when its personal data is masked
+func formatETag(version int64, masked bool) string {
+	tag := strconv.FormatInt(version, 10)
+	if masked {
+		tag += maskedETagSuffix
+	}
+	return `"` + tag + `"`
+}
+
+// parseIfMatch returns the version of the ETag sent in an If-Match header, false when the header is not a single
+// strong ETag returned by formatETag. Weak ETags never match, as If-Match uses the strong comparison. The masked and
+// unmasked ETags of a version both match it.
+func parseIfMatch(header string) (int64, bool) {
+	unquoted, ok := strings.CutPrefix(strings.TrimSpace(header), `"`)
+	if !ok {
+		return 0, false
+	}
+	unquoted, ok = strings.CutSuffix(unquoted, `"`)
+	if !ok {
+		return 0, false
+	}
+	unquoted = strings.TrimSuffix(unquoted, maskedETagSuffix)
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if err != nil || version < 0 {
+		return 0, false
+	}
+	return version, true
+}
+
+// ifMatchVersion returns the version an update requires from the If-Match header. Without the header it responds
+// 428 and with an ETag that can never match 412 with the mismatch error, returning false.
+func ifMatchVersion(c *gin.Context, mismatch error) (int64, bool) {
+	header := c.GetHeader("If-Match")
+	if header == "" {
+		c.JSON(http.StatusPreconditionRequired, gin.H{"error": errors.PreconditionRequiredError.Error()})
+		return 0, false
+	}
+	version, ok := parseIfMatch(header)
+	if !ok {
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": mismatch.Error()})
+		return 0, false
+	}
+	return version, true
+}
+
+// notModified responds 304 when the If-None-Match header has the ETag, "*" or its weak form, returning true
+func notModified(c *gin.Context, etag string) bool {
+	header := c.GetHeader("If-None-Match")
+	if header == "" {
+		return false
+	}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			c.Header("ETag", etag)
+			c.Status(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+// varyByCaller tells caches that the response depends on the credentials of the caller, whose scopes decide whether
+// personal data is masked
+func varyByCaller(c *gin.Context) {
+	c.Header("Vary", "Authorization, "+middleware.APIKeyHeader)
+}
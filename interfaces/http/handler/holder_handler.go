@@ -0,0 +1,176 @@
+package handler
+
+import (
+	stderrors "errors"
+	"github.com/gin-gonic/gin"
+	"github.com/kiosanim/pismo-code-assessment/application/holder/dto"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/pii"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/holder"
+	"net/http"
+)
+
+type HolderHandler struct {
+	service holder.Service
+	log     logger.Logger
+}
+
+func NewHolderHandler(service holder.Service, log logger.Logger) *HolderHandler {
+	return &HolderHandler{
+		service: service,
+		log:     log,
+	}
+}
+
+// GetHolder godoc
+// @Summary      Get the holder of an account
+// @Description  Returns the profile and contact data of the holder of an account, with its version as the ETag header, ending with -m when personal data is masked.
+// @Description  Holders never changed have empty values and version 0. Personal data is masked unless the caller has the accounts:pii scope.
+// @Description  Responds 304 with no body when the If-None-Match header has the current ETag.
+// @Tags         Accounts
+// @Param        account_id     path    int     true   "Account ID"
+// @Param        If-None-Match  header  string  false  "ETag of the holder already held by the caller"
+// @Produce      json
+// @Success      200  {object}  dto.HolderResponse
+// @Header       200  {string}  ETag  "Version of the holder, sent back in the If-Match header to change it"
+// @Success      304  "The holder was not changed since the ETag of the If-None-Match header"
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /accounts/{account_id}/holder [get]
+func (h *HolderHandler) GetHolder(c *gin.Context) {
+	var req dto.FindHolderRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errors.InvalidParametersError.Error()})
+		return
+	}
+	res, err := h.service.Find(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(holderErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	masked := !canReadPII(c)
+	etag := formatETag(res.Version, masked)
+	varyByCaller(c)
+	if notModified(c, etag) {
+		return
+	}
+	if masked {
+		maskHolder(res)
+	}
+	c.Header("ETag", etag)
+	c.JSON(http.StatusOK, res)
+}
+
+// UpdateHolder godoc
+// @Summary      Update the holder of an account
+// @Description  Sets the holder values sent in the body, leaving the others as they are. An empty value clears it.
+// @Description  The If-Match header must carry the ETag of the holder, the update failing with 412 when the holder was changed since it was read.
+// @Description  email is stored in lower case, phone must be an E.164 number, birth_date is the YYYY-MM-DD birth or incorporation date and address.country an ISO 3166-1 alpha-2 code.
+// @Tags         Accounts
+// @Accept       json
+// @Produce      json
+// @Param        account_id  path    int                      true  "Account ID"
+// @Param        If-Match    header  string                   true  "ETag of the holder"
+// @Param        holder      body    dto.UpdateHolderRequest  true  "Holder values to set"
+// @Success      200  {object}  dto.HolderResponse
+// @Header       200  {string}  ETag  "New version of the holder"
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      412  {object}  map[string]string  "The holder was changed since it was read"
+// @Failure      428  {object}  map[string]string  "The If-Match header is missing"
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /accounts/{account_id}/holder [patch]
+func (h *HolderHandler) UpdateHolder(c *gin.Context) {
+	var uri dto.FindHolderRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errors.InvalidParametersError.Error()})
+		return
+	}
+	version, ok := ifMatchVersion(c, errors.AccountHolderVersionMismatchError)
+	if !ok {
+		return
+	}
+	var req dto.UpdateHolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errors.InvalidParametersError.Error()})
+		return
+	}
+	req.AccountID = uri.AccountID
+	req.Version = version
+	res, err := h.service.Update(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(holderErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	masked := !canReadPII(c)
+	if masked {
+		maskHolder(res)
+	}
+	c.Header("ETag", formatETag(res.Version, masked))
+	c.JSON(http.StatusOK, res)
+}
+
+// GetHolderHistory godoc
+// @Summary      Get the change history of the holder of an account
+// @Description  Returns every version of the holder of an account, newest first, with the fields changed, who changed them and the holder after the change.
+// @Description  Personal data is masked unless the caller has the accounts:pii scope.
+// @Tags         Accounts
+// @Param        account_id   path	int  true  "Account ID"
+// @Produce      json
+// @Success      200  {object}  dto.HolderHistoryResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /accounts/{account_id}/holder/history [get]
+func (h *HolderHandler) GetHolderHistory(c *gin.Context) {
+	var req dto.HolderHistoryRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errors.InvalidParametersError.Error()})
+		return
+	}
+	res, err := h.service.History(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(holderErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if !canReadPII(c) {
+		for i := range res.Changes {
+			maskHolder(&res.Changes[i].Holder)
+		}
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func holderErrorStatus(err error) int {
+	switch {
+	case stderrors.Is(err, errors.AccountNotFoundError):
+		return http.StatusNotFound
+	case stderrors.Is(err, errors.AccountHolderVersionMismatchError):
+		return http.StatusPreconditionFailed
+	case stderrors.Is(err, errors.InvalidParametersError), stderrors.Is(err, errors.AccountHolderInvalidError):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// maskHolder masks the personal data of a holder but its city, state and country
+func maskHolder(res *dto.HolderResponse) {
+	res.Name = pii.Mask(res.Name)
+	res.Email = pii.Mask(res.Email)
+	res.Phone = pii.Mask(res.Phone)
+	res.BirthDate = pii.Mask(res.BirthDate)
+	res.Address.Line1 = pii.Mask(res.Address.Line1)
+	res.Address.Line2 = pii.Mask(res.Address.Line2)
+	res.Address.PostalCode = pii.Mask(res.Address.PostalCode)
+}
@@ -1,23 +1,36 @@
 package handler
 
 import (
+	stderrors "errors"
 	"github.com/gin-gonic/gin"
 	"github.com/kiosanim/pismo-code-assessment/application/transaction/dto"
+	"github.com/kiosanim/pismo-code-assessment/application/transaction/exporter"
+	"github.com/kiosanim/pismo-code-assessment/application/transaction/importer"
+	"github.com/kiosanim/pismo-code-assessment/interfaces/http/middleware"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/clock"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/contextutils"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/errors"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/ratelimit"
 	"github.com/kiosanim/pismo-code-assessment/internal/domains/transaction"
+	"mime"
 	"net/http"
 	"strconv"
 )
 
+// maxBatchRows is the maximum number of transactions accepted by a single batch request
+const maxBatchRows = 10000
+
 type TransactionHandler struct {
 	service transaction.Service
+	clock   clock.Clock // Time the exports are generated at
 	log     logger.Logger
 }
 
-func NewTransactionHandler(service transaction.Service, log logger.Logger) *TransactionHandler {
+func NewTransactionHandler(service transaction.Service, clock clock.Clock, log logger.Logger) *TransactionHandler {
 	return &TransactionHandler{
 		service: service,
+		clock:   clock,
 		log:     log,
 	}
 }
@@ -31,6 +44,11 @@ func NewTransactionHandler(service transaction.Service, log logger.Logger) *Tran
 // @Param        account  body	dto.CreateTransactionRequest  true  "Transaction Data"
 // @Success      201  {object}  dto.CreateTransactionResponse
 // @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      429  {object}  map[string]string
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Router       /transactions [post]
 func (h *TransactionHandler) CreateTransaction(c *gin.Context) {
 	var req dto.CreateTransactionRequest
@@ -39,21 +57,226 @@ func (h *TransactionHandler) CreateTransaction(c *gin.Context) {
 		return
 	}
 	res, err := h.service.Create(c.Request.Context(), req)
+	var exceeded *ratelimit.ExceededError
+	if stderrors.As(err, &exceeded) {
+		middleware.RespondRateLimitExceeded(c, exceeded.Result)
+		return
+	} else if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, res)
+}
+
+// CreateTransactionBatch godoc
+// @Summary      Create transactions in bulk
+// @Description  Creates up to 10000 transactions from a JSON array or from NDJSON (Content-Type: application/x-ndjson).
+// @Description  Every row is validated like a single transaction and reported on its own: valid rows are created even when others fail.
+// @Tags         Transactions
+// @Accept       json
+// @Accept       application/x-ndjson
+// @Produce      json
+// @Param        transactions  body	[]dto.CreateTransactionRequest  true  "Transactions"
+// @Success      201  {object}  dto.CreateTransactionBatchResponse  "Every row was created"
+// @Success      207  {object}  dto.CreateTransactionBatchResponse  "Some rows failed"
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      413  {object}  map[string]string
+// @Failure      429  {object}  map[string]string
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /transactions/batch [post]
+func (h *TransactionHandler) CreateTransactionBatch(c *gin.Context) {
+	format := importer.FormatJSON
+	if contentType := c.ContentType(); contentType == "application/x-ndjson" || contentType == "application/ndjson" {
+		format = importer.FormatNDJSON
+	}
+	reader, err := importer.NewReader(format, c.Request.Body)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	rows, err := importer.ReadAll(reader, maxBatchRows)
+	if stderrors.Is(err, errors.BatchTooLargeError) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(rows) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errors.InvalidParametersError.Error()})
+		return
+	}
+	res, err := h.service.CreateBatch(c.Request.Context(), rows)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if res.Failed > 0 {
+		c.JSON(http.StatusMultiStatus, res)
+		return
+	}
 	c.JSON(http.StatusCreated, res)
 }
 
+// ExportTransactions godoc
+// @Summary      Export the transactions of an account
+// @Description  Streams the transactions of an account as CSV, NDJSON or OFX. Amounts are presented as informed on creation, with their direction.
+// @Description  from and to accept RFC 3339 or YYYY-MM-DD; from is inclusive and to is exclusive, dates used as to include the whole day.
+// @Description  Dates are days of the tz time zone, which also sets local_date and the OFX dates.
+// @Tags         Transactions
+// @Param        account_id  path   int     true   "Account ID"
+// @Param        format      query  string  false  "csv (default), ndjson or ofx"
+// @Param        from        query  string  false  "First event date"
+// @Param        to          query  string  false  "Last event date"
+// @Param        tz          query  string  false  "IANA time zone, e.g. America/Sao_Paulo (default UTC)"
+// @Produce      text/csv
+// @Produce      application/x-ndjson
+// @Produce      application/x-ofx
+// @Success      200  {file}    file
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /accounts/{account_id}/transactions/export [get]
+func (h *TransactionHandler) ExportTransactions(c *gin.Context) {
+	accountID, err := strconv.ParseInt(c.Param("account_id"), 10, 64)
+	if err != nil || accountID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errors.InvalidParametersError.Error()})
+		return
+	}
+	location, err := exporter.ParseLocation(c.Query("tz"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	from, err := exporter.ParseBoundIn(c.Query("from"), false, location)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	to, err := exporter.ParseBoundIn(c.Query("to"), true, location)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	format := c.DefaultQuery("format", exporter.FormatCSV)
+	metadata := exporter.Metadata{AccountID: accountID, From: from, To: to, GeneratedAt: h.clock.Now(), Location: location}
+	writer, err := exporter.NewWriter(format, c.Writer, metadata)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Content-Type", exporter.ContentType(format))
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": exporter.FileName(format, metadata)}))
+	request := dto.ExportTransactionsRequest{AccountID: accountID, From: from, To: to, Location: location}
+	err = h.service.Export(c.Request.Context(), request, writer.Write)
+	if err == nil {
+		err = writer.Close()
+	}
+	if err == nil {
+		return
+	}
+	if c.Writer.Written() {
+		// the status was already sent, the client gets a truncated file
+		h.log.Error("TransactionHandler.ExportTransactions", "error", err, "x_trace_id", contextutils.GetTraceID(c.Request.Context()))
+		c.Abort()
+		return
+	}
+	c.Writer.Header().Del("Content-Disposition")
+	switch {
+	case stderrors.Is(err, errors.AccountNotFoundError):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case stderrors.Is(err, errors.InvalidParametersError):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// ListTransactions godoc
+// @Summary      Search transactions
+// @Description  Returns a page of transactions ordered by ID. merchant_name and description match case-insensitive substrings,
+// @Description  metadata[key]=value filters by metadata entries and from/to accept RFC 3339 or YYYY-MM-DD like the export.
+// @Description  Amounts are signed as stored. Pass the returned cursor to get the next page, it is zero on the last page.
+// @Description  tz is the time zone of the from/to dates and of local_date, which groups transactions by local day.
+// @Tags         Transactions
+// @Param        account_id        query  int     false  "Account ID"
+// @Param        status            query  string  false  "PENDING, POSTED or CANCELLED"
+// @Param        merchant_name     query  string  false  "Merchant name"
+// @Param        mcc               query  string  false  "Merchant category code"
+// @Param        merchant_country  query  string  false  "Merchant country (ISO 3166-1 alpha-2)"
+// @Param        description       query  string  false  "Description"
+// @Param        metadata          query  object  false  "Metadata entries, e.g. metadata[order_id]=42"
+// @Param        from              query  string  false  "First event date"
+// @Param        to                query  string  false  "Last event date"
+// @Param        tz                query  string  false  "IANA time zone, e.g. America/Sao_Paulo (default UTC)"
+// @Param        cursor            query  int     false  "Cursor returned by the previous page"
+// @Param        limit             query  int     false  "Page size, 50 by default and up to 500"
+// @Produce      json
+// @Success      200  {object}  dto.ListTransactionsResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /transactions [get]
+func (h *TransactionHandler) ListTransactions(c *gin.Context) {
+	var request dto.ListTransactionsRequest
+	var err error
+	for param, value := range map[string]*int64{"account_id": &request.AccountID, "cursor": &request.Cursor, "limit": &request.Limit} {
+		if raw := c.Query(param); raw != "" {
+			if *value, err = strconv.ParseInt(raw, 10, 64); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": errors.InvalidParametersError.Error()})
+				return
+			}
+		}
+	}
+	if request.Location, err = exporter.ParseLocation(c.Query("tz")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if request.From, err = exporter.ParseBoundIn(c.Query("from"), false, request.Location); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if request.To, err = exporter.ParseBoundIn(c.Query("to"), true, request.Location); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	request.Status = c.Query("status")
+	request.MerchantName = c.Query("merchant_name")
+	request.MCC = c.Query("mcc")
+	request.MerchantCountry = c.Query("merchant_country")
+	request.Description = c.Query("description")
+	request.Metadata = c.QueryMap("metadata")
+	res, err := h.service.List(c.Request.Context(), request)
+	switch {
+	case stderrors.Is(err, errors.InvalidParametersError), stderrors.Is(err, errors.TransactionInvalidDetailsError):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusOK, res)
+	}
+}
+
 // GetTransactionID godoc
 // @Summary      Get transaction by ID
-// @Description  Returns a transaction by ID
+// @Description  Returns a transaction by ID with the statuses it went through
 // @Tags         Transactions
 // @Param        id   path	int  true  "Transaction ID"
 // @Produce      json
 // @Success      200  {object}  dto.FindTransactionByIdResponse
 // @Failure      404  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Router       /transactions/{id} [get]
 func (h *TransactionHandler) GetTransactionByID(c *gin.Context) {
 	transactionId := c.Param("transaction_id")
@@ -73,3 +296,39 @@ func (h *TransactionHandler) GetTransactionByID(c *gin.Context) {
 
 	c.JSON(http.StatusOK, res)
 }
+
+// CancelTransaction godoc
+// @Summary      Cancel a scheduled transaction
+// @Description  Cancels a PENDING transaction, created with a future effective date, so it is never posted
+// @Tags         Transactions
+// @Param        transaction_id   path	int  true  "Transaction ID"
+// @Produce      json
+// @Success      200  {object}  dto.CancelTransactionResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string  "The transaction is not pending"
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /transactions/{transaction_id}/cancel [post]
+func (h *TransactionHandler) CancelTransaction(c *gin.Context) {
+	var req dto.CancelTransactionRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errors.InvalidParametersError.Error()})
+		return
+	}
+	res, err := h.service.Cancel(c.Request.Context(), req)
+	switch {
+	case stderrors.Is(err, errors.TransactionNotFoundError):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case stderrors.Is(err, errors.TransactionNotPendingError):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case stderrors.Is(err, errors.InvalidParametersError):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusOK, res)
+	}
+}
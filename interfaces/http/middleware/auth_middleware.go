@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/auth"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/contextkeys"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/contextutils"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"net/http"
+	"strings"
+)
+
+const APIKeyHeader = "X-API-Key"
+
+// AuthMiddleware authenticates the request with the authenticator matching the presented scheme
+// (Authorization: Bearer <jwt>, Authorization: ApiKey <key> or X-API-Key: <key>) and stores the principal in the context
+func AuthMiddleware(authenticators ...auth.Authenticator) gin.HandlerFunc {
+	authenticatorsByScheme := make(map[string]auth.Authenticator)
+	for _, authenticator := range authenticators {
+		authenticatorsByScheme[strings.ToLower(authenticator.Scheme())] = authenticator
+	}
+	return func(c *gin.Context) {
+		scheme, credential := credentialFromRequest(c)
+		authenticator, ok := authenticatorsByScheme[strings.ToLower(scheme)]
+		if credential == "" || !ok {
+			abortUnauthorized(c, errors.AuthenticationRequiredError)
+			return
+		}
+		principal, err := authenticator.Authenticate(c.Request.Context(), credential)
+		if err != nil {
+			abortUnauthorized(c, err)
+			return
+		}
+		ctx := context.WithValue(c.Request.Context(), contextkeys.PrincipalKey, principal)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// Authorization returns a factory of per route middlewares requiring scopes, or no-op middlewares when auth is disabled
+func Authorization(enabled bool) func(scopes ...string) gin.HandlerFunc {
+	return func(scopes ...string) gin.HandlerFunc {
+		if !enabled {
+			return func(c *gin.Context) { c.Next() }
+		}
+		return RequireScopes(scopes...)
+	}
+}
+
+// RequireScopes aborts with 403 when the authenticated principal was not granted every scope
+func RequireScopes(scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal := contextutils.GetPrincipal(c.Request.Context())
+		if principal == nil {
+			abortUnauthorized(c, errors.AuthenticationRequiredError)
+			return
+		}
+		if !principal.HasScopes(scopes...) {
+			c.Header("WWW-Authenticate", `Bearer error="insufficient_scope", scope="`+strings.Join(scopes, " ")+`"`)
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": errors.AuthInsufficientScopeError.Error()})
+			return
+		}
+		c.Next()
+	}
+}
+
+func credentialFromRequest(c *gin.Context) (string, string) {
+	if apiKey := c.GetHeader(APIKeyHeader); apiKey != "" {
+		return auth.SchemeAPIKey, apiKey
+	}
+	scheme, credential, found := strings.Cut(c.GetHeader("Authorization"), " ")
+	if !found {
+		return "", ""
+	}
+	return scheme, strings.TrimSpace(credential)
+}
+
+func abortUnauthorized(c *gin.Context, err error) {
+	c.Header("WWW-Authenticate", auth.SchemeBearer+`, `+auth.SchemeAPIKey)
+	c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/auth"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/contextutils"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type staticAuthenticator struct {
+	scheme     string
+	credential string
+	principal  *auth.Principal
+}
+
+func (s staticAuthenticator) Scheme() string {
+	return s.scheme
+}
+
+func (s staticAuthenticator) Authenticate(ctx context.Context, credential string) (*auth.Principal, error) {
+	if credential != s.credential {
+		return nil, errors.AuthInvalidCredentialsError
+	}
+	return s.principal, nil
+}
+
+func newAuthTestRouter(enabled bool) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	if enabled {
+		router.Use(AuthMiddleware(
+			staticAuthenticator{auth.SchemeBearer, "valid-token", &auth.Principal{Subject: "user-1", Scopes: []string{auth.ScopeAccountsRead}}},
+			staticAuthenticator{auth.SchemeAPIKey, "valid-key", &auth.Principal{Subject: "integrator", Scopes: []string{auth.ScopeAccountsWrite}}},
+		))
+	}
+	requireScopes := Authorization(enabled)
+	router.GET("/accounts", requireScopes(auth.ScopeAccountsRead), func(c *gin.Context) {
+		c.String(http.StatusOK, contextutils.GetPrincipalSubject(c.Request.Context()))
+	})
+	return router
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		enabled    bool
+		headers    map[string]string
+		wantStatus int
+		wantBody   string
+	}{
+		{"must allow anonymous requests when auth is disabled", false, nil, http.StatusOK, ""},
+		{"must reject requests without credentials", true, nil, http.StatusUnauthorized, ""},
+		{"must reject an unknown scheme", true, map[string]string{"Authorization": "Basic dXNlcjpwYXNz"}, http.StatusUnauthorized, ""},
+		{"must reject an invalid bearer token", true, map[string]string{"Authorization": "Bearer wrong"}, http.StatusUnauthorized, ""},
+		{"must accept a bearer token with the route scope", true, map[string]string{"Authorization": "Bearer valid-token"}, http.StatusOK, "user-1"},
+		{"must forbid an api key without the route scope", true, map[string]string{APIKeyHeader: "valid-key"}, http.StatusForbidden, ""},
+		{"must read api keys from the authorization header", true, map[string]string{"Authorization": "ApiKey valid-key"}, http.StatusForbidden, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newAuthTestRouter(tt.enabled)
+			request := httptest.NewRequest(http.MethodGet, "/accounts", nil)
+			for key, value := range tt.headers {
+				request.Header.Set(key, value)
+			}
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, tt.wantBody, recorder.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package middleware
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/contextkeys"
+)
+
+// ClientIPMiddleware stores the client IP in the request context, where it is read by the audit log
+func ClientIPMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := context.WithValue(c.Request.Context(), contextkeys.ClientIPKey, c.ClientIP())
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
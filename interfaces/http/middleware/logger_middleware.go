@@ -19,6 +19,7 @@ func LoggerMiddleware(sLogger logger.Logger) gin.HandlerFunc {
 		clientIP := c.ClientIP()
 		host := c.Request.Host
 		xTraceID := contextutils.GetTraceID(c.Request.Context())
+		principal := contextutils.GetPrincipalSubject(c.Request.Context())
 		sLogger.Info(
 			"HTTP Request",
 			"method", method,
@@ -28,6 +29,7 @@ func LoggerMiddleware(sLogger logger.Logger) gin.HandlerFunc {
 			"client_ip", clientIP,
 			"host", host,
 			"x_trace_id", xTraceID,
+			"principal", principal,
 		)
 	}
 }
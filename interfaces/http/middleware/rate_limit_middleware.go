@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/config"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/contextutils"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/ratelimit"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimitMiddleware limits the requests of each caller to each route using the first matching rule of the
+// configuration. The caller is the authenticated principal or, for anonymous requests, the client IP.
+// Requests are allowed when the limiter is unavailable.
+func RateLimitMiddleware(limiter ratelimit.RateLimiter, cfg config.RateLimitConfig, log logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		caller := contextutils.GetPrincipalSubject(c.Request.Context())
+		callerKey := "principal:" + caller
+		if caller == "" {
+			caller = c.ClientIP()
+			callerKey = "ip:" + caller
+		}
+		rule := MatchRateLimitRule(cfg, c.Request.Method, c.FullPath(), caller)
+		if rule.Requests <= 0 || rule.WindowMs <= 0 {
+			c.Next()
+			return
+		}
+		key := strings.Join([]string{ratelimit.RequestKeyPrefix, c.Request.Method, c.FullPath(), callerKey}, ":")
+		limit := ratelimit.Limit{Requests: rule.Requests, Window: time.Duration(rule.WindowMs) * time.Millisecond}
+		result, err := limiter.Allow(c.Request.Context(), key, limit)
+		if err != nil {
+			log.Warn("RateLimitMiddleware", "status", "limiter unavailable, request allowed", "error", err, "x_trace_id", contextutils.GetTraceID(c.Request.Context()))
+			c.Next()
+			return
+		}
+		if !result.Allowed {
+			RespondRateLimitExceeded(c, result)
+			return
+		}
+		SetRateLimitHeaders(c.Writer.Header(), result)
+		c.Next()
+	}
+}
+
+// MatchRateLimitRule returns the first rule matching the request or the default rule
+func MatchRateLimitRule(cfg config.RateLimitConfig, method string, path string, caller string) config.RateLimitRule {
+	for _, rule := range cfg.Rules {
+		if (rule.Method == "" || strings.EqualFold(rule.Method, method)) &&
+			(rule.Path == "" || rule.Path == path) &&
+			(rule.Principal == "" || rule.Principal == caller) {
+			return rule
+		}
+	}
+	return cfg.Default
+}
+
+// SetRateLimitHeaders writes the RateLimit-* headers of the IETF draft, in seconds
+func SetRateLimitHeaders(header http.Header, result *ratelimit.Result) {
+	header.Set("RateLimit-Limit", strconv.FormatInt(result.Limit, 10))
+	header.Set("RateLimit-Remaining", strconv.FormatInt(max(result.Remaining, 0), 10))
+	header.Set("RateLimit-Reset", strconv.FormatInt(ceilSeconds(result.ResetAfter), 10))
+}
+
+// RespondRateLimitExceeded aborts the request with 429 and the Retry-After header
+func RespondRateLimitExceeded(c *gin.Context, result *ratelimit.Result) {
+	SetRateLimitHeaders(c.Writer.Header(), result)
+	c.Header("Retry-After", strconv.FormatInt(max(ceilSeconds(result.RetryAfter), 1), 10))
+	c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": errors.RateLimitExceededError.Error()})
+}
+
+func ceilSeconds(duration time.Duration) int64 {
+	return int64(math.Ceil(duration.Seconds()))
+}
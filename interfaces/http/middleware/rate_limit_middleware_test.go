@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/config"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/ratelimit"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/logger/mock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestMatchRateLimitRule(t *testing.T) {
+	cfg := config.RateLimitConfig{
+		Default: config.RateLimitRule{Requests: 100, WindowMs: 60000},
+		Rules: []config.RateLimitRule{
+			{Principal: "batch-importer", Requests: 1000, WindowMs: 60000},
+			{Method: "POST", Path: "/transactions", Requests: 10, WindowMs: 1000},
+		},
+	}
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		caller       string
+		wantRequests int64
+	}{
+		{"must match a principal rule on any route", "POST", "/transactions", "batch-importer", 1000},
+		{"must match a route rule", "post", "/transactions", "integrator", 10},
+		{"must fall back to the default rule", "GET", "/accounts/:account_id", "integrator", 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := MatchRateLimitRule(cfg, tt.method, tt.path, tt.caller)
+			assert.Equal(t, tt.wantRequests, rule.Requests)
+		})
+	}
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	limiter := ratelimit.NewRateLimiterMock(ctrl)
+	cfg := config.RateLimitConfig{Enabled: true, Default: config.RateLimitRule{Requests: 1, WindowMs: 1000}}
+	router := gin.New()
+	router.Use(RateLimitMiddleware(limiter, cfg, mock.NewMockLogger()))
+	router.GET("/accounts/:account_id", func(c *gin.Context) { c.Status(http.StatusOK) })
+	expectedLimit := ratelimit.Limit{Requests: 1, Window: time.Second}
+
+	limiter.EXPECT().Allow(gomock.Any(), "ratelimit:GET:/accounts/:account_id:ip:192.0.2.1", expectedLimit).Return(
+		&ratelimit.Result{Allowed: true, Limit: 1, Remaining: 0, ResetAfter: 800 * time.Millisecond}, nil)
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/accounts/1", nil)
+	request.RemoteAddr = "192.0.2.1:1234"
+	router.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "1", recorder.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "0", recorder.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "1", recorder.Header().Get("RateLimit-Reset"))
+
+	limiter.EXPECT().Allow(gomock.Any(), gomock.Any(), expectedLimit).Return(
+		&ratelimit.Result{Allowed: false, Limit: 1, Remaining: 0, ResetAfter: 1500 * time.Millisecond, RetryAfter: 1500 * time.Millisecond}, nil)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	assert.Equal(t, "2", recorder.Header().Get("Retry-After"))
+	assert.Equal(t, "2", recorder.Header().Get("RateLimit-Reset"))
+}
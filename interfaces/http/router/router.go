@@ -5,22 +5,42 @@ import (
 	_ "github.com/kiosanim/pismo-code-assessment/docs"
 	"github.com/kiosanim/pismo-code-assessment/interfaces/http/handler"
 	"github.com/kiosanim/pismo-code-assessment/interfaces/http/middleware"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/auth"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
 	"github.com/swaggo/files"
 	"github.com/swaggo/gin-swagger"
 )
 
-func SetupRouter(accountHandler handler.AccountHandler, transactionHandler handler.TransactionHandler, log logger.Logger) *gin.Engine {
+// SetupRouter registers the API routes, requiring authentication and per route scopes when authenticators are provided
+// and limiting the request rate when rateLimit is not nil
+func SetupRouter(accountHandler handler.AccountHandler, transactionHandler handler.TransactionHandler, auditHandler handler.AuditHandler, holderHandler handler.HolderHandler, authenticators []auth.Authenticator, rateLimit gin.HandlerFunc, log logger.Logger) *gin.Engine {
 	router := gin.Default()
 	router.Use(middleware.TraceMiddleware())
+	router.Use(middleware.ClientIPMiddleware())
 	router.Use(middleware.LoggerMiddleware(log))
 	api := router.Group("")
+	if len(authenticators) > 0 {
+		api.Use(middleware.AuthMiddleware(authenticators...))
+	}
+	if rateLimit != nil {
+		api.Use(rateLimit)
+	}
+	requireScopes := middleware.Authorization(len(authenticators) > 0)
 	{
-		api.POST("/accounts", accountHandler.CreateAccount)
-		api.GET("/accounts/:account_id", accountHandler.GetAccountByID)
-		api.GET("/accounts/list/:cursor/:limit", accountHandler.ListAccounts)
-		api.POST("/transactions", transactionHandler.CreateTransaction)
-		api.GET("/transactions/:transaction_id", transactionHandler.GetTransactionByID)
+		api.POST("/accounts", requireScopes(auth.ScopeAccountsWrite), accountHandler.CreateAccount)
+		api.GET("/accounts/:account_id", requireScopes(auth.ScopeAccountsRead), accountHandler.GetAccountByID)
+		api.GET("/accounts/list/:cursor/:limit", requireScopes(auth.ScopeAccountsRead), accountHandler.ListAccounts)
+		api.GET("/accounts/:account_id/holder", requireScopes(auth.ScopeAccountsRead), holderHandler.GetHolder)
+		api.PATCH("/accounts/:account_id/holder", requireScopes(auth.ScopeAccountsWrite), holderHandler.UpdateHolder)
+		api.GET("/accounts/:account_id/holder/history", requireScopes(auth.ScopeAccountsRead), holderHandler.GetHolderHistory)
+		api.GET("/accounts/:account_id/transactions/export", requireScopes(auth.ScopeTransactionsRead), transactionHandler.ExportTransactions)
+		api.GET("/transactions", requireScopes(auth.ScopeTransactionsRead), transactionHandler.ListTransactions)
+		api.POST("/transactions", requireScopes(auth.ScopeTransactionsWrite), transactionHandler.CreateTransaction)
+		api.POST("/transactions/batch", requireScopes(auth.ScopeTransactionsWrite), transactionHandler.CreateTransactionBatch)
+		api.GET("/transactions/:transaction_id", requireScopes(auth.ScopeTransactionsRead), transactionHandler.GetTransactionByID)
+		api.POST("/transactions/:transaction_id/cancel", requireScopes(auth.ScopeTransactionsWrite), transactionHandler.CancelTransaction)
+		api.GET("/audit-log", requireScopes(auth.ScopeAuditRead), auditHandler.ListAuditLog)
+		api.GET("/audit-log/verify", requireScopes(auth.ScopeAuditRead), auditHandler.VerifyAuditLog)
 	}
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	return router
@@ -3,12 +3,26 @@ package router
 import (
 	"github.com/gin-gonic/gin"
 	acc "github.com/kiosanim/pismo-code-assessment/application/account/service"
+	aud "github.com/kiosanim/pismo-code-assessment/application/audit/service"
+	hol "github.com/kiosanim/pismo-code-assessment/application/holder/service"
 	tra "github.com/kiosanim/pismo-code-assessment/application/transaction/service"
+	"github.com/kiosanim/pismo-code-assessment/interfaces/http/middleware"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/auth"
+	corefactory "github.com/kiosanim/pismo-code-assessment/internal/core/factory"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
 	"github.com/kiosanim/pismo-code-assessment/internal/infra/factory"
 )
 
 func NewRouterFactory(appFactory factory.AppFactory, log logger.Logger) *gin.Engine {
+	authenticators, err := appFactory.Authenticators()
+	if err != nil {
+		panic(err)
+	}
+	return NewRouter(&appFactory, authenticators, log)
+}
+
+// NewRouter wires the services and handlers built by appFactory, authenticating requests when there are authenticators
+func NewRouter(appFactory corefactory.Factory, authenticators []auth.Authenticator, log logger.Logger) *gin.Engine {
 	accountRepo := appFactory.AccountRepository()
 	if accountRepo == nil {
 		panic("Account Repository not initialized")
@@ -17,11 +31,11 @@ func NewRouterFactory(appFactory factory.AppFactory, log logger.Logger) *gin.Eng
 	if transactionRepo == nil {
 		panic("Transaction Repository not initialized")
 	}
-	accountSvc := acc.NewAccountService(&appFactory)
+	accountSvc := acc.NewAccountService(appFactory)
 	if accountSvc == nil {
 		panic("Account Service not initialized")
 	}
-	transactionSvc := tra.NewTransactionService(&appFactory)
+	transactionSvc := tra.NewTransactionService(appFactory)
 	if transactionSvc == nil {
 		panic("Transaction Service not initialized")
 	}
@@ -33,5 +47,17 @@ func NewRouterFactory(appFactory factory.AppFactory, log logger.Logger) *gin.Eng
 	if transactionHandler == nil {
 		panic("Transaction Handler not initialized")
 	}
-	return SetupRouter(*accountHandler, *transactionHandler, log)
+	auditHandler := appFactory.AuditHandler(aud.NewAuditService(appFactory))
+	if auditHandler == nil {
+		panic("Audit Handler not initialized")
+	}
+	holderHandler := appFactory.HolderHandler(hol.NewHolderService(appFactory))
+	if holderHandler == nil {
+		panic("Holder Handler not initialized")
+	}
+	var rateLimit gin.HandlerFunc
+	if rateLimitConfig := appFactory.Configuration().RateLimit; rateLimitConfig.Enabled {
+		rateLimit = middleware.RateLimitMiddleware(appFactory.RateLimiter(), rateLimitConfig, log)
+	}
+	return SetupRouter(*accountHandler, *transactionHandler, *auditHandler, *holderHandler, authenticators, rateLimit, log)
 }
@@ -0,0 +1,46 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/contextkeys"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+)
+
+// UnitOfWork runs several repository calls in a single database transaction, so they succeed or fail together
+type UnitOfWork interface {
+	// Do runs fn in a database transaction at isolation, committed when fn returns nil and rolled back otherwise. The
+	// repositories called with the context given to fn run their statements in that transaction. Calls of Do nested in
+	// fn join the running transaction, whatever their isolation. fn may run again in a new transaction when the first one
+	// conflicted with a concurrent transaction.
+	Do(ctx context.Context, isolation sql.IsolationLevel, fn func(ctx context.Context) error) error
+}
+
+// ContextWithTx returns a copy of ctx carrying the transaction of a unit of work
+func ContextWithTx(ctx context.Context, tx *sql.Tx) context.Context {
+	return context.WithValue(ctx, contextkeys.UnitOfWorkTxKey, tx)
+}
+
+// TxFromContext returns the transaction of the unit of work running in ctx, or nil outside of a unit of work
+func TxFromContext(ctx context.Context) *sql.Tx {
+	tx, _ := ctx.Value(contextkeys.UnitOfWorkTxKey).(*sql.Tx)
+	return tx
+}
+
+// ParseIsolationLevel returns the isolation level named read_committed, repeatable_read or serializable in the
+// configuration, an empty name being the default level of the database
+func ParseIsolationLevel(name string) (sql.IsolationLevel, error) {
+	switch name {
+	case "":
+		return sql.LevelDefault, nil
+	case "read_committed":
+		return sql.LevelReadCommitted, nil
+	case "repeatable_read":
+		return sql.LevelRepeatableRead, nil
+	case "serializable":
+		return sql.LevelSerializable, nil
+	default:
+		return sql.LevelDefault, fmt.Errorf("%w: unknown isolation level %q", errors.ConfigValidationError, name)
+	}
+}
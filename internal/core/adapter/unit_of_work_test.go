@@ -0,0 +1,41 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseIsolationLevel(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    sql.IsolationLevel
+		wantErr bool
+	}{
+		{"", sql.LevelDefault, false},
+		{"read_committed", sql.LevelReadCommitted, false},
+		{"repeatable_read", sql.LevelRepeatableRead, false},
+		{"serializable", sql.LevelSerializable, false},
+		{"snapshot", sql.LevelDefault, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isolation, err := ParseIsolationLevel(tt.name)
+			assert.Equal(t, tt.want, isolation)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, errors.ConfigValidationError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTxFromContext(t *testing.T) {
+	assert.Nil(t, TxFromContext(context.Background()), "a context outside of a unit of work should have no transaction")
+	tx := &sql.Tx{}
+	assert.Same(t, tx, TxFromContext(ContextWithTx(context.Background(), tx)))
+}
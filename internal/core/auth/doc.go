@@ -0,0 +1,2 @@
+// Package auth provides the principal and authenticator abstractions used to protect the API.
+package auth
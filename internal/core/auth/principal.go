@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"context"
+	"slices"
+)
+
+const (
+	ScopeAccountsRead      = "accounts:read"
+	ScopeAccountsWrite     = "accounts:write"
+	ScopeAccountsPII       = "accounts:pii" // Reads unmasked document numbers
+	ScopeTransactionsRead  = "transactions:read"
+	ScopeTransactionsWrite = "transactions:write"
+	ScopeAuditRead         = "audit:read"
+)
+
+const (
+	MethodJWT    = "jwt"
+	MethodAPIKey = "api_key"
+)
+
+const (
+	SchemeBearer = "Bearer"
+	SchemeAPIKey = "ApiKey"
+)
+
+// Principal represents the authenticated caller of a request
+type Principal struct {
+	Subject string   // User or machine client identifier
+	Method  string   // Authentication method used (jwt or api_key)
+	Scopes  []string // Granted scopes, e.g. accounts:read
+}
+
+// HasScopes checks if every scope was granted to the principal
+func (p *Principal) HasScopes(scopes ...string) bool {
+	if p == nil {
+		return false
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(p.Scopes, scope) {
+			return false
+		}
+	}
+	return true
+}
+
+// Authenticator validates a credential presented using its Scheme
+type Authenticator interface {
+	Scheme() string
+	Authenticate(ctx context.Context, credential string) (*Principal, error)
+}
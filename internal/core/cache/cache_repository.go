@@ -24,3 +24,9 @@ type CacheRepository interface {
 	HExists(ctx context.Context, key string, fieldName string) (bool, error)
 	HExpire(ctx context.Context, key string, fieldName string, duration time.Duration) error
 }
+
+// Purger removes cached entries in bulk
+type Purger interface {
+	// Purge removes the keys matching a glob pattern, see KeyPattern, returning how many were removed
+	Purge(ctx context.Context, pattern string) (int64, error)
+}
@@ -0,0 +1,171 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/config"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/contextutils"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
+	"golang.org/x/sync/singleflight"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	AccountEntity       = "account"
+	TransactionEntity   = "transaction"
+	OperationTypeEntity = "operation_type"
+)
+
+// statsLogInterval is the number of lookups between two hit ratio log messages
+const statsLogInterval = 1000
+
+// Codec converts the entities of an EntityCache to the form they are cached in and back, so fields that must not be
+// stored in clear text, like document numbers, are encrypted. Both return a new value, leaving the one given unchanged.
+type Codec[T any] interface {
+	Encode(value *T) (*T, error)
+	Decode(value *T) (*T, error)
+}
+
+// EntityCache is a read-through cache of JSON encoded entities stored in a CacheRepository.
+// Concurrent misses of the same key are collapsed into a single load to protect the database against stampedes.
+type EntityCache[T any] struct {
+	entity     string
+	repository CacheRepository
+	codec      Codec[T] // Converts the cached entities, nil when they are cached as they are
+	enabled    bool
+	ttl        time.Duration
+	group      singleflight.Group
+	hits       atomic.Int64
+	misses     atomic.Int64
+	log        logger.Logger
+}
+
+func NewEntityCache[T any](entity string, repository CacheRepository, cfg config.CacheEntityConfig, log logger.Logger) *EntityCache[T] {
+	return &EntityCache[T]{
+		entity:     entity,
+		repository: repository,
+		enabled:    cfg.Enabled && cfg.TTLMs > 0,
+		ttl:        time.Duration(cfg.TTLMs) * time.Millisecond,
+		log:        log,
+	}
+}
+
+// WithCodec converts the entities with codec when they are written to and read from the cache
+func (e *EntityCache[T]) WithCodec(codec Codec[T]) *EntityCache[T] {
+	e.codec = codec
+	return e
+}
+
+// Key returns the cache key of an entity
+func (e *EntityCache[T]) Key(id int64) string {
+	return fmt.Sprintf("cache:%s:%d", e.entity, id)
+}
+
+// KeyPattern returns the pattern of the cache keys of the entities of a kind, the single key of an entity when id is
+// greater than zero, or of all the cached entities when entity is empty
+func KeyPattern(entity string, id int64) string {
+	switch {
+	case entity == "":
+		return "cache:*"
+	case id > 0:
+		return fmt.Sprintf("cache:%s:%d", entity, id)
+	}
+	return fmt.Sprintf("cache:%s:*", entity)
+}
+
+// Get returns the cached entity or loads and caches it. Cache failures fall back to load.
+func (e *EntityCache[T]) Get(ctx context.Context, id int64, load func(ctx context.Context) (*T, error)) (*T, error) {
+	if !e.enabled {
+		return load(ctx)
+	}
+	key := e.Key(id)
+	if cached, ok := e.read(ctx, key); ok {
+		e.record(ctx, true)
+		return cached, nil
+	}
+	e.record(ctx, false)
+	value, err, _ := e.group.Do(key, func() (any, error) {
+		loaded, err := load(ctx)
+		if err != nil || loaded == nil {
+			return loaded, err
+		}
+		e.write(ctx, key, loaded)
+		return loaded, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*T), nil
+}
+
+// Invalidate removes an entity from the cache after it was written
+func (e *EntityCache[T]) Invalidate(ctx context.Context, id int64) {
+	if !e.enabled {
+		return
+	}
+	if err := e.repository.Del(ctx, e.Key(id)); err != nil {
+		e.log.Warn("EntityCache.Invalidate", "entity", e.entity, "id", id, "error", err, "x_trace_id", contextutils.GetTraceID(ctx))
+	}
+}
+
+// HitRatio returns the ratio of lookups answered by the cache
+func (e *EntityCache[T]) HitRatio() float64 {
+	hits, misses := e.hits.Load(), e.misses.Load()
+	if hits+misses == 0 {
+		return 0
+	}
+	return float64(hits) / float64(hits+misses)
+}
+
+func (e *EntityCache[T]) read(ctx context.Context, key string) (*T, bool) {
+	content, err := e.repository.Get(ctx, key)
+	if err != nil {
+		return nil, false
+	}
+	value := new(T)
+	if err = json.Unmarshal([]byte(content), value); err != nil {
+		e.log.Warn("EntityCache.read", "entity", e.entity, "key", key, "error", err)
+		return nil, false
+	}
+	if e.codec != nil {
+		// Entries that can not be decoded, like the ones cached before the codec, are loaded again
+		if value, err = e.codec.Decode(value); err != nil {
+			e.log.Warn("EntityCache.read", "entity", e.entity, "key", key, "error", err)
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+func (e *EntityCache[T]) write(ctx context.Context, key string, value *T) {
+	var err error
+	if e.codec != nil {
+		if value, err = e.codec.Encode(value); err != nil {
+			e.log.Warn("EntityCache.write", "entity", e.entity, "key", key, "error", err)
+			return
+		}
+	}
+	content, err := json.Marshal(value)
+	if err != nil {
+		e.log.Warn("EntityCache.write", "entity", e.entity, "key", key, "error", err)
+		return
+	}
+	if err = e.repository.Set(ctx, key, string(content), e.ttl); err != nil {
+		e.log.Warn("EntityCache.write", "entity", e.entity, "key", key, "error", err, "x_trace_id", contextutils.GetTraceID(ctx))
+	}
+}
+
+func (e *EntityCache[T]) record(ctx context.Context, hit bool) {
+	var lookups int64
+	if hit {
+		lookups = e.hits.Add(1) + e.misses.Load()
+	} else {
+		lookups = e.misses.Add(1) + e.hits.Load()
+	}
+	e.log.Debug("EntityCache.Get", "entity", e.entity, "hit", hit, "x_trace_id", contextutils.GetTraceID(ctx))
+	if lookups%statsLogInterval == 0 {
+		e.log.Info("EntityCache.Stats", "entity", e.entity, "lookups", lookups, "hit_ratio", e.HitRatio())
+	}
+}
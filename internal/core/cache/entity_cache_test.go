@@ -0,0 +1,116 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kiosanim/pismo-code-assessment/internal/core/config"
+	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type cachedEntity struct {
+	ID   int64
+	Name string
+}
+
+func newTestEntityCache(t *testing.T, cfg config.CacheEntityConfig) (*EntityCache[cachedEntity], *CacheRepositoryMock) {
+	ctrl := gomock.NewController(t)
+	repository := NewCacheRepositoryMock(ctrl)
+	log := logger.NewLoggerMock(ctrl)
+	log.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+	log.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+	log.EXPECT().Warn(gomock.Any(), gomock.Any()).AnyTimes()
+	return NewEntityCache[cachedEntity]("entity", repository, cfg, log), repository
+}
+
+func TestEntityCacheDisabled(t *testing.T) {
+	entityCache, _ := newTestEntityCache(t, config.CacheEntityConfig{Enabled: false, TTLMs: 1000})
+	value, err := entityCache.Get(context.Background(), 1, func(ctx context.Context) (*cachedEntity, error) {
+		return &cachedEntity{ID: 1}, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), value.ID, "value should be loaded without touching the cache")
+	entityCache.Invalidate(context.Background(), 1)
+}
+
+func TestEntityCacheHitAndMiss(t *testing.T) {
+	entityCache, repository := newTestEntityCache(t, config.CacheEntityConfig{Enabled: true, TTLMs: 1000})
+	ctx := context.Background()
+	repository.EXPECT().Get(ctx, "cache:entity:1").Return("", coreerr.CacheNotFoundError)
+	repository.EXPECT().Set(ctx, "cache:entity:1", `{"ID":1,"Name":"loaded"}`, time.Second).Return(nil)
+	value, err := entityCache.Get(ctx, 1, func(ctx context.Context) (*cachedEntity, error) {
+		return &cachedEntity{ID: 1, Name: "loaded"}, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "loaded", value.Name)
+
+	repository.EXPECT().Get(ctx, "cache:entity:1").Return(`{"ID":1,"Name":"cached"}`, nil)
+	value, err = entityCache.Get(ctx, 1, func(ctx context.Context) (*cachedEntity, error) {
+		t.Fatal("load should not be called on a cache hit")
+		return nil, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "cached", value.Name)
+	assert.Equal(t, 0.5, entityCache.HitRatio())
+
+	repository.EXPECT().Del(ctx, "cache:entity:1").Return(nil)
+	entityCache.Invalidate(ctx, 1)
+}
+
+func TestEntityCacheDoesNotCacheErrors(t *testing.T) {
+	entityCache, repository := newTestEntityCache(t, config.CacheEntityConfig{Enabled: true, TTLMs: 1000})
+	ctx := context.Background()
+	repository.EXPECT().Get(ctx, "cache:entity:2").Return("", coreerr.CacheNotFoundError)
+	loadErr := errors.New("not found")
+	value, err := entityCache.Get(ctx, 2, func(ctx context.Context) (*cachedEntity, error) {
+		return nil, loadErr
+	})
+	assert.ErrorIs(t, err, loadErr)
+	assert.Nil(t, value)
+}
+
+func TestEntityCacheCollapsesConcurrentMisses(t *testing.T) {
+	entityCache, repository := newTestEntityCache(t, config.CacheEntityConfig{Enabled: true, TTLMs: 1000})
+	ctx := context.Background()
+	repository.EXPECT().Get(ctx, "cache:entity:3").Return("", coreerr.CacheNotFoundError).AnyTimes()
+	repository.EXPECT().Set(ctx, "cache:entity:3", gomock.Any(), time.Second).Return(nil).AnyTimes()
+	var loads atomic.Int64
+	release := make(chan struct{})
+	load := func(ctx context.Context) (*cachedEntity, error) {
+		loads.Add(1)
+		<-release
+		return &cachedEntity{ID: 3}, nil
+	}
+	const callers = 10
+	var started, finished sync.WaitGroup
+	started.Add(callers)
+	finished.Add(callers)
+	for i := 0; i < callers; i++ {
+		go func() {
+			defer finished.Done()
+			started.Done()
+			value, err := entityCache.Get(ctx, 3, load)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(3), value.ID)
+		}()
+	}
+	started.Wait()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	finished.Wait()
+	assert.Equal(t, int64(1), loads.Load(), "concurrent misses should trigger a single load")
+}
+
+func TestKeyPattern(t *testing.T) {
+	assert.Equal(t, "cache:*", KeyPattern("", 0), "no entity should match every cached entity")
+	assert.Equal(t, "cache:*", KeyPattern("", 1), "an ID without entity should match every cached entity")
+	assert.Equal(t, "cache:account:*", KeyPattern(AccountEntity, 0), "an entity should match all its entries")
+	assert.Equal(t, "cache:account:7", KeyPattern(AccountEntity, 7), "an entity ID should match its key")
+}
@@ -0,0 +1,2 @@
+// Package clock defines the source of the current time, so dates and timeouts can be controlled in tests
+package clock
@@ -1,17 +1,38 @@
 package config
 
+const (
+	LockBackendRedis    = "redis"
+	LockBackendPostgres = "postgres"
+)
+
+const (
+	DatabaseDriverPostgres = "postgres"
+	DatabaseDriverSQLite   = "sqlite"
+)
+
 type DistributedLock struct {
-	TTL           int64 `mapstructure:"ttl_ms"`
-	RetryInterval int64 `mapstructure:"retry_interval_ms"`
-	WaitingTime   int64 `mapstructure:"waiting_time_ms"`
+	Backend       string `mapstructure:"backend"` // LockBackendRedis, the default, or LockBackendPostgres
+	TTL           int64  `mapstructure:"ttl_ms"`
+	RetryInterval int64  `mapstructure:"retry_interval_ms"`
+	WaitingTime   int64  `mapstructure:"waiting_time_ms"`
+}
+
+// CacheEntityConfig toggles the read-through cache of an entity
+type CacheEntityConfig struct {
+	Enabled bool  `mapstructure:"enabled"`
+	TTLMs   int64 `mapstructure:"ttl_ms"`
 }
 
 type CacheConfig struct {
-	URL string `mapstructure:"url"`
+	URL            string            `mapstructure:"url"`
+	Accounts       CacheEntityConfig `mapstructure:"accounts"`
+	Transactions   CacheEntityConfig `mapstructure:"transactions"`
+	OperationTypes CacheEntityConfig `mapstructure:"operation_types"`
 }
 
 type DatabaseConfig struct {
-	URL string `yaml:"url"`
+	Driver string `mapstructure:"driver"` // DatabaseDriverPostgres, the default, or DatabaseDriverSQLite
+	URL    string `yaml:"url"`            // Connection string of Postgres or file name of SQLite
 }
 type AppConfig struct {
 	Env      string `mapstructure:"env"`
@@ -19,14 +40,96 @@ type AppConfig struct {
 	LogLevel string `mapstructure:"log_level"`
 }
 
+type JWTConfig struct {
+	Algorithm string `mapstructure:"algorithm"` // HS256 or RS256
+	Secret    string `mapstructure:"secret"`    // Shared secret used by HS256
+	JWKSFile  string `mapstructure:"jwks_file"` // Local JWKS file with the RS256 public keys
+	Issuer    string `mapstructure:"issuer"`
+	Audience  string `mapstructure:"audience"`
+}
+
+type APIKeyConfig struct {
+	Name      string   `mapstructure:"name"`       // Machine client identifier
+	KeySHA256 string   `mapstructure:"key_sha256"` // Hex encoded SHA-256 of the API key
+	Scopes    []string `mapstructure:"scopes"`
+}
+
+type AuthConfig struct {
+	Enabled bool           `mapstructure:"enabled"`
+	JWT     JWTConfig      `mapstructure:"jwt"`
+	APIKeys []APIKeyConfig `mapstructure:"api_keys"`
+}
+
+// RateLimitRule limits the requests of each caller (principal subject or client IP) to each route.
+// Empty Method, Path and Principal match any value.
+type RateLimitRule struct {
+	Method    string `mapstructure:"method"`
+	Path      string `mapstructure:"path"` // Gin route path, e.g. /accounts/:account_id
+	Principal string `mapstructure:"principal"`
+	Requests  int64  `mapstructure:"requests"`
+	WindowMs  int64  `mapstructure:"window_ms"`
+}
+
+type RateLimitConfig struct {
+	Enabled                      bool            `mapstructure:"enabled"`
+	Default                      RateLimitRule   `mapstructure:"default"`
+	Rules                        []RateLimitRule `mapstructure:"rules"` // The first matching rule is used
+	AccountTransactionsPerMinute int64           `mapstructure:"account_transactions_per_minute"`
+}
+
+// CurrencyConfig sets the currency of new accounts and the file with the exchange rates of foreign currency transactions
+type CurrencyConfig struct {
+	Default   string `mapstructure:"default"`
+	RatesFile string `mapstructure:"rates_file"`
+}
+
+// PIIConfig sets the keys protecting document numbers at rest and the log attributes whose values are redacted.
+// Keys are base64 encoded 32 bytes, informed inline or in a file.
+type PIIConfig struct {
+	EncryptionKey         string   `mapstructure:"encryption_key"`      // AES-256-GCM key
+	EncryptionKeyFile     string   `mapstructure:"encryption_key_file"` // File with the encryption key, used when encryption_key is empty
+	BlindIndexKey         string   `mapstructure:"blind_index_key"`     // HMAC-SHA256 key of the lookup index
+	BlindIndexKeyFile     string   `mapstructure:"blind_index_key_file"`
+	RedactedLogAttributes []string `mapstructure:"redacted_log_attributes"` // Case-insensitive attribute names
+}
+
+// LedgerConfig sets the flat fees, in the account currency, charged by operation type and posted to the fees ledger account
+type LedgerConfig struct {
+	Fees []LedgerFeeConfig `mapstructure:"fees"`
+}
+
+type LedgerFeeConfig struct {
+	OperationTypeID int     `mapstructure:"operation_type_id"`
+	Amount          float64 `mapstructure:"amount"`
+}
+
+// TransactionsConfig sets how far in the past clients may date their transactions. Zero rejects client event dates.
+type TransactionsConfig struct {
+	BackdatingWindowMs int64 `mapstructure:"backdating_window_ms"`
+	// IsolationLevel of the database transaction creating a transaction: read_committed, repeatable_read or
+	// serializable, the database default when empty
+	IsolationLevel string `mapstructure:"isolation_level"`
+}
+
 type Configuration struct {
-	App             AppConfig       `mapstructure:"app"`
-	Database        DatabaseConfig  `mapstructure:"database"`
-	Cache           CacheConfig     `mapstructure:"cache"`
-	DistributedLock DistributedLock `mapstructure:"distributed_lock"`
+	App             AppConfig          `mapstructure:"app"`
+	Database        DatabaseConfig     `mapstructure:"database"`
+	Cache           CacheConfig        `mapstructure:"cache"`
+	DistributedLock DistributedLock    `mapstructure:"distributed_lock"`
+	Auth            AuthConfig         `mapstructure:"auth"`
+	RateLimit       RateLimitConfig    `mapstructure:"rate_limit"`
+	Currency        CurrencyConfig     `mapstructure:"currency"`
+	PII             PIIConfig          `mapstructure:"pii"`
+	Ledger          LedgerConfig       `mapstructure:"ledger"`
+	Transactions    TransactionsConfig `mapstructure:"transactions"`
 }
 
 type Config interface {
 	LoadConfig(path string) (*Configuration, error)
 	MustLoadConfig(path string) *Configuration
 }
+
+// Reloadable is implemented by components that can apply non-critical configuration changes at runtime
+type Reloadable interface {
+	ApplyConfiguration(cfg *Configuration)
+}
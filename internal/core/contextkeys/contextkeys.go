@@ -1,5 +1,9 @@
 package contextkeys
 
 const (
-	TraceIDKey string = "x-trace-id"
+	TraceIDKey   string = "x-trace-id"
+	PrincipalKey string = "principal"
+	ClientIPKey  string = "client-ip"
+	// UnitOfWorkTxKey holds the database transaction of the unit of work running in a context
+	UnitOfWorkTxKey string = "unit-of-work-tx"
 )
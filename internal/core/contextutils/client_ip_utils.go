@@ -0,0 +1,18 @@
+package contextutils
+
+import (
+	"context"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/contextkeys"
+)
+
+// GetClientIP returns the IP address of the client of the request or an empty string outside of requests
+func GetClientIP(ctx context.Context) string {
+	value := ctx.Value(contextkeys.ClientIPKey)
+	if value != nil {
+		clientIP, ok := value.(string)
+		if ok {
+			return clientIP
+		}
+	}
+	return ""
+}
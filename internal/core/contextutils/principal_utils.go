@@ -0,0 +1,27 @@
+package contextutils
+
+import (
+	"context"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/auth"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/contextkeys"
+)
+
+func GetPrincipal(ctx context.Context) *auth.Principal {
+	value := ctx.Value(contextkeys.PrincipalKey)
+	if value != nil {
+		principal, ok := value.(*auth.Principal)
+		if ok {
+			return principal
+		}
+	}
+	return nil
+}
+
+// GetPrincipalSubject returns the subject of the authenticated principal or an empty string for anonymous requests
+func GetPrincipalSubject(ctx context.Context) string {
+	principal := GetPrincipal(ctx)
+	if principal == nil {
+		return ""
+	}
+	return principal.Subject
+}
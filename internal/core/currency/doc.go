@@ -0,0 +1,2 @@
+// Package currency defines the currency codes and the exchange rate provider used to convert foreign currency transactions
+package currency
@@ -0,0 +1,35 @@
+package currency
+
+import (
+	"context"
+	"fmt"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"math"
+	"regexp"
+	"strings"
+)
+
+// DefaultCode is the currency of accounts created without one
+const DefaultCode = "BRL"
+
+var codeRegex = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// RateProvider returns exchange rates between currencies
+type RateProvider interface {
+	// Rate returns how many units of the to currency one unit of the from currency is worth
+	Rate(ctx context.Context, from string, to string) (float64, error)
+}
+
+// Normalize returns the upper case ISO 4217 alphabetic code of a currency
+func Normalize(code string) (string, error) {
+	normalized := strings.ToUpper(strings.TrimSpace(code))
+	if !codeRegex.MatchString(normalized) {
+		return "", fmt.Errorf("%w: %q", errors.CurrencyInvalidError, code)
+	}
+	return normalized, nil
+}
+
+// Convert converts an amount with a rate, rounding the result to cents
+func Convert(amount float64, rate float64) float64 {
+	return math.Round(amount*rate*100) / 100
+}
@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/core/currency/rate_provider.go
+//
+// Generated by this command:
+//
+//	mockgen -package currency -source=./internal/core/currency/rate_provider.go -destination=./internal/core/currency/rate_provider_mock.go -mock_names=RateProvider=RateProviderMock
+//
+
+// Package currency is a generated GoMock package.
+package currency
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// RateProviderMock is a mock of RateProvider interface.
+type RateProviderMock struct {
+	ctrl     *gomock.Controller
+	recorder *RateProviderMockMockRecorder
+	isgomock struct{}
+}
+
+// RateProviderMockMockRecorder is the mock recorder for RateProviderMock.
+type RateProviderMockMockRecorder struct {
+	mock *RateProviderMock
+}
+
+// NewRateProviderMock creates a new mock instance.
+func NewRateProviderMock(ctrl *gomock.Controller) *RateProviderMock {
+	mock := &RateProviderMock{ctrl: ctrl}
+	mock.recorder = &RateProviderMockMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *RateProviderMock) EXPECT() *RateProviderMockMockRecorder {
+	return m.recorder
+}
+
+// Rate mocks base method.
+func (m *RateProviderMock) Rate(ctx context.Context, from, to string) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rate", ctx, from, to)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rate indicates an expected call of Rate.
+func (mr *RateProviderMockMockRecorder) Rate(ctx, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rate", reflect.TypeOf((*RateProviderMock)(nil).Rate), ctx, from, to)
+}
@@ -0,0 +1,92 @@
+package errors
+
+import (
+	"errors"
+	"strings"
+)
+
+// codes names the errors of the package, the code of an error is the name of its variable
+var codes = map[error]string{
+	AccountNotFoundError:                       "AccountNotFoundError",
+	AccountAlreadyExistsForDocumentNumberError: "AccountAlreadyExistsForDocumentNumberError",
+	AccountBlockedError:                        "AccountBlockedError",
+	AccountHolderInvalidError:                  "AccountHolderInvalidError",
+	AccountHolderNotFoundError:                 "AccountHolderNotFoundError",
+	AccountHolderVersionMismatchError:          "AccountHolderVersionMismatchError",
+	AccountVersionConflictError:                "AccountVersionConflictError",
+	AuditLogTamperedError:                      "AuditLogTamperedError",
+	AuthenticationRequiredError:                "AuthenticationRequiredError",
+	AuthInsufficientScopeError:                 "AuthInsufficientScopeError",
+	AuthInvalidCredentialsError:                "AuthInvalidCredentialsError",
+	BatchInvalidFormatError:                    "BatchInvalidFormatError",
+	BatchTooLargeError:                         "BatchTooLargeError",
+	CacheConnectionFailedError:                 "CacheConnectionFailedError",
+	CacheConnectionValidationFailedError:       "CacheConnectionValidationFailedError",
+	CacheInsertionError:                        "CacheInsertionError",
+	CacheFailedToDeleteError:                   "CacheFailedToDeleteError",
+	CacheFailedToExpireError:                   "CacheFailedToExpireError",
+	CacheNotFoundError:                         "CacheNotFoundError",
+	ConfigFileNotFountError:                    "ConfigFileNotFountError",
+	ConfigFileUnmarshalError:                   "ConfigFileUnmarshalError",
+	ConfigValidationError:                      "ConfigValidationError",
+	CurrencyInvalidError:                       "CurrencyInvalidError",
+	CurrencyRateNotFoundError:                  "CurrencyRateNotFoundError",
+	DatabaseConnectionFailedError:              "DatabaseConnectionFailedError",
+	DatabaseConnectionValidationFailedError:    "DatabaseConnectionValidationFailedError",
+	DatabaseCreateTransactionError:             "DatabaseCreateTransactionError",
+	DatabaseFailToCommitError:                  "DatabaseFailToCommitError",
+	DatabaseInsertionError:                     "DatabaseInsertionError",
+	DatabasePrepareStatementError:              "DatabasePrepareStatementError",
+	DatabaseQueryError:                         "DatabaseQueryError",
+	DatabaseSerializationError:                 "DatabaseSerializationError",
+	DistributedLockFailToAcquire:               "DistributedLockFailToAcquire",
+	DistributedLockNotHeldError:                "DistributedLockNotHeldError",
+	DocumentTypeInvalidError:                   "DocumentTypeInvalidError",
+	InvalidParametersError:                     "InvalidParametersError",
+	LedgerUnbalancedError:                      "LedgerUnbalancedError",
+	OperationTypeNotFoundError:                 "OperationTypeNotFoundError",
+	PIIDecryptionError:                         "PIIDecryptionError",
+	PreconditionRequiredError:                  "PreconditionRequiredError",
+	RateLimitExceededError:                     "RateLimitExceededError",
+	TransactionInvalidAccountIDError:           "TransactionInvalidAccountIDError",
+	TransactionInvalidAmountNegativeError:      "TransactionInvalidAmountNegativeError",
+	TransactionInvalidDetailsError:             "TransactionInvalidDetailsError",
+	TransactionInvalidEffectiveDateError:       "TransactionInvalidEffectiveDateError",
+	TransactionInvalidEventDateError:           "TransactionInvalidEventDateError",
+	TransactionInvalidOperationTypeError:       "TransactionInvalidOperationTypeError",
+	TransactionNotFoundError:                   "TransactionNotFoundError",
+	TransactionNotPendingError:                 "TransactionNotPendingError",
+	TransactionNotReversibleError:              "TransactionNotReversibleError",
+	TransactionAlreadyReversedError:            "TransactionAlreadyReversedError",
+}
+
+// byMessage finds the errors of the package by message
+var byMessage = make(map[string]error, len(codes))
+
+func init() {
+	for err := range codes {
+		byMessage[err.Error()] = err
+	}
+}
+
+// Code returns the code of the error of the package err is or wraps, e.g. "AccountNotFoundError", empty when there is none
+func Code(err error) string {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if code, ok := codes[err]; ok {
+			return code
+		}
+	}
+	return ""
+}
+
+// FromMessage returns the error of the package whose message is message, nil when there is none. Messages of
+// wrapped errors, e.g. "invalid parameters: unknown distribution", are matched by the text before the first colon.
+func FromMessage(message string) error {
+	if err, ok := byMessage[message]; ok {
+		return err
+	}
+	if prefix, _, found := strings.Cut(message, ": "); found {
+		return byMessage[prefix]
+	}
+	return nil
+}
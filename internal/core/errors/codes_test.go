@@ -0,0 +1,42 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEveryErrorHasACode(t *testing.T) {
+	file, err := parser.ParseFile(token.NewFileSet(), "errors.go", nil, 0)
+	require.NoError(t, err)
+	declared := 0
+	ast.Inspect(file, func(node ast.Node) bool {
+		if spec, ok := node.(*ast.ValueSpec); ok {
+			declared += len(spec.Names)
+		}
+		return true
+	})
+	assert.Equal(t, declared, len(codes), "every error of errors.go should be named in codes")
+	assert.Equal(t, len(codes), len(byMessage), "messages should be unique")
+}
+
+func TestCode(t *testing.T) {
+	assert.Equal(t, "AccountNotFoundError", Code(AccountNotFoundError))
+	assert.Equal(t, "InvalidParametersError", Code(fmt.Errorf("%w: unknown distribution", InvalidParametersError)))
+	assert.Empty(t, Code(errors.New("other")))
+	assert.Empty(t, Code(nil))
+}
+
+func TestFromMessage(t *testing.T) {
+	assert.Equal(t, TransactionInvalidAmountNegativeError, FromMessage("invalid amount. must be a positive value"))
+	assert.Equal(t, InvalidParametersError, FromMessage("invalid parameters: unknown distribution"))
+	assert.Equal(t, DocumentTypeInvalidError, FromMessage(fmt.Errorf("%w: %q", DocumentTypeInvalidError, "RG").Error()))
+	assert.Nil(t, FromMessage("something else"))
+	assert.Nil(t, FromMessage(""))
+}
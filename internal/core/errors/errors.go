@@ -5,6 +5,17 @@ import "errors"
 var (
 	AccountNotFoundError                       = errors.New("account not found")
 	AccountAlreadyExistsForDocumentNumberError = errors.New("an account already exists for this document number")
+	AccountBlockedError                        = errors.New("account is blocked")
+	AccountHolderInvalidError                  = errors.New("invalid account holder data")
+	AccountHolderNotFoundError                 = errors.New("account holder not found")
+	AccountHolderVersionMismatchError          = errors.New("account holder was changed by another request. get it again and retry with its ETag")
+	AccountVersionConflictError                = errors.New("account was changed by another request. get it again and retry with its ETag")
+	AuditLogTamperedError                      = errors.New("audit log hash chain is broken")
+	AuthenticationRequiredError                = errors.New("authentication required")
+	AuthInsufficientScopeError                 = errors.New("insufficient scope")
+	AuthInvalidCredentialsError                = errors.New("invalid credentials")
+	BatchInvalidFormatError                    = errors.New("invalid batch format")
+	BatchTooLargeError                         = errors.New("batch exceeds the maximum number of rows")
 	CacheConnectionFailedError                 = errors.New("failed to connect to cache")
 	CacheConnectionValidationFailedError       = errors.New("cache connection validation error")
 	CacheInsertionError                        = errors.New("cache insertion error")
@@ -13,6 +24,9 @@ var (
 	CacheNotFoundError                         = errors.New("not found in cache")
 	ConfigFileNotFountError                    = errors.New("config file not found")
 	ConfigFileUnmarshalError                   = errors.New("config unmarshal error")
+	ConfigValidationError                      = errors.New("invalid configuration")
+	CurrencyInvalidError                       = errors.New("invalid currency")
+	CurrencyRateNotFoundError                  = errors.New("exchange rate not found")
 	DatabaseConnectionFailedError              = errors.New("failed to connect to database")
 	DatabaseConnectionValidationFailedError    = errors.New("database connection validation error")
 	DatabaseCreateTransactionError             = errors.New("database create transaction error")
@@ -20,11 +34,24 @@ var (
 	DatabaseInsertionError                     = errors.New("database insertion error")
 	DatabasePrepareStatementError              = errors.New("database prepare statement error")
 	DatabaseQueryError                         = errors.New("database query error")
+	DatabaseSerializationError                 = errors.New("database transaction conflicted with a concurrent one")
 	DistributedLockFailToAcquire               = errors.New("distributed lock fail to acquire")
+	DistributedLockNotHeldError                = errors.New("distributed lock is not held")
+	DocumentTypeInvalidError                   = errors.New("invalid document type")
 	InvalidParametersError                     = errors.New("invalid parameters")
+	LedgerUnbalancedError                      = errors.New("ledger postings are not balanced")
 	OperationTypeNotFoundError                 = errors.New("operation type not found")
+	PIIDecryptionError                         = errors.New("failed to decrypt personal data")
+	PreconditionRequiredError                  = errors.New("precondition required. send the If-Match header with the ETag of the resource")
+	RateLimitExceededError                     = errors.New("rate limit exceeded")
 	TransactionInvalidAccountIDError           = errors.New("invalid account ID")
 	TransactionInvalidAmountNegativeError      = errors.New("invalid amount. must be a positive value")
+	TransactionInvalidDetailsError             = errors.New("invalid transaction details")
+	TransactionInvalidEffectiveDateError       = errors.New("invalid effective date. must be in the future")
+	TransactionInvalidEventDateError           = errors.New("invalid event date. must not be in the future nor older than the backdating window")
 	TransactionInvalidOperationTypeError       = errors.New("invalid operation type")
 	TransactionNotFoundError                   = errors.New("transaction not found")
+	TransactionNotPendingError                 = errors.New("transaction is not pending")
+	TransactionNotReversibleError              = errors.New("transaction is not reversible. only posted transactions that are not reversals can be reversed")
+	TransactionAlreadyReversedError            = errors.New("transaction is already reversed")
 )
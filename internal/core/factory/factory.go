@@ -4,10 +4,17 @@ import (
 	"github.com/kiosanim/pismo-code-assessment/interfaces/http/handler"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/adapter"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/cache"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/clock"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/config"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/currency"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/idgen"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/lock"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/pii"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/ratelimit"
 	"github.com/kiosanim/pismo-code-assessment/internal/domains/account"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/audit"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/holder"
 	"github.com/kiosanim/pismo-code-assessment/internal/domains/transaction"
 )
 
@@ -17,9 +24,19 @@ type Factory interface {
 	CacheConnectionData() *adapter.CacheConnectionData
 	AccountRepository() account.AccountRepository
 	TransactionRepository() transaction.TransactionRepository
+	AuditLogRepository() audit.AuditLogRepository
+	HolderRepository() holder.HolderRepository
+	UnitOfWork() adapter.UnitOfWork
 	AccountHandler(accountService account.Service) *handler.AccountHandler
 	TransactionHandler(transactionService transaction.Service) *handler.TransactionHandler
+	AuditHandler(auditService audit.Service) *handler.AuditHandler
+	HolderHandler(holderService holder.Service) *handler.HolderHandler
 	CacheRepository() cache.CacheRepository
 	DistributedLockManager() lock.DistributedLockManager
+	RateLimiter() ratelimit.RateLimiter
+	RateProvider() currency.RateProvider
+	Clock() clock.Clock
+	IDGenerator() idgen.Generator
+	FieldCipher() pii.FieldCipher
 	Log() logger.Logger
 }
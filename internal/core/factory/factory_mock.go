@@ -15,10 +15,17 @@ import (
 	handler "github.com/kiosanim/pismo-code-assessment/interfaces/http/handler"
 	adapter "github.com/kiosanim/pismo-code-assessment/internal/core/adapter"
 	cache "github.com/kiosanim/pismo-code-assessment/internal/core/cache"
+	clock "github.com/kiosanim/pismo-code-assessment/internal/core/clock"
 	config "github.com/kiosanim/pismo-code-assessment/internal/core/config"
+	currency "github.com/kiosanim/pismo-code-assessment/internal/core/currency"
+	idgen "github.com/kiosanim/pismo-code-assessment/internal/core/idgen"
 	lock "github.com/kiosanim/pismo-code-assessment/internal/core/lock"
 	logger "github.com/kiosanim/pismo-code-assessment/internal/core/logger"
+	pii "github.com/kiosanim/pismo-code-assessment/internal/core/pii"
+	ratelimit "github.com/kiosanim/pismo-code-assessment/internal/core/ratelimit"
 	account "github.com/kiosanim/pismo-code-assessment/internal/domains/account"
+	audit "github.com/kiosanim/pismo-code-assessment/internal/domains/audit"
+	holder "github.com/kiosanim/pismo-code-assessment/internal/domains/holder"
 	transaction "github.com/kiosanim/pismo-code-assessment/internal/domains/transaction"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -75,6 +82,34 @@ func (mr *FactoryMockMockRecorder) AccountRepository() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountRepository", reflect.TypeOf((*FactoryMock)(nil).AccountRepository))
 }
 
+// AuditHandler mocks base method.
+func (m *FactoryMock) AuditHandler(auditService audit.Service) *handler.AuditHandler {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuditHandler", auditService)
+	ret0, _ := ret[0].(*handler.AuditHandler)
+	return ret0
+}
+
+// AuditHandler indicates an expected call of AuditHandler.
+func (mr *FactoryMockMockRecorder) AuditHandler(auditService any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuditHandler", reflect.TypeOf((*FactoryMock)(nil).AuditHandler), auditService)
+}
+
+// AuditLogRepository mocks base method.
+func (m *FactoryMock) AuditLogRepository() audit.AuditLogRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuditLogRepository")
+	ret0, _ := ret[0].(audit.AuditLogRepository)
+	return ret0
+}
+
+// AuditLogRepository indicates an expected call of AuditLogRepository.
+func (mr *FactoryMockMockRecorder) AuditLogRepository() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuditLogRepository", reflect.TypeOf((*FactoryMock)(nil).AuditLogRepository))
+}
+
 // CacheConnectionData mocks base method.
 func (m *FactoryMock) CacheConnectionData() *adapter.CacheConnectionData {
 	m.ctrl.T.Helper()
@@ -103,6 +138,20 @@ func (mr *FactoryMockMockRecorder) CacheRepository() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CacheRepository", reflect.TypeOf((*FactoryMock)(nil).CacheRepository))
 }
 
+// Clock mocks base method.
+func (m *FactoryMock) Clock() clock.Clock {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Clock")
+	ret0, _ := ret[0].(clock.Clock)
+	return ret0
+}
+
+// Clock indicates an expected call of Clock.
+func (mr *FactoryMockMockRecorder) Clock() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Clock", reflect.TypeOf((*FactoryMock)(nil).Clock))
+}
+
 // Configuration mocks base method.
 func (m *FactoryMock) Configuration() *config.Configuration {
 	m.ctrl.T.Helper()
@@ -145,6 +194,62 @@ func (mr *FactoryMockMockRecorder) DistributedLockManager() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DistributedLockManager", reflect.TypeOf((*FactoryMock)(nil).DistributedLockManager))
 }
 
+// FieldCipher mocks base method.
+func (m *FactoryMock) FieldCipher() pii.FieldCipher {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FieldCipher")
+	ret0, _ := ret[0].(pii.FieldCipher)
+	return ret0
+}
+
+// FieldCipher indicates an expected call of FieldCipher.
+func (mr *FactoryMockMockRecorder) FieldCipher() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FieldCipher", reflect.TypeOf((*FactoryMock)(nil).FieldCipher))
+}
+
+// HolderHandler mocks base method.
+func (m *FactoryMock) HolderHandler(holderService holder.Service) *handler.HolderHandler {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HolderHandler", holderService)
+	ret0, _ := ret[0].(*handler.HolderHandler)
+	return ret0
+}
+
+// HolderHandler indicates an expected call of HolderHandler.
+func (mr *FactoryMockMockRecorder) HolderHandler(holderService any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HolderHandler", reflect.TypeOf((*FactoryMock)(nil).HolderHandler), holderService)
+}
+
+// HolderRepository mocks base method.
+func (m *FactoryMock) HolderRepository() holder.HolderRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HolderRepository")
+	ret0, _ := ret[0].(holder.HolderRepository)
+	return ret0
+}
+
+// HolderRepository indicates an expected call of HolderRepository.
+func (mr *FactoryMockMockRecorder) HolderRepository() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HolderRepository", reflect.TypeOf((*FactoryMock)(nil).HolderRepository))
+}
+
+// IDGenerator mocks base method.
+func (m *FactoryMock) IDGenerator() idgen.Generator {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IDGenerator")
+	ret0, _ := ret[0].(idgen.Generator)
+	return ret0
+}
+
+// IDGenerator indicates an expected call of IDGenerator.
+func (mr *FactoryMockMockRecorder) IDGenerator() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IDGenerator", reflect.TypeOf((*FactoryMock)(nil).IDGenerator))
+}
+
 // Log mocks base method.
 func (m *FactoryMock) Log() logger.Logger {
 	m.ctrl.T.Helper()
@@ -159,6 +264,34 @@ func (mr *FactoryMockMockRecorder) Log() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Log", reflect.TypeOf((*FactoryMock)(nil).Log))
 }
 
+// RateLimiter mocks base method.
+func (m *FactoryMock) RateLimiter() ratelimit.RateLimiter {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RateLimiter")
+	ret0, _ := ret[0].(ratelimit.RateLimiter)
+	return ret0
+}
+
+// RateLimiter indicates an expected call of RateLimiter.
+func (mr *FactoryMockMockRecorder) RateLimiter() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RateLimiter", reflect.TypeOf((*FactoryMock)(nil).RateLimiter))
+}
+
+// RateProvider mocks base method.
+func (m *FactoryMock) RateProvider() currency.RateProvider {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RateProvider")
+	ret0, _ := ret[0].(currency.RateProvider)
+	return ret0
+}
+
+// RateProvider indicates an expected call of RateProvider.
+func (mr *FactoryMockMockRecorder) RateProvider() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RateProvider", reflect.TypeOf((*FactoryMock)(nil).RateProvider))
+}
+
 // TransactionHandler mocks base method.
 func (m *FactoryMock) TransactionHandler(transactionService transaction.Service) *handler.TransactionHandler {
 	m.ctrl.T.Helper()
@@ -186,3 +319,17 @@ func (mr *FactoryMockMockRecorder) TransactionRepository() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransactionRepository", reflect.TypeOf((*FactoryMock)(nil).TransactionRepository))
 }
+
+// UnitOfWork mocks base method.
+func (m *FactoryMock) UnitOfWork() adapter.UnitOfWork {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnitOfWork")
+	ret0, _ := ret[0].(adapter.UnitOfWork)
+	return ret0
+}
+
+// UnitOfWork indicates an expected call of UnitOfWork.
+func (mr *FactoryMockMockRecorder) UnitOfWork() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnitOfWork", reflect.TypeOf((*FactoryMock)(nil).UnitOfWork))
+}
@@ -0,0 +1,2 @@
+// Package idgen defines the generator of unique identifiers and tokens, such as the values of distributed locks
+package idgen
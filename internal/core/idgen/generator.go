@@ -0,0 +1,25 @@
+package idgen
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// Generator returns identifiers that are unique across processes
+type Generator interface {
+	NewID() string
+}
+
+// FakeGenerator returns the sequence Prefix-1, Prefix-2, ..., safe for concurrent use
+type FakeGenerator struct {
+	Prefix string
+	last   atomic.Int64
+}
+
+func NewFakeGenerator(prefix string) *FakeGenerator {
+	return &FakeGenerator{Prefix: prefix}
+}
+
+func (f *FakeGenerator) NewID() string {
+	return fmt.Sprintf("%s-%d", f.Prefix, f.last.Add(1))
+}
@@ -2,19 +2,30 @@ package lock
 
 import (
 	"context"
+	"database/sql"
 	"github.com/redis/go-redis/v9"
 	"time"
 )
 
+// KeyPrefix starts the keys of all the distributed locks
+const KeyPrefix = "lock-"
+
 const (
-	AccountCreationLockKey     = "lock-account-creation"
 	TransactionCreationLockKey = "lock-transaction-creation"
+	SchedulerLeaderLockKey     = "lock-scheduler-leader"
 )
 
+// Keys are the keys of the locks taken by the application, naming the Postgres advisory locks known only by a number
+var Keys = []string{TransactionCreationLockKey, SchedulerLeaderLockKey}
+
 type Lock struct {
 	Key    string        `redis:"key"`
 	Value  string        `redis:"value"`
 	Client *redis.Client `redis:"-"`
+	// Tx is the transaction holding a Postgres advisory lock, released when it ends. Nil for Redis locks.
+	Tx *sql.Tx `redis:"-"`
+	// Joined tells that Tx is the transaction of a unit of work, which releases the lock when it commits or rolls back
+	Joined bool `redis:"-"`
 }
 
 type DistributedLockManager interface {
@@ -22,4 +33,22 @@ type DistributedLockManager interface {
 	WaitToLock(ctx context.Context, key string, ttl time.Duration, waitingTimeMilliseconds time.Duration, retryMilliseconds time.Duration) (*Lock, error)
 	WaitToLockUsingDefaultTimeConfiguration(ctx context.Context, key string) (*Lock, error)
 	Unlock(ctx context.Context, acquiredLock *Lock) error
+	// Refresh extends the ttl of a lock still held, failing with DistributedLockNotHeldError when it expired or was taken
+	Refresh(ctx context.Context, acquiredLock *Lock, ttl time.Duration) error
+}
+
+// HeldLock is a distributed lock currently held by some instance
+type HeldLock struct {
+	Key   string        `json:"key"`
+	Value string        `json:"value"` // Value unique to the holder of the lock
+	TTL   time.Duration `json:"ttl"`   // Time until the lock expires, negative when it never expires
+}
+
+// Administrator inspects and releases the distributed locks held by any instance, for operators only
+type Administrator interface {
+	// List returns the held locks sorted by key
+	List(ctx context.Context) ([]HeldLock, error)
+	// ForceRelease releases a lock whatever its holder, returning false when it was not held. Keys without
+	// KeyPrefix fail with InvalidParametersError, so no other key is removed.
+	ForceRelease(ctx context.Context, key string) (bool, error)
 }
@@ -56,6 +56,20 @@ func (mr *DistributedLockManagerMockMockRecorder) Lock(ctx, key, ttl any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*DistributedLockManagerMock)(nil).Lock), ctx, key, ttl)
 }
 
+// Refresh mocks base method.
+func (m *DistributedLockManagerMock) Refresh(ctx context.Context, acquiredLock *Lock, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", ctx, acquiredLock, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *DistributedLockManagerMockMockRecorder) Refresh(ctx, acquiredLock, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*DistributedLockManagerMock)(nil).Refresh), ctx, acquiredLock, ttl)
+}
+
 // Unlock mocks base method.
 func (m *DistributedLockManagerMock) Unlock(ctx context.Context, acquiredLock *Lock) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,2 @@
+// Package pii defines the protection of personally identifiable information: field encryption at rest and masking
+package pii
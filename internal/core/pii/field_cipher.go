@@ -0,0 +1,29 @@
+package pii
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// visibleCharacters is the number of trailing characters kept by Mask
+const visibleCharacters = 4
+
+// FieldCipher encrypts single values stored at rest
+type FieldCipher interface {
+	// Encrypt returns the value encrypted with a random nonce, so equal values have different ciphertexts
+	Encrypt(plaintext string) (string, error)
+	// Decrypt returns the plaintext of a value returned by Encrypt
+	Decrypt(ciphertext string) (string, error)
+	// BlindIndex returns a deterministic keyed hash of the value, used to look up and enforce uniqueness of encrypted values
+	BlindIndex(plaintext string) string
+}
+
+// Mask replaces every character of a value but the last four with '*'. Values with up to four characters are fully masked.
+func Mask(value string) string {
+	length := utf8.RuneCountInString(value)
+	if length <= visibleCharacters {
+		return strings.Repeat("*", length)
+	}
+	runes := []rune(value)
+	return strings.Repeat("*", length-visibleCharacters) + string(runes[length-visibleCharacters:])
+}
@@ -0,0 +1,23 @@
+package pii
+
+import "testing"
+
+func TestMask(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"must keep the last four characters of a CPF", "12345678909", "*******8909"},
+		{"must keep the last four characters of a passport", "AB1234567", "*****4567"},
+		{"must fully mask short values", "1234", "****"},
+		{"must keep an empty value empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Mask(tt.value); got != tt.want {
+				t.Errorf("Mask() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
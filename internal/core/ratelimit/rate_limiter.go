@@ -0,0 +1,44 @@
+package ratelimit
+
+import (
+	"context"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"time"
+)
+
+const (
+	RequestKeyPrefix  = "ratelimit"
+	VelocityKeyPrefix = "velocity"
+)
+
+// Limit is the maximum number of requests accepted in a sliding window
+type Limit struct {
+	Requests int64
+	Window   time.Duration
+}
+
+// Result is the outcome of a rate limit check
+type Result struct {
+	Allowed    bool
+	Limit      int64
+	Remaining  int64
+	ResetAfter time.Duration // Time until the oldest request leaves the window
+	RetryAfter time.Duration // Time to wait before retrying when not allowed
+}
+
+type RateLimiter interface {
+	Allow(ctx context.Context, key string, limit Limit) (*Result, error)
+}
+
+// ExceededError is returned by services when a rate limit is exceeded, carrying the check Result
+type ExceededError struct {
+	Result *Result
+}
+
+func (e *ExceededError) Error() string {
+	return errors.RateLimitExceededError.Error()
+}
+
+func (e *ExceededError) Unwrap() error {
+	return errors.RateLimitExceededError
+}
@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/core/ratelimit/rate_limiter.go
+//
+// Generated by this command:
+//
+//	mockgen -package ratelimit -source=./internal/core/ratelimit/rate_limiter.go -destination=./internal/core/ratelimit/rate_limiter_mock.go -mock_names=RateLimiter=RateLimiterMock
+//
+
+// Package ratelimit is a generated GoMock package.
+package ratelimit
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// RateLimiterMock is a mock of RateLimiter interface.
+type RateLimiterMock struct {
+	ctrl     *gomock.Controller
+	recorder *RateLimiterMockMockRecorder
+	isgomock struct{}
+}
+
+// RateLimiterMockMockRecorder is the mock recorder for RateLimiterMock.
+type RateLimiterMockMockRecorder struct {
+	mock *RateLimiterMock
+}
+
+// NewRateLimiterMock creates a new mock instance.
+func NewRateLimiterMock(ctrl *gomock.Controller) *RateLimiterMock {
+	mock := &RateLimiterMock{ctrl: ctrl}
+	mock.recorder = &RateLimiterMockMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *RateLimiterMock) EXPECT() *RateLimiterMockMockRecorder {
+	return m.recorder
+}
+
+// Allow mocks base method.
+func (m *RateLimiterMock) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Allow", ctx, key, limit)
+	ret0, _ := ret[0].(*Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Allow indicates an expected call of Allow.
+func (mr *RateLimiterMockMockRecorder) Allow(ctx, key, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Allow", reflect.TypeOf((*RateLimiterMock)(nil).Allow), ctx, key, limit)
+}
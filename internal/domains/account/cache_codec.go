@@ -0,0 +1,36 @@
+package account
+
+import (
+	"github.com/kiosanim/pismo-code-assessment/internal/core/pii"
+)
+
+// CacheCodec encrypts the document number of the accounts kept in the cache, so it is never stored there in clear text
+type CacheCodec struct {
+	fieldCipher pii.FieldCipher
+}
+
+func NewCacheCodec(fieldCipher pii.FieldCipher) *CacheCodec {
+	return &CacheCodec{fieldCipher: fieldCipher}
+}
+
+// Encode returns a copy of an account with its document number encrypted
+func (c *CacheCodec) Encode(value *Account) (*Account, error) {
+	encoded := *value
+	documentNumber, err := c.fieldCipher.Encrypt(value.DocumentNumber)
+	if err != nil {
+		return nil, err
+	}
+	encoded.DocumentNumber = documentNumber
+	return &encoded, nil
+}
+
+// Decode returns a copy of an account returned by Encode with its document number decrypted
+func (c *CacheCodec) Decode(value *Account) (*Account, error) {
+	decoded := *value
+	documentNumber, err := c.fieldCipher.Decrypt(value.DocumentNumber)
+	if err != nil {
+		return nil, err
+	}
+	decoded.DocumentNumber = documentNumber
+	return &decoded, nil
+}
@@ -0,0 +1,111 @@
+package account
+
+import (
+	"fmt"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/paemuri/brdoc"
+	"regexp"
+	"strings"
+)
+
+// DocumentType identifies the kind of document an account holder is identified by
+type DocumentType string
+
+const (
+	DocumentTypeCPF          DocumentType = "CPF"            // Brazilian individual taxpayer registry
+	DocumentTypeCNPJ         DocumentType = "CNPJ"           // Brazilian company registry
+	DocumentTypePassport     DocumentType = "PASSPORT"       // Passport number of any country
+	DocumentTypeForeignTaxID DocumentType = "FOREIGN_TAX_ID" // Non-Brazilian tax identification number
+)
+
+var (
+	passportRegex              = regexp.MustCompile(`^[A-Z0-9]{5,20}$`)
+	foreignTaxIDRegex          = regexp.MustCompile(`^[A-Z0-9]{4,30}$`)
+	alphanumericSeparatorRegex = regexp.MustCompile(`[\s./-]`)
+)
+
+// DocumentValidator normalizes and validates the document numbers of a DocumentType
+type DocumentValidator interface {
+	// Normalize returns the canonical form a document number is stored and compared in
+	Normalize(documentNumber string) string
+	// Validate checks a normalized document number
+	Validate(documentNumber string) error
+}
+
+// documentValidators are the validators of the accepted document types
+var documentValidators = map[DocumentType]DocumentValidator{
+	DocumentTypeCPF:          brazilianDocumentValidator{isValid: brdoc.IsCPF},
+	DocumentTypeCNPJ:         brazilianDocumentValidator{isValid: brdoc.IsCNPJ},
+	DocumentTypePassport:     alphanumericDocumentValidator{regex: passportRegex},
+	DocumentTypeForeignTaxID: alphanumericDocumentValidator{regex: foreignTaxIDRegex},
+}
+
+// RegisterDocumentValidator adds or replaces the validator of a document type.
+// It must be called during initialization, before any document is normalized.
+func RegisterDocumentValidator(documentType DocumentType, validator DocumentValidator) {
+	documentValidators[documentType] = validator
+}
+
+// ParseDocumentType returns the DocumentType named by value, case-insensitive
+func ParseDocumentType(value string) (DocumentType, error) {
+	documentType := DocumentType(strings.ToUpper(strings.TrimSpace(value)))
+	if _, ok := documentValidators[documentType]; !ok {
+		return "", fmt.Errorf("%w: %q", errors.DocumentTypeInvalidError, value)
+	}
+	return documentType, nil
+}
+
+// NormalizeDocument validates a document number and returns it in its normalized form.
+// When documentType is empty the number must be a CPF or a CNPJ, and the type is taken from its length.
+func NormalizeDocument(documentType DocumentType, documentNumber string) (DocumentType, string, error) {
+	if documentType == "" {
+		documentType = DocumentTypeCPF
+		if len(SanitizeDocumentNumber(documentNumber)) == 14 {
+			documentType = DocumentTypeCNPJ
+		}
+	}
+	validator, ok := documentValidators[documentType]
+	if !ok {
+		return "", "", fmt.Errorf("%w: %q", errors.DocumentTypeInvalidError, documentType)
+	}
+	normalized := validator.Normalize(documentNumber)
+	if normalized == "" {
+		return "", "", errors.InvalidParametersError
+	}
+	if err := validator.Validate(normalized); err != nil {
+		return "", "", err
+	}
+	return documentType, normalized, nil
+}
+
+// brazilianDocumentValidator keeps only the digits of CPF and CNPJ numbers and validates their check digits
+type brazilianDocumentValidator struct {
+	isValid func(documentNumber string) bool
+}
+
+func (b brazilianDocumentValidator) Normalize(documentNumber string) string {
+	return SanitizeDocumentNumber(documentNumber)
+}
+
+func (b brazilianDocumentValidator) Validate(documentNumber string) error {
+	if !b.isValid(documentNumber) {
+		return errors.InvalidParametersError
+	}
+	return nil
+}
+
+// alphanumericDocumentValidator upper cases the document number and removes spaces, dots, slashes and hyphens
+type alphanumericDocumentValidator struct {
+	regex *regexp.Regexp
+}
+
+func (a alphanumericDocumentValidator) Normalize(documentNumber string) string {
+	return alphanumericSeparatorRegex.ReplaceAllString(strings.ToUpper(documentNumber), "")
+}
+
+func (a alphanumericDocumentValidator) Validate(documentNumber string) error {
+	if !a.regex.MatchString(documentNumber) {
+		return errors.InvalidParametersError
+	}
+	return nil
+}
@@ -2,7 +2,9 @@ package account
 
 import (
 	"github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/pii"
 	"github.com/paemuri/brdoc"
+	"log/slog"
 	"regexp"
 )
 
@@ -10,10 +12,36 @@ var (
 	documentNumberRegex = regexp.MustCompile(`\D`)
 )
 
+const (
+	StatusActive  = "ACTIVE"  // Account accepting new transactions
+	StatusBlocked = "BLOCKED" // Account rejecting new transactions
+)
+
 // Account represent a customer account
 type Account struct {
-	AccountID      int64  // Unique identifier of an Account
-	DocumentNumber string // Brazilian CPF or CNPJ
+	AccountID      int64        // Unique identifier of an Account
+	DocumentType   DocumentType // Kind of the DocumentNumber
+	DocumentNumber string       // Normalized document number, see NormalizeDocument
+	Currency       string       // ISO 4217 code of the currency the account transactions are kept in
+	Status         string       // StatusActive or StatusBlocked, empty meaning StatusActive
+	Version        int64        // Incremented on every change, the ETag of the account
+}
+
+// Blocked checks if the account rejects new transactions
+func (a Account) Blocked() bool {
+	return a.Status == StatusBlocked
+}
+
+// LogValue logs an Account with its document number masked
+func (a Account) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Int64("account_id", a.AccountID),
+		slog.String("document_type", string(a.DocumentType)),
+		slog.String("document_number", pii.Mask(a.DocumentNumber)),
+		slog.String("currency", a.Currency),
+		slog.String("status", a.Status),
+		slog.Int64("version", a.Version),
+	)
 }
 
 // IsValidDocumentNumber validate if a user DocumentNumber is a Brazilian CPF or CNPJ
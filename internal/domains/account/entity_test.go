@@ -45,3 +45,73 @@ func TestSanitizeDocumentNumber(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizeDocument(t *testing.T) {
+	tests := []struct {
+		name           string
+		documentType   DocumentType
+		documentNumber string
+		wantType       DocumentType
+		want           string
+		wantErr        bool
+	}{
+		{"must infer a formatted CPF", "", "123.456.789-09", DocumentTypeCPF, "12345678909", false},
+		{"must infer a formatted CNPJ", "", "46.047.310/0001-62", DocumentTypeCNPJ, "46047310000162", false},
+		{"must reject an invalid CPF", DocumentTypeCPF, "123.456.789-00", "", "", true},
+		{"must reject a CNPJ informed as CPF", DocumentTypeCPF, "46047310000162", "", "", true},
+		{"must normalize a passport", DocumentTypePassport, "ab 123-4567", DocumentTypePassport, "AB1234567", false},
+		{"must reject a short passport", DocumentTypePassport, "A12", "", "", true},
+		{"must reject a passport with symbols", DocumentTypePassport, "AB#12345", "", "", true},
+		{"must normalize a foreign tax ID", DocumentTypeForeignTaxID, "123-45-6789", DocumentTypeForeignTaxID, "123456789", false},
+		{"must reject an empty document", DocumentTypePassport, " - ", "", "", true},
+		{"must reject an unknown type", DocumentType("DRIVER_LICENSE"), "123456", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotType, got, err := NormalizeDocument(tt.documentType, tt.documentNumber)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NormalizeDocument() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if gotType != tt.wantType || got != tt.want {
+				t.Errorf("NormalizeDocument() = %v, %v, want %v, %v", gotType, got, tt.wantType, tt.want)
+			}
+		})
+	}
+}
+
+type prefixedDocumentValidator struct{}
+
+func (prefixedDocumentValidator) Normalize(documentNumber string) string {
+	return "X" + documentNumber
+}
+
+func (prefixedDocumentValidator) Validate(documentNumber string) error {
+	return nil
+}
+
+func TestRegisterDocumentValidator(t *testing.T) {
+	const documentType DocumentType = "TEST_ID"
+	RegisterDocumentValidator(documentType, prefixedDocumentValidator{})
+	defer delete(documentValidators, documentType)
+	parsed, err := ParseDocumentType("test_id")
+	if err != nil || parsed != documentType {
+		t.Fatalf("ParseDocumentType() = %v, %v", parsed, err)
+	}
+	_, got, err := NormalizeDocument(documentType, "1")
+	if err != nil || got != "X1" {
+		t.Errorf("NormalizeDocument() = %v, %v, want X1", got, err)
+	}
+}
+
+func TestAccountLogValueMasksDocumentNumber(t *testing.T) {
+	value := Account{AccountID: 1, DocumentType: DocumentTypeCPF, DocumentNumber: "11987408098", Currency: "BRL"}.LogValue()
+	masked := ""
+	for _, attr := range value.Group() {
+		if attr.Key == "document_number" {
+			masked = attr.Value.String()
+		}
+	}
+	if masked != "*******8098" {
+		t.Errorf("LogValue() document_number = %q, want *******8098", masked)
+	}
+}
@@ -24,6 +24,16 @@ func (m *AccountRepositoryMock) FindByID(ctx context.Context, accountID int64) (
 	return p, nil
 }
 
+func (m *AccountRepositoryMock) FindByIDForShare(ctx context.Context, accountID int64) (*Account, error) {
+	args := m.Called(ctx, accountID)
+	val := args.Get(0)
+	p, ok := val.(*Account)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return p, nil
+}
+
 func (m *AccountRepositoryMock) Save(ctx context.Context, account *Account) (*Account, error) {
 	args := m.Called(ctx, account)
 	val := args.Get(0)
@@ -44,6 +54,26 @@ func (m *AccountRepositoryMock) FindByDocumentNumber(ctx context.Context, docume
 	return p, nil
 }
 
+func (m *AccountRepositoryMock) List(ctx context.Context, limit int64, cursorID int64) ([]Account, error) {
+	args := m.Called(ctx, limit, cursorID)
+	val := args.Get(0)
+	p, ok := val.([]Account)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return p, nil
+}
+
+func (m *AccountRepositoryMock) Block(ctx context.Context, accountID int64, expectedVersion int64) (*Account, error) {
+	args := m.Called(ctx, accountID, expectedVersion)
+	val := args.Get(0)
+	p, ok := val.(*Account)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return p, nil
+}
+
 type AccountServiceMock struct {
 	mock.Mock
 }
@@ -71,3 +101,23 @@ func (m *AccountServiceMock) Create(ctx context.Context, response dto.CreateAcco
 	}
 	return p, nil
 }
+
+func (m *AccountServiceMock) List(ctx context.Context, request dto.ListAccountsRequest) (*dto.ListAccountsResponse, error) {
+	args := m.Called(ctx, request)
+	val := args.Get(0)
+	p, ok := val.(*dto.ListAccountsResponse)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return p, nil
+}
+
+func (m *AccountServiceMock) Block(ctx context.Context, request dto.BlockAccountRequest) (*dto.BlockAccountResponse, error) {
+	args := m.Called(ctx, request)
+	val := args.Get(0)
+	p, ok := val.(*dto.BlockAccountResponse)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return p, nil
+}
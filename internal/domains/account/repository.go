@@ -6,7 +6,13 @@ import (
 
 type AccountRepository interface {
 	FindByID(ctx context.Context, accountID int64) (*Account, error)
+	// FindByIDForShare finds an account like FindByID and, called in a unit of work, keeps it from being changed until
+	// the unit of work ends, so changes based on its state are not made on a stale account
+	FindByIDForShare(ctx context.Context, accountID int64) (*Account, error)
 	FindByDocumentNumber(ctx context.Context, documentNumber string) (*Account, error)
 	Save(ctx context.Context, newAccount *Account) (*Account, error)
 	List(ctx context.Context, limit int64, cursorID int64) ([]Account, error)
+	// Block sets the status of the account at expectedVersion to StatusBlocked, returning the blocked account. It fails
+	// with AccountVersionConflictError when the account is at another version.
+	Block(ctx context.Context, accountID int64, expectedVersion int64) (*Account, error)
 }
@@ -9,4 +9,5 @@ type Service interface {
 	FindByID(ctx context.Context, request dto.FindAccountByIdRequest) (*dto.FindAccountByIdResponse, error)
 	Create(ctx context.Context, response dto.CreateAccountRequest) (*dto.CreateAccountResponse, error)
 	List(ctx context.Context, request dto.ListAccountsRequest) (*dto.ListAccountsResponse, error)
+	Block(ctx context.Context, request dto.BlockAccountRequest) (*dto.BlockAccountResponse, error)
 }
@@ -0,0 +1,96 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// GenesisHash is the previous hash of the first entry of the chain
+var GenesisHash = strings.Repeat("0", sha256.Size*2)
+
+// hashedEntry holds the fields covered by the hash, in a fixed order
+type hashedEntry struct {
+	PreviousHash string          `json:"previous_hash"`
+	Principal    string          `json:"principal"`
+	ClientIP     string          `json:"client_ip"`
+	TraceID      string          `json:"trace_id"`
+	Action       string          `json:"action"`
+	EntityType   string          `json:"entity_type"`
+	EntityID     int64           `json:"entity_id"`
+	Before       json.RawMessage `json:"before"`
+	After        json.RawMessage `json:"after"`
+	CreatedAt    string          `json:"created_at"`
+}
+
+// ComputeHash returns the hex encoded SHA-256 of the entry chained to its PreviousHash.
+// The audit ID is not covered, so entries can be hashed before they are stored.
+func ComputeHash(entry *Entry) string {
+	content, _ := json.Marshal(hashedEntry{
+		PreviousHash: entry.PreviousHash,
+		Principal:    entry.Principal,
+		ClientIP:     entry.ClientIP,
+		TraceID:      entry.TraceID,
+		Action:       entry.Action,
+		EntityType:   entry.EntityType,
+		EntityID:     entry.EntityID,
+		Before:       nullIfEmpty(entry.Before),
+		After:        nullIfEmpty(entry.After),
+		CreatedAt:    entry.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// Chain links entries to the last hash of the log, setting their PreviousHash and Hash in order
+func Chain(lastHash string, entries ...*Entry) {
+	for _, entry := range entries {
+		entry.PreviousHash = lastHash
+		entry.Hash = ComputeHash(entry)
+		lastHash = entry.Hash
+	}
+}
+
+// ChainVerifier checks entries given in audit ID order, from the first one, against the hash chain
+type ChainVerifier struct {
+	lastHash  string
+	checked   int64
+	invalidID int64
+}
+
+func NewChainVerifier() *ChainVerifier {
+	return &ChainVerifier{lastHash: GenesisHash}
+}
+
+// Verify checks the next entry, returning false once an entry was changed, removed or inserted out of the chain
+func (c *ChainVerifier) Verify(entry *Entry) bool {
+	if c.invalidID != 0 {
+		return false
+	}
+	c.checked++
+	if entry.PreviousHash != c.lastHash || ComputeHash(entry) != entry.Hash {
+		c.invalidID = entry.AuditID
+		return false
+	}
+	c.lastHash = entry.Hash
+	return true
+}
+
+// Checked returns the number of entries verified
+func (c *ChainVerifier) Checked() int64 {
+	return c.checked
+}
+
+// FirstInvalidID returns the ID of the first entry that broke the chain, zero when every entry is valid
+func (c *ChainVerifier) FirstInvalidID() int64 {
+	return c.invalidID
+}
+
+func nullIfEmpty(value json.RawMessage) json.RawMessage {
+	if len(value) == 0 {
+		return json.RawMessage("null")
+	}
+	return value
+}
@@ -0,0 +1,90 @@
+package audit
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func chainedEntries() []*Entry {
+	createdAt := time.Date(2026, 10, 19, 12, 0, 0, 123456000, time.UTC)
+	entries := []*Entry{
+		{AuditID: 1, Principal: "local-integrator", ClientIP: "10.0.0.1", TraceID: "trace-1", Action: ActionAccountCreate,
+			EntityType: EntityAccount, EntityID: 1, After: json.RawMessage(`{"AccountID":1}`), CreatedAt: createdAt},
+		{AuditID: 2, Action: ActionTransactionCreate, EntityType: EntityTransaction, EntityID: 1,
+			After: json.RawMessage(`{"TransactionID":1,"Amount":-10}`), CreatedAt: createdAt.Add(time.Second)},
+		{AuditID: 3, Action: ActionTransactionCreate, EntityType: EntityTransaction, EntityID: 2,
+			After: json.RawMessage(`{"TransactionID":2,"Amount":5}`), CreatedAt: createdAt.Add(2 * time.Second)},
+	}
+	Chain(GenesisHash, entries...)
+	return entries
+}
+
+func verify(entries []*Entry) *ChainVerifier {
+	verifier := NewChainVerifier()
+	for _, entry := range entries {
+		if !verifier.Verify(entry) {
+			break
+		}
+	}
+	return verifier
+}
+
+func TestChainLinksEntries(t *testing.T) {
+	entries := chainedEntries()
+	if entries[0].PreviousHash != GenesisHash {
+		t.Errorf("first PreviousHash = %s, want the genesis hash", entries[0].PreviousHash)
+	}
+	for i := 1; i < len(entries); i++ {
+		if entries[i].PreviousHash != entries[i-1].Hash {
+			t.Errorf("entry %d is not linked to the previous one", entries[i].AuditID)
+		}
+	}
+}
+
+func TestComputeHashIgnoresTimeZone(t *testing.T) {
+	entry := chainedEntries()[0]
+	local := *entry
+	local.CreatedAt = entry.CreatedAt.In(time.FixedZone("BRT", -3*60*60))
+	if ComputeHash(&local) != entry.Hash {
+		t.Error("the hash should not depend on the time zone of the creation time")
+	}
+}
+
+func TestChainVerifier(t *testing.T) {
+	tests := []struct {
+		name          string
+		tamper        func(entries []*Entry) []*Entry
+		wantInvalidID int64
+		wantChecked   int64
+	}{
+		{"must accept an untouched chain", func(entries []*Entry) []*Entry { return entries }, 0, 3},
+		{"must detect a changed snapshot", func(entries []*Entry) []*Entry {
+			entries[1].After = json.RawMessage(`{"TransactionID":1,"Amount":-1}`)
+			return entries
+		}, 2, 2},
+		{"must detect a changed principal", func(entries []*Entry) []*Entry {
+			entries[0].Principal = "someone-else"
+			return entries
+		}, 1, 1},
+		{"must detect a removed entry", func(entries []*Entry) []*Entry {
+			return append(entries[:1], entries[2:]...)
+		}, 3, 2},
+		{"must detect a rehashed entry", func(entries []*Entry) []*Entry {
+			entries[1].EntityID = 9
+			entries[1].Hash = ComputeHash(entries[1])
+			return entries
+		}, 3, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier := verify(tt.tamper(chainedEntries()))
+			if verifier.FirstInvalidID() != tt.wantInvalidID {
+				t.Errorf("FirstInvalidID() = %d, want %d", verifier.FirstInvalidID(), tt.wantInvalidID)
+			}
+			if verifier.Checked() != tt.wantChecked {
+				t.Errorf("Checked() = %d, want %d", verifier.Checked(), tt.wantChecked)
+			}
+		})
+	}
+}
@@ -0,0 +1,3 @@
+// Package audit provides the append-only audit log entries recorded for every state-changing request
+// and the hash chain that makes tampering with them detectable
+package audit
@@ -0,0 +1,56 @@
+package audit
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	ActionAccountCreate     = "account.create"
+	ActionAccountBlock      = "account.block"
+	ActionHolderUpdate      = "account_holder.update"
+	ActionTransactionCreate = "transaction.create"
+	ActionTransactionPost   = "transaction.post"
+	ActionTransactionCancel = "transaction.cancel"
+)
+
+const (
+	EntityAccount       = "account"
+	EntityAccountHolder = "account_holder" // Identified by the account ID
+	EntityTransaction   = "transaction"
+)
+
+// Entry records who changed an entity, from where and how. Before is null for created entities.
+type Entry struct {
+	AuditID      int64
+	Principal    string // Subject of the authenticated principal, empty for anonymous requests
+	ClientIP     string
+	TraceID      string
+	Action       string
+	EntityType   string
+	EntityID     int64
+	Before       json.RawMessage
+	After        json.RawMessage
+	CreatedAt    time.Time
+	PreviousHash string // Hash of the previous entry, GenesisHash for the first one
+	Hash         string
+}
+
+// Filter selects a page of the entries of an entity type, or of a single entity when EntityID is not zero,
+// created in the [From, To) interval. Zero values are ignored.
+type Filter struct {
+	EntityType string
+	EntityID   int64
+	From       time.Time
+	To         time.Time
+	Cursor     int64 // Only entries with a greater ID are returned
+	Limit      int64
+}
+
+// Snapshot encodes the state of an entity to be recorded in an entry
+func Snapshot(entity any) (json.RawMessage, error) {
+	if entity == nil {
+		return nil, nil
+	}
+	return json.Marshal(entity)
+}
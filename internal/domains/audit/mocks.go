@@ -0,0 +1,66 @@
+package audit
+
+import (
+	"context"
+	"github.com/kiosanim/pismo-code-assessment/application/audit/dto"
+	"github.com/stretchr/testify/mock"
+)
+
+type AuditLogRepositoryMock struct {
+	mock.Mock
+}
+
+func NewAuditLogRepositoryMock() *AuditLogRepositoryMock {
+	return &AuditLogRepositoryMock{}
+}
+
+func (m *AuditLogRepositoryMock) Search(ctx context.Context, filter Filter) ([]*Entry, error) {
+	args := m.Called(ctx, filter)
+	val := args.Get(0)
+	p, ok := val.([]*Entry)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return p, nil
+}
+
+// StreamEntries calls fn with every entry returned in the first mocked value
+func (m *AuditLogRepositoryMock) StreamEntries(ctx context.Context, fn func(*Entry) error) error {
+	args := m.Called(ctx, fn)
+	if entries, ok := args.Get(0).([]*Entry); ok {
+		for _, entry := range entries {
+			if err := fn(entry); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+type AuditServiceMock struct {
+	mock.Mock
+}
+
+func NewAuditServiceMock() *AuditServiceMock {
+	return &AuditServiceMock{}
+}
+
+func (m *AuditServiceMock) List(ctx context.Context, request dto.ListAuditLogRequest) (*dto.ListAuditLogResponse, error) {
+	args := m.Called(ctx, request)
+	val := args.Get(0)
+	p, ok := val.(*dto.ListAuditLogResponse)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return p, nil
+}
+
+func (m *AuditServiceMock) Verify(ctx context.Context) (*dto.VerifyAuditLogResponse, error) {
+	args := m.Called(ctx)
+	val := args.Get(0)
+	p, ok := val.(*dto.VerifyAuditLogResponse)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return p, nil
+}
@@ -0,0 +1,10 @@
+package audit
+
+import "context"
+
+// AuditLogRepository reads the audit log. Entries are appended by the repositories of the audited entities,
+// in the same database transaction as the change they record.
+type AuditLogRepository interface {
+	Search(ctx context.Context, filter Filter) ([]*Entry, error)
+	StreamEntries(ctx context.Context, fn func(*Entry) error) error
+}
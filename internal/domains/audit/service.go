@@ -0,0 +1,11 @@
+package audit
+
+import (
+	"context"
+	"github.com/kiosanim/pismo-code-assessment/application/audit/dto"
+)
+
+type Service interface {
+	List(ctx context.Context, request dto.ListAuditLogRequest) (*dto.ListAuditLogResponse, error)
+	Verify(ctx context.Context) (*dto.VerifyAuditLogResponse, error)
+}
@@ -0,0 +1,3 @@
+// Package holder provides the account holder aggregate, the profile and contact data of the owner of an account,
+// and the history of its changes
+package holder
@@ -0,0 +1,205 @@
+package holder
+
+import (
+	"fmt"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/pii"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	MaxNameLength        = 120
+	MaxEmailLength       = 254
+	MaxAddressLineLength = 120
+	MaxCityLength        = 80
+	MaxStateLength       = 80
+	MaxPostalCodeLength  = 16
+	MinBirthYear         = 1850
+)
+
+var (
+	emailRegex          = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+	phoneRegex          = regexp.MustCompile(`^\+[1-9]\d{7,14}$`)
+	phoneSeparatorRegex = regexp.MustCompile(`[\s().-]`)
+	countryCodeRegex    = regexp.MustCompile(`^[A-Z]{2}$`)
+)
+
+// Address is the postal address of a holder
+type Address struct {
+	Line1      string
+	Line2      string
+	City       string
+	State      string
+	PostalCode string
+	Country    string // ISO 3166-1 alpha-2 code
+}
+
+// Holder is the profile and contact data of the owner of an account. Every account has a holder, with no data and
+// version 0 until it is first changed.
+type Holder struct {
+	AccountID int64
+	Name      string
+	Email     string // Lower case e-mail address
+	Phone     string // E.164 phone number, e.g. +5511987654321
+	BirthDate string // YYYY-MM-DD birth date of people or incorporation date of companies
+	Address   Address
+	Version   int64     // Incremented on every change, the ETag of the holder
+	UpdatedAt time.Time // Zero until the holder is first changed
+}
+
+// LogValue logs a Holder with its personal data masked
+func (h Holder) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Int64("account_id", h.AccountID),
+		slog.String("name", pii.Mask(h.Name)),
+		slog.String("email", pii.Mask(h.Email)),
+		slog.String("phone", pii.Mask(h.Phone)),
+		slog.String("country", h.Address.Country),
+		slog.Int64("version", h.Version),
+	)
+}
+
+// Masked returns the holder with every personal value but the city, state and country masked
+func (h Holder) Masked() Holder {
+	h.Name = pii.Mask(h.Name)
+	h.Email = pii.Mask(h.Email)
+	h.Phone = pii.Mask(h.Phone)
+	h.BirthDate = pii.Mask(h.BirthDate)
+	h.Address.Line1 = pii.Mask(h.Address.Line1)
+	h.Address.Line2 = pii.Mask(h.Address.Line2)
+	h.Address.PostalCode = pii.Mask(h.Address.PostalCode)
+	return h
+}
+
+// AddressPatch sets the address values that are not nil
+type AddressPatch struct {
+	Line1      *string
+	Line2      *string
+	City       *string
+	State      *string
+	PostalCode *string
+	Country    *string
+}
+
+// Patch sets the holder values that are not nil, an empty value clearing it
+type Patch struct {
+	Name      *string
+	Email     *string
+	Phone     *string
+	BirthDate *string
+	Address   *AddressPatch
+}
+
+// Apply returns the holder with the normalized values of the patch and the names of the fields they changed,
+// e.g. "email" or "address.city", in the order of the Holder fields
+func (h Holder) Apply(patch Patch) (Holder, []string) {
+	var changed []string
+	set := func(field string, target *string, value *string, normalize func(string) string) {
+		if value == nil {
+			return
+		}
+		if normalized := normalize(*value); normalized != *target {
+			*target = normalized
+			changed = append(changed, field)
+		}
+	}
+	set("name", &h.Name, patch.Name, normalizeName)
+	set("email", &h.Email, patch.Email, normalizeEmail)
+	set("phone", &h.Phone, patch.Phone, NormalizePhone)
+	set("birth_date", &h.BirthDate, patch.BirthDate, strings.TrimSpace)
+	if address := patch.Address; address != nil {
+		set("address.line1", &h.Address.Line1, address.Line1, strings.TrimSpace)
+		set("address.line2", &h.Address.Line2, address.Line2, strings.TrimSpace)
+		set("address.city", &h.Address.City, address.City, strings.TrimSpace)
+		set("address.state", &h.Address.State, address.State, strings.TrimSpace)
+		set("address.postal_code", &h.Address.PostalCode, address.PostalCode, strings.TrimSpace)
+		set("address.country", &h.Address.Country, address.Country, normalizeCountry)
+	}
+	return h, changed
+}
+
+// NormalizePhone removes spaces, dots, dashes and parentheses from a phone number
+func NormalizePhone(phone string) string {
+	return phoneSeparatorRegex.ReplaceAllString(phone, "")
+}
+
+// normalizeName trims a name and collapses its inner spaces
+func normalizeName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func normalizeCountry(country string) string {
+	return strings.ToUpper(strings.TrimSpace(country))
+}
+
+// Validate validates the optional values of a normalized Holder, the birth date must not be after today
+func Validate(h Holder, today time.Time) error {
+	if utf8.RuneCountInString(h.Name) > MaxNameLength {
+		return fmt.Errorf("%w: name longer than %d characters", errors.AccountHolderInvalidError, MaxNameLength)
+	}
+	if h.Email != "" && (len(h.Email) > MaxEmailLength || !emailRegex.MatchString(h.Email)) {
+		return fmt.Errorf("%w: invalid email", errors.AccountHolderInvalidError)
+	}
+	if h.Phone != "" && !phoneRegex.MatchString(h.Phone) {
+		return fmt.Errorf("%w: phone must be an E.164 number, e.g. +5511987654321", errors.AccountHolderInvalidError)
+	}
+	if h.BirthDate != "" {
+		if err := validateBirthDate(h.BirthDate, today); err != nil {
+			return err
+		}
+	}
+	return validateAddress(h.Address)
+}
+
+func validateBirthDate(birthDate string, today time.Time) error {
+	date, err := time.Parse(time.DateOnly, birthDate)
+	if err != nil {
+		return fmt.Errorf("%w: birth_date must be a YYYY-MM-DD date", errors.AccountHolderInvalidError)
+	}
+	if date.Year() < MinBirthYear || date.After(time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)) {
+		return fmt.Errorf("%w: birth_date must not be in the future nor before %d", errors.AccountHolderInvalidError, MinBirthYear)
+	}
+	return nil
+}
+
+func validateAddress(address Address) error {
+	lengths := []struct {
+		field string
+		value string
+		max   int
+	}{
+		{"address.line1", address.Line1, MaxAddressLineLength},
+		{"address.line2", address.Line2, MaxAddressLineLength},
+		{"address.city", address.City, MaxCityLength},
+		{"address.state", address.State, MaxStateLength},
+		{"address.postal_code", address.PostalCode, MaxPostalCodeLength},
+	}
+	for _, length := range lengths {
+		if utf8.RuneCountInString(length.value) > length.max {
+			return fmt.Errorf("%w: %s longer than %d characters", errors.AccountHolderInvalidError, length.field, length.max)
+		}
+	}
+	if address.Country != "" && !countryCodeRegex.MatchString(address.Country) {
+		return fmt.Errorf("%w: address.country must be an ISO 3166-1 alpha-2 code", errors.AccountHolderInvalidError)
+	}
+	return nil
+}
+
+// Change is a version of a holder, recorded with who made it and the fields it changed
+type Change struct {
+	AccountID     int64
+	Version       int64
+	ChangedFields []string
+	Principal     string // Subject of the authenticated principal, empty for anonymous requests
+	TraceID       string
+	ChangedAt     time.Time
+	Holder        Holder // State of the holder after the change
+}
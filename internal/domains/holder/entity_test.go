@@ -0,0 +1,95 @@
+package holder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func ptr(value string) *string {
+	return &value
+}
+
+func TestApply(t *testing.T) {
+	current := Holder{AccountID: 1, Name: "Ana Silva", Email: "ana@example.com", Address: Address{City: "Sao Paulo", Country: "BR"}, Version: 3}
+	tests := []struct {
+		name        string
+		patch       Patch
+		wantHolder  Holder
+		wantChanged []string
+	}{
+		{
+			name:        "must keep the holder when the patch is empty",
+			patch:       Patch{},
+			wantHolder:  current,
+			wantChanged: nil,
+		},
+		{
+			name:        "must ignore values equal to the current ones once normalized",
+			patch:       Patch{Name: ptr("  Ana   Silva "), Email: ptr("ANA@example.com"), Address: &AddressPatch{Country: ptr("br")}},
+			wantHolder:  current,
+			wantChanged: nil,
+		},
+		{
+			name:  "must set the normalized values and list the changed fields",
+			patch: Patch{Phone: ptr("+55 (11) 98765-4321"), Address: &AddressPatch{City: ptr(" Campinas "), PostalCode: ptr("13010-000")}},
+			wantHolder: Holder{AccountID: 1, Name: "Ana Silva", Email: "ana@example.com", Phone: "+5511987654321",
+				Address: Address{City: "Campinas", PostalCode: "13010-000", Country: "BR"}, Version: 3},
+			wantChanged: []string{"phone", "address.city", "address.postal_code"},
+		},
+		{
+			name:        "must clear values set to empty",
+			patch:       Patch{Email: ptr("")},
+			wantHolder:  Holder{AccountID: 1, Name: "Ana Silva", Address: Address{City: "Sao Paulo", Country: "BR"}, Version: 3},
+			wantChanged: []string{"email"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patched, changed := current.Apply(tt.patch)
+			assert.Equal(t, tt.wantHolder, patched)
+			assert.Equal(t, tt.wantChanged, changed)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	today := time.Date(2026, 10, 19, 15, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		holder  Holder
+		wantErr bool
+	}{
+		{"must accept an empty holder", Holder{}, false},
+		{"must accept a complete holder", Holder{Name: "Ana Silva", Email: "ana@example.com", Phone: "+5511987654321", BirthDate: "1990-05-31",
+			Address: Address{Line1: "Av. Paulista, 1000", City: "Sao Paulo", State: "SP", PostalCode: "01310-100", Country: "BR"}}, false},
+		{"must accept a birth date of today", Holder{BirthDate: "2026-10-19"}, false},
+		{"must reject a birth date in the future", Holder{BirthDate: "2026-10-20"}, true},
+		{"must reject a birth date too old", Holder{BirthDate: "1849-12-31"}, true},
+		{"must reject a birth date not in the YYYY-MM-DD format", Holder{BirthDate: "31/05/1990"}, true},
+		{"must reject an invalid email", Holder{Email: "ana.example.com"}, true},
+		{"must reject a phone without the country code", Holder{Phone: "11987654321"}, true},
+		{"must reject a too long name", Holder{Name: string(make([]rune, MaxNameLength+1))}, true},
+		{"must reject a country not in ISO 3166-1 alpha-2", Holder{Address: Address{Country: "BRA"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.holder, today)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, errors.AccountHolderInvalidError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestMasked(t *testing.T) {
+	masked := Holder{Name: "Ana Silva", Email: "ana@example.com", Address: Address{Line1: "Av. Paulista, 1000", City: "Sao Paulo"}}.Masked()
+	assert.Equal(t, "*****ilva", masked.Name)
+	assert.Equal(t, "***********.com", masked.Email)
+	assert.Equal(t, "**************1000", masked.Address.Line1)
+	assert.Equal(t, "Sao Paulo", masked.Address.City, "the city should not be masked")
+}
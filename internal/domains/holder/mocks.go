@@ -0,0 +1,83 @@
+package holder
+
+import (
+	"context"
+	"github.com/kiosanim/pismo-code-assessment/application/holder/dto"
+	"github.com/stretchr/testify/mock"
+)
+
+type HolderRepositoryMock struct {
+	mock.Mock
+}
+
+func NewHolderRepositoryMock() *HolderRepositoryMock {
+	return &HolderRepositoryMock{}
+}
+
+func (m *HolderRepositoryMock) FindByAccountID(ctx context.Context, accountID int64) (*Holder, error) {
+	args := m.Called(ctx, accountID)
+	val := args.Get(0)
+	p, ok := val.(*Holder)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return p, nil
+}
+
+func (m *HolderRepositoryMock) Save(ctx context.Context, holder *Holder, expectedVersion int64, changedFields []string) (*Holder, error) {
+	args := m.Called(ctx, holder, expectedVersion, changedFields)
+	val := args.Get(0)
+	p, ok := val.(*Holder)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return p, nil
+}
+
+func (m *HolderRepositoryMock) History(ctx context.Context, accountID int64) ([]Change, error) {
+	args := m.Called(ctx, accountID)
+	val := args.Get(0)
+	p, ok := val.([]Change)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return p, nil
+}
+
+type HolderServiceMock struct {
+	mock.Mock
+}
+
+func NewHolderServiceMock() *HolderServiceMock {
+	return &HolderServiceMock{}
+}
+
+func (m *HolderServiceMock) Find(ctx context.Context, request dto.FindHolderRequest) (*dto.HolderResponse, error) {
+	args := m.Called(ctx, request)
+	val := args.Get(0)
+	p, ok := val.(*dto.HolderResponse)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return p, nil
+}
+
+func (m *HolderServiceMock) Update(ctx context.Context, request dto.UpdateHolderRequest) (*dto.HolderResponse, error) {
+	args := m.Called(ctx, request)
+	val := args.Get(0)
+	p, ok := val.(*dto.HolderResponse)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return p, nil
+}
+
+func (m *HolderServiceMock) History(ctx context.Context, request dto.HolderHistoryRequest) (*dto.HolderHistoryResponse, error) {
+	args := m.Called(ctx, request)
+	val := args.Get(0)
+	p, ok := val.(*dto.HolderHistoryResponse)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return p, nil
+}
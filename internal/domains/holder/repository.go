@@ -0,0 +1,16 @@
+package holder
+
+import (
+	"context"
+)
+
+type HolderRepository interface {
+	// FindByAccountID returns the holder of an account, AccountHolderNotFoundError when it was never changed
+	FindByAccountID(ctx context.Context, accountID int64) (*Holder, error)
+	// Save stores the holder as the version following expectedVersion, changed at its UpdatedAt, recording the change
+	// and its changed fields in the history. It fails with AccountHolderVersionMismatchError when the stored version is
+	// not expectedVersion.
+	Save(ctx context.Context, holder *Holder, expectedVersion int64, changedFields []string) (*Holder, error)
+	// History returns the changes of the holder of an account, newest first
+	History(ctx context.Context, accountID int64) ([]Change, error)
+}
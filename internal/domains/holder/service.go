@@ -0,0 +1,12 @@
+package holder
+
+import (
+	"context"
+	"github.com/kiosanim/pismo-code-assessment/application/holder/dto"
+)
+
+type Service interface {
+	Find(ctx context.Context, request dto.FindHolderRequest) (*dto.HolderResponse, error)
+	Update(ctx context.Context, request dto.UpdateHolderRequest) (*dto.HolderResponse, error)
+	History(ctx context.Context, request dto.HolderHistoryRequest) (*dto.HolderHistoryResponse, error)
+}
@@ -0,0 +1,2 @@
+// Package ledger provides the double-entry postings made by every transaction and the invariants they must keep
+package ledger
@@ -0,0 +1,60 @@
+package ledger
+
+import (
+	"strconv"
+	"time"
+)
+
+// Direction is the side of a posting, debits increase and credits decrease the balance of a ledger account
+type Direction string
+
+const (
+	Debit  Direction = "DEBIT"
+	Credit Direction = "CREDIT"
+)
+
+const (
+	SettlementAccount = "settlement" // Funds owed to or received from the card network
+	FeesAccount       = "fees"       // Fee revenue
+)
+
+// CustomerAccount is the ledger account of an account, it is debited by what the customer owes
+func CustomerAccount(accountID int64) string {
+	return "customer:" + strconv.FormatInt(accountID, 10)
+}
+
+// Posting is a single ledger entry. Amount is always positive, the postings of a transaction are balanced
+// when their debits and credits are equal.
+type Posting struct {
+	EntryID       int64
+	TransactionID int64
+	LedgerAccount string
+	Direction     Direction
+	Amount        float64 // Amount in Currency, rounded to cents
+	Currency      string
+	CreatedAt     time.Time
+}
+
+// CurrencyTotals sums the postings of a currency
+type CurrencyTotals struct {
+	Currency string
+	Debits   float64
+	Credits  float64
+}
+
+// InvariantReport is the result of checking every posting of the ledger
+type InvariantReport struct {
+	Totals                   []CurrencyTotals
+	UnbalancedTransactionIDs []int64 // Transactions whose postings do not sum to zero
+	UnpostedTransactionIDs   []int64 // Posted transactions without postings
+}
+
+// Valid checks if the debits and credits of every currency and transaction are equal and every transaction was posted
+func (r *InvariantReport) Valid() bool {
+	for _, totals := range r.Totals {
+		if toCents(totals.Debits) != toCents(totals.Credits) {
+			return false
+		}
+	}
+	return len(r.UnbalancedTransactionIDs) == 0 && len(r.UnpostedTransactionIDs) == 0
+}
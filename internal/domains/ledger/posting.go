@@ -0,0 +1,83 @@
+package ledger
+
+import (
+	"github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"math"
+)
+
+// Post returns the balanced postings of a transaction of an account. amount is signed as stored,
+// negative for what the customer owes and positive for payments. A positive fee is charged to the customer
+// and credited to the fees account.
+func Post(accountID int64, amount float64, fee float64, currency string) ([]Posting, error) {
+	amountCents := toCents(math.Abs(amount))
+	feeCents := toCents(fee)
+	if amountCents == 0 || feeCents < 0 {
+		return nil, errors.InvalidParametersError
+	}
+	customer := CustomerAccount(accountID)
+	var postings []Posting
+	if amount < 0 {
+		postings = append(postings,
+			Posting{LedgerAccount: customer, Direction: Debit, Amount: fromCents(amountCents), Currency: currency},
+			Posting{LedgerAccount: SettlementAccount, Direction: Credit, Amount: fromCents(amountCents), Currency: currency})
+	} else {
+		postings = append(postings,
+			Posting{LedgerAccount: SettlementAccount, Direction: Debit, Amount: fromCents(amountCents), Currency: currency},
+			Posting{LedgerAccount: customer, Direction: Credit, Amount: fromCents(amountCents), Currency: currency})
+	}
+	if feeCents > 0 {
+		postings = append(postings,
+			Posting{LedgerAccount: customer, Direction: Debit, Amount: fromCents(feeCents), Currency: currency},
+			Posting{LedgerAccount: FeesAccount, Direction: Credit, Amount: fromCents(feeCents), Currency: currency})
+	}
+	return postings, nil
+}
+
+// Reverse returns the postings cancelling out the given ones, each one with its direction swapped
+func Reverse(postings []Posting) []Posting {
+	reversed := make([]Posting, 0, len(postings))
+	for _, posting := range postings {
+		direction := Debit
+		if posting.Direction == Debit {
+			direction = Credit
+		}
+		reversed = append(reversed, Posting{LedgerAccount: posting.LedgerAccount, Direction: direction, Amount: posting.Amount, Currency: posting.Currency})
+	}
+	return reversed
+}
+
+// Balanced checks if there are postings, all positive, and their debits and credits of each currency are equal
+func Balanced(postings []Posting) bool {
+	if len(postings) == 0 {
+		return false
+	}
+	balances := make(map[string]int64)
+	for _, posting := range postings {
+		cents := toCents(posting.Amount)
+		if cents <= 0 {
+			return false
+		}
+		switch posting.Direction {
+		case Debit:
+			balances[posting.Currency] += cents
+		case Credit:
+			balances[posting.Currency] -= cents
+		default:
+			return false
+		}
+	}
+	for _, balance := range balances {
+		if balance != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func toCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+func fromCents(cents int64) float64 {
+	return float64(cents) / 100
+}
@@ -0,0 +1,123 @@
+package ledger
+
+import (
+	"github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"reflect"
+	"testing"
+)
+
+func TestPost(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount float64
+		fee    float64
+		want   []Posting
+	}{
+		{"must debit the customer of a purchase", -50.5, 0, []Posting{
+			{LedgerAccount: "customer:1", Direction: Debit, Amount: 50.5, Currency: "BRL"},
+			{LedgerAccount: SettlementAccount, Direction: Credit, Amount: 50.5, Currency: "BRL"},
+		}},
+		{"must credit the customer of a payment", 60, 0, []Posting{
+			{LedgerAccount: SettlementAccount, Direction: Debit, Amount: 60, Currency: "BRL"},
+			{LedgerAccount: "customer:1", Direction: Credit, Amount: 60, Currency: "BRL"},
+		}},
+		{"must charge the fee to the customer", -100, 2.499, []Posting{
+			{LedgerAccount: "customer:1", Direction: Debit, Amount: 100, Currency: "BRL"},
+			{LedgerAccount: SettlementAccount, Direction: Credit, Amount: 100, Currency: "BRL"},
+			{LedgerAccount: "customer:1", Direction: Debit, Amount: 2.5, Currency: "BRL"},
+			{LedgerAccount: FeesAccount, Direction: Credit, Amount: 2.5, Currency: "BRL"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Post(1, tt.amount, tt.fee, "BRL")
+			if err != nil {
+				t.Fatalf("Post() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Post() = %v, want %v", got, tt.want)
+			}
+			if !Balanced(got) {
+				t.Error("Post() postings must be balanced")
+			}
+		})
+	}
+}
+
+func TestPostInvalidAmounts(t *testing.T) {
+	for _, amounts := range [][2]float64{{0, 0}, {0.004, 0}, {-10, -1}} {
+		if _, err := Post(1, amounts[0], amounts[1], "BRL"); err != errors.InvalidParametersError {
+			t.Errorf("Post(%v, %v) error = %v, want %v", amounts[0], amounts[1], err, errors.InvalidParametersError)
+		}
+	}
+}
+
+func TestBalanced(t *testing.T) {
+	tests := []struct {
+		name     string
+		postings []Posting
+		want     bool
+	}{
+		{"must reject no postings", nil, false},
+		{"must accept equal debits and credits", []Posting{
+			{Direction: Debit, Amount: 0.1, Currency: "BRL"}, {Direction: Debit, Amount: 0.2, Currency: "BRL"},
+			{Direction: Credit, Amount: 0.3, Currency: "BRL"},
+		}, true},
+		{"must reject different debits and credits", []Posting{
+			{Direction: Debit, Amount: 10, Currency: "BRL"}, {Direction: Credit, Amount: 9.99, Currency: "BRL"},
+		}, false},
+		{"must reject postings balanced across currencies", []Posting{
+			{Direction: Debit, Amount: 10, Currency: "BRL"}, {Direction: Credit, Amount: 10, Currency: "USD"},
+		}, false},
+		{"must reject negative amounts", []Posting{
+			{Direction: Debit, Amount: -10, Currency: "BRL"}, {Direction: Credit, Amount: -10, Currency: "BRL"},
+		}, false},
+		{"must reject unknown directions", []Posting{
+			{Direction: "SIDEWAYS", Amount: 10, Currency: "BRL"}, {Direction: Credit, Amount: 10, Currency: "BRL"},
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Balanced(tt.postings); got != tt.want {
+				t.Errorf("Balanced() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInvariantReportValid(t *testing.T) {
+	balanced := &InvariantReport{Totals: []CurrencyTotals{{Currency: "BRL", Debits: 10.1, Credits: 10.1}}}
+	if !balanced.Valid() {
+		t.Error("a balanced ledger should be valid")
+	}
+	for name, report := range map[string]*InvariantReport{
+		"different totals":       {Totals: []CurrencyTotals{{Currency: "BRL", Debits: 10, Credits: 9}}},
+		"unbalanced transaction": {UnbalancedTransactionIDs: []int64{1}},
+		"unposted transaction":   {UnpostedTransactionIDs: []int64{2}},
+	} {
+		if report.Valid() {
+			t.Errorf("a ledger with %s should be invalid", name)
+		}
+	}
+}
+
+func TestReverse(t *testing.T) {
+	postings, err := Post(1, -50.5, 1, "BRL")
+	if err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+	postings[0].EntryID, postings[0].TransactionID = 1, 10
+	reversed := Reverse(postings)
+	want := []Posting{
+		{LedgerAccount: "customer:1", Direction: Credit, Amount: 50.5, Currency: "BRL"},
+		{LedgerAccount: SettlementAccount, Direction: Debit, Amount: 50.5, Currency: "BRL"},
+		{LedgerAccount: "customer:1", Direction: Credit, Amount: 1, Currency: "BRL"},
+		{LedgerAccount: FeesAccount, Direction: Debit, Amount: 1, Currency: "BRL"},
+	}
+	if !reflect.DeepEqual(reversed, want) {
+		t.Errorf("Reverse() = %v, want %v", reversed, want)
+	}
+	if !Balanced(reversed) {
+		t.Error("Reverse() postings must be balanced")
+	}
+}
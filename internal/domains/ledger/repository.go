@@ -0,0 +1,8 @@
+package ledger
+
+import "context"
+
+// LedgerRepository checks the postings, which are written by the transaction repository with their transactions
+type LedgerRepository interface {
+	CheckInvariants(ctx context.Context) (*InvariantReport, error)
+}
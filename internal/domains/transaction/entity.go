@@ -1,6 +1,8 @@
 package transaction
 
 import (
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/ledger"
+	"strconv"
 	"time"
 )
 
@@ -11,16 +13,112 @@ const (
 	Payment
 )
 
+// Status of a transaction. Transactions effective in the future are PENDING until they are posted or cancelled.
+const (
+	StatusPending   = "PENDING"
+	StatusPosted    = "POSTED"
+	StatusCancelled = "CANCELLED"
+)
+
 // Transaction represent a transaction
 type Transaction struct {
-	TransactionID   int64 // Unique identifier of a Transaction
-	AccountID       int64
-	OperationTypeID int
-	Amount          float64
-	EventDate       time.Time
+	TransactionID    int64 // Unique identifier of a Transaction
+	AccountID        int64
+	OperationTypeID  int
+	Amount           float64 // Amount in the account currency
+	Currency         string  // ISO 4217 code of the account currency
+	OriginalAmount   float64 // Amount informed by the client in OriginalCurrency
+	OriginalCurrency string
+	ExchangeRate     float64 // Units of Currency worth one unit of OriginalCurrency
+	Merchant         Merchant
+	Description      string
+	Metadata         map[string]string // Arbitrary key/value pairs informed by the client
+	EventDate        time.Time         // Date the transaction happened, backdated by the client or the creation date
+	Status           string            // StatusPending, StatusPosted or StatusCancelled
+	EffectiveDate    time.Time         // Date the transaction is, or is scheduled to be, posted
+	PostedAt         time.Time         // Zero while the transaction is not posted
+	CancelledAt      time.Time         // Zero while the transaction is not cancelled
+	CreatedAt        time.Time         // Date the transaction was recorded
+	Postings         []ledger.Posting  // Ledger entries of the transaction, set when it is posted
+}
+
+// ReversalOfMetadataKey is the metadata entry of a reversal holding the ID of the transaction it reverses
+const ReversalOfMetadataKey = "reversal_of"
+
+// IsReversal checks if the transaction reverses another one
+func (t *Transaction) IsReversal() bool {
+	_, ok := t.Metadata[ReversalOfMetadataKey]
+	return ok
+}
+
+// Reverse returns a new transaction, posted at now, cancelling out a posted transaction: its amounts have the
+// opposite sign and its postings mirror the original ones, fees included
+func Reverse(original *Transaction, now time.Time) *Transaction {
+	return &Transaction{
+		AccountID:        original.AccountID,
+		OperationTypeID:  original.OperationTypeID,
+		Amount:           -original.Amount,
+		Currency:         original.Currency,
+		OriginalAmount:   -original.OriginalAmount,
+		OriginalCurrency: original.OriginalCurrency,
+		ExchangeRate:     original.ExchangeRate,
+		Merchant:         original.Merchant,
+		Description:      original.Description,
+		Metadata:         map[string]string{ReversalOfMetadataKey: strconv.FormatInt(original.TransactionID, 10)},
+		EventDate:        now,
+		Status:           StatusPosted,
+		EffectiveDate:    now,
+		PostedAt:         now,
+		CreatedAt:        now,
+		Postings:         ledger.Reverse(original.Postings),
+	}
+}
+
+// StatusTransition is a status a transaction entered and when
+type StatusTransition struct {
+	Status string
+	At     time.Time
+}
+
+// Transitions returns the statuses the transaction went through, the oldest first.
+// Transactions posted when created were never pending.
+func (t *Transaction) Transitions() []StatusTransition {
+	var transitions []StatusTransition
+	if t.Status == StatusPending || !t.CancelledAt.IsZero() || t.PostedAt.After(t.CreatedAt) {
+		transitions = append(transitions, StatusTransition{Status: StatusPending, At: t.CreatedAt})
+	}
+	if !t.PostedAt.IsZero() {
+		transitions = append(transitions, StatusTransition{Status: StatusPosted, At: t.PostedAt})
+	}
+	if !t.CancelledAt.IsZero() {
+		transitions = append(transitions, StatusTransition{Status: StatusCancelled, At: t.CancelledAt})
+	}
+	return transitions
 }
 
 type OperationType struct {
 	OperationTypeID int64
 	Description     string
 }
+
+// TransactionFilter selects the transactions of an account, or of every account when AccountID is zero,
+// whose event date is in the [From, To) interval. Zero times leave the interval open.
+type TransactionFilter struct {
+	AccountID int64
+	From      time.Time
+	To        time.Time
+}
+
+// TransactionSearch selects a page of the transactions matching the filter and the merchant and detail values.
+// MerchantName and Description match case-insensitive substrings and every Metadata entry must be present.
+type TransactionSearch struct {
+	TransactionFilter
+	Status               string // Any status when empty
+	MerchantName         string
+	MerchantCategoryCode string
+	MerchantCountry      string
+	Description          string
+	Metadata             map[string]string
+	Cursor               int64 // Only transactions with a greater ID are returned
+	Limit                int64
+}
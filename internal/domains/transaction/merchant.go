@@ -0,0 +1,72 @@
+package transaction
+
+import (
+	"fmt"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	MaxMerchantNameLength  = 120
+	MaxDescriptionLength   = 255
+	MaxMetadataEntries     = 20
+	MaxMetadataKeyLength   = 40
+	MaxMetadataValueLength = 255
+)
+
+var (
+	merchantCategoryCodeRegex = regexp.MustCompile(`^\d{4}$`)
+	countryCodeRegex          = regexp.MustCompile(`^[A-Z]{2}$`)
+	metadataKeyRegex          = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+)
+
+// Merchant describes where a transaction happened
+type Merchant struct {
+	Name         string
+	CategoryCode string // ISO 18245 merchant category code (MCC)
+	Country      string // ISO 3166-1 alpha-2 code
+}
+
+// NormalizeMerchant trims the merchant values and upper cases the country code
+func NormalizeMerchant(merchant Merchant) Merchant {
+	return Merchant{
+		Name:         strings.TrimSpace(merchant.Name),
+		CategoryCode: strings.TrimSpace(merchant.CategoryCode),
+		Country:      strings.ToUpper(strings.TrimSpace(merchant.Country)),
+	}
+}
+
+// ValidateMerchant validates the optional merchant values of a normalized Merchant
+func ValidateMerchant(merchant Merchant) error {
+	if utf8.RuneCountInString(merchant.Name) > MaxMerchantNameLength {
+		return fmt.Errorf("%w: merchant_name longer than %d characters", errors.TransactionInvalidDetailsError, MaxMerchantNameLength)
+	}
+	if merchant.CategoryCode != "" && !merchantCategoryCodeRegex.MatchString(merchant.CategoryCode) {
+		return fmt.Errorf("%w: mcc must have 4 digits", errors.TransactionInvalidDetailsError)
+	}
+	if merchant.Country != "" && !countryCodeRegex.MatchString(merchant.Country) {
+		return fmt.Errorf("%w: merchant_country must be an ISO 3166-1 alpha-2 code", errors.TransactionInvalidDetailsError)
+	}
+	return nil
+}
+
+// ValidateDetails validates the description and the key/value metadata of a transaction
+func ValidateDetails(description string, metadata map[string]string) error {
+	if utf8.RuneCountInString(description) > MaxDescriptionLength {
+		return fmt.Errorf("%w: description longer than %d characters", errors.TransactionInvalidDetailsError, MaxDescriptionLength)
+	}
+	if len(metadata) > MaxMetadataEntries {
+		return fmt.Errorf("%w: more than %d metadata entries", errors.TransactionInvalidDetailsError, MaxMetadataEntries)
+	}
+	for key, value := range metadata {
+		if len(key) > MaxMetadataKeyLength || !metadataKeyRegex.MatchString(key) {
+			return fmt.Errorf("%w: invalid metadata key %q", errors.TransactionInvalidDetailsError, key)
+		}
+		if utf8.RuneCountInString(value) > MaxMetadataValueLength {
+			return fmt.Errorf("%w: metadata %q longer than %d characters", errors.TransactionInvalidDetailsError, key, MaxMetadataValueLength)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package transaction
+
+import (
+	"github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeMerchant(t *testing.T) {
+	got := NormalizeMerchant(Merchant{Name: "  Coffee Shop ", CategoryCode: " 5814", Country: "br "})
+	want := Merchant{Name: "Coffee Shop", CategoryCode: "5814", Country: "BR"}
+	if got != want {
+		t.Errorf("NormalizeMerchant() = %v, want %v", got, want)
+	}
+}
+
+func TestValidateMerchant(t *testing.T) {
+	tests := []struct {
+		name     string
+		merchant Merchant
+		wantErr  bool
+	}{
+		{"must accept an empty merchant", Merchant{}, false},
+		{"must accept a complete merchant", Merchant{Name: "Coffee Shop", CategoryCode: "5814", Country: "BR"}, false},
+		{"must reject a long merchant name", Merchant{Name: strings.Repeat("a", MaxMerchantNameLength+1)}, true},
+		{"must reject a MCC with letters", Merchant{CategoryCode: "58A4"}, true},
+		{"must reject a MCC with 3 digits", Merchant{CategoryCode: "581"}, true},
+		{"must reject an alpha-3 country", Merchant{Country: "BRA"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMerchant(tt.merchant)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateMerchant() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateDetails(t *testing.T) {
+	tooMany := map[string]string{}
+	for i := 0; i <= MaxMetadataEntries; i++ {
+		tooMany[strings.Repeat("k", i+1)] = "v"
+	}
+	tests := []struct {
+		name        string
+		description string
+		metadata    map[string]string
+		wantErr     bool
+	}{
+		{"must accept empty details", "", nil, false},
+		{"must accept valid details", "Monthly subscription", map[string]string{"order_id": "123", "channel.app": "ios"}, false},
+		{"must reject a long description", strings.Repeat("a", MaxDescriptionLength+1), nil, true},
+		{"must reject too many metadata entries", "", tooMany, true},
+		{"must reject a metadata key with spaces", "", map[string]string{"order id": "1"}, true},
+		{"must reject an empty metadata key", "", map[string]string{"": "1"}, true},
+		{"must reject a long metadata value", "", map[string]string{"note": strings.Repeat("a", MaxMetadataValueLength+1)}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateDetails(tt.description, tt.metadata)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateDetails() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), errors.TransactionInvalidDetailsError.Error()) {
+				t.Errorf("ValidateDetails() error = %v, want TransactionInvalidDetailsError", err)
+			}
+		})
+	}
+}
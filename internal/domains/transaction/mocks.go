@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/kiosanim/pismo-code-assessment/application/transaction/dto"
 	"github.com/stretchr/testify/mock"
+	"time"
 )
 
 type TransactionRepositoryMock struct {
@@ -24,6 +25,39 @@ func (tr *TransactionRepositoryMock) Save(ctx context.Context, transaction *Tran
 	return p, nil
 }
 
+func (tr *TransactionRepositoryMock) SaveBatch(ctx context.Context, transactions []*Transaction) ([]*Transaction, error) {
+	args := tr.Called(ctx, transactions)
+	val := args.Get(0)
+	p, ok := val.([]*Transaction)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return p, nil
+}
+
+// StreamTransactions calls fn with every transaction returned in the first mocked value
+func (tr *TransactionRepositoryMock) StreamTransactions(ctx context.Context, filter TransactionFilter, fn func(*Transaction) error) error {
+	args := tr.Called(ctx, filter, fn)
+	if transactions, ok := args.Get(0).([]*Transaction); ok {
+		for _, t := range transactions {
+			if err := fn(t); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (tr *TransactionRepositoryMock) Search(ctx context.Context, search TransactionSearch) ([]*Transaction, error) {
+	args := tr.Called(ctx, search)
+	val := args.Get(0)
+	p, ok := val.([]*Transaction)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return p, nil
+}
+
 func (tr *TransactionRepositoryMock) FindOperationTypeByID(ctx context.Context, operationTypeID int) (*OperationType, error) {
 	args := tr.Called(ctx, operationTypeID)
 	val := args.Get(0)
@@ -44,6 +78,36 @@ func (tr *TransactionRepositoryMock) FindTransactionByID(ctx context.Context, tr
 	return p, nil
 }
 
+func (tr *TransactionRepositoryMock) FindDue(ctx context.Context, until time.Time, limit int64) ([]*Transaction, error) {
+	args := tr.Called(ctx, until, limit)
+	val := args.Get(0)
+	p, ok := val.([]*Transaction)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return p, nil
+}
+
+func (tr *TransactionRepositoryMock) Post(ctx context.Context, dueTransaction *Transaction) (*Transaction, error) {
+	args := tr.Called(ctx, dueTransaction)
+	val := args.Get(0)
+	p, ok := val.(*Transaction)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return p, nil
+}
+
+func (tr *TransactionRepositoryMock) Cancel(ctx context.Context, transactionID int64, cancelledAt time.Time) (*Transaction, error) {
+	args := tr.Called(ctx, transactionID, cancelledAt)
+	val := args.Get(0)
+	p, ok := val.(*Transaction)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return p, nil
+}
+
 type TransactionServiceMock struct {
 	mock.Mock
 }
@@ -61,3 +125,63 @@ func (m *TransactionServiceMock) Create(ctx context.Context, input dto.CreateTra
 	}
 	return p, nil
 }
+
+func (m *TransactionServiceMock) FindByID(ctx context.Context, request dto.FindTransactionByIdRequest) (*dto.FindTransactionByIdResponse, error) {
+	args := m.Called(ctx, request)
+	val := args.Get(0)
+	p, ok := val.(*dto.FindTransactionByIdResponse)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return p, nil
+}
+
+func (m *TransactionServiceMock) CreateBatch(ctx context.Context, rows []dto.BatchTransactionRow) (*dto.CreateTransactionBatchResponse, error) {
+	args := m.Called(ctx, rows)
+	val := args.Get(0)
+	p, ok := val.(*dto.CreateTransactionBatchResponse)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return p, nil
+}
+
+func (m *TransactionServiceMock) Export(ctx context.Context, request dto.ExportTransactionsRequest, write func(row dto.ExportTransactionRow) error) error {
+	args := m.Called(ctx, request, write)
+	return args.Error(0)
+}
+
+func (m *TransactionServiceMock) List(ctx context.Context, request dto.ListTransactionsRequest) (*dto.ListTransactionsResponse, error) {
+	args := m.Called(ctx, request)
+	val := args.Get(0)
+	p, ok := val.(*dto.ListTransactionsResponse)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return p, nil
+}
+
+func (m *TransactionServiceMock) Cancel(ctx context.Context, request dto.CancelTransactionRequest) (*dto.CancelTransactionResponse, error) {
+	args := m.Called(ctx, request)
+	val := args.Get(0)
+	p, ok := val.(*dto.CancelTransactionResponse)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return p, nil
+}
+
+func (m *TransactionServiceMock) PostDue(ctx context.Context, limit int64) (int, error) {
+	args := m.Called(ctx, limit)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *TransactionServiceMock) Reverse(ctx context.Context, request dto.ReverseTransactionRequest) (*dto.ReverseTransactionResponse, error) {
+	args := m.Called(ctx, request)
+	val := args.Get(0)
+	p, ok := val.(*dto.ReverseTransactionResponse)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return p, nil
+}
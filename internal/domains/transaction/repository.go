@@ -2,10 +2,17 @@ package transaction
 
 import (
 	"context"
+	"time"
 )
 
 type TransactionRepository interface {
 	FindOperationTypeByID(ctx context.Context, operationTypeID int) (*OperationType, error)
 	FindTransactionByID(ctx context.Context, transactionID int64) (*Transaction, error)
 	Save(ctx context.Context, newTransaction *Transaction) (*Transaction, error)
+	SaveBatch(ctx context.Context, newTransactions []*Transaction) ([]*Transaction, error)
+	StreamTransactions(ctx context.Context, filter TransactionFilter, fn func(*Transaction) error) error
+	Search(ctx context.Context, search TransactionSearch) ([]*Transaction, error)
+	FindDue(ctx context.Context, until time.Time, limit int64) ([]*Transaction, error)
+	Post(ctx context.Context, dueTransaction *Transaction) (*Transaction, error)
+	Cancel(ctx context.Context, transactionID int64, cancelledAt time.Time) (*Transaction, error)
 }
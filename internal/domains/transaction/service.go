@@ -8,4 +8,10 @@ import (
 type Service interface {
 	Create(ctx context.Context, request dto.CreateTransactionRequest) (*dto.CreateTransactionResponse, error)
 	FindByID(ctx context.Context, request dto.FindTransactionByIdRequest) (*dto.FindTransactionByIdResponse, error)
+	CreateBatch(ctx context.Context, rows []dto.BatchTransactionRow) (*dto.CreateTransactionBatchResponse, error)
+	List(ctx context.Context, request dto.ListTransactionsRequest) (*dto.ListTransactionsResponse, error)
+	Export(ctx context.Context, request dto.ExportTransactionsRequest, write func(row dto.ExportTransactionRow) error) error
+	Cancel(ctx context.Context, request dto.CancelTransactionRequest) (*dto.CancelTransactionResponse, error)
+	PostDue(ctx context.Context, limit int64) (int, error)
+	Reverse(ctx context.Context, request dto.ReverseTransactionRequest) (*dto.ReverseTransactionResponse, error)
 }
@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/auth"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/config"
+	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
+	"strings"
+)
+
+type apiKey struct {
+	name   string
+	hash   []byte
+	scopes []string
+}
+
+// APIKeyAuthenticator authenticates machine clients comparing the SHA-256 of the presented key with the configured ones
+type APIKeyAuthenticator struct {
+	keys          []apiKey
+	componentName string
+	log           logger.Logger
+}
+
+func NewAPIKeyAuthenticator(cfg []config.APIKeyConfig, log logger.Logger) *APIKeyAuthenticator {
+	authenticator := &APIKeyAuthenticator{
+		log: log,
+	}
+	authenticator.componentName = logger.ComponentNameFromStruct(authenticator)
+	for _, keyCfg := range cfg {
+		hash, err := hex.DecodeString(strings.ToLower(keyCfg.KeySHA256))
+		if err != nil || len(hash) != sha256.Size {
+			log.Warn(authenticator.componentName, "status", "ignoring api key with invalid key_sha256", "name", keyCfg.Name)
+			continue
+		}
+		authenticator.keys = append(authenticator.keys, apiKey{name: keyCfg.Name, hash: hash, scopes: keyCfg.Scopes})
+	}
+	return authenticator
+}
+
+func (a *APIKeyAuthenticator) Scheme() string {
+	return auth.SchemeAPIKey
+}
+
+func (a *APIKeyAuthenticator) Authenticate(ctx context.Context, credential string) (*auth.Principal, error) {
+	presented := sha256.Sum256([]byte(credential))
+	for _, key := range a.keys {
+		if subtle.ConstantTimeCompare(presented[:], key.hash) == 1 {
+			return &auth.Principal{
+				Subject: key.name,
+				Method:  auth.MethodAPIKey,
+				Scopes:  key.scopes,
+			}, nil
+		}
+	}
+	a.log.Debug(a.componentName+".Authenticate", "error", coreerr.AuthInvalidCredentialsError)
+	return nil, coreerr.AuthInvalidCredentialsError
+}
@@ -0,0 +1,32 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/kiosanim/pismo-code-assessment/internal/core/auth"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/config"
+	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/logger/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeyAuthenticator(t *testing.T) {
+	hash := sha256.Sum256([]byte("integrator-secret-key"))
+	authenticator := NewAPIKeyAuthenticator([]config.APIKeyConfig{
+		{Name: "integrator", KeySHA256: hex.EncodeToString(hash[:]), Scopes: []string{auth.ScopeTransactionsWrite}},
+		{Name: "broken", KeySHA256: "not-hex"},
+	}, mock.NewMockLogger())
+
+	principal, err := authenticator.Authenticate(context.Background(), "integrator-secret-key")
+	assert.NoError(t, err)
+	assert.Equal(t, "integrator", principal.Subject)
+	assert.Equal(t, auth.MethodAPIKey, principal.Method)
+	assert.True(t, principal.HasScopes(auth.ScopeTransactionsWrite))
+
+	principal, err = authenticator.Authenticate(context.Background(), "wrong-key")
+	assert.ErrorIs(t, err, coreerr.AuthInvalidCredentialsError)
+	assert.Nil(t, principal)
+}
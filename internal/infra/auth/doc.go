@@ -0,0 +1,2 @@
+// Package auth contains the JWT and API key authenticators used by the HTTP middlewares
+package auth
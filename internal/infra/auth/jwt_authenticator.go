@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/auth"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/config"
+	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
+	"strings"
+)
+
+// tokenClaims are the registered claims plus the OAuth 2.0 space separated scope claim
+type tokenClaims struct {
+	jwt.RegisteredClaims
+	Scope string `json:"scope"`
+}
+
+type JWTAuthenticator struct {
+	algorithm     string
+	secret        []byte
+	publicKeys    map[string]*rsa.PublicKey
+	parser        *jwt.Parser
+	componentName string
+	log           logger.Logger
+}
+
+func NewJWTAuthenticator(cfg config.JWTConfig, log logger.Logger) (*JWTAuthenticator, error) {
+	authenticator := &JWTAuthenticator{
+		algorithm: strings.ToUpper(cfg.Algorithm),
+		log:       log,
+	}
+	authenticator.componentName = logger.ComponentNameFromStruct(authenticator)
+	switch authenticator.algorithm {
+	case jwt.SigningMethodHS256.Alg():
+		if cfg.Secret == "" {
+			return nil, fmt.Errorf("%w: auth.jwt.secret is required for HS256", coreerr.ConfigValidationError)
+		}
+		authenticator.secret = []byte(cfg.Secret)
+	case jwt.SigningMethodRS256.Alg():
+		publicKeys, err := LoadJWKS(cfg.JWKSFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", coreerr.ConfigValidationError, err)
+		}
+		authenticator.publicKeys = publicKeys
+	default:
+		return nil, fmt.Errorf("%w: unsupported auth.jwt.algorithm %q", coreerr.ConfigValidationError, cfg.Algorithm)
+	}
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{authenticator.algorithm}),
+		jwt.WithExpirationRequired(),
+	}
+	if cfg.Issuer != "" {
+		options = append(options, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		options = append(options, jwt.WithAudience(cfg.Audience))
+	}
+	authenticator.parser = jwt.NewParser(options...)
+	return authenticator, nil
+}
+
+func (j *JWTAuthenticator) Scheme() string {
+	return auth.SchemeBearer
+}
+
+// Authenticate validates the signature and the registered claims of a bearer token
+func (j *JWTAuthenticator) Authenticate(ctx context.Context, credential string) (*auth.Principal, error) {
+	var claims tokenClaims
+	_, err := j.parser.ParseWithClaims(credential, &claims, j.signingKey)
+	if err != nil {
+		j.log.Debug(j.componentName+".Authenticate", "error", err)
+		return nil, coreerr.AuthInvalidCredentialsError
+	}
+	if claims.Subject == "" {
+		return nil, coreerr.AuthInvalidCredentialsError
+	}
+	return &auth.Principal{
+		Subject: claims.Subject,
+		Method:  auth.MethodJWT,
+		Scopes:  strings.Fields(claims.Scope),
+	}, nil
+}
+
+// signingKey resolves the key used to verify a token, using the kid header for RS256
+func (j *JWTAuthenticator) signingKey(token *jwt.Token) (any, error) {
+	if j.algorithm == jwt.SigningMethodHS256.Alg() {
+		return j.secret, nil
+	}
+	keyID, _ := token.Header["kid"].(string)
+	publicKey, ok := j.publicKeys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", keyID)
+	}
+	return publicKey, nil
+}
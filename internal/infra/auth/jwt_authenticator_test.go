@@ -0,0 +1,147 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/auth"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/config"
+	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/logger/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testKeyID = "test-key"
+
+func signToken(t *testing.T, method jwt.SigningMethod, key any, keyID string, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if keyID != "" {
+		token.Header["kid"] = keyID
+	}
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+// writeJWKS writes a local JWKS file containing the public part of privateKey
+func writeJWKS(t *testing.T, privateKey *rsa.PrivateKey) string {
+	t.Helper()
+	keySet := jsonWebKeySet{Keys: []jsonWebKey{{
+		KeyType:   "RSA",
+		KeyID:     testKeyID,
+		Use:       "sig",
+		Algorithm: "RS256",
+		Modulus:   base64.RawURLEncoding.EncodeToString(privateKey.N.Bytes()),
+		Exponent:  base64.RawURLEncoding.EncodeToString(big.NewInt(int64(privateKey.E)).Bytes()),
+	}}}
+	content, err := json.Marshal(keySet)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, content, 0644))
+	return path
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":   "user-1",
+		"iss":   "pismo-tests",
+		"scope": auth.ScopeAccountsRead + " " + auth.ScopeTransactionsWrite,
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func TestJWTAuthenticatorHS256(t *testing.T) {
+	secret := []byte("test-secret")
+	authenticator, err := NewJWTAuthenticator(config.JWTConfig{Algorithm: "HS256", Secret: string(secret), Issuer: "pismo-tests"}, mock.NewMockLogger())
+	require.NoError(t, err)
+	expired := validClaims()
+	expired["exp"] = time.Now().Add(-time.Minute).Unix()
+	wrongIssuer := validClaims()
+	wrongIssuer["iss"] = "someone-else"
+	noExpiration := validClaims()
+	delete(noExpiration, "exp")
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{"must accept a valid token", signToken(t, jwt.SigningMethodHS256, secret, "", validClaims()), false},
+		{"must reject a token signed with another secret", signToken(t, jwt.SigningMethodHS256, []byte("other"), "", validClaims()), true},
+		{"must reject an expired token", signToken(t, jwt.SigningMethodHS256, secret, "", expired), true},
+		{"must reject a token without expiration", signToken(t, jwt.SigningMethodHS256, secret, "", noExpiration), true},
+		{"must reject a token from another issuer", signToken(t, jwt.SigningMethodHS256, secret, "", wrongIssuer), true},
+		{"must reject a malformed token", "not-a-token", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := authenticator.Authenticate(context.Background(), tt.token)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, coreerr.AuthInvalidCredentialsError)
+				assert.Nil(t, principal)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "user-1", principal.Subject)
+			assert.Equal(t, auth.MethodJWT, principal.Method)
+			assert.True(t, principal.HasScopes(auth.ScopeAccountsRead, auth.ScopeTransactionsWrite))
+			assert.False(t, principal.HasScopes(auth.ScopeAccountsWrite))
+		})
+	}
+}
+
+func TestJWTAuthenticatorRS256(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	authenticator, err := NewJWTAuthenticator(config.JWTConfig{Algorithm: "RS256", JWKSFile: writeJWKS(t, privateKey)}, mock.NewMockLogger())
+	require.NoError(t, err)
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{"must accept a token signed by a key of the JWKS", signToken(t, jwt.SigningMethodRS256, privateKey, testKeyID, validClaims()), false},
+		{"must reject a token signed by an unknown key", signToken(t, jwt.SigningMethodRS256, otherKey, testKeyID, validClaims()), true},
+		{"must reject a token with an unknown key id", signToken(t, jwt.SigningMethodRS256, privateKey, "unknown", validClaims()), true},
+		{"must reject a HS256 token", signToken(t, jwt.SigningMethodHS256, []byte("secret"), testKeyID, validClaims()), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := authenticator.Authenticate(context.Background(), tt.token)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, coreerr.AuthInvalidCredentialsError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNewJWTAuthenticatorInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.JWTConfig
+	}{
+		{"must reject an unsupported algorithm", config.JWTConfig{Algorithm: "none"}},
+		{"must reject HS256 without secret", config.JWTConfig{Algorithm: "HS256"}},
+		{"must reject RS256 without JWKS file", config.JWTConfig{Algorithm: "RS256", JWKSFile: "missing.json"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewJWTAuthenticator(tt.cfg, mock.NewMockLogger())
+			assert.ErrorIs(t, err, coreerr.ConfigValidationError)
+		})
+	}
+}
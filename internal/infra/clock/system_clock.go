@@ -0,0 +1,14 @@
+package clock
+
+import "time"
+
+// SystemClock is the wall clock of the host
+type SystemClock struct{}
+
+func NewSystemClock() *SystemClock {
+	return &SystemClock{}
+}
+
+func (s *SystemClock) Now() time.Time {
+	return time.Now()
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"context"
+	"github.com/fsnotify/fsnotify"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/config"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+)
+
+// ConfigWatcher reloads the config file when it changes or when the process receives a SIGHUP,
+// applying the non-critical values to every registered config.Reloadable component
+type ConfigWatcher struct {
+	path          string
+	current       atomic.Pointer[config.Configuration]
+	listeners     []config.Reloadable
+	mu            sync.Mutex
+	componentName string
+	log           logger.Logger
+}
+
+func NewConfigWatcher(path string, cfg *config.Configuration, log logger.Logger) *ConfigWatcher {
+	watcher := &ConfigWatcher{
+		path: path,
+		log:  log,
+	}
+	watcher.current.Store(cfg)
+	watcher.componentName = logger.ComponentNameFromStruct(watcher)
+	return watcher
+}
+
+// Register adds a component to be notified when the configuration is reloaded
+func (w *ConfigWatcher) Register(listener config.Reloadable) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.listeners = append(w.listeners, listener)
+}
+
+// Current returns the configuration in effect
+func (w *ConfigWatcher) Current() *config.Configuration {
+	return w.current.Load()
+}
+
+// Watch starts listening for config file changes and SIGHUP signals until ctx is done
+func (w *ConfigWatcher) Watch(ctx context.Context) {
+	v := newViper(w.path)
+	if err := v.ReadInConfig(); err != nil {
+		w.log.Warn(w.componentName+".Watch", "error", errors.ConfigFileNotFountError)
+		return
+	}
+	v.OnConfigChange(func(event fsnotify.Event) {
+		w.log.Info(w.componentName+".Watch", "event", event.String())
+		_ = w.Reload()
+	})
+	v.WatchConfig()
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	go func() {
+		defer signal.Stop(hangup)
+		for {
+			select {
+			case <-hangup:
+				w.log.Info(w.componentName+".Watch", "event", "SIGHUP")
+				_ = w.Reload()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Reload reads the config file again, rejecting it when invalid, and applies the reloadable values
+func (w *ConfigWatcher) Reload() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	candidate, err := LoadConfig(w.path)
+	if err != nil {
+		w.log.Error(w.componentName+".Reload", "error", err)
+		return err
+	}
+	if err = ValidateConfig(candidate); err != nil {
+		w.log.Error(w.componentName+".Reload", "status", "rejected", "error", err)
+		return err
+	}
+	current := w.current.Load()
+	changes := DiffConfig(current, candidate)
+	if len(changes) == 0 {
+		w.log.Debug(w.componentName+".Reload", "status", "unchanged")
+		return nil
+	}
+	var applied, ignored []string
+	for _, change := range changes {
+		if change.Reloadable {
+			applied = append(applied, change.String())
+		} else {
+			ignored = append(ignored, change.Key)
+		}
+	}
+	if len(ignored) > 0 {
+		w.log.Warn(w.componentName+".Reload", "status", "restart required", "keys", ignored)
+	}
+	if len(applied) == 0 {
+		return nil
+	}
+	effective := MergeReloadable(current, candidate)
+	w.current.Store(effective)
+	for _, listener := range w.listeners {
+		listener.ApplyConfiguration(effective)
+	}
+	w.log.Info(w.componentName+".Reload", "status", "applied", "changes", applied)
+	return nil
+}
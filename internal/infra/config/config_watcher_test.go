@@ -0,0 +1,71 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kiosanim/pismo-code-assessment/internal/core/config"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/logger/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+type reloadableSpy struct {
+	applied []*config.Configuration
+}
+
+func (r *reloadableSpy) ApplyConfiguration(cfg *config.Configuration) {
+	r.applied = append(r.applied, cfg)
+}
+
+func writeConfigFile(t *testing.T, path string, logLevel string, ttl string) {
+	t.Helper()
+	content := `
+app:
+  env: "development"
+  address: ":8080"
+  log_level: "` + logLevel + `"
+database:
+  url: "postgres://localhost:5432/db"
+cache:
+  url: "redis://localhost:6379/0"
+distributed_lock:
+  ttl_ms: ` + ttl + `
+  retry_interval_ms: 2000
+  waiting_time_ms: 4500
+`
+	err := os.WriteFile(filepath.Join(path, "config.yaml"), []byte(content), 0644)
+	if err != nil {
+		t.Fatalf("Failed to write test config file: %v", err)
+	}
+}
+
+func TestConfigWatcherReload(t *testing.T) {
+	path := t.TempDir()
+	writeConfigFile(t, path, "debug", "5000")
+	watcher := NewConfigWatcher(path, MustLoadConfig(path), mock.NewMockLogger())
+	spy := &reloadableSpy{}
+	watcher.Register(spy)
+
+	writeConfigFile(t, path, "warn", "3000")
+	assert.NoError(t, watcher.Reload())
+	assert.Len(t, spy.applied, 1, "listener should be notified once")
+	assert.Equal(t, "warn", watcher.Current().App.LogLevel)
+	assert.Equal(t, int64(3000), watcher.Current().DistributedLock.TTL)
+
+	assert.NoError(t, watcher.Reload())
+	assert.Len(t, spy.applied, 1, "listener should not be notified when nothing changed")
+}
+
+func TestConfigWatcherReloadRejectsInvalidConfig(t *testing.T) {
+	path := t.TempDir()
+	writeConfigFile(t, path, "debug", "5000")
+	watcher := NewConfigWatcher(path, MustLoadConfig(path), mock.NewMockLogger())
+	spy := &reloadableSpy{}
+	watcher.Register(spy)
+
+	writeConfigFile(t, path, "loud", "0")
+	assert.Error(t, watcher.Reload())
+	assert.Empty(t, spy.applied, "listener should not be notified for an invalid config")
+	assert.Equal(t, "debug", watcher.Current().App.LogLevel, "current configuration should be kept")
+}
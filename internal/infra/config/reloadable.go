@@ -0,0 +1,99 @@
+package config
+
+import (
+	"fmt"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/config"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	infralogger "github.com/kiosanim/pismo-code-assessment/internal/infra/logger"
+	"reflect"
+	"strings"
+)
+
+// reloadableKeys are the only configuration keys applied without restarting the application
+var reloadableKeys = map[string]bool{
+	"app.log_level":                      true,
+	"distributed_lock.ttl_ms":            true,
+	"distributed_lock.retry_interval_ms": true,
+	"distributed_lock.waiting_time_ms":   true,
+	"pii.redacted_log_attributes":        true,
+}
+
+// ConfigChange represents a single configuration key that changed between two versions of the configuration
+type ConfigChange struct {
+	Key        string
+	OldValue   any
+	NewValue   any
+	Reloadable bool
+}
+
+func (c ConfigChange) String() string {
+	return fmt.Sprintf("%s: %v -> %v", c.Key, c.OldValue, c.NewValue)
+}
+
+// ValidateConfig validates the values that can be changed at runtime
+func ValidateConfig(cfg *config.Configuration) error {
+	if cfg == nil {
+		return errors.ConfigValidationError
+	}
+	if _, ok := infralogger.ParseLevel(cfg.App.LogLevel); !ok {
+		return fmt.Errorf("%w: unknown app.log_level %q", errors.ConfigValidationError, cfg.App.LogLevel)
+	}
+	lockCfg := cfg.DistributedLock
+	if lockCfg.TTL <= 0 || lockCfg.RetryInterval <= 0 || lockCfg.WaitingTime <= 0 {
+		return fmt.Errorf("%w: distributed_lock timings must be greater than zero", errors.ConfigValidationError)
+	}
+	if lockCfg.RetryInterval > lockCfg.WaitingTime {
+		return fmt.Errorf("%w: distributed_lock.retry_interval_ms must not be greater than waiting_time_ms", errors.ConfigValidationError)
+	}
+	return nil
+}
+
+// DiffConfig lists every key whose value differs between oldCfg and newCfg
+func DiffConfig(oldCfg *config.Configuration, newCfg *config.Configuration) []ConfigChange {
+	var changes []ConfigChange
+	diffStruct("", reflect.ValueOf(*oldCfg), reflect.ValueOf(*newCfg), &changes)
+	return changes
+}
+
+// MergeReloadable returns a copy of current with only the reloadable values taken from candidate
+func MergeReloadable(current *config.Configuration, candidate *config.Configuration) *config.Configuration {
+	merged := *current
+	merged.App.LogLevel = candidate.App.LogLevel
+	merged.DistributedLock = candidate.DistributedLock
+	merged.DistributedLock.Backend = current.DistributedLock.Backend
+	merged.PII.RedactedLogAttributes = candidate.PII.RedactedLogAttributes
+	return &merged
+}
+
+func diffStruct(prefix string, oldValue reflect.Value, newValue reflect.Value, changes *[]ConfigChange) {
+	for i := 0; i < oldValue.NumField(); i++ {
+		field := oldValue.Type().Field(i)
+		key := configKey(field)
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		oldField, newField := oldValue.Field(i), newValue.Field(i)
+		if field.Type.Kind() == reflect.Struct {
+			diffStruct(key, oldField, newField, changes)
+			continue
+		}
+		if !reflect.DeepEqual(oldField.Interface(), newField.Interface()) {
+			*changes = append(*changes, ConfigChange{
+				Key:        key,
+				OldValue:   oldField.Interface(),
+				NewValue:   newField.Interface(),
+				Reloadable: reloadableKeys[key],
+			})
+		}
+	}
+}
+
+// configKey returns the name used in the config file for a struct field
+func configKey(field reflect.StructField) string {
+	for _, tagName := range []string{"mapstructure", "yaml"} {
+		if tag := field.Tag.Get(tagName); tag != "" {
+			return strings.Split(tag, ",")[0]
+		}
+	}
+	return strings.ToLower(field.Name)
+}
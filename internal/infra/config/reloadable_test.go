@@ -0,0 +1,80 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kiosanim/pismo-code-assessment/internal/core/config"
+	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func validConfiguration() *config.Configuration {
+	return &config.Configuration{
+		App:             config.AppConfig{Env: "development", Address: ":8080", LogLevel: "debug"},
+		Database:        config.DatabaseConfig{URL: "postgres://localhost:5432/db"},
+		Cache:           config.CacheConfig{URL: "redis://localhost:6379/0"},
+		DistributedLock: config.DistributedLock{TTL: 5000, RetryInterval: 2000, WaitingTime: 4500},
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(cfg *config.Configuration)
+		wantErr bool
+	}{
+		{"must accept a valid configuration", func(cfg *config.Configuration) {}, false},
+		{"must accept an empty log level", func(cfg *config.Configuration) { cfg.App.LogLevel = "" }, false},
+		{"must reject an unknown log level", func(cfg *config.Configuration) { cfg.App.LogLevel = "verbose" }, true},
+		{"must reject a zero lock ttl", func(cfg *config.Configuration) { cfg.DistributedLock.TTL = 0 }, true},
+		{"must reject a negative retry interval", func(cfg *config.Configuration) { cfg.DistributedLock.RetryInterval = -1 }, true},
+		{"must reject a retry interval greater than the waiting time", func(cfg *config.Configuration) { cfg.DistributedLock.RetryInterval = 5000 }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfiguration()
+			tt.change(cfg)
+			err := ValidateConfig(cfg)
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, coreerr.ConfigValidationError), "error should be a ConfigValidationError")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDiffConfig(t *testing.T) {
+	oldCfg := validConfiguration()
+	newCfg := validConfiguration()
+	newCfg.App.LogLevel = "info"
+	newCfg.Database.URL = "postgres://other:5432/db"
+	newCfg.DistributedLock.TTL = 1000
+	changes := DiffConfig(oldCfg, newCfg)
+	assert.Equal(t, []ConfigChange{
+		{Key: "app.log_level", OldValue: "debug", NewValue: "info", Reloadable: true},
+		{Key: "database.url", OldValue: "postgres://localhost:5432/db", NewValue: "postgres://other:5432/db", Reloadable: false},
+		{Key: "distributed_lock.ttl_ms", OldValue: int64(5000), NewValue: int64(1000), Reloadable: true},
+	}, changes)
+	assert.Empty(t, DiffConfig(oldCfg, validConfiguration()), "equal configurations should have no changes")
+}
+
+func TestMergeReloadable(t *testing.T) {
+	current := validConfiguration()
+	candidate := validConfiguration()
+	candidate.App.LogLevel = "error"
+	candidate.App.Address = ":9090"
+	candidate.DistributedLock.WaitingTime = 9000
+	candidate.DistributedLock.Backend = config.LockBackendPostgres
+	candidate.PII.RedactedLogAttributes = []string{"document_number"}
+	candidate.PII.EncryptionKeyFile = "/run/secrets/pii_encryption_key"
+	merged := MergeReloadable(current, candidate)
+	assert.Equal(t, "error", merged.App.LogLevel, "log level should be reloaded")
+	assert.Equal(t, int64(9000), merged.DistributedLock.WaitingTime, "lock timings should be reloaded")
+	assert.Equal(t, ":8080", merged.App.Address, "address requires a restart")
+	assert.Empty(t, merged.DistributedLock.Backend, "the lock backend requires a restart")
+	assert.Equal(t, []string{"document_number"}, merged.PII.RedactedLogAttributes, "redacted log attributes should be reloaded")
+	assert.Empty(t, merged.PII.EncryptionKeyFile, "encryption keys require a restart")
+	assert.Equal(t, "debug", current.App.LogLevel, "current configuration must not be modified")
+}
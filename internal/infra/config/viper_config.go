@@ -9,17 +9,7 @@ import (
 )
 
 func LoadConfig(path string) (*config.Configuration, error) {
-	v := viper.New()
-	env := os.Getenv("ENV")
-	if env == "production" {
-		v.SetConfigName("config.production")
-	} else {
-		v.SetConfigName("config")
-	}
-	v.SetConfigType("yaml")
-	v.AddConfigPath(path)
-	v.AutomaticEnv()
-
+	v := newViper(path)
 	if err := v.ReadInConfig(); err != nil {
 		return nil, errors.ConfigFileNotFountError
 	}
@@ -38,3 +28,18 @@ func MustLoadConfig(path string) *config.Configuration {
 	}
 	return cfg
 }
+
+// newViper creates a viper instance pointing to the config file of the current environment
+func newViper(path string) *viper.Viper {
+	v := viper.New()
+	env := os.Getenv("ENV")
+	if env == "production" {
+		v.SetConfigName("config.production")
+	} else {
+		v.SetConfigName("config")
+	}
+	v.SetConfigType("yaml")
+	v.AddConfigPath(path)
+	v.AutomaticEnv()
+	return v
+}
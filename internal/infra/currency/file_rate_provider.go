@@ -0,0 +1,65 @@
+package currency
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/currency"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"os"
+)
+
+// ratesFile is the content of a rates file, e.g. {"base": "USD", "rates": {"BRL": 5.42, "EUR": 0.92}}
+type ratesFile struct {
+	Base  string             `json:"base"`
+	Rates map[string]float64 `json:"rates"` // Units of each currency worth one unit of Base
+}
+
+// FileRateProvider returns the exchange rates of a JSON file, converting between any two of its currencies through the base currency
+type FileRateProvider struct {
+	rates map[string]float64
+}
+
+// NewFileRateProvider loads the rates of a file. Without a file only conversions to the same currency are possible.
+func NewFileRateProvider(path string) (*FileRateProvider, error) {
+	provider := &FileRateProvider{rates: map[string]float64{}}
+	if path == "" {
+		return provider, nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ConfigValidationError, err)
+	}
+	var file ratesFile
+	if err = json.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("%w: invalid rates file: %v", errors.ConfigValidationError, err)
+	}
+	base, err := currency.Normalize(file.Base)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid rates file base: %v", errors.ConfigValidationError, err)
+	}
+	provider.rates[base] = 1
+	for code, rate := range file.Rates {
+		normalized, err := currency.Normalize(code)
+		if err != nil || rate <= 0 {
+			return nil, fmt.Errorf("%w: invalid rate for %q", errors.ConfigValidationError, code)
+		}
+		provider.rates[normalized] = rate
+	}
+	return provider, nil
+}
+
+func (f *FileRateProvider) Rate(ctx context.Context, from string, to string) (float64, error) {
+	if from == to {
+		return 1, nil
+	}
+	fromRate, ok := f.rates[from]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s to %s", errors.CurrencyRateNotFoundError, from, to)
+	}
+	toRate, ok := f.rates[to]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s to %s", errors.CurrencyRateNotFoundError, from, to)
+	}
+	return toRate / fromRate, nil
+}
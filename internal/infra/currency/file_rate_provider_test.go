@@ -0,0 +1,59 @@
+package currency
+
+import (
+	"context"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeRatesFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestFileRateProvider_Rate(t *testing.T) {
+	provider, err := NewFileRateProvider(writeRatesFile(t, `{"base": "usd", "rates": {"BRL": 5, "EUR": 0.8}}`))
+	require.NoError(t, err)
+	ctx := context.Background()
+	rate, err := provider.Rate(ctx, "USD", "BRL")
+	require.NoError(t, err)
+	assert.Equal(t, 5.0, rate)
+	rate, err = provider.Rate(ctx, "BRL", "USD")
+	require.NoError(t, err)
+	assert.Equal(t, 0.2, rate)
+	rate, err = provider.Rate(ctx, "EUR", "BRL")
+	require.NoError(t, err)
+	assert.Equal(t, 6.25, rate)
+	_, err = provider.Rate(ctx, "JPY", "BRL")
+	assert.ErrorIs(t, err, errors.CurrencyRateNotFoundError)
+}
+
+func TestFileRateProvider_WithoutFile(t *testing.T) {
+	provider, err := NewFileRateProvider("")
+	require.NoError(t, err)
+	rate, err := provider.Rate(context.Background(), "BRL", "BRL")
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, rate)
+	_, err = provider.Rate(context.Background(), "USD", "BRL")
+	assert.ErrorIs(t, err, errors.CurrencyRateNotFoundError)
+}
+
+func TestNewFileRateProvider_InvalidFile(t *testing.T) {
+	for name, content := range map[string]string{
+		"not json":      `base: USD`,
+		"invalid base":  `{"base": "dollar", "rates": {}}`,
+		"negative rate": `{"base": "USD", "rates": {"BRL": -1}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewFileRateProvider(writeRatesFile(t, content))
+			assert.ErrorIs(t, err, errors.ConfigValidationError)
+		})
+	}
+	_, err := NewFileRateProvider(filepath.Join(t.TempDir(), "missing.json"))
+	assert.ErrorIs(t, err, errors.ConfigValidationError)
+}
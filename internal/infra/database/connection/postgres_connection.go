@@ -28,6 +28,6 @@ func (p *PostgresConnection) Connect() (*adapter.DatabaseConnectionData, error)
 	if err != nil {
 		return nil, errors.DatabaseConnectionValidationFailedError
 	}
-	p.connectionData = &adapter.DatabaseConnectionData{db}
+	p.connectionData = &adapter.DatabaseConnectionData{Db: db}
 	return p.connectionData, nil
 }
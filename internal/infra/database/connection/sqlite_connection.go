@@ -0,0 +1,53 @@
+package connection
+
+import (
+	"database/sql"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/adapter"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/config"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	_ "modernc.org/sqlite"
+	"strings"
+)
+
+// sqlitePragmas enforce the foreign keys and wait for the locks of other processes sharing the database file
+const sqlitePragmas = "_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+
+type SQLiteConnection struct {
+	configuration  *config.Configuration
+	connectionData *adapter.DatabaseConnectionData
+}
+
+func NewSQLiteConnection(configuration *config.Configuration) *SQLiteConnection {
+	return &SQLiteConnection{
+		configuration: configuration,
+	}
+}
+
+// Connect opens the database file of database.url. SQLite has a single writer, so every caller shares one connection,
+// which serializes the database transactions instead of failing them as busy.
+func (s *SQLiteConnection) Connect() (*adapter.DatabaseConnectionData, error) {
+	db, err := OpenSQLite(s.configuration.Database.URL)
+	if err != nil {
+		return nil, errors.DatabaseConnectionFailedError
+	}
+	err = db.Ping()
+	if err != nil {
+		return nil, errors.DatabaseConnectionValidationFailedError
+	}
+	s.connectionData = &adapter.DatabaseConnectionData{Db: db}
+	return s.connectionData, nil
+}
+
+// OpenSQLite opens a SQLite database file, or an in memory database for ":memory:", limited to one connection
+func OpenSQLite(file string) (*sql.DB, error) {
+	separator := "?"
+	if strings.Contains(file, "?") {
+		separator = "&"
+	}
+	db, err := sql.Open("sqlite", "file:"+strings.TrimPrefix(file, "file:")+separator+sqlitePragmas)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+	return db, nil
+}
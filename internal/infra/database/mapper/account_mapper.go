@@ -11,7 +11,11 @@ func ToAccountModel(entity *account.Account) *model.AccountModel {
 	}
 	return &model.AccountModel{
 		AccountID:      entity.AccountID,
+		DocumentType:   string(entity.DocumentType),
 		DocumentNumber: entity.DocumentNumber,
+		Currency:       entity.Currency,
+		Status:         entity.Status,
+		Version:        entity.Version,
 	}
 }
 
@@ -21,6 +25,10 @@ func ToAccountEntity(model *model.AccountModel) *account.Account {
 	}
 	return &account.Account{
 		AccountID:      model.AccountID,
+		DocumentType:   account.DocumentType(model.DocumentType),
 		DocumentNumber: model.DocumentNumber,
+		Currency:       model.Currency,
+		Status:         model.Status,
+		Version:        model.Version,
 	}
 }
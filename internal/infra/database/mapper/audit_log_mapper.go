@@ -0,0 +1,62 @@
+package mapper
+
+import (
+	"database/sql"
+	"encoding/json"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/audit"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/database/model"
+)
+
+func ToAuditLogModel(entity *audit.Entry) *model.AuditLogModel {
+	if entity == nil {
+		return nil
+	}
+	return &model.AuditLogModel{
+		AuditID:      entity.AuditID,
+		Principal:    entity.Principal,
+		ClientIP:     entity.ClientIP,
+		TraceID:      entity.TraceID,
+		Action:       entity.Action,
+		EntityType:   entity.EntityType,
+		EntityID:     entity.EntityID,
+		Before:       snapshotToNullString(entity.Before),
+		After:        snapshotToNullString(entity.After),
+		CreatedAt:    entity.CreatedAt,
+		PreviousHash: entity.PreviousHash,
+		Hash:         entity.Hash,
+	}
+}
+
+func ToAuditEntry(model *model.AuditLogModel) *audit.Entry {
+	if model == nil {
+		return nil
+	}
+	return &audit.Entry{
+		AuditID:      model.AuditID,
+		Principal:    model.Principal,
+		ClientIP:     model.ClientIP,
+		TraceID:      model.TraceID,
+		Action:       model.Action,
+		EntityType:   model.EntityType,
+		EntityID:     model.EntityID,
+		Before:       snapshotFromNullString(model.Before),
+		After:        snapshotFromNullString(model.After),
+		CreatedAt:    model.CreatedAt,
+		PreviousHash: model.PreviousHash,
+		Hash:         model.Hash,
+	}
+}
+
+func snapshotToNullString(snapshot json.RawMessage) sql.NullString {
+	if len(snapshot) == 0 {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: string(snapshot), Valid: true}
+}
+
+func snapshotFromNullString(snapshot sql.NullString) json.RawMessage {
+	if !snapshot.Valid {
+		return nil
+	}
+	return json.RawMessage(snapshot.String)
+}
@@ -0,0 +1,68 @@
+package mapper
+
+import (
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/holder"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/database/model"
+	"strings"
+)
+
+func ToHolderModel(entity *holder.Holder) *model.HolderModel {
+	if entity == nil {
+		return nil
+	}
+	return &model.HolderModel{
+		AccountID:    entity.AccountID,
+		Name:         entity.Name,
+		Email:        entity.Email,
+		Phone:        entity.Phone,
+		BirthDate:    entity.BirthDate,
+		AddressLine1: entity.Address.Line1,
+		AddressLine2: entity.Address.Line2,
+		City:         entity.Address.City,
+		State:        entity.Address.State,
+		PostalCode:   entity.Address.PostalCode,
+		Country:      entity.Address.Country,
+		Version:      entity.Version,
+		UpdatedAt:    entity.UpdatedAt,
+	}
+}
+
+func ToHolderEntity(model *model.HolderModel) *holder.Holder {
+	if model == nil {
+		return nil
+	}
+	return &holder.Holder{
+		AccountID: model.AccountID,
+		Name:      model.Name,
+		Email:     model.Email,
+		Phone:     model.Phone,
+		BirthDate: model.BirthDate,
+		Address: holder.Address{
+			Line1:      model.AddressLine1,
+			Line2:      model.AddressLine2,
+			City:       model.City,
+			State:      model.State,
+			PostalCode: model.PostalCode,
+			Country:    model.Country,
+		},
+		Version:   model.Version,
+		UpdatedAt: model.UpdatedAt,
+	}
+}
+
+// ToHolderChange maps a history row, whose decoded snapshot is the holder after the change
+func ToHolderChange(model *model.HolderHistoryModel, snapshot holder.Holder) holder.Change {
+	changedFields := []string{}
+	if model.ChangedFields != "" {
+		changedFields = strings.Split(model.ChangedFields, ",")
+	}
+	return holder.Change{
+		AccountID:     model.AccountID,
+		Version:       model.Version,
+		ChangedFields: changedFields,
+		Principal:     model.Principal,
+		TraceID:       model.TraceID,
+		ChangedAt:     model.ChangedAt,
+		Holder:        snapshot,
+	}
+}
@@ -0,0 +1,21 @@
+package mapper
+
+import (
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/ledger"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/database/model"
+)
+
+func ToLedgerEntryModel(entity *ledger.Posting) *model.LedgerEntryModel {
+	if entity == nil {
+		return nil
+	}
+	return &model.LedgerEntryModel{
+		EntryID:       entity.EntryID,
+		TransactionID: entity.TransactionID,
+		LedgerAccount: entity.LedgerAccount,
+		Direction:     string(entity.Direction),
+		Amount:        entity.Amount,
+		Currency:      entity.Currency,
+		CreatedAt:     entity.CreatedAt,
+	}
+}
@@ -1,6 +1,8 @@
 package mapper
 
 import (
+	"database/sql"
+	"encoding/json"
 	"github.com/kiosanim/pismo-code-assessment/internal/domains/transaction"
 	"github.com/kiosanim/pismo-code-assessment/internal/infra/database/model"
 )
@@ -10,11 +12,25 @@ func ToTransactionModel(entity *transaction.Transaction) *model.TransactionModel
 		return nil
 	}
 	return &model.TransactionModel{
-		AccountID:       entity.AccountID,
-		TransactionID:   entity.TransactionID,
-		OperationTypeID: entity.OperationTypeID,
-		Amount:          entity.Amount,
-		EventDate:       entity.EventDate,
+		AccountID:        entity.AccountID,
+		TransactionID:    entity.TransactionID,
+		OperationTypeID:  entity.OperationTypeID,
+		Amount:           entity.Amount,
+		Currency:         entity.Currency,
+		OriginalAmount:   entity.OriginalAmount,
+		OriginalCurrency: entity.OriginalCurrency,
+		ExchangeRate:     entity.ExchangeRate,
+		MerchantName:     entity.Merchant.Name,
+		MerchantCategory: entity.Merchant.CategoryCode,
+		MerchantCountry:  entity.Merchant.Country,
+		Description:      entity.Description,
+		Metadata:         metadataToJSON(entity.Metadata),
+		EventDate:        entity.EventDate,
+		Status:           entity.Status,
+		EffectiveDate:    entity.EffectiveDate,
+		PostedAt:         sql.NullTime{Time: entity.PostedAt, Valid: !entity.PostedAt.IsZero()},
+		CreatedAt:        entity.CreatedAt,
+		CancelledAt:      sql.NullTime{Time: entity.CancelledAt, Valid: !entity.CancelledAt.IsZero()},
 	}
 }
 
@@ -23,10 +39,49 @@ func ToTransactionEntity(model *model.TransactionModel) *transaction.Transaction
 		return nil
 	}
 	return &transaction.Transaction{
-		AccountID:       model.AccountID,
-		TransactionID:   model.TransactionID,
-		OperationTypeID: model.OperationTypeID,
-		Amount:          model.Amount,
-		EventDate:       model.EventDate,
+		AccountID:        model.AccountID,
+		TransactionID:    model.TransactionID,
+		OperationTypeID:  model.OperationTypeID,
+		Amount:           model.Amount,
+		Currency:         model.Currency,
+		OriginalAmount:   model.OriginalAmount,
+		OriginalCurrency: model.OriginalCurrency,
+		ExchangeRate:     model.ExchangeRate,
+		Merchant: transaction.Merchant{
+			Name:         model.MerchantName,
+			CategoryCode: model.MerchantCategory,
+			Country:      model.MerchantCountry,
+		},
+		Description:   model.Description,
+		Metadata:      metadataFromJSON(model.Metadata),
+		EventDate:     model.EventDate,
+		Status:        model.Status,
+		EffectiveDate: model.EffectiveDate,
+		PostedAt:      model.PostedAt.Time,
+		CreatedAt:     model.CreatedAt,
+		CancelledAt:   model.CancelledAt.Time,
 	}
 }
+
+// metadataToJSON encodes the metadata as a JSON object, empty when there is no metadata
+func metadataToJSON(metadata map[string]string) string {
+	if len(metadata) == 0 {
+		return "{}"
+	}
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return "{}"
+	}
+	return string(data)
+}
+
+func metadataFromJSON(data string) map[string]string {
+	if data == "" {
+		return nil
+	}
+	var metadata map[string]string
+	if err := json.Unmarshal([]byte(data), &metadata); err != nil || len(metadata) == 0 {
+		return nil
+	}
+	return metadata
+}
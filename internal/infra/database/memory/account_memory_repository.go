@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/contextutils"
+	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/account"
+	"sync"
+)
+
+// AccountMemoryRepository keeps accounts in memory, identified by sequential IDs. Document numbers are unique like
+// in the database.
+type AccountMemoryRepository struct {
+	mu            sync.RWMutex
+	accounts      []account.Account // Account of ID i at index i-1
+	byDocument    map[string]int64  // Account ID by document number
+	componentName string
+	log           logger.Logger
+}
+
+func NewAccountMemoryRepository(log logger.Logger) *AccountMemoryRepository {
+	repository := &AccountMemoryRepository{
+		byDocument: make(map[string]int64),
+		log:        log,
+	}
+	repository.componentName = logger.ComponentNameFromStruct(repository)
+	return repository
+}
+
+func (a *AccountMemoryRepository) FindByID(ctx context.Context, accountID int64) (*account.Account, error) {
+	a.log.Debug(a.componentName+".FindByID", "accountID", accountID, "x_trace_id", contextutils.GetTraceID(ctx))
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if accountID <= 0 || accountID > int64(len(a.accounts)) {
+		return nil, coreerr.AccountNotFoundError
+	}
+	found := a.accounts[accountID-1]
+	return &found, nil
+}
+
+// FindByIDForShare reads the account like FindByID, the memory repositories are not transactional
+func (a *AccountMemoryRepository) FindByIDForShare(ctx context.Context, accountID int64) (*account.Account, error) {
+	return a.FindByID(ctx, accountID)
+}
+
+func (a *AccountMemoryRepository) FindByDocumentNumber(ctx context.Context, documentNumber string) (*account.Account, error) {
+	a.log.Debug(a.componentName+".FindByDocumentNumber", "x_trace_id", contextutils.GetTraceID(ctx))
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	accountID, ok := a.byDocument[documentNumber]
+	if !ok {
+		return nil, coreerr.AccountNotFoundError
+	}
+	found := a.accounts[accountID-1]
+	return &found, nil
+}
+
+func (a *AccountMemoryRepository) Save(ctx context.Context, newAccount *account.Account) (*account.Account, error) {
+	traceID := contextutils.GetTraceID(ctx)
+	a.log.Debug(a.componentName+".Save", "newAccount", newAccount, "x_trace_id", traceID)
+	if newAccount == nil {
+		return nil, coreerr.InvalidParametersError
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, exists := a.byDocument[newAccount.DocumentNumber]; exists {
+		a.log.Warn(a.componentName+".Save", "error", coreerr.AccountAlreadyExistsForDocumentNumberError, "x_trace_id", traceID)
+		return nil, coreerr.AccountAlreadyExistsForDocumentNumberError
+	}
+	saved := *newAccount
+	saved.AccountID = int64(len(a.accounts) + 1)
+	saved.Status = account.StatusActive
+	saved.Version = 1
+	a.accounts = append(a.accounts, saved)
+	a.byDocument[saved.DocumentNumber] = saved.AccountID
+	return &saved, nil
+}
+
+// List returns up to limit accounts with an ID greater than cursorID, ordered by ID
+func (a *AccountMemoryRepository) List(ctx context.Context, limit int64, cursorID int64) ([]account.Account, error) {
+	a.log.Debug(a.componentName+".List", "limit", limit, "cursorID", cursorID, "x_trace_id", contextutils.GetTraceID(ctx))
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	start := min(max(cursorID, 0), int64(len(a.accounts)))
+	end := min(start+max(limit, 0), int64(len(a.accounts)))
+	return append([]account.Account{}, a.accounts[start:end]...), nil
+}
+
+func (a *AccountMemoryRepository) Block(ctx context.Context, accountID int64, expectedVersion int64) (*account.Account, error) {
+	a.log.Debug(a.componentName+".Block", "accountID", accountID, "expectedVersion", expectedVersion, "x_trace_id", contextutils.GetTraceID(ctx))
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if accountID <= 0 || accountID > int64(len(a.accounts)) {
+		return nil, coreerr.AccountNotFoundError
+	}
+	found := &a.accounts[accountID-1]
+	if found.Version != expectedVersion {
+		return nil, coreerr.AccountVersionConflictError
+	}
+	if !found.Blocked() {
+		found.Status = account.StatusBlocked
+		found.Version++
+	}
+	blocked := *found
+	return &blocked, nil
+}
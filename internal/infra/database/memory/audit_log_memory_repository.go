@@ -0,0 +1,21 @@
+package memory
+
+import (
+	"context"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/audit"
+)
+
+// AuditLogMemoryRepository is the audit log of the memory repositories, which record nothing: it is always empty
+type AuditLogMemoryRepository struct{}
+
+func NewAuditLogMemoryRepository() *AuditLogMemoryRepository {
+	return &AuditLogMemoryRepository{}
+}
+
+func (a *AuditLogMemoryRepository) Search(ctx context.Context, filter audit.Filter) ([]*audit.Entry, error) {
+	return []*audit.Entry{}, nil
+}
+
+func (a *AuditLogMemoryRepository) StreamEntries(ctx context.Context, fn func(*audit.Entry) error) error {
+	return nil
+}
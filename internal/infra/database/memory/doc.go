@@ -0,0 +1,3 @@
+// Package memory implements the repositories in memory, for the in-process load test server and local experiments.
+// Data is lost when the process exits and nothing is recorded in the audit log.
+package memory
@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"context"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/contextutils"
+	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/holder"
+	"slices"
+	"sync"
+)
+
+// HolderMemoryRepository keeps the account holders and their history in memory
+type HolderMemoryRepository struct {
+	mu            sync.RWMutex
+	holders       map[int64]holder.Holder   // Holder by account ID
+	history       map[int64][]holder.Change // Changes by account ID, oldest first
+	componentName string
+	log           logger.Logger
+}
+
+func NewHolderMemoryRepository(log logger.Logger) *HolderMemoryRepository {
+	repository := &HolderMemoryRepository{
+		holders: make(map[int64]holder.Holder),
+		history: make(map[int64][]holder.Change),
+		log:     log,
+	}
+	repository.componentName = logger.ComponentNameFromStruct(repository)
+	return repository
+}
+
+func (h *HolderMemoryRepository) FindByAccountID(ctx context.Context, accountID int64) (*holder.Holder, error) {
+	h.log.Debug(h.componentName+".FindByAccountID", "accountID", accountID, "x_trace_id", contextutils.GetTraceID(ctx))
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	found, ok := h.holders[accountID]
+	if !ok {
+		return nil, coreerr.AccountHolderNotFoundError
+	}
+	return &found, nil
+}
+
+func (h *HolderMemoryRepository) Save(ctx context.Context, changed *holder.Holder, expectedVersion int64, changedFields []string) (*holder.Holder, error) {
+	traceID := contextutils.GetTraceID(ctx)
+	h.log.Debug(h.componentName+".Save", "holder", changed, "expectedVersion", expectedVersion, "x_trace_id", traceID)
+	if changed == nil {
+		return nil, coreerr.InvalidParametersError
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.holders[changed.AccountID].Version != expectedVersion {
+		h.log.Warn(h.componentName+".Save", "error", coreerr.AccountHolderVersionMismatchError, "x_trace_id", traceID)
+		return nil, coreerr.AccountHolderVersionMismatchError
+	}
+	saved := *changed
+	saved.Version = expectedVersion + 1
+	saved.UpdatedAt = changed.UpdatedAt.UTC()
+	h.holders[saved.AccountID] = saved
+	h.history[saved.AccountID] = append(h.history[saved.AccountID], holder.Change{
+		AccountID:     saved.AccountID,
+		Version:       saved.Version,
+		ChangedFields: slices.Clone(changedFields),
+		Principal:     contextutils.GetPrincipalSubject(ctx),
+		TraceID:       traceID,
+		ChangedAt:     saved.UpdatedAt,
+		Holder:        saved,
+	})
+	return &saved, nil
+}
+
+func (h *HolderMemoryRepository) History(ctx context.Context, accountID int64) ([]holder.Change, error) {
+	h.log.Debug(h.componentName+".History", "accountID", accountID, "x_trace_id", contextutils.GetTraceID(ctx))
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	changes := slices.Clone(h.history[accountID])
+	slices.Reverse(changes)
+	if changes == nil {
+		changes = []holder.Change{}
+	}
+	return changes, nil
+}
@@ -0,0 +1,17 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+)
+
+// MemoryUnitOfWork runs the function given to Do as it is, the memory repositories are not transactional
+type MemoryUnitOfWork struct{}
+
+func NewMemoryUnitOfWork() *MemoryUnitOfWork {
+	return &MemoryUnitOfWork{}
+}
+
+func (m *MemoryUnitOfWork) Do(ctx context.Context, isolation sql.IsolationLevel, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
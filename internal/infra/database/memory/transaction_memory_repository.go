@@ -0,0 +1,236 @@
+package memory
+
+import (
+	"context"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/contextutils"
+	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/ledger"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/transaction"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// operationTypes are the operation types inserted by the 02_insert_operation_type.sql migration
+var operationTypes = map[int]string{
+	transaction.Purchase:            "PURCHASE",
+	transaction.InstallmentPurchase: "INSTALLMENT PURCHASE",
+	transaction.Withdrawal:          "WITHDRAWAL",
+	transaction.Payment:             "PAYMENT",
+}
+
+// TransactionMemoryRepository keeps transactions and their ledger postings in memory, identified by sequential IDs
+type TransactionMemoryRepository struct {
+	mu            sync.RWMutex
+	transactions  []*transaction.Transaction // Transaction of ID i at index i-1
+	nextEntryID   int64
+	componentName string
+	log           logger.Logger
+}
+
+func NewTransactionMemoryRepository(log logger.Logger) *TransactionMemoryRepository {
+	repository := &TransactionMemoryRepository{log: log}
+	repository.componentName = logger.ComponentNameFromStruct(repository)
+	return repository
+}
+
+func (t *TransactionMemoryRepository) FindOperationTypeByID(ctx context.Context, operationTypeID int) (*transaction.OperationType, error) {
+	t.log.Debug(t.componentName+".FindOperationTypeByID", "operationTypeID", operationTypeID, "x_trace_id", contextutils.GetTraceID(ctx))
+	description, ok := operationTypes[operationTypeID]
+	if !ok {
+		return nil, coreerr.OperationTypeNotFoundError
+	}
+	return &transaction.OperationType{OperationTypeID: int64(operationTypeID), Description: description}, nil
+}
+
+func (t *TransactionMemoryRepository) FindTransactionByID(ctx context.Context, transactionID int64) (*transaction.Transaction, error) {
+	t.log.Debug(t.componentName+".FindTransactionByID", "transactionID", transactionID, "x_trace_id", contextutils.GetTraceID(ctx))
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	found, err := t.find(transactionID)
+	if err != nil {
+		return nil, err
+	}
+	return clone(found), nil
+}
+
+func (t *TransactionMemoryRepository) Save(ctx context.Context, newTransaction *transaction.Transaction) (*transaction.Transaction, error) {
+	saved, err := t.SaveBatch(ctx, []*transaction.Transaction{newTransaction})
+	if err != nil {
+		return nil, err
+	}
+	return saved[0], nil
+}
+
+// SaveBatch stores every transaction, or none when one of them is invalid, returning them in the order they were given
+func (t *TransactionMemoryRepository) SaveBatch(ctx context.Context, newTransactions []*transaction.Transaction) ([]*transaction.Transaction, error) {
+	traceID := contextutils.GetTraceID(ctx)
+	t.log.Debug(t.componentName+".SaveBatch", "rows", len(newTransactions), "x_trace_id", traceID)
+	for _, newTransaction := range newTransactions {
+		if newTransaction == nil {
+			t.log.Warn(t.componentName+".SaveBatch", "error", coreerr.InvalidParametersError, "x_trace_id", traceID)
+			return nil, coreerr.InvalidParametersError
+		}
+		if newTransaction.Status != transaction.StatusPending && !ledger.Balanced(newTransaction.Postings) {
+			t.log.Warn(t.componentName+".SaveBatch", "error", coreerr.LedgerUnbalancedError, "x_trace_id", traceID)
+			return nil, coreerr.LedgerUnbalancedError
+		}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	saved := make([]*transaction.Transaction, 0, len(newTransactions))
+	for _, newTransaction := range newTransactions {
+		stored := clone(newTransaction)
+		stored.TransactionID = int64(len(t.transactions) + 1)
+		if stored.Status == transaction.StatusPending {
+			stored.Postings = nil
+		} else {
+			t.post(stored, stored.Postings)
+		}
+		t.transactions = append(t.transactions, stored)
+		saved = append(saved, clone(stored))
+	}
+	return saved, nil
+}
+
+// StreamTransactions calls fn with every posted transaction selected by filter, ordered by account and transaction id
+func (t *TransactionMemoryRepository) StreamTransactions(ctx context.Context, filter transaction.TransactionFilter, fn func(*transaction.Transaction) error) error {
+	t.log.Debug(t.componentName+".StreamTransactions", "filter", filter, "x_trace_id", contextutils.GetTraceID(ctx))
+	selected := t.selectTransactions(func(candidate *transaction.Transaction) bool {
+		return candidate.Status == transaction.StatusPosted && matchesFilter(candidate, filter)
+	})
+	sort.SliceStable(selected, func(i, j int) bool { return selected[i].AccountID < selected[j].AccountID })
+	for _, streamed := range selected {
+		if err := fn(streamed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Search returns up to search.Limit transactions matching every informed criteria, ordered by transaction id
+// and starting after search.Cursor
+func (t *TransactionMemoryRepository) Search(ctx context.Context, search transaction.TransactionSearch) ([]*transaction.Transaction, error) {
+	t.log.Debug(t.componentName+".Search", "search", search, "x_trace_id", contextutils.GetTraceID(ctx))
+	selected := t.selectTransactions(func(candidate *transaction.Transaction) bool {
+		return candidate.TransactionID > search.Cursor && matchesFilter(candidate, search.TransactionFilter) && matchesSearch(candidate, search)
+	})
+	return selected[:min(int64(len(selected)), max(search.Limit, 0))], nil
+}
+
+// FindDue returns up to limit pending transactions effective until the given date, the oldest first
+func (t *TransactionMemoryRepository) FindDue(ctx context.Context, until time.Time, limit int64) ([]*transaction.Transaction, error) {
+	t.log.Debug(t.componentName+".FindDue", "until", until, "limit", limit, "x_trace_id", contextutils.GetTraceID(ctx))
+	due := t.selectTransactions(func(candidate *transaction.Transaction) bool {
+		return candidate.Status == transaction.StatusPending && !candidate.EffectiveDate.After(until)
+	})
+	sort.SliceStable(due, func(i, j int) bool { return due[i].EffectiveDate.Before(due[j].EffectiveDate) })
+	return due[:min(int64(len(due)), max(limit, 0))], nil
+}
+
+// Post marks a pending transaction as posted at dueTransaction.PostedAt with its postings, which must be balanced
+func (t *TransactionMemoryRepository) Post(ctx context.Context, dueTransaction *transaction.Transaction) (*transaction.Transaction, error) {
+	t.log.Debug(t.componentName+".Post", "transactionID", dueTransaction.TransactionID, "x_trace_id", contextutils.GetTraceID(ctx))
+	if !ledger.Balanced(dueTransaction.Postings) {
+		return nil, coreerr.LedgerUnbalancedError
+	}
+	return t.leavePending(dueTransaction.TransactionID, func(pending *transaction.Transaction) {
+		pending.Status = transaction.StatusPosted
+		pending.PostedAt = dueTransaction.PostedAt
+		t.post(pending, dueTransaction.Postings)
+	})
+}
+
+// Cancel marks a pending transaction as cancelled at cancelledAt
+func (t *TransactionMemoryRepository) Cancel(ctx context.Context, transactionID int64, cancelledAt time.Time) (*transaction.Transaction, error) {
+	t.log.Debug(t.componentName+".Cancel", "transactionID", transactionID, "x_trace_id", contextutils.GetTraceID(ctx))
+	return t.leavePending(transactionID, func(pending *transaction.Transaction) {
+		pending.Status = transaction.StatusCancelled
+		pending.CancelledAt = cancelledAt
+	})
+}
+
+func (t *TransactionMemoryRepository) leavePending(transactionID int64, apply func(pending *transaction.Transaction)) (*transaction.Transaction, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	pending, err := t.find(transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if pending.Status != transaction.StatusPending {
+		return nil, coreerr.TransactionNotPendingError
+	}
+	apply(pending)
+	return clone(pending), nil
+}
+
+// post sets the postings of a stored transaction, numbering its entries. Callers must hold the write lock.
+func (t *TransactionMemoryRepository) post(stored *transaction.Transaction, postings []ledger.Posting) {
+	stored.Postings = make([]ledger.Posting, 0, len(postings))
+	for _, posting := range postings {
+		t.nextEntryID++
+		posting.EntryID = t.nextEntryID
+		posting.TransactionID = stored.TransactionID
+		posting.CreatedAt = stored.PostedAt
+		stored.Postings = append(stored.Postings, posting)
+	}
+}
+
+// find returns the stored transaction. Callers must hold the lock.
+func (t *TransactionMemoryRepository) find(transactionID int64) (*transaction.Transaction, error) {
+	if transactionID <= 0 || transactionID > int64(len(t.transactions)) {
+		return nil, coreerr.TransactionNotFoundError
+	}
+	return t.transactions[transactionID-1], nil
+}
+
+// selectTransactions returns copies of the transactions matching the predicate, ordered by transaction id
+func (t *TransactionMemoryRepository) selectTransactions(matches func(candidate *transaction.Transaction) bool) []*transaction.Transaction {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	selected := make([]*transaction.Transaction, 0)
+	for _, candidate := range t.transactions {
+		if matches(candidate) {
+			selected = append(selected, clone(candidate))
+		}
+	}
+	return selected
+}
+
+func matchesFilter(candidate *transaction.Transaction, filter transaction.TransactionFilter) bool {
+	return (filter.AccountID <= 0 || candidate.AccountID == filter.AccountID) &&
+		(filter.From.IsZero() || !candidate.EventDate.Before(filter.From)) &&
+		(filter.To.IsZero() || candidate.EventDate.Before(filter.To))
+}
+
+func matchesSearch(candidate *transaction.Transaction, search transaction.TransactionSearch) bool {
+	if (search.Status != "" && candidate.Status != search.Status) ||
+		(search.MerchantName != "" && !containsFold(candidate.Merchant.Name, search.MerchantName)) ||
+		(search.MerchantCategoryCode != "" && candidate.Merchant.CategoryCode != search.MerchantCategoryCode) ||
+		(search.MerchantCountry != "" && candidate.Merchant.Country != search.MerchantCountry) ||
+		(search.Description != "" && !containsFold(candidate.Description, search.Description)) {
+		return false
+	}
+	for key, value := range search.Metadata {
+		if stored, ok := candidate.Metadata[key]; !ok || stored != value {
+			return false
+		}
+	}
+	return true
+}
+
+func containsFold(value, substring string) bool {
+	return strings.Contains(strings.ToLower(value), strings.ToLower(substring))
+}
+
+// clone copies a transaction so callers never share the metadata and postings of the stored one
+func clone(stored *transaction.Transaction) *transaction.Transaction {
+	copied := *stored
+	copied.Metadata = maps.Clone(stored.Metadata)
+	copied.Postings = slices.Clone(stored.Postings)
+	return &copied
+}
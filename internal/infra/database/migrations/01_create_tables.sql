@@ -38,5 +38,6 @@ alter table transactions
 
 
 -- +goose down
-drop table if exists accounts;
 drop table if exists transactions;
+drop table if exists operation_types;
+drop table if exists accounts;
@@ -10,4 +10,4 @@ insert into operation_types(operation_type_id, description) values(4, 'PAYMENT')
 delete from operation_types where operation_type_id = 1;
 delete from operation_types where operation_type_id = 2;
 delete from operation_types where operation_type_id = 3;
-delete from operation_types where operation_type_id = 4;
\ No newline at end of file
+delete from operation_types where operation_type_id = 4;
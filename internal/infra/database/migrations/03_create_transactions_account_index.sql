@@ -0,0 +1,7 @@
+-- +goose up
+
+create index if not exists transactions_account_id_transaction_id_idx on transactions (account_id, transaction_id);
+
+-- +goose down
+
+drop index if exists transactions_account_id_transaction_id_idx;
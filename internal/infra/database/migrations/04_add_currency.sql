@@ -0,0 +1,26 @@
+-- +goose up
+
+alter table accounts
+    add column if not exists currency varchar(3) not null default 'BRL';
+
+alter table transactions
+    add column if not exists currency          varchar(3)       not null default 'BRL',
+    add column if not exists original_amount   double precision,
+    add column if not exists original_currency varchar(3)       not null default 'BRL',
+    add column if not exists exchange_rate     double precision not null default 1;
+
+update transactions set original_amount = abs(amount) where original_amount is null;
+
+alter table transactions
+    alter column original_amount set not null;
+
+-- +goose down
+
+alter table transactions
+    drop column if exists exchange_rate,
+    drop column if exists original_currency,
+    drop column if exists original_amount,
+    drop column if exists currency;
+
+alter table accounts
+    drop column if exists currency;
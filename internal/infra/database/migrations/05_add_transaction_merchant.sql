@@ -0,0 +1,23 @@
+-- +goose up
+
+alter table transactions
+    add column if not exists merchant_name          varchar(120) not null default '',
+    add column if not exists merchant_category_code varchar(4)   not null default '',
+    add column if not exists merchant_country       varchar(2)   not null default '',
+    add column if not exists description            varchar(255) not null default '',
+    add column if not exists metadata               jsonb        not null default '{}';
+
+create index if not exists transactions_merchant_category_code_idx on transactions (merchant_category_code);
+create index if not exists transactions_metadata_idx on transactions using gin (metadata jsonb_path_ops);
+
+-- +goose down
+
+drop index if exists transactions_metadata_idx;
+drop index if exists transactions_merchant_category_code_idx;
+
+alter table transactions
+    drop column if exists metadata,
+    drop column if exists description,
+    drop column if exists merchant_country,
+    drop column if exists merchant_category_code,
+    drop column if exists merchant_name;
@@ -0,0 +1,84 @@
+-- +goose up
+
+-- Every account whose document number is formatted or duplicated once normalized is recorded in the report.
+-- Duplicates are merged into the oldest account of their normalized number, which keeps their transactions.
+create table if not exists account_document_migration_report
+(
+    account_id               bigint primary key,
+    kept_account_id          bigint                   not null,
+    original_document_number varchar                  not null,
+    document_number          varchar                  not null,
+    currency                 varchar(3)               not null,
+    moved_transaction_ids    bigint[]                 not null default '{}',
+    migrated_at              timestamp with time zone not null default now()
+);
+
+alter table account_document_migration_report
+    owner to pismo;
+
+insert into account_document_migration_report (account_id, kept_account_id, original_document_number, document_number, currency)
+select account_id, kept_account_id, document_number, normalized, currency
+from (select account_id,
+             document_number,
+             currency,
+             regexp_replace(document_number, '\D', '', 'g')                                       as normalized,
+             min(account_id) over (partition by regexp_replace(document_number, '\D', '', 'g')) as kept_account_id,
+             count(*) over (partition by regexp_replace(document_number, '\D', '', 'g'))        as duplicates
+      from accounts) normalized_accounts
+where document_number <> normalized
+   or duplicates > 1;
+
+update account_document_migration_report r
+set moved_transaction_ids = (select coalesce(array_agg(t.transaction_id order by t.transaction_id), '{}')
+                             from transactions t
+                             where t.account_id = r.account_id)
+where r.account_id <> r.kept_account_id;
+
+update transactions t
+set account_id = r.kept_account_id
+from account_document_migration_report r
+where t.account_id = r.account_id
+  and r.account_id <> r.kept_account_id;
+
+delete
+from accounts a
+    using account_document_migration_report r
+where a.account_id = r.account_id
+  and r.account_id <> r.kept_account_id;
+
+update accounts a
+set document_number = r.document_number
+from account_document_migration_report r
+where a.account_id = r.account_id;
+
+alter table accounts
+    add column if not exists document_type varchar(20);
+
+update accounts
+set document_type = case when length(document_number) = 14 then 'CNPJ' else 'CPF' end
+where document_type is null;
+
+alter table accounts
+    alter column document_type set not null;
+
+-- +goose down
+
+alter table accounts
+    drop column if exists document_type;
+
+update accounts a
+set document_number = r.original_document_number
+from account_document_migration_report r
+where a.account_id = r.account_id;
+
+insert into accounts (account_id, document_number, currency)
+select account_id, original_document_number, currency
+from account_document_migration_report
+where account_id <> kept_account_id;
+
+update transactions t
+set account_id = r.account_id
+from account_document_migration_report r
+where t.transaction_id = any (r.moved_transaction_ids);
+
+drop table if exists account_document_migration_report;
@@ -0,0 +1,23 @@
+-- +goose up
+
+-- Document numbers are stored encrypted, so uniqueness and lookups move to their blind index.
+-- Existing rows keep their plaintext number until `go run cmd/migrate/main.go protect-documents` is run.
+alter table accounts
+    add column if not exists document_number_index varchar(64);
+
+create unique index if not exists accounts_document_number_index_key
+    on accounts (document_number_index);
+
+alter table accounts
+    drop constraint if exists accounts_document_number_key;
+
+-- +goose down
+
+-- Encrypted document numbers can not be decrypted by the database, restore them to plaintext before rolling back.
+alter table accounts
+    add constraint accounts_document_number_key unique (document_number);
+
+drop index if exists accounts_document_number_index_key;
+
+alter table accounts
+    drop column if exists document_number_index;
@@ -0,0 +1,50 @@
+-- +goose up
+
+-- Snapshots are json, not jsonb, so the hashed text is kept byte for byte.
+create table if not exists audit_log
+(
+    audit_id        bigserial primary key,
+    principal       varchar                  not null default '',
+    client_ip       varchar(45)              not null default '',
+    trace_id        varchar                  not null default '',
+    action          varchar(64)              not null,
+    entity_type     varchar(32)              not null,
+    entity_id       bigint                   not null,
+    before_snapshot json,
+    after_snapshot  json,
+    created_at      timestamp with time zone not null,
+    previous_hash   varchar(64)              not null,
+    hash            varchar(64)              not null unique
+);
+
+alter table audit_log
+    owner to pismo;
+
+create index if not exists audit_log_entity_idx on audit_log (entity_type, entity_id, audit_id);
+create index if not exists audit_log_created_at_idx on audit_log (created_at);
+
+-- +goose StatementBegin
+create or replace function audit_log_append_only() returns trigger as
+$$
+begin
+    raise exception 'audit_log is append-only';
+end;
+$$ language plpgsql;
+-- +goose StatementEnd
+
+create trigger audit_log_append_only_rows
+    before update or delete
+    on audit_log
+    for each row
+execute function audit_log_append_only();
+
+create trigger audit_log_append_only_truncate
+    before truncate
+    on audit_log
+    for each statement
+execute function audit_log_append_only();
+
+-- +goose down
+
+drop table if exists audit_log;
+drop function if exists audit_log_append_only();
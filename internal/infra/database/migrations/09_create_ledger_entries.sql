@@ -0,0 +1,44 @@
+-- +goose up
+
+-- Every transaction posts balanced entries: debits increase and credits decrease the balance of a ledger account.
+create table if not exists ledger_entries
+(
+    entry_id       bigserial primary key,
+    transaction_id bigint                   not null references transactions (transaction_id),
+    ledger_account varchar(64)              not null,
+    direction      varchar(6)               not null check (direction in ('DEBIT', 'CREDIT')),
+    amount         numeric(18, 2)           not null check (amount > 0),
+    currency       varchar(3)               not null,
+    created_at     timestamp with time zone not null default now()
+);
+
+alter table ledger_entries
+    owner to pismo;
+
+create index if not exists ledger_entries_transaction_id_idx on ledger_entries (transaction_id);
+create index if not exists ledger_entries_ledger_account_idx on ledger_entries (ledger_account, currency);
+
+-- Existing transactions are posted without fees: debts debit the customer and credit settlement, payments the opposite.
+insert into ledger_entries (transaction_id, ledger_account, direction, amount, currency, created_at)
+select transaction_id, ledger_account, direction, amount, currency, event_date
+from (select transaction_id,
+             'customer:' || account_id                         as ledger_account,
+             case when amount < 0 then 'DEBIT' else 'CREDIT' end as direction,
+             round(abs(amount)::numeric, 2)                      as amount,
+             currency,
+             event_date
+      from transactions
+      union all
+      select transaction_id,
+             'settlement',
+             case when amount < 0 then 'CREDIT' else 'DEBIT' end,
+             round(abs(amount)::numeric, 2),
+             currency,
+             event_date
+      from transactions) postings
+where amount > 0
+order by transaction_id, direction desc;
+
+-- +goose down
+
+drop table if exists ledger_entries;
@@ -0,0 +1,30 @@
+-- +goose up
+
+-- Transactions with a future effective date are PENDING until the scheduler posts them, or until they are cancelled.
+alter table transactions
+    add column if not exists status         varchar(9)               not null default 'POSTED'
+        check (status in ('PENDING', 'POSTED', 'CANCELLED')),
+    add column if not exists effective_date timestamp with time zone,
+    add column if not exists posted_at      timestamp with time zone,
+    add column if not exists cancelled_at   timestamp with time zone;
+
+update transactions
+set effective_date = event_date,
+    posted_at      = event_date
+where effective_date is null;
+
+alter table transactions
+    alter column effective_date set not null;
+
+create index if not exists transactions_pending_effective_date_idx on transactions (effective_date, transaction_id)
+    where status = 'PENDING';
+
+-- +goose down
+
+drop index if exists transactions_pending_effective_date_idx;
+
+alter table transactions
+    drop column if exists cancelled_at,
+    drop column if exists posted_at,
+    drop column if exists effective_date,
+    drop column if exists status;
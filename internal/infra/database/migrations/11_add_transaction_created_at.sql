@@ -0,0 +1,18 @@
+-- +goose up
+
+-- event_date may be backdated by the client, so the time a transaction was recorded is kept apart.
+alter table transactions
+    add column if not exists created_at timestamp with time zone;
+
+update transactions
+set created_at = event_date
+where created_at is null;
+
+alter table transactions
+    alter column created_at set default now(),
+    alter column created_at set not null;
+
+-- +goose down
+
+alter table transactions
+    drop column if exists created_at;
@@ -0,0 +1,11 @@
+-- +goose up
+
+-- BLOCKED accounts reject new transactions.
+alter table accounts
+    add column if not exists status varchar(7) not null default 'ACTIVE'
+        check (status in ('ACTIVE', 'BLOCKED'));
+
+-- +goose down
+
+alter table accounts
+    drop column if exists status;
@@ -0,0 +1,11 @@
+-- +goose up
+
+-- Incremented on every change of an account, updates are only applied to the version they were based on.
+alter table accounts
+    add column if not exists version bigint not null default 1
+        check (version > 0);
+
+-- +goose down
+
+alter table accounts
+    drop column if exists version;
@@ -0,0 +1,19 @@
+-- +goose up
+
+-- The hash of the last entry of the audit log. Writers lock its single row until they commit, so entries are chained
+-- one after the other, and a writer whose snapshot missed a moved head fails instead of forking the chain.
+create table if not exists audit_log_head
+(
+    singleton boolean primary key default true check (singleton),
+    hash      varchar(64) not null
+);
+
+alter table audit_log_head
+    owner to pismo;
+
+insert into audit_log_head (hash)
+select coalesce((select hash from audit_log order by audit_id desc limit 1), repeat('0', 64));
+
+-- +goose down
+
+drop table if exists audit_log_head;
@@ -0,0 +1,195 @@
+// Package migrations embeds the goose SQL migrations of the database, so the migration tool does not depend on
+// the directory it runs from.
+package migrations
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"fmt"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/config"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/pressly/goose/v3"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// FS holds the migration files of Postgres
+//
+//go:embed *.sql
+var FS embed.FS
+
+//go:embed sqlite/*.sql
+var sqliteFiles embed.FS
+
+// SQLiteFS holds the migration files of SQLite, kept in the sqlite directory
+var SQLiteFS = mustSub(sqliteFiles, "sqlite")
+
+// Dir is the directory of the migrations in FS and SQLiteFS
+const Dir = "."
+
+// SourceDir is the directory new migrations are created in, relative to the repository root
+const SourceDir = "internal/infra/database/migrations"
+
+// SQLiteSourceDir is the directory new SQLite migrations are created in, relative to the repository root
+const SQLiteSourceDir = SourceDir + "/sqlite"
+
+// Source is the migrations of a database driver
+type Source struct {
+	FS        fs.FS
+	Dialect   goose.Dialect
+	SourceDir string
+}
+
+// ForDriver returns the migrations of database.driver, Postgres by default
+func ForDriver(driver string) (Source, error) {
+	switch driver {
+	case "", config.DatabaseDriverPostgres:
+		return Source{FS: FS, Dialect: goose.DialectPostgres, SourceDir: SourceDir}, nil
+	case config.DatabaseDriverSQLite:
+		return Source{FS: SQLiteFS, Dialect: goose.DialectSQLite3, SourceDir: SQLiteSourceDir}, nil
+	default:
+		return Source{}, fmt.Errorf("%w: unknown database.driver %q", errors.ConfigValidationError, driver)
+	}
+}
+
+func mustSub(fsys fs.FS, dir string) fs.FS {
+	sub, err := fs.Sub(fsys, dir)
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
+
+const (
+	DirectionUp   = "up"
+	DirectionDown = "down"
+)
+
+// Step is a migration applied, or rolled back, by a command
+type Step struct {
+	Version   int64
+	Source    string // File name of the migration
+	Direction string // DirectionUp or DirectionDown
+}
+
+var invalidNameCharacters = regexp.MustCompile(`[^a-z0-9]+`)
+
+// List returns the migration files of fsys ordered by version
+func List(fsys fs.FS) ([]Step, error) {
+	files, err := fs.Glob(fsys, path.Join(Dir, "*.sql"))
+	if err != nil {
+		return nil, err
+	}
+	migrations := make([]Step, 0, len(files))
+	for _, file := range files {
+		version, err := goose.NumericComponent(file)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration file name %q: %w", file, err)
+		}
+		migrations = append(migrations, Step{Version: version, Source: file})
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Plan returns the steps that move a database from the current version to the target one: the migrations after
+// current up to target, oldest first, or the migrations down to target (exclusive), newest first
+func Plan(fsys fs.FS, current, target int64) ([]Step, error) {
+	migrations, err := List(fsys)
+	if err != nil {
+		return nil, err
+	}
+	var steps []Step
+	for _, migration := range migrations {
+		switch {
+		case target > current && migration.Version > current && migration.Version <= target:
+			migration.Direction = DirectionUp
+			steps = append(steps, migration)
+		case target < current && migration.Version <= current && migration.Version > target:
+			migration.Direction = DirectionDown
+			steps = append([]Step{migration}, steps...)
+		}
+	}
+	return steps, nil
+}
+
+// Previous returns the version before current, zero when current is the first migration
+func Previous(fsys fs.FS, current int64) (int64, error) {
+	migrations, err := List(fsys)
+	if err != nil {
+		return 0, err
+	}
+	previous := int64(0)
+	for _, migration := range migrations {
+		if migration.Version >= current {
+			break
+		}
+		previous = migration.Version
+	}
+	return previous, nil
+}
+
+// Last returns the version of the newest migration, zero when there is none
+func Last(fsys fs.FS) (int64, error) {
+	migrations, err := List(fsys)
+	if err != nil || len(migrations) == 0 {
+		return 0, err
+	}
+	return migrations[len(migrations)-1].Version, nil
+}
+
+// SQL returns the statements of a step, the section of its file under the -- +goose up or -- +goose down annotation
+func SQL(fsys fs.FS, step Step) (string, error) {
+	content, err := fs.ReadFile(fsys, step.Source)
+	if err != nil {
+		return "", err
+	}
+	var section strings.Builder
+	current := ""
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch strings.ToLower(strings.Join(strings.Fields(line), " ")) {
+		case "-- +goose up":
+			current = DirectionUp
+			continue
+		case "-- +goose down":
+			current = DirectionDown
+			continue
+		}
+		if current == step.Direction {
+			section.WriteString(line)
+			section.WriteByte('\n')
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(section.String()), nil
+}
+
+// FileName returns the file name of a new migration named name, numbered after the migrations of fsys,
+// e.g. 12_add_account_holders.sql
+func FileName(fsys fs.FS, name string) (string, error) {
+	slug := strings.Trim(invalidNameCharacters.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if slug == "" {
+		return "", fmt.Errorf("invalid migration name %q", name)
+	}
+	last, err := Last(fsys)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%02d_%s.sql", last+1, slug), nil
+}
+
+// Template is the content of a new migration
+const Template = `-- +goose up
+
+
+-- +goose down
+
+`
@@ -0,0 +1,140 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/config"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/fs"
+	_ "modernc.org/sqlite"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+var testFS = fstest.MapFS{
+	"01_create_tables.sql":  {Data: []byte("-- +goose up\ncreate table a (id int);\n\n-- +goose down\ndrop table a;\n")},
+	"02_add_column.sql":     {Data: []byte("-- +goose Up\n-- +goose StatementBegin\nalter table a add b int;\n-- +goose StatementEnd\n-- +goose Down\nalter table a drop b;\n")},
+	"10_create_index.sql":   {Data: []byte("-- +goose up\ncreate index a_b on a (b);\n-- +goose down\ndrop index a_b;\n")},
+	"migrations_readme.txt": {Data: []byte("not a migration")},
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	for name, fsys := range map[string]fs.FS{"postgres": FS, "sqlite": SQLiteFS} {
+		embedded, err := List(fsys)
+		require.NoError(t, err)
+		require.NotEmpty(t, embedded, name)
+		for i, migration := range embedded {
+			assert.Equal(t, int64(i+1), migration.Version, "%s migrations should be numbered without gaps", name)
+			for _, direction := range []string{DirectionUp, DirectionDown} {
+				statements, err := SQL(fsys, Step{Version: migration.Version, Source: migration.Source, Direction: direction})
+				require.NoError(t, err)
+				assert.NotEmpty(t, statements, "%s should have a %s section", migration.Source, direction)
+			}
+		}
+	}
+}
+
+func TestSQLiteMigrationsUpAndDown(t *testing.T) {
+	ctx := context.Background()
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = db.Close() })
+	provider, err := goose.NewProvider(goose.DialectSQLite3, db, SQLiteFS)
+	require.NoError(t, err)
+	_, err = provider.Up(ctx)
+	require.NoError(t, err)
+	var operationTypes int
+	require.NoError(t, db.QueryRowContext(ctx, "SELECT count(*) FROM operation_types").Scan(&operationTypes))
+	assert.Equal(t, 4, operationTypes)
+	_, err = provider.DownTo(ctx, 0)
+	require.NoError(t, err)
+	var tables int
+	require.NoError(t, db.QueryRowContext(ctx, "SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' AND name <> $1", goose.TableName()).Scan(&tables))
+	assert.Zero(t, tables, "rolling back every migration should drop every table")
+}
+
+func TestForDriver(t *testing.T) {
+	source, err := ForDriver("")
+	require.NoError(t, err)
+	assert.Equal(t, goose.DialectPostgres, source.Dialect, "postgres should be the default driver")
+	source, err = ForDriver(config.DatabaseDriverSQLite)
+	require.NoError(t, err)
+	assert.Equal(t, goose.DialectSQLite3, source.Dialect)
+	assert.Equal(t, SQLiteSourceDir, source.SourceDir)
+	_, err = ForDriver("mysql")
+	assert.ErrorIs(t, err, errors.ConfigValidationError)
+}
+
+func TestCreateTablesDownDropsEveryTable(t *testing.T) {
+	statements, err := SQL(FS, Step{Version: 1, Source: "01_create_tables.sql", Direction: DirectionDown})
+	require.NoError(t, err)
+	transactions := strings.Index(statements, "drop table if exists transactions")
+	operationTypes := strings.Index(statements, "drop table if exists operation_types")
+	accounts := strings.Index(statements, "drop table if exists accounts")
+	require.True(t, transactions >= 0 && operationTypes >= 0 && accounts >= 0, "every table should be dropped")
+	assert.Less(t, transactions, operationTypes, "transactions reference operation types")
+	assert.Less(t, transactions, accounts, "transactions reference accounts")
+}
+
+func TestPlan(t *testing.T) {
+	steps, err := Plan(testFS, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []Step{
+		{Version: 1, Source: "01_create_tables.sql", Direction: DirectionUp},
+		{Version: 2, Source: "02_add_column.sql", Direction: DirectionUp},
+		{Version: 10, Source: "10_create_index.sql", Direction: DirectionUp},
+	}, steps)
+
+	steps, err = Plan(testFS, 1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []Step{{Version: 2, Source: "02_add_column.sql", Direction: DirectionUp}}, steps)
+
+	steps, err = Plan(testFS, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []Step{
+		{Version: 10, Source: "10_create_index.sql", Direction: DirectionDown},
+		{Version: 2, Source: "02_add_column.sql", Direction: DirectionDown},
+		{Version: 1, Source: "01_create_tables.sql", Direction: DirectionDown},
+	}, steps, "rollbacks should run the newest migration first")
+
+	steps, err = Plan(testFS, 2, 2)
+	require.NoError(t, err)
+	assert.Empty(t, steps)
+}
+
+func TestPreviousAndLast(t *testing.T) {
+	previous, err := Previous(testFS, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), previous)
+	previous, err = Previous(testFS, 1)
+	require.NoError(t, err)
+	assert.Zero(t, previous)
+	last, err := Last(testFS)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), last)
+}
+
+func TestSQL(t *testing.T) {
+	up, err := SQL(testFS, Step{Source: "02_add_column.sql", Direction: DirectionUp})
+	require.NoError(t, err)
+	assert.Equal(t, "-- +goose StatementBegin\nalter table a add b int;\n-- +goose StatementEnd", up)
+	down, err := SQL(testFS, Step{Source: "02_add_column.sql", Direction: DirectionDown})
+	require.NoError(t, err)
+	assert.Equal(t, "alter table a drop b;", down)
+}
+
+func TestFileName(t *testing.T) {
+	fileName, err := FileName(testFS, "Add Account Holders")
+	require.NoError(t, err)
+	assert.Equal(t, "11_add_account_holders.sql", fileName)
+	fileName, err = FileName(fstest.MapFS{}, "create-tables")
+	require.NoError(t, err)
+	assert.Equal(t, "01_create_tables.sql", fileName)
+	_, err = FileName(testFS, "--")
+	assert.Error(t, err)
+}
@@ -0,0 +1,61 @@
+-- +goose up
+
+-- SQLite databases start from the current schema of the Postgres migrations, without their data migrations.
+-- Timestamps are stored as UTC text of fixed width, so they are ordered and compared as text.
+create table if not exists accounts
+(
+    account_id            integer primary key autoincrement,
+    document_type         varchar(20) not null,
+    document_number       varchar     not null,
+    document_number_index varchar(64),
+    currency              varchar(3)  not null default 'BRL',
+    status                varchar(7)  not null default 'ACTIVE'
+        check (status in ('ACTIVE', 'BLOCKED')),
+    version               bigint      not null default 1
+        check (version > 0)
+);
+
+create unique index if not exists accounts_document_number_index_key
+    on accounts (document_number_index);
+
+create table if not exists operation_types
+(
+    operation_type_id bigint primary key,
+    description       varchar not null
+);
+
+create table if not exists transactions
+(
+    transaction_id         integer primary key autoincrement,
+    account_id             bigint           not null references accounts (account_id),
+    operation_type_id      bigint           not null references operation_types (operation_type_id),
+    amount                 double precision not null,
+    currency               varchar(3)       not null default 'BRL',
+    original_amount        double precision not null,
+    original_currency      varchar(3)       not null default 'BRL',
+    exchange_rate          double precision not null default 1,
+    merchant_name          varchar(120)     not null default '',
+    merchant_category_code varchar(4)       not null default '',
+    merchant_country       varchar(2)       not null default '',
+    description            varchar(255)     not null default '',
+    metadata               text             not null default '{}'
+        check (json_valid(metadata)),
+    event_date             timestamp        not null,
+    status                 varchar(9)       not null default 'POSTED'
+        check (status in ('PENDING', 'POSTED', 'CANCELLED')),
+    effective_date         timestamp        not null,
+    posted_at              timestamp,
+    created_at             timestamp        not null,
+    cancelled_at           timestamp
+);
+
+create index if not exists transactions_account_id_transaction_id_idx on transactions (account_id, transaction_id);
+create index if not exists transactions_merchant_category_code_idx on transactions (merchant_category_code);
+create index if not exists transactions_pending_effective_date_idx on transactions (effective_date, transaction_id)
+    where status = 'PENDING';
+
+
+-- +goose down
+drop table if exists transactions;
+drop table if exists operation_types;
+drop table if exists accounts;
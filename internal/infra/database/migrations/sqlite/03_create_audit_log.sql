@@ -0,0 +1,43 @@
+-- +goose up
+
+-- Snapshots are text, so the hashed json is kept byte for byte.
+create table if not exists audit_log
+(
+    audit_id        integer primary key autoincrement,
+    principal       varchar     not null default '',
+    client_ip       varchar(45) not null default '',
+    trace_id        varchar     not null default '',
+    action          varchar(64) not null,
+    entity_type     varchar(32) not null,
+    entity_id       bigint      not null,
+    before_snapshot text,
+    after_snapshot  text,
+    created_at      timestamp   not null,
+    previous_hash   varchar(64) not null,
+    hash            varchar(64) not null unique
+);
+
+create index if not exists audit_log_entity_idx on audit_log (entity_type, entity_id, audit_id);
+create index if not exists audit_log_created_at_idx on audit_log (created_at);
+
+-- +goose StatementBegin
+create trigger if not exists audit_log_append_only_update
+    before update
+    on audit_log
+begin
+    select raise(abort, 'audit_log is append-only');
+end;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+create trigger if not exists audit_log_append_only_delete
+    before delete
+    on audit_log
+begin
+    select raise(abort, 'audit_log is append-only');
+end;
+-- +goose StatementEnd
+
+
+-- +goose down
+drop table if exists audit_log;
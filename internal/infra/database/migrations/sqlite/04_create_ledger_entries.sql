@@ -0,0 +1,20 @@
+-- +goose up
+
+-- Every transaction posts balanced entries: debits increase and credits decrease the balance of a ledger account.
+create table if not exists ledger_entries
+(
+    entry_id       integer primary key autoincrement,
+    transaction_id bigint         not null references transactions (transaction_id),
+    ledger_account varchar(64)    not null,
+    direction      varchar(6)     not null check (direction in ('DEBIT', 'CREDIT')),
+    amount         numeric(18, 2) not null check (amount > 0),
+    currency       varchar(3)     not null,
+    created_at     timestamp      not null
+);
+
+create index if not exists ledger_entries_transaction_id_idx on ledger_entries (transaction_id);
+create index if not exists ledger_entries_ledger_account_idx on ledger_entries (ledger_account, currency);
+
+
+-- +goose down
+drop table if exists ledger_entries;
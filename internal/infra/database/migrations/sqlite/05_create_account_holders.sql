@@ -0,0 +1,37 @@
+-- +goose up
+
+-- Personal values are encrypted by the application, city, state and country are kept in clear text.
+create table if not exists account_holders
+(
+    account_id    bigint primary key references accounts (account_id),
+    name          varchar     not null,
+    email         varchar     not null,
+    phone         varchar     not null,
+    birth_date    varchar     not null,
+    address_line1 varchar     not null,
+    address_line2 varchar     not null,
+    city          varchar(80) not null,
+    state         varchar(80) not null,
+    postal_code   varchar     not null,
+    country       varchar(2)  not null,
+    version       bigint      not null check (version > 0),
+    updated_at    timestamp   not null
+);
+
+create table if not exists account_holder_history
+(
+    history_id     integer primary key autoincrement,
+    account_id     bigint    not null references accounts (account_id),
+    version        bigint    not null,
+    changed_fields varchar   not null,
+    principal      varchar   not null default '',
+    trace_id       varchar   not null default '',
+    snapshot       varchar   not null,
+    changed_at     timestamp not null,
+    unique (account_id, version)
+);
+
+
+-- +goose down
+drop table if exists account_holder_history;
+drop table if exists account_holders;
@@ -2,5 +2,9 @@ package model
 
 type AccountModel struct {
 	AccountID      int64  `bun:"account_id,pk,autoincrement"` // Unique identifier of an Account
-	DocumentNumber string `bun:"document_number,notnull"`     // Brazilian CPF or CNPJ
+	DocumentType   string `bun:"document_type,notnull"`
+	DocumentNumber string `bun:"document_number,notnull"` // Normalized document number
+	Currency       string `bun:"currency,notnull"`
+	Status         string `bun:"status,notnull"`
+	Version        int64  `bun:"version,notnull"`
 }
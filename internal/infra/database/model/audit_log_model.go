@@ -0,0 +1,21 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+type AuditLogModel struct {
+	AuditID      int64          `bun:"audit_id,pk,autoincrement"`
+	Principal    string         `bun:"principal,notnull"`
+	ClientIP     string         `bun:"client_ip,notnull"`
+	TraceID      string         `bun:"trace_id,notnull"`
+	Action       string         `bun:"action,notnull"`
+	EntityType   string         `bun:"entity_type,notnull"`
+	EntityID     int64          `bun:"entity_id,notnull"`
+	Before       sql.NullString `bun:"before_snapshot,type:json"` // Stored as json, not jsonb, so the hashed text is kept as is
+	After        sql.NullString `bun:"after_snapshot,type:json"`
+	CreatedAt    time.Time      `bun:"created_at,notnull"`
+	PreviousHash string         `bun:"previous_hash,notnull"`
+	Hash         string         `bun:"hash,notnull"`
+}
@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+type HolderModel struct {
+	AccountID    int64     `bun:"account_id,pk"`
+	Name         string    `bun:"name,notnull"`
+	Email        string    `bun:"email,notnull"`
+	Phone        string    `bun:"phone,notnull"`
+	BirthDate    string    `bun:"birth_date,notnull"`
+	AddressLine1 string    `bun:"address_line1,notnull"`
+	AddressLine2 string    `bun:"address_line2,notnull"`
+	City         string    `bun:"city,notnull"`
+	State        string    `bun:"state,notnull"`
+	PostalCode   string    `bun:"postal_code,notnull"`
+	Country      string    `bun:"country,notnull"`
+	Version      int64     `bun:"version,notnull"`
+	UpdatedAt    time.Time `bun:"updated_at,notnull"`
+}
+
+type HolderHistoryModel struct {
+	HistoryID     int64     `bun:"history_id,pk,autoincrement"`
+	AccountID     int64     `bun:"account_id,notnull"`
+	Version       int64     `bun:"version,notnull"`
+	ChangedFields string    `bun:"changed_fields,notnull"` // Comma separated field names
+	Principal     string    `bun:"principal,notnull"`
+	TraceID       string    `bun:"trace_id,notnull"`
+	Snapshot      string    `bun:"snapshot,notnull"` // json of the holder after the change
+	ChangedAt     time.Time `bun:"changed_at,notnull"`
+}
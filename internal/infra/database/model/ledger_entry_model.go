@@ -0,0 +1,15 @@
+package model
+
+import (
+	"time"
+)
+
+type LedgerEntryModel struct {
+	EntryID       int64     `bun:"entry_id,pk,autoincrement"`
+	TransactionID int64     `bun:"transaction_id,notnull"`
+	LedgerAccount string    `bun:"ledger_account,notnull"`
+	Direction     string    `bun:"direction,notnull"` // DEBIT or CREDIT
+	Amount        float64   `bun:"amount,type:numeric(18,2),notnull"`
+	Currency      string    `bun:"currency,notnull"`
+	CreatedAt     time.Time `bun:"created_at,notnull"`
+}
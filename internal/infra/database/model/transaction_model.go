@@ -1,13 +1,28 @@
 package model
 
 import (
+	"database/sql"
 	"time"
 )
 
 type TransactionModel struct {
-	TransactionID   int64     `bun:"transaction_id,pk,autoincrement"` // Unique identifier of an Account
-	AccountID       int64     `bun:"account_id,notnull"`
-	OperationTypeID int       `bun:"operation_type_id,notnull"`
-	Amount          float64   `bun:"amount,notnull"`
-	EventDate       time.Time `bun:"event_date,notnull"`
+	TransactionID    int64        `bun:"transaction_id,pk,autoincrement"` // Unique identifier of an Account
+	AccountID        int64        `bun:"account_id,notnull"`
+	OperationTypeID  int          `bun:"operation_type_id,notnull"`
+	Amount           float64      `bun:"amount,notnull"`
+	Currency         string       `bun:"currency,notnull"`
+	OriginalAmount   float64      `bun:"original_amount,notnull"`
+	OriginalCurrency string       `bun:"original_currency,notnull"`
+	ExchangeRate     float64      `bun:"exchange_rate,notnull"`
+	MerchantName     string       `bun:"merchant_name,notnull"`
+	MerchantCategory string       `bun:"merchant_category_code,notnull"`
+	MerchantCountry  string       `bun:"merchant_country,notnull"`
+	Description      string       `bun:"description,notnull"`
+	Metadata         string       `bun:"metadata,type:jsonb,notnull"` // JSON object with string values
+	EventDate        time.Time    `bun:"event_date,notnull"`
+	Status           string       `bun:"status,notnull"`
+	EffectiveDate    time.Time    `bun:"effective_date,notnull"`
+	PostedAt         sql.NullTime `bun:"posted_at"`
+	CreatedAt        time.Time    `bun:"created_at,notnull"`
+	CancelledAt      sql.NullTime `bun:"cancelled_at"`
 }
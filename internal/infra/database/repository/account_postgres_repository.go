@@ -5,23 +5,31 @@ import (
 	"database/sql"
 	"errors"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/adapter"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/clock"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/contextutils"
 	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/pii"
 	"github.com/kiosanim/pismo-code-assessment/internal/domains/account"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/audit"
 	"github.com/kiosanim/pismo-code-assessment/internal/infra/database/mapper"
 	"github.com/kiosanim/pismo-code-assessment/internal/infra/database/model"
 )
 
+// AccountPostgresRepository stores document numbers encrypted, looking them up by their blind index
 type AccountPostgresRepository struct {
 	connectionData *adapter.DatabaseConnectionData
+	fieldCipher    pii.FieldCipher
+	clock          clock.Clock
 	componentName  string
 	log            logger.Logger
 }
 
-func NewAccountPostgresRepository(connectionData *adapter.DatabaseConnectionData, log logger.Logger) *AccountPostgresRepository {
+func NewAccountPostgresRepository(connectionData *adapter.DatabaseConnectionData, fieldCipher pii.FieldCipher, clock clock.Clock, log logger.Logger) *AccountPostgresRepository {
 	repository := &AccountPostgresRepository{
 		connectionData: connectionData,
+		fieldCipher:    fieldCipher,
+		clock:          clock,
 		componentName:  "AccountPostgresRepository",
 		log:            log,
 	}
@@ -30,12 +38,22 @@ func NewAccountPostgresRepository(connectionData *adapter.DatabaseConnectionData
 }
 
 func (a *AccountPostgresRepository) FindByID(ctx context.Context, accountID int64) (*account.Account, error) {
+	return a.findByID(ctx, ".FindByID", accountID, "")
+}
+
+// FindByIDForShare reads the account FOR SHARE, so in a unit of work it is not changed until the unit of work ends
+func (a *AccountPostgresRepository) FindByIDForShare(ctx context.Context, accountID int64) (*account.Account, error) {
+	return a.findByID(ctx, ".FindByIDForShare", accountID, " FOR SHARE")
+}
+
+// findByID reads an account, locking it with the locking clause, logged as method
+func (a *AccountPostgresRepository) findByID(ctx context.Context, method string, accountID int64, lockingClause string) (*account.Account, error) {
 	traceID := contextutils.GetTraceID(ctx)
-	a.log.Debug(a.componentName+".FindByID", "accountID", accountID, "x_trace_id", traceID)
+	a.log.Debug(a.componentName+method, "accountID", accountID, "x_trace_id", traceID)
 	var selectedAccount model.AccountModel
-	stmt, err := a.connectionData.Db.PrepareContext(ctx, "SELECT account_id, document_number FROM accounts WHERE account_id = $1")
+	stmt, err := queryerFor(ctx, a.connectionData.Db).PrepareContext(ctx, "SELECT account_id, document_type, document_number, currency, status, version FROM accounts WHERE account_id = $1"+lockingClause)
 	if err != nil {
-		a.log.Warn(a.componentName+".FindByID", "error", err, "x_trace_id", traceID)
+		a.log.Warn(a.componentName+method, "error", err, "x_trace_id", traceID)
 		return nil, coreerr.DatabasePrepareStatementError
 	}
 	defer stmt.Close()
@@ -43,15 +61,15 @@ func (a *AccountPostgresRepository) FindByID(ctx context.Context, accountID int6
 	if done {
 		return acc, err
 	}
-	err = stmt.QueryRowContext(ctx, accountID).Scan(&selectedAccount.AccountID, &selectedAccount.DocumentNumber)
+	err = stmt.QueryRowContext(ctx, accountID).Scan(&selectedAccount.AccountID, &selectedAccount.DocumentType, &selectedAccount.DocumentNumber, &selectedAccount.Currency, &selectedAccount.Status, &selectedAccount.Version)
 	if err != nil {
-		a.log.Warn(a.componentName+".FindByID", "error", err, "x_trace_id", traceID)
+		a.log.Warn(a.componentName+method, "error", err, "x_trace_id", traceID)
 		if err == sql.ErrNoRows {
 			return nil, coreerr.AccountNotFoundError
 		}
 		return nil, coreerr.DatabaseQueryError
 	}
-	return mapper.ToAccountEntity(&selectedAccount), nil
+	return a.toAccountEntity(ctx, &selectedAccount)
 }
 
 func (a *AccountPostgresRepository) validateAccountError(err error) (*account.Account, error, bool) {
@@ -65,9 +83,9 @@ func (a *AccountPostgresRepository) validateAccountError(err error) (*account.Ac
 
 func (a *AccountPostgresRepository) FindByDocumentNumber(ctx context.Context, documentNumber string) (*account.Account, error) {
 	traceID := contextutils.GetTraceID(ctx)
-	a.log.Debug(a.componentName+".FindByDocumentNumber", "documentNumber", documentNumber, "x_trace_id", traceID)
+	a.log.Debug(a.componentName+".FindByDocumentNumber", "documentNumber", pii.Mask(documentNumber), "x_trace_id", traceID)
 	var selectedAccount model.AccountModel
-	stmt, err := a.connectionData.Db.PrepareContext(ctx, "SELECT account_id, document_number FROM accounts WHERE document_number = $1")
+	stmt, err := queryerFor(ctx, a.connectionData.Db).PrepareContext(ctx, "SELECT account_id, document_type, document_number, currency, status, version FROM accounts WHERE document_number_index = $1 OR (document_number_index IS NULL AND document_number = $2)")
 	if err != nil {
 		a.log.Warn(a.componentName+".FindByDocumentNumber", "error", err, "x_trace_id", traceID)
 		return nil, coreerr.DatabasePrepareStatementError
@@ -77,7 +95,8 @@ func (a *AccountPostgresRepository) FindByDocumentNumber(ctx context.Context, do
 	if done {
 		return acc, err
 	}
-	err = stmt.QueryRowContext(ctx, documentNumber).Scan(&selectedAccount.AccountID, &selectedAccount.DocumentNumber)
+	// Rows created before document numbers were encrypted have no blind index until they are protected
+	err = stmt.QueryRowContext(ctx, a.fieldCipher.BlindIndex(documentNumber), documentNumber).Scan(&selectedAccount.AccountID, &selectedAccount.DocumentType, &selectedAccount.DocumentNumber, &selectedAccount.Currency, &selectedAccount.Status, &selectedAccount.Version)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			a.log.Warn(a.componentName+".FindByDocumentNumber", "error", err, "x_trace_id", traceID)
@@ -86,8 +105,7 @@ func (a *AccountPostgresRepository) FindByDocumentNumber(ctx context.Context, do
 		a.log.Warn(a.componentName+".FindByDocumentNumber", "error", err, "x_trace_id", traceID)
 		return nil, coreerr.DatabaseQueryError
 	}
-
-	return mapper.ToAccountEntity(&selectedAccount), nil
+	return a.toAccountEntity(ctx, &selectedAccount)
 }
 
 func (a *AccountPostgresRepository) Save(ctx context.Context, newAccount *account.Account) (*account.Account, error) {
@@ -97,14 +115,21 @@ func (a *AccountPostgresRepository) Save(ctx context.Context, newAccount *accoun
 	if accountModel == nil {
 		return nil, coreerr.InvalidParametersError
 	}
+	documentNumberIndex := a.fieldCipher.BlindIndex(accountModel.DocumentNumber)
+	encryptedDocumentNumber, err := a.fieldCipher.Encrypt(accountModel.DocumentNumber)
+	if err != nil {
+		a.log.Warn(a.componentName+".Save", "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
+	accountModel.DocumentNumber = encryptedDocumentNumber
 
-	tx, err := a.connectionData.Db.BeginTx(ctx, nil)
+	tx, err := beginTx(ctx, a.connectionData.Db, nil)
 	if err != nil {
 		a.log.Warn(a.componentName+".Save", "error", err, "x_trace_id", traceID)
 		return nil, coreerr.DatabaseCreateTransactionError
 	}
 	defer tx.Rollback()
-	stmt, err := tx.PrepareContext(ctx, "INSERT INTO accounts (document_number) VALUES ($1) RETURNING account_id, document_number;")
+	stmt, err := tx.PrepareContext(ctx, "INSERT INTO accounts (document_type, document_number, document_number_index, currency) VALUES ($1, $2, $3, $4) RETURNING account_id, document_type, document_number, currency, status, version;")
 	if err != nil {
 		a.log.Warn(a.componentName+".Save", "error", err, "x_trace_id", traceID)
 		return nil, coreerr.DatabasePrepareStatementError
@@ -112,31 +137,51 @@ func (a *AccountPostgresRepository) Save(ctx context.Context, newAccount *accoun
 	defer stmt.Close()
 	err = stmt.QueryRowContext(
 		ctx,
-		accountModel.DocumentNumber).Scan(
+		accountModel.DocumentType,
+		accountModel.DocumentNumber,
+		documentNumberIndex,
+		accountModel.Currency).Scan(
 		&accountModel.AccountID,
-		&accountModel.DocumentNumber)
+		&accountModel.DocumentType,
+		&accountModel.DocumentNumber,
+		&accountModel.Currency,
+		&accountModel.Status,
+		&accountModel.Version)
+	if isUniqueViolation(err, accountDocumentNumberIndexKey) {
+		// Another request created an account for the document after it was looked up
+		a.log.Warn(a.componentName+".Save", "error", coreerr.AccountAlreadyExistsForDocumentNumberError, "x_trace_id", traceID)
+		return nil, coreerr.AccountAlreadyExistsForDocumentNumberError
+	}
 	if err != nil {
 		a.log.Warn(a.componentName+".Save", "error", err, "x_trace_id", traceID)
 		return nil, coreerr.DatabaseInsertionError
 	}
+	savedAccount, err := a.toAccountEntity(ctx, accountModel)
+	if err != nil {
+		return nil, err
+	}
+	if err = a.appendAuditEntry(ctx, tx.Tx, audit.ActionAccountCreate, nil, savedAccount); err != nil {
+		a.log.Warn(a.componentName+".Save", "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
 	err = tx.Commit()
 	if err != nil {
 		a.log.Warn(a.componentName+".Save", "error", err, "x_trace_id", traceID)
 		return nil, coreerr.DatabaseFailToCommitError
 	}
-	return mapper.ToAccountEntity(accountModel), nil
+	return savedAccount, nil
 }
 
 func (a *AccountPostgresRepository) List(ctx context.Context, limit int64, cursorID int64) ([]account.Account, error) {
 	traceID := contextutils.GetTraceID(ctx)
 	a.log.Debug(a.componentName+".List", "limit", limit, "cursorID", cursorID, "x_trace_id", traceID)
-	tx, err := a.connectionData.Db.BeginTx(ctx, nil)
+	tx, err := beginTx(ctx, a.connectionData.Db, nil)
 	if err != nil {
 		a.log.Warn(a.componentName+".List", "error", err, "x_trace_id", traceID)
 		return nil, coreerr.DatabaseCreateTransactionError
 	}
 	defer tx.Rollback()
-	stmt, err := tx.PrepareContext(ctx, "SELECT account_id, document_number FROM accounts WHERE account_id > $1 ORDER BY account_id LIMIT $2")
+	stmt, err := tx.PrepareContext(ctx, "SELECT account_id, document_type, document_number, currency, status, version FROM accounts WHERE account_id > $1 ORDER BY account_id LIMIT $2")
 	if err != nil {
 		a.log.Warn(a.componentName+".List", "error", err, "x_trace_id", traceID)
 		return nil, coreerr.DatabasePrepareStatementError
@@ -155,12 +200,171 @@ func (a *AccountPostgresRepository) List(ctx context.Context, limit int64, curso
 	var accounts []account.Account
 	for rows.Next() {
 		var account model.AccountModel
-		err = rows.Scan(&account.AccountID, &account.DocumentNumber)
+		err = rows.Scan(&account.AccountID, &account.DocumentType, &account.DocumentNumber, &account.Currency, &account.Status, &account.Version)
 		if err != nil {
 			a.log.Warn(a.componentName+".List", "error", err, "x_trace_id", traceID)
 			return nil, err
 		}
-		accounts = append(accounts, *mapper.ToAccountEntity(&account))
+		entity, err := a.toAccountEntity(ctx, &account)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, *entity)
 	}
 	return accounts, nil
 }
+
+// Block sets the status of an account at expectedVersion to BLOCKED, recording the change in the audit log when it
+// was not blocked yet. The update fails with AccountVersionConflictError when the account is at another version.
+func (a *AccountPostgresRepository) Block(ctx context.Context, accountID int64, expectedVersion int64) (*account.Account, error) {
+	traceID := contextutils.GetTraceID(ctx)
+	a.log.Debug(a.componentName+".Block", "accountID", accountID, "expectedVersion", expectedVersion, "x_trace_id", traceID)
+	tx, err := beginTx(ctx, a.connectionData.Db, nil)
+	if err != nil {
+		a.log.Warn(a.componentName+".Block", "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseCreateTransactionError
+	}
+	defer tx.Rollback()
+	var accountModel model.AccountModel
+	err = tx.QueryRowContext(ctx, "SELECT account_id, document_type, document_number, currency, status, version FROM accounts WHERE account_id = $1", accountID).Scan(
+		&accountModel.AccountID, &accountModel.DocumentType, &accountModel.DocumentNumber, &accountModel.Currency, &accountModel.Status, &accountModel.Version)
+	if err != nil {
+		a.log.Warn(a.componentName+".Block", "error", err, "x_trace_id", traceID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, coreerr.AccountNotFoundError
+		}
+		return nil, coreerr.DatabaseQueryError
+	}
+	before, err := a.toAccountEntity(ctx, &accountModel)
+	if err != nil {
+		return nil, err
+	}
+	if before.Version != expectedVersion {
+		a.log.Warn(a.componentName+".Block", "error", coreerr.AccountVersionConflictError, "version", before.Version, "x_trace_id", traceID)
+		return nil, coreerr.AccountVersionConflictError
+	}
+	if before.Blocked() {
+		return before, nil
+	}
+	// The version condition rejects the update when another one was committed since the account was read
+	result, err := tx.ExecContext(ctx, "UPDATE accounts SET status = $1, version = version + 1 WHERE account_id = $2 AND version = $3", account.StatusBlocked, accountID, expectedVersion)
+	if err != nil {
+		a.log.Warn(a.componentName+".Block", "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseInsertionError
+	}
+	if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+		a.log.Warn(a.componentName+".Block", "error", coreerr.AccountVersionConflictError, "x_trace_id", traceID)
+		return nil, coreerr.AccountVersionConflictError
+	}
+	after := *before
+	after.Status = account.StatusBlocked
+	after.Version++
+	if err = a.appendAuditEntry(ctx, tx.Tx, audit.ActionAccountBlock, before, &after); err != nil {
+		a.log.Warn(a.componentName+".Block", "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		a.log.Warn(a.componentName+".Block", "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseFailToCommitError
+	}
+	return &after, nil
+}
+
+// ProtectDocumentNumbers encrypts and indexes, batchSize rows at a time, the document numbers stored before they were encrypted.
+// It returns the number of accounts updated.
+func (a *AccountPostgresRepository) ProtectDocumentNumbers(ctx context.Context, batchSize int) (int, error) {
+	traceID := contextutils.GetTraceID(ctx)
+	protected := 0
+	for {
+		updated, err := a.protectDocumentNumbersBatch(ctx, batchSize)
+		protected += updated
+		if err != nil {
+			a.log.Warn(a.componentName+".ProtectDocumentNumbers", "error", err, "protected", protected, "x_trace_id", traceID)
+			return protected, err
+		}
+		if updated < batchSize {
+			a.log.Info(a.componentName+".ProtectDocumentNumbers", "protected", protected, "x_trace_id", traceID)
+			return protected, nil
+		}
+	}
+}
+
+func (a *AccountPostgresRepository) protectDocumentNumbersBatch(ctx context.Context, batchSize int) (int, error) {
+	tx, err := beginTx(ctx, a.connectionData.Db, nil)
+	if err != nil {
+		return 0, coreerr.DatabaseCreateTransactionError
+	}
+	defer tx.Rollback()
+	rows, err := tx.QueryContext(ctx, "SELECT account_id, document_number FROM accounts WHERE document_number_index IS NULL ORDER BY account_id LIMIT $1 FOR UPDATE", batchSize)
+	if err != nil {
+		return 0, coreerr.DatabaseQueryError
+	}
+	documentNumbers := make(map[int64]string)
+	for rows.Next() {
+		var accountID int64
+		var documentNumber string
+		if err = rows.Scan(&accountID, &documentNumber); err != nil {
+			rows.Close()
+			return 0, coreerr.DatabaseQueryError
+		}
+		documentNumbers[accountID] = documentNumber
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return 0, coreerr.DatabaseQueryError
+	}
+	stmt, err := tx.PrepareContext(ctx, "UPDATE accounts SET document_number = $1, document_number_index = $2 WHERE account_id = $3")
+	if err != nil {
+		return 0, coreerr.DatabasePrepareStatementError
+	}
+	defer stmt.Close()
+	for accountID, documentNumber := range documentNumbers {
+		encrypted, err := a.fieldCipher.Encrypt(documentNumber)
+		if err != nil {
+			return 0, err
+		}
+		if _, err = stmt.ExecContext(ctx, encrypted, a.fieldCipher.BlindIndex(documentNumber), accountID); err != nil {
+			return 0, coreerr.DatabaseInsertionError
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return 0, coreerr.DatabaseFailToCommitError
+	}
+	return len(documentNumbers), nil
+}
+
+// toAccountEntity maps a stored account decrypting its document number
+func (a *AccountPostgresRepository) toAccountEntity(ctx context.Context, accountModel *model.AccountModel) (*account.Account, error) {
+	documentNumber, err := a.fieldCipher.Decrypt(accountModel.DocumentNumber)
+	if err != nil {
+		a.log.Error(a.componentName+".toAccountEntity", "error", err, "accountID", accountModel.AccountID, "x_trace_id", contextutils.GetTraceID(ctx))
+		return nil, err
+	}
+	accountModel.DocumentNumber = documentNumber
+	return mapper.ToAccountEntity(accountModel), nil
+}
+
+// appendAuditEntry records the change of an account in tx, with the document numbers masked in the snapshots
+func (a *AccountPostgresRepository) appendAuditEntry(ctx context.Context, tx *sql.Tx, action string, before *account.Account, after *account.Account) error {
+	entityID := int64(0)
+	var beforeSnapshot, afterSnapshot any
+	if before != nil {
+		entityID = before.AccountID
+		beforeSnapshot = maskedAccount(before)
+	}
+	if after != nil {
+		entityID = after.AccountID
+		afterSnapshot = maskedAccount(after)
+	}
+	entry, err := newAuditEntry(ctx, a.clock.Now(), action, audit.EntityAccount, entityID, beforeSnapshot, afterSnapshot)
+	if err != nil {
+		return err
+	}
+	return appendAuditEntries(ctx, tx, entry)
+}
+
+func maskedAccount(entity *account.Account) account.Account {
+	masked := *entity
+	masked.DocumentNumber = pii.Mask(masked.DocumentNumber)
+	return masked
+}
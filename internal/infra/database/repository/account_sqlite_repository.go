@@ -0,0 +1,235 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/adapter"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/clock"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/contextutils"
+	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/pii"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/account"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/audit"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/database/mapper"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/database/model"
+)
+
+const accountColumns = "account_id, document_type, document_number, currency, status, version"
+
+// AccountSQLiteRepository stores document numbers encrypted, looking them up by their blind index. SQLite databases
+// never held plaintext document numbers, so every row has a blind index.
+type AccountSQLiteRepository struct {
+	connectionData *adapter.DatabaseConnectionData
+	fieldCipher    pii.FieldCipher
+	clock          clock.Clock
+	componentName  string
+	log            logger.Logger
+}
+
+func NewAccountSQLiteRepository(connectionData *adapter.DatabaseConnectionData, fieldCipher pii.FieldCipher, clock clock.Clock, log logger.Logger) *AccountSQLiteRepository {
+	repository := &AccountSQLiteRepository{
+		connectionData: connectionData,
+		fieldCipher:    fieldCipher,
+		clock:          clock,
+		log:            log,
+	}
+	repository.componentName = logger.ComponentNameFromStruct(repository)
+	return repository
+}
+
+func (a *AccountSQLiteRepository) FindByID(ctx context.Context, accountID int64) (*account.Account, error) {
+	traceID := contextutils.GetTraceID(ctx)
+	a.log.Debug(a.componentName+".FindByID", "accountID", accountID, "x_trace_id", traceID)
+	row := queryerFor(ctx, a.connectionData.Db).QueryRowContext(ctx, "SELECT "+accountColumns+" FROM accounts WHERE account_id = $1", accountID)
+	found, err := a.scanAccount(ctx, row)
+	if err != nil {
+		a.log.Warn(a.componentName+".FindByID", "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
+	return found, nil
+}
+
+// FindByIDForShare reads the account like FindByID, the single connection to SQLite already keeps it from being changed
+// by another unit of work until the running one ends
+func (a *AccountSQLiteRepository) FindByIDForShare(ctx context.Context, accountID int64) (*account.Account, error) {
+	return a.FindByID(ctx, accountID)
+}
+
+func (a *AccountSQLiteRepository) FindByDocumentNumber(ctx context.Context, documentNumber string) (*account.Account, error) {
+	traceID := contextutils.GetTraceID(ctx)
+	a.log.Debug(a.componentName+".FindByDocumentNumber", "documentNumber", pii.Mask(documentNumber), "x_trace_id", traceID)
+	row := queryerFor(ctx, a.connectionData.Db).QueryRowContext(ctx, "SELECT "+accountColumns+" FROM accounts WHERE document_number_index = $1", a.fieldCipher.BlindIndex(documentNumber))
+	found, err := a.scanAccount(ctx, row)
+	if err != nil {
+		a.log.Warn(a.componentName+".FindByDocumentNumber", "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
+	return found, nil
+}
+
+func (a *AccountSQLiteRepository) Save(ctx context.Context, newAccount *account.Account) (*account.Account, error) {
+	traceID := contextutils.GetTraceID(ctx)
+	a.log.Debug(a.componentName+".Save", "newAccount", newAccount, "x_trace_id", traceID)
+	accountModel := mapper.ToAccountModel(newAccount)
+	if accountModel == nil {
+		return nil, coreerr.InvalidParametersError
+	}
+	documentNumberIndex := a.fieldCipher.BlindIndex(accountModel.DocumentNumber)
+	encryptedDocumentNumber, err := a.fieldCipher.Encrypt(accountModel.DocumentNumber)
+	if err != nil {
+		a.log.Warn(a.componentName+".Save", "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
+	tx, err := beginTx(ctx, a.connectionData.Db, nil)
+	if err != nil {
+		a.log.Warn(a.componentName+".Save", "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseCreateTransactionError
+	}
+	defer tx.Rollback()
+	err = tx.QueryRowContext(ctx, "INSERT INTO accounts (document_type, document_number, document_number_index, currency) VALUES ($1, $2, $3, $4) RETURNING "+accountColumns,
+		accountModel.DocumentType, encryptedDocumentNumber, documentNumberIndex, accountModel.Currency).Scan(
+		&accountModel.AccountID, &accountModel.DocumentType, &accountModel.DocumentNumber, &accountModel.Currency, &accountModel.Status, &accountModel.Version)
+	if isSQLiteUniqueViolation(err, accountDocumentNumberIndexColumn) {
+		// Another request created an account for the document after it was looked up
+		a.log.Warn(a.componentName+".Save", "error", coreerr.AccountAlreadyExistsForDocumentNumberError, "x_trace_id", traceID)
+		return nil, coreerr.AccountAlreadyExistsForDocumentNumberError
+	}
+	if err != nil {
+		a.log.Warn(a.componentName+".Save", "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseInsertionError
+	}
+	savedAccount, err := a.toAccountEntity(ctx, accountModel)
+	if err != nil {
+		return nil, err
+	}
+	if err = a.appendAuditEntry(ctx, tx.Tx, audit.ActionAccountCreate, nil, savedAccount); err != nil {
+		a.log.Warn(a.componentName+".Save", "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		a.log.Warn(a.componentName+".Save", "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseFailToCommitError
+	}
+	return savedAccount, nil
+}
+
+func (a *AccountSQLiteRepository) List(ctx context.Context, limit int64, cursorID int64) ([]account.Account, error) {
+	traceID := contextutils.GetTraceID(ctx)
+	a.log.Debug(a.componentName+".List", "limit", limit, "cursorID", cursorID, "x_trace_id", traceID)
+	rows, err := queryerFor(ctx, a.connectionData.Db).QueryContext(ctx, "SELECT "+accountColumns+" FROM accounts WHERE account_id > $1 ORDER BY account_id LIMIT $2", cursorID, limit)
+	if err != nil {
+		a.log.Warn(a.componentName+".List", "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseQueryError
+	}
+	defer rows.Close()
+	var accounts []account.Account
+	for rows.Next() {
+		var accountModel model.AccountModel
+		err = rows.Scan(&accountModel.AccountID, &accountModel.DocumentType, &accountModel.DocumentNumber, &accountModel.Currency, &accountModel.Status, &accountModel.Version)
+		if err != nil {
+			a.log.Warn(a.componentName+".List", "error", err, "x_trace_id", traceID)
+			return nil, coreerr.DatabaseQueryError
+		}
+		entity, err := a.toAccountEntity(ctx, &accountModel)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, *entity)
+	}
+	if err = rows.Err(); err != nil {
+		a.log.Warn(a.componentName+".List", "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseQueryError
+	}
+	return accounts, nil
+}
+
+// Block sets the status of an account at expectedVersion to BLOCKED, recording the change in the audit log when it
+// was not blocked yet. The update fails with AccountVersionConflictError when the account is at another version.
+func (a *AccountSQLiteRepository) Block(ctx context.Context, accountID int64, expectedVersion int64) (*account.Account, error) {
+	traceID := contextutils.GetTraceID(ctx)
+	a.log.Debug(a.componentName+".Block", "accountID", accountID, "expectedVersion", expectedVersion, "x_trace_id", traceID)
+	tx, err := beginTx(ctx, a.connectionData.Db, nil)
+	if err != nil {
+		a.log.Warn(a.componentName+".Block", "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseCreateTransactionError
+	}
+	defer tx.Rollback()
+	before, err := a.scanAccount(ctx, tx.QueryRowContext(ctx, "SELECT "+accountColumns+" FROM accounts WHERE account_id = $1", accountID))
+	if err != nil {
+		a.log.Warn(a.componentName+".Block", "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
+	if before.Version != expectedVersion {
+		a.log.Warn(a.componentName+".Block", "error", coreerr.AccountVersionConflictError, "version", before.Version, "x_trace_id", traceID)
+		return nil, coreerr.AccountVersionConflictError
+	}
+	if before.Blocked() {
+		return before, nil
+	}
+	result, err := tx.ExecContext(ctx, "UPDATE accounts SET status = $1, version = version + 1 WHERE account_id = $2 AND version = $3", account.StatusBlocked, accountID, expectedVersion)
+	if err != nil {
+		a.log.Warn(a.componentName+".Block", "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseInsertionError
+	}
+	if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+		a.log.Warn(a.componentName+".Block", "error", coreerr.AccountVersionConflictError, "x_trace_id", traceID)
+		return nil, coreerr.AccountVersionConflictError
+	}
+	after := *before
+	after.Status = account.StatusBlocked
+	after.Version++
+	if err = a.appendAuditEntry(ctx, tx.Tx, audit.ActionAccountBlock, before, &after); err != nil {
+		a.log.Warn(a.componentName+".Block", "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		a.log.Warn(a.componentName+".Block", "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseFailToCommitError
+	}
+	return &after, nil
+}
+
+// scanAccount reads an account row selected with accountColumns, failing with AccountNotFoundError when there is none
+func (a *AccountSQLiteRepository) scanAccount(ctx context.Context, row *sql.Row) (*account.Account, error) {
+	var accountModel model.AccountModel
+	err := row.Scan(&accountModel.AccountID, &accountModel.DocumentType, &accountModel.DocumentNumber, &accountModel.Currency, &accountModel.Status, &accountModel.Version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, coreerr.AccountNotFoundError
+	}
+	if err != nil {
+		return nil, coreerr.DatabaseQueryError
+	}
+	return a.toAccountEntity(ctx, &accountModel)
+}
+
+// toAccountEntity maps a stored account decrypting its document number
+func (a *AccountSQLiteRepository) toAccountEntity(ctx context.Context, accountModel *model.AccountModel) (*account.Account, error) {
+	documentNumber, err := a.fieldCipher.Decrypt(accountModel.DocumentNumber)
+	if err != nil {
+		a.log.Error(a.componentName+".toAccountEntity", "error", err, "accountID", accountModel.AccountID, "x_trace_id", contextutils.GetTraceID(ctx))
+		return nil, err
+	}
+	accountModel.DocumentNumber = documentNumber
+	return mapper.ToAccountEntity(accountModel), nil
+}
+
+// appendAuditEntry records the change of an account in tx, with the document numbers masked in the snapshots
+func (a *AccountSQLiteRepository) appendAuditEntry(ctx context.Context, tx *sql.Tx, action string, before *account.Account, after *account.Account) error {
+	entityID := int64(0)
+	var beforeSnapshot, afterSnapshot any
+	if before != nil {
+		entityID = before.AccountID
+		beforeSnapshot = maskedAccount(before)
+	}
+	if after != nil {
+		entityID = after.AccountID
+		afterSnapshot = maskedAccount(after)
+	}
+	entry, err := newAuditEntry(ctx, a.clock.Now(), action, audit.EntityAccount, entityID, beforeSnapshot, afterSnapshot)
+	if err != nil {
+		return err
+	}
+	return appendSQLiteAuditEntries(ctx, tx, entry)
+}
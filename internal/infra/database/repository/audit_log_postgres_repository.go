@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/adapter"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/contextutils"
+	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/audit"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/database/mapper"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/database/model"
+	"strings"
+	"time"
+)
+
+const (
+	auditLogInsertColumns = "principal, client_ip, trace_id, action, entity_type, entity_id, before_snapshot, after_snapshot, " +
+		"created_at, previous_hash, hash"
+	// auditLogSelectColumns must be kept in the order of auditLogColumns
+	auditLogSelectColumns = "audit_id, " + auditLogInsertColumns
+)
+
+type AuditLogPostgresRepository struct {
+	connectionData *adapter.DatabaseConnectionData
+	componentName  string
+	log            logger.Logger
+}
+
+func NewAuditLogPostgresRepository(connectionData *adapter.DatabaseConnectionData, log logger.Logger) *AuditLogPostgresRepository {
+	repository := &AuditLogPostgresRepository{
+		connectionData: connectionData,
+		log:            log,
+	}
+	repository.componentName = logger.ComponentNameFromStruct(repository)
+	return repository
+}
+
+func (a *AuditLogPostgresRepository) Search(ctx context.Context, filter audit.Filter) ([]*audit.Entry, error) {
+	traceID := contextutils.GetTraceID(ctx)
+	a.log.Debug(a.componentName+".Search", "filter", filter, "x_trace_id", traceID)
+	var conditions []string
+	var args []any
+	addCondition := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	addCondition("audit_id > $%d", filter.Cursor)
+	if filter.EntityType != "" {
+		addCondition("entity_type = $%d", filter.EntityType)
+	}
+	if filter.EntityID > 0 {
+		addCondition("entity_id = $%d", filter.EntityID)
+	}
+	if !filter.From.IsZero() {
+		addCondition("created_at >= $%d", filter.From.UTC())
+	}
+	if !filter.To.IsZero() {
+		addCondition("created_at < $%d", filter.To.UTC())
+	}
+	args = append(args, filter.Limit)
+	query := fmt.Sprintf("SELECT %s FROM audit_log WHERE %s ORDER BY audit_id LIMIT $%d",
+		auditLogSelectColumns, strings.Join(conditions, " AND "), len(args))
+	rows, err := queryerFor(ctx, a.connectionData.Db).QueryContext(ctx, query, args...)
+	if err != nil {
+		a.log.Warn(a.componentName+".Search", "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseQueryError
+	}
+	defer rows.Close()
+	entries := make([]*audit.Entry, 0)
+	for rows.Next() {
+		var auditLogModel model.AuditLogModel
+		if err = rows.Scan(auditLogColumns(&auditLogModel)...); err != nil {
+			a.log.Warn(a.componentName+".Search", "error", err, "x_trace_id", traceID)
+			return nil, coreerr.DatabaseQueryError
+		}
+		entries = append(entries, mapper.ToAuditEntry(&auditLogModel))
+	}
+	if err = rows.Err(); err != nil {
+		a.log.Warn(a.componentName+".Search", "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseQueryError
+	}
+	return entries, nil
+}
+
+// StreamEntries calls fn with every entry ordered by audit ID, read from a single snapshot of the log.
+// Errors returned by fn stop the stream and are returned as is.
+func (a *AuditLogPostgresRepository) StreamEntries(ctx context.Context, fn func(*audit.Entry) error) error {
+	traceID := contextutils.GetTraceID(ctx)
+	a.log.Debug(a.componentName+".StreamEntries", "x_trace_id", traceID)
+	tx, err := beginTx(ctx, a.connectionData.Db, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		a.log.Warn(a.componentName+".StreamEntries", "error", err, "x_trace_id", traceID)
+		return coreerr.DatabaseCreateTransactionError
+	}
+	defer tx.Rollback()
+	query := "SELECT " + auditLogSelectColumns + " FROM audit_log WHERE audit_id > $1 ORDER BY audit_id LIMIT $2"
+	var lastAuditID int64
+	for {
+		entries, err := fetchAuditEntries(ctx, tx.Tx, query, lastAuditID)
+		if err != nil {
+			a.log.Warn(a.componentName+".StreamEntries", "error", err, "x_trace_id", traceID)
+			return err
+		}
+		for _, entry := range entries {
+			if err = fn(entry); err != nil {
+				return err
+			}
+			lastAuditID = entry.AuditID
+		}
+		if len(entries) < streamFetchSize {
+			return nil
+		}
+	}
+}
+
+// fetchAuditEntries returns up to streamFetchSize entries after lastAuditID, selected in tx by query
+func fetchAuditEntries(ctx context.Context, tx *sql.Tx, query string, lastAuditID int64) ([]*audit.Entry, error) {
+	rows, err := tx.QueryContext(ctx, query, lastAuditID, streamFetchSize)
+	if err != nil {
+		return nil, coreerr.DatabaseQueryError
+	}
+	defer rows.Close()
+	entries := make([]*audit.Entry, 0, streamFetchSize)
+	for rows.Next() {
+		var auditLogModel model.AuditLogModel
+		if err = rows.Scan(auditLogColumns(&auditLogModel)...); err != nil {
+			return nil, coreerr.DatabaseQueryError
+		}
+		entries = append(entries, mapper.ToAuditEntry(&auditLogModel))
+	}
+	if err = rows.Err(); err != nil {
+		return nil, coreerr.DatabaseQueryError
+	}
+	return entries, nil
+}
+
+// newAuditEntry describes a change made at createdAt on behalf of the principal, client and trace of the request in ctx
+func newAuditEntry(ctx context.Context, createdAt time.Time, action string, entityType string, entityID int64, before any, after any) (*audit.Entry, error) {
+	beforeSnapshot, err := audit.Snapshot(before)
+	if err != nil {
+		return nil, err
+	}
+	afterSnapshot, err := audit.Snapshot(after)
+	if err != nil {
+		return nil, err
+	}
+	return &audit.Entry{
+		Principal:  contextutils.GetPrincipalSubject(ctx),
+		ClientIP:   contextutils.GetClientIP(ctx),
+		TraceID:    contextutils.GetTraceID(ctx),
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Before:     beforeSnapshot,
+		After:      afterSnapshot,
+		// Postgres keeps microseconds, the hashed time must survive the round trip
+		CreatedAt: createdAt.UTC().Truncate(time.Microsecond),
+	}, nil
+}
+
+// appendAuditEntries chains and inserts the entries in tx, so they are only recorded if the audited change is committed.
+// Writers are serialized by the lock of the head of the chain held until tx ends, keeping the chain in audit ID order.
+// Under the repeatable read and serializable isolation levels, a head moved after the snapshot of tx was taken fails
+// with DatabaseSerializationError instead of forking the chain, and the unit of work runs again.
+func appendAuditEntries(ctx context.Context, tx *sql.Tx, entries ...*audit.Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	var lastHash string
+	if err := tx.QueryRowContext(ctx, "SELECT hash FROM audit_log_head FOR UPDATE").Scan(&lastHash); err != nil {
+		if isSerializationFailure(err) {
+			return coreerr.DatabaseSerializationError
+		}
+		return coreerr.DatabaseQueryError
+	}
+	audit.Chain(lastHash, entries...)
+	stmt, err := tx.PrepareContext(ctx, "INSERT INTO audit_log("+auditLogInsertColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7::json, $8::json, $9, $10, $11) RETURNING audit_id")
+	if err != nil {
+		return coreerr.DatabasePrepareStatementError
+	}
+	defer stmt.Close()
+	for _, entry := range entries {
+		auditLogModel := mapper.ToAuditLogModel(entry)
+		err = stmt.QueryRowContext(ctx,
+			auditLogModel.Principal,
+			auditLogModel.ClientIP,
+			auditLogModel.TraceID,
+			auditLogModel.Action,
+			auditLogModel.EntityType,
+			auditLogModel.EntityID,
+			auditLogModel.Before,
+			auditLogModel.After,
+			auditLogModel.CreatedAt,
+			auditLogModel.PreviousHash,
+			auditLogModel.Hash).Scan(&entry.AuditID)
+		if err != nil {
+			return coreerr.DatabaseInsertionError
+		}
+	}
+	if _, err = tx.ExecContext(ctx, "UPDATE audit_log_head SET hash = $1", entries[len(entries)-1].Hash); err != nil {
+		return coreerr.DatabaseQueryError
+	}
+	return nil
+}
+
+// auditLogColumns returns the scan destinations of auditLogSelectColumns
+func auditLogColumns(auditLogModel *model.AuditLogModel) []any {
+	return []any{
+		&auditLogModel.AuditID,
+		&auditLogModel.Principal,
+		&auditLogModel.ClientIP,
+		&auditLogModel.TraceID,
+		&auditLogModel.Action,
+		&auditLogModel.EntityType,
+		&auditLogModel.EntityID,
+		&auditLogModel.Before,
+		&auditLogModel.After,
+		&auditLogModel.CreatedAt,
+		&auditLogModel.PreviousHash,
+		&auditLogModel.Hash,
+	}
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/adapter"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/contextutils"
+	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/audit"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/database/mapper"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/database/model"
+	"strings"
+)
+
+type AuditLogSQLiteRepository struct {
+	connectionData *adapter.DatabaseConnectionData
+	componentName  string
+	log            logger.Logger
+}
+
+func NewAuditLogSQLiteRepository(connectionData *adapter.DatabaseConnectionData, log logger.Logger) *AuditLogSQLiteRepository {
+	repository := &AuditLogSQLiteRepository{
+		connectionData: connectionData,
+		log:            log,
+	}
+	repository.componentName = logger.ComponentNameFromStruct(repository)
+	return repository
+}
+
+func (a *AuditLogSQLiteRepository) Search(ctx context.Context, filter audit.Filter) ([]*audit.Entry, error) {
+	traceID := contextutils.GetTraceID(ctx)
+	a.log.Debug(a.componentName+".Search", "filter", filter, "x_trace_id", traceID)
+	var conditions []string
+	var args []any
+	addCondition := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	addCondition("audit_id > $%d", filter.Cursor)
+	if filter.EntityType != "" {
+		addCondition("entity_type = $%d", filter.EntityType)
+	}
+	if filter.EntityID > 0 {
+		addCondition("entity_id = $%d", filter.EntityID)
+	}
+	if !filter.From.IsZero() {
+		addCondition("created_at >= $%d", sqliteTimestamp(filter.From))
+	}
+	if !filter.To.IsZero() {
+		addCondition("created_at < $%d", sqliteTimestamp(filter.To))
+	}
+	args = append(args, filter.Limit)
+	query := fmt.Sprintf("SELECT %s FROM audit_log WHERE %s ORDER BY audit_id LIMIT $%d",
+		auditLogSelectColumns, strings.Join(conditions, " AND "), len(args))
+	rows, err := queryerFor(ctx, a.connectionData.Db).QueryContext(ctx, query, args...)
+	if err != nil {
+		a.log.Warn(a.componentName+".Search", "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseQueryError
+	}
+	defer rows.Close()
+	entries := make([]*audit.Entry, 0)
+	for rows.Next() {
+		var auditLogModel model.AuditLogModel
+		if err = rows.Scan(auditLogColumns(&auditLogModel)...); err != nil {
+			a.log.Warn(a.componentName+".Search", "error", err, "x_trace_id", traceID)
+			return nil, coreerr.DatabaseQueryError
+		}
+		entries = append(entries, mapper.ToAuditEntry(&auditLogModel))
+	}
+	if err = rows.Err(); err != nil {
+		a.log.Warn(a.componentName+".Search", "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseQueryError
+	}
+	return entries, nil
+}
+
+// StreamEntries calls fn with every entry ordered by audit ID, read in one database transaction, which sees a single
+// snapshot of the log in SQLite. Errors returned by fn stop the stream and are returned as is.
+func (a *AuditLogSQLiteRepository) StreamEntries(ctx context.Context, fn func(*audit.Entry) error) error {
+	traceID := contextutils.GetTraceID(ctx)
+	a.log.Debug(a.componentName+".StreamEntries", "x_trace_id", traceID)
+	tx, err := beginTx(ctx, a.connectionData.Db, nil)
+	if err != nil {
+		a.log.Warn(a.componentName+".StreamEntries", "error", err, "x_trace_id", traceID)
+		return coreerr.DatabaseCreateTransactionError
+	}
+	defer tx.Rollback()
+	query := "SELECT " + auditLogSelectColumns + " FROM audit_log WHERE audit_id > $1 ORDER BY audit_id LIMIT $2"
+	var lastAuditID int64
+	for {
+		entries, err := fetchAuditEntries(ctx, tx.Tx, query, lastAuditID)
+		if err != nil {
+			a.log.Warn(a.componentName+".StreamEntries", "error", err, "x_trace_id", traceID)
+			return err
+		}
+		for _, entry := range entries {
+			if err = fn(entry); err != nil {
+				return err
+			}
+			lastAuditID = entry.AuditID
+		}
+		if len(entries) < streamFetchSize {
+			return nil
+		}
+	}
+}
+
+// appendSQLiteAuditEntries chains and inserts the entries in tx, so they are only recorded if the audited change is
+// committed. SQLite has a single writer, so the last hash can not change until tx ends.
+func appendSQLiteAuditEntries(ctx context.Context, tx *sql.Tx, entries ...*audit.Entry) error {
+	lastHash := audit.GenesisHash
+	err := tx.QueryRowContext(ctx, "SELECT hash FROM audit_log ORDER BY audit_id DESC LIMIT 1").Scan(&lastHash)
+	if err != nil && err != sql.ErrNoRows {
+		return coreerr.DatabaseQueryError
+	}
+	audit.Chain(lastHash, entries...)
+	stmt, err := tx.PrepareContext(ctx, "INSERT INTO audit_log("+auditLogInsertColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING audit_id")
+	if err != nil {
+		return coreerr.DatabasePrepareStatementError
+	}
+	defer stmt.Close()
+	for _, entry := range entries {
+		auditLogModel := mapper.ToAuditLogModel(entry)
+		err = stmt.QueryRowContext(ctx,
+			auditLogModel.Principal,
+			auditLogModel.ClientIP,
+			auditLogModel.TraceID,
+			auditLogModel.Action,
+			auditLogModel.EntityType,
+			auditLogModel.EntityID,
+			auditLogModel.Before,
+			auditLogModel.After,
+			sqliteTimestamp(auditLogModel.CreatedAt),
+			auditLogModel.PreviousHash,
+			auditLogModel.Hash).Scan(&entry.AuditID)
+		if err != nil {
+			return coreerr.DatabaseInsertionError
+		}
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/adapter"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/clock"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/contextutils"
+	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/pii"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/audit"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/holder"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/database/mapper"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/database/model"
+	"strings"
+	"time"
+)
+
+// HolderSQLiteRepository stores the personal values of the holders and their history snapshots encrypted
+type HolderSQLiteRepository struct {
+	connectionData *adapter.DatabaseConnectionData
+	fieldCipher    pii.FieldCipher
+	clock          clock.Clock
+	componentName  string
+	log            logger.Logger
+}
+
+func NewHolderSQLiteRepository(connectionData *adapter.DatabaseConnectionData, fieldCipher pii.FieldCipher, clock clock.Clock, log logger.Logger) *HolderSQLiteRepository {
+	repository := &HolderSQLiteRepository{
+		connectionData: connectionData,
+		fieldCipher:    fieldCipher,
+		clock:          clock,
+		log:            log,
+	}
+	repository.componentName = logger.ComponentNameFromStruct(repository)
+	return repository
+}
+
+func (h *HolderSQLiteRepository) FindByAccountID(ctx context.Context, accountID int64) (*holder.Holder, error) {
+	traceID := contextutils.GetTraceID(ctx)
+	h.log.Debug(h.componentName+".FindByAccountID", "accountID", accountID, "x_trace_id", traceID)
+	row := queryerFor(ctx, h.connectionData.Db).QueryRowContext(ctx, "SELECT "+holderColumns+" FROM account_holders WHERE account_id = $1", accountID)
+	found, err := scanHolder(h.fieldCipher, row)
+	if err != nil {
+		h.log.Warn(h.componentName+".FindByAccountID", "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
+	return found, nil
+}
+
+// Save inserts the first version of a holder or updates the row at expectedVersion, appending the history and audit
+// entries in the same database transaction. SQLite has a single writer, so the row can not change until it ends.
+func (h *HolderSQLiteRepository) Save(ctx context.Context, changed *holder.Holder, expectedVersion int64, changedFields []string) (*holder.Holder, error) {
+	traceID := contextutils.GetTraceID(ctx)
+	h.log.Debug(h.componentName+".Save", "holder", changed, "expectedVersion", expectedVersion, "x_trace_id", traceID)
+	if changed == nil {
+		return nil, coreerr.InvalidParametersError
+	}
+	tx, err := beginTx(ctx, h.connectionData.Db, nil)
+	if err != nil {
+		h.log.Warn(h.componentName+".Save", "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseCreateTransactionError
+	}
+	defer tx.Rollback()
+	var before *holder.Holder
+	if expectedVersion > 0 {
+		row := tx.QueryRowContext(ctx, "SELECT "+holderColumns+" FROM account_holders WHERE account_id = $1", changed.AccountID)
+		if before, err = scanHolder(h.fieldCipher, row); err != nil && err != coreerr.AccountHolderNotFoundError {
+			h.log.Warn(h.componentName+".Save", "error", err, "x_trace_id", traceID)
+			return nil, err
+		}
+		if before == nil || before.Version != expectedVersion {
+			h.log.Warn(h.componentName+".Save", "error", coreerr.AccountHolderVersionMismatchError, "x_trace_id", traceID)
+			return nil, coreerr.AccountHolderVersionMismatchError
+		}
+	}
+	saved := *changed
+	saved.Version = expectedVersion + 1
+	// Timestamps are stored with microseconds, the history snapshot must match the stored row
+	saved.UpdatedAt = changed.UpdatedAt.UTC().Truncate(time.Microsecond)
+	holderModel := mapper.ToHolderModel(&saved)
+	if err = encryptHolder(h.fieldCipher, holderModel); err != nil {
+		h.log.Warn(h.componentName+".Save", "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
+	values := []any{holderModel.AccountID, holderModel.Name, holderModel.Email, holderModel.Phone, holderModel.BirthDate,
+		holderModel.AddressLine1, holderModel.AddressLine2, holderModel.City, holderModel.State, holderModel.PostalCode,
+		holderModel.Country, holderModel.Version, sqliteTimestamp(holderModel.UpdatedAt)}
+	var result sql.Result
+	if expectedVersion == 0 {
+		// Concurrent first changes insert the same account ID, only one of them succeeds
+		result, err = tx.ExecContext(ctx, "INSERT INTO account_holders("+holderColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) ON CONFLICT (account_id) DO NOTHING", values...)
+	} else {
+		result, err = tx.ExecContext(ctx, "UPDATE account_holders SET name = $2, email = $3, phone = $4, birth_date = $5, address_line1 = $6, address_line2 = $7, city = $8, state = $9, postal_code = $10, country = $11, version = $12, updated_at = $13 WHERE account_id = $1", values...)
+	}
+	if err != nil {
+		h.log.Warn(h.componentName+".Save", "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseInsertionError
+	}
+	if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+		h.log.Warn(h.componentName+".Save", "error", coreerr.AccountHolderVersionMismatchError, "x_trace_id", traceID)
+		return nil, coreerr.AccountHolderVersionMismatchError
+	}
+	if err = h.appendHistory(ctx, tx.Tx, &saved, changedFields); err != nil {
+		h.log.Warn(h.componentName+".Save", "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
+	if err = h.appendAuditEntry(ctx, tx.Tx, before, &saved); err != nil {
+		h.log.Warn(h.componentName+".Save", "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		h.log.Warn(h.componentName+".Save", "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseFailToCommitError
+	}
+	return &saved, nil
+}
+
+func (h *HolderSQLiteRepository) History(ctx context.Context, accountID int64) ([]holder.Change, error) {
+	traceID := contextutils.GetTraceID(ctx)
+	h.log.Debug(h.componentName+".History", "accountID", accountID, "x_trace_id", traceID)
+	rows, err := queryerFor(ctx, h.connectionData.Db).QueryContext(ctx, "SELECT history_id, account_id, version, changed_fields, principal, trace_id, snapshot, changed_at FROM account_holder_history WHERE account_id = $1 ORDER BY version DESC", accountID)
+	if err != nil {
+		h.log.Warn(h.componentName+".History", "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseQueryError
+	}
+	defer rows.Close()
+	changes := []holder.Change{}
+	for rows.Next() {
+		var historyModel model.HolderHistoryModel
+		err = rows.Scan(&historyModel.HistoryID, &historyModel.AccountID, &historyModel.Version, &historyModel.ChangedFields,
+			&historyModel.Principal, &historyModel.TraceID, &historyModel.Snapshot, &historyModel.ChangedAt)
+		if err != nil {
+			h.log.Warn(h.componentName+".History", "error", err, "x_trace_id", traceID)
+			return nil, coreerr.DatabaseQueryError
+		}
+		snapshot, err := h.fieldCipher.Decrypt(historyModel.Snapshot)
+		if err != nil {
+			h.log.Error(h.componentName+".History", "error", err, "accountID", accountID, "x_trace_id", traceID)
+			return nil, err
+		}
+		var after holder.Holder
+		if err = json.Unmarshal([]byte(snapshot), &after); err != nil {
+			h.log.Warn(h.componentName+".History", "error", err, "x_trace_id", traceID)
+			return nil, coreerr.DatabaseQueryError
+		}
+		changes = append(changes, mapper.ToHolderChange(&historyModel, after))
+	}
+	if err = rows.Err(); err != nil {
+		h.log.Warn(h.componentName+".History", "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseQueryError
+	}
+	return changes, nil
+}
+
+// appendHistory records the new version of a holder in tx, with its encrypted snapshot
+func (h *HolderSQLiteRepository) appendHistory(ctx context.Context, tx *sql.Tx, saved *holder.Holder, changedFields []string) error {
+	encryptedSnapshot, err := encryptedHolderSnapshot(h.fieldCipher, saved)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "INSERT INTO account_holder_history(account_id, version, changed_fields, principal, trace_id, snapshot, changed_at) VALUES ($1, $2, $3, $4, $5, $6, $7)",
+		saved.AccountID, saved.Version, strings.Join(changedFields, ","), contextutils.GetPrincipalSubject(ctx),
+		contextutils.GetTraceID(ctx), encryptedSnapshot, sqliteTimestamp(saved.UpdatedAt))
+	if err != nil {
+		return coreerr.DatabaseInsertionError
+	}
+	return nil
+}
+
+// appendAuditEntry records the change of a holder in tx, with the personal values masked in the snapshots
+func (h *HolderSQLiteRepository) appendAuditEntry(ctx context.Context, tx *sql.Tx, before *holder.Holder, after *holder.Holder) error {
+	var beforeSnapshot any
+	if before != nil {
+		beforeSnapshot = before.Masked()
+	}
+	entry, err := newAuditEntry(ctx, h.clock.Now(), audit.ActionHolderUpdate, audit.EntityAccountHolder, after.AccountID, beforeSnapshot, after.Masked())
+	if err != nil {
+		return err
+	}
+	return appendSQLiteAuditEntries(ctx, tx, entry)
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/adapter"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/contextutils"
+	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/ledger"
+)
+
+// invariantSampleSize is the maximum number of transaction IDs listed for each violated invariant
+const invariantSampleSize = 100
+
+type LedgerPostgresRepository struct {
+	connectionData *adapter.DatabaseConnectionData
+	componentName  string
+	log            logger.Logger
+}
+
+func NewLedgerPostgresRepository(connectionData *adapter.DatabaseConnectionData, log logger.Logger) *LedgerPostgresRepository {
+	repository := &LedgerPostgresRepository{
+		connectionData: connectionData,
+		log:            log,
+	}
+	repository.componentName = logger.ComponentNameFromStruct(repository)
+	return repository
+}
+
+// CheckInvariants sums the postings of every currency and lists the transactions, up to invariantSampleSize of each,
+// whose postings are not balanced or that were posted without ledger entries. Every query reads the same snapshot of the ledger.
+func (l *LedgerPostgresRepository) CheckInvariants(ctx context.Context) (*ledger.InvariantReport, error) {
+	traceID := contextutils.GetTraceID(ctx)
+	l.log.Debug(l.componentName+".CheckInvariants", "x_trace_id", traceID)
+	tx, err := beginTx(ctx, l.connectionData.Db, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		l.log.Warn(l.componentName+".CheckInvariants", "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseCreateTransactionError
+	}
+	defer tx.Rollback()
+	report := &ledger.InvariantReport{}
+	report.Totals, err = currencyTotals(ctx, tx.Tx, "SELECT currency, "+
+		"coalesce(sum(amount) FILTER (WHERE direction = 'DEBIT'), 0), coalesce(sum(amount) FILTER (WHERE direction = 'CREDIT'), 0) "+
+		"FROM ledger_entries GROUP BY currency ORDER BY currency")
+	if err != nil {
+		l.log.Warn(l.componentName+".CheckInvariants", "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseQueryError
+	}
+	report.UnbalancedTransactionIDs, err = transactionIDs(ctx, tx.Tx,
+		"SELECT transaction_id FROM ledger_entries GROUP BY transaction_id "+
+			"HAVING sum(CASE WHEN direction = 'DEBIT' THEN amount ELSE -amount END) <> 0 ORDER BY transaction_id LIMIT $1")
+	if err != nil {
+		l.log.Warn(l.componentName+".CheckInvariants", "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseQueryError
+	}
+	report.UnpostedTransactionIDs, err = transactionIDs(ctx, tx.Tx,
+		"SELECT transaction_id FROM transactions t WHERE status = 'POSTED' AND NOT EXISTS "+
+			"(SELECT 1 FROM ledger_entries e WHERE e.transaction_id = t.transaction_id) ORDER BY transaction_id LIMIT $1")
+	if err != nil {
+		l.log.Warn(l.componentName+".CheckInvariants", "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseQueryError
+	}
+	return report, nil
+}
+
+// currencyTotals reads the debits and credits of every currency selected by query
+func currencyTotals(ctx context.Context, tx *sql.Tx, query string) ([]ledger.CurrencyTotals, error) {
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var totals []ledger.CurrencyTotals
+	for rows.Next() {
+		var currencyTotals ledger.CurrencyTotals
+		if err = rows.Scan(&currencyTotals.Currency, &currencyTotals.Debits, &currencyTotals.Credits); err != nil {
+			return nil, err
+		}
+		totals = append(totals, currencyTotals)
+	}
+	return totals, rows.Err()
+}
+
+// transactionIDs reads up to invariantSampleSize transaction IDs selected by query, which takes the limit as $1
+func transactionIDs(ctx context.Context, tx *sql.Tx, query string) ([]int64, error) {
+	rows, err := tx.QueryContext(ctx, query, invariantSampleSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var transactionIDs []int64
+	for rows.Next() {
+		var transactionID int64
+		if err = rows.Scan(&transactionID); err != nil {
+			return nil, err
+		}
+		transactionIDs = append(transactionIDs, transactionID)
+	}
+	return transactionIDs, rows.Err()
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/adapter"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/contextutils"
+	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/ledger"
+)
+
+type LedgerSQLiteRepository struct {
+	connectionData *adapter.DatabaseConnectionData
+	componentName  string
+	log            logger.Logger
+}
+
+func NewLedgerSQLiteRepository(connectionData *adapter.DatabaseConnectionData, log logger.Logger) *LedgerSQLiteRepository {
+	repository := &LedgerSQLiteRepository{
+		connectionData: connectionData,
+		log:            log,
+	}
+	repository.componentName = logger.ComponentNameFromStruct(repository)
+	return repository
+}
+
+// CheckInvariants sums the postings of every currency and lists the transactions, up to invariantSampleSize of each,
+// whose postings are not balanced or that were posted without ledger entries. Every query runs in one database
+// transaction, which sees a single snapshot of the ledger in SQLite. Amounts are stored as floating point numbers,
+// so the sums are rounded to cents.
+func (l *LedgerSQLiteRepository) CheckInvariants(ctx context.Context) (*ledger.InvariantReport, error) {
+	traceID := contextutils.GetTraceID(ctx)
+	l.log.Debug(l.componentName+".CheckInvariants", "x_trace_id", traceID)
+	tx, err := beginTx(ctx, l.connectionData.Db, nil)
+	if err != nil {
+		l.log.Warn(l.componentName+".CheckInvariants", "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseCreateTransactionError
+	}
+	defer tx.Rollback()
+	report := &ledger.InvariantReport{}
+	report.Totals, err = currencyTotals(ctx, tx.Tx, "SELECT currency, "+
+		"round(coalesce(sum(amount) FILTER (WHERE direction = 'DEBIT'), 0), 2), round(coalesce(sum(amount) FILTER (WHERE direction = 'CREDIT'), 0), 2) "+
+		"FROM ledger_entries GROUP BY currency ORDER BY currency")
+	if err != nil {
+		l.log.Warn(l.componentName+".CheckInvariants", "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseQueryError
+	}
+	report.UnbalancedTransactionIDs, err = transactionIDs(ctx, tx.Tx,
+		"SELECT transaction_id FROM ledger_entries GROUP BY transaction_id "+
+			"HAVING round(sum(CASE WHEN direction = 'DEBIT' THEN amount ELSE -amount END), 2) <> 0 ORDER BY transaction_id LIMIT $1")
+	if err != nil {
+		l.log.Warn(l.componentName+".CheckInvariants", "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseQueryError
+	}
+	report.UnpostedTransactionIDs, err = transactionIDs(ctx, tx.Tx,
+		"SELECT transaction_id FROM transactions t WHERE status = 'POSTED' AND NOT EXISTS "+
+			"(SELECT 1 FROM ledger_entries e WHERE e.transaction_id = t.transaction_id) ORDER BY transaction_id LIMIT $1")
+	if err != nil {
+		l.log.Warn(l.componentName+".CheckInvariants", "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseQueryError
+	}
+	return report, nil
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"errors"
+	"github.com/lib/pq"
+)
+
+const (
+	// uniqueViolation is the Postgres error code of a row violating a unique constraint or index
+	uniqueViolation = "23505"
+	// serializationFailure is the Postgres error code of a transaction conflicting with a concurrent one under the
+	// repeatable read or serializable isolation levels, which may succeed when run again
+	serializationFailure = "40001"
+	// accountDocumentNumberIndexKey is the unique index of the document numbers of the accounts, by their blind index
+	accountDocumentNumberIndexKey = "accounts_document_number_index_key"
+)
+
+// isUniqueViolation reports whether err was raised by a row violating the unique constraint or index named constraint
+func isUniqueViolation(err error, constraint string) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolation && pqErr.Constraint == constraint
+}
+
+// isSerializationFailure reports whether err was raised by a transaction that must be run again
+func isSerializationFailure(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == serializationFailure
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/adapter"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/contextutils"
+	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
+)
+
+// maxUnitOfWorkAttempts bounds the runs of a unit of work conflicting with concurrent ones
+const maxUnitOfWorkAttempts = 3
+
+// PostgresUnitOfWork runs the calls of the Postgres repositories made with the context of Do in one database transaction
+type PostgresUnitOfWork struct {
+	connectionData *adapter.DatabaseConnectionData
+	componentName  string
+	log            logger.Logger
+}
+
+func NewPostgresUnitOfWork(connectionData *adapter.DatabaseConnectionData, log logger.Logger) *PostgresUnitOfWork {
+	unitOfWork := &PostgresUnitOfWork{
+		connectionData: connectionData,
+		log:            log,
+	}
+	unitOfWork.componentName = logger.ComponentNameFromStruct(unitOfWork)
+	return unitOfWork
+}
+
+// Do runs fn again, in a new transaction, when the transaction conflicted with a concurrent one under the repeatable
+// read or serializable isolation levels, up to maxUnitOfWorkAttempts times
+func (p *PostgresUnitOfWork) Do(ctx context.Context, isolation sql.IsolationLevel, fn func(ctx context.Context) error) error {
+	if adapter.TxFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+	traceID := contextutils.GetTraceID(ctx)
+	var err error
+	for attempt := 1; attempt <= maxUnitOfWorkAttempts; attempt++ {
+		p.log.Debug(p.componentName+".Do", "isolation", isolation.String(), "attempt", attempt, "x_trace_id", traceID)
+		if err = p.run(ctx, isolation, fn); !errors.Is(err, coreerr.DatabaseSerializationError) {
+			return err
+		}
+		p.log.Warn(p.componentName+".Do", "error", err, "attempt", attempt, "x_trace_id", traceID)
+	}
+	return err
+}
+
+// run runs fn once in a transaction at isolation
+func (p *PostgresUnitOfWork) run(ctx context.Context, isolation sql.IsolationLevel, fn func(ctx context.Context) error) error {
+	traceID := contextutils.GetTraceID(ctx)
+	tx, err := p.connectionData.Db.BeginTx(ctx, &sql.TxOptions{Isolation: isolation})
+	if err != nil {
+		p.log.Warn(p.componentName+".Do", "error", err, "x_trace_id", traceID)
+		return coreerr.DatabaseCreateTransactionError
+	}
+	defer tx.Rollback()
+	if err = fn(adapter.ContextWithTx(ctx, tx)); err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		p.log.Warn(p.componentName+".Do", "error", err, "x_trace_id", traceID)
+		if isSerializationFailure(err) {
+			return coreerr.DatabaseSerializationError
+		}
+		return coreerr.DatabaseFailToCommitError
+	}
+	return nil
+}
@@ -60,6 +60,23 @@ func (r *RedisRepository) Del(ctx context.Context, key string) error {
 	return nil
 }
 
+// Purge Remove the keys matching a glob pattern, returning how many were removed
+func (r *RedisRepository) Purge(ctx context.Context, pattern string) (int64, error) {
+	var removed int64
+	iterator := r.cacheConnectionData.Rdb.Scan(ctx, 0, pattern, 100).Iterator()
+	for iterator.Next(ctx) {
+		count, err := r.cacheConnectionData.Rdb.Del(ctx, iterator.Val()).Result()
+		if err != nil {
+			return removed, errors.CacheFailedToDeleteError
+		}
+		removed += count
+	}
+	if err := iterator.Err(); err != nil {
+		return removed, errors.CacheFailedToDeleteError
+	}
+	return removed, nil
+}
+
 // Exists Check if key exists
 func (r *RedisRepository) Exists(ctx context.Context, key string) (bool, error) {
 	_, err := r.cacheConnectionData.Rdb.Exists(ctx, key).Result()
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/adapter"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/cache"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/logger/mock"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisRepositoryPurge(t *testing.T) {
+	server := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	repository := NewRedisRepository(&adapter.CacheConnectionData{Rdb: rdb}, mock.NewMockLogger())
+	ctx := context.Background()
+	for _, key := range []string{"cache:account:1", "cache:account:2", "cache:transaction:1", "lock-transaction-creation"} {
+		require.NoError(t, server.Set(key, "{}"))
+	}
+
+	removed, err := repository.Purge(ctx, cache.KeyPattern(cache.AccountEntity, 2))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), removed, "an entity key should remove a single entry")
+	assert.False(t, server.Exists("cache:account:2"))
+
+	removed, err = repository.Purge(ctx, cache.KeyPattern("", 0))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), removed, "every cached entity should be removed")
+	assert.True(t, server.Exists("lock-transaction-creation"), "keys other than the cache should be kept")
+}
@@ -0,0 +1,539 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kiosanim/pismo-code-assessment/internal/core/adapter"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/clock"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/config"
+	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/account"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/audit"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/ledger"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/transaction"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/database/connection"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/database/migrations"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/logger/mock"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/pii"
+	_ "github.com/lib/pq"
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testDatabaseURLEnv names the variable with the URL of a Postgres database the contract tests migrate and write
+// to, the Postgres backend is skipped when it is not set
+const testDatabaseURLEnv = "TEST_DATABASE_URL"
+
+// testClockStart is the initial time of the clocks of the repositories, with the microseconds both backends keep
+var testClockStart = time.Date(2026, time.January, 2, 3, 4, 5, 678901000, time.UTC)
+
+// repositories are the implementations of a backend under test
+type repositories struct {
+	clock        *clock.FakeClock // Time of the audit entries
+	accounts     account.AccountRepository
+	auditLog     audit.AuditLogRepository
+	transactions transaction.TransactionRepository
+	unitOfWork   adapter.UnitOfWork
+}
+
+// backend opens the repositories of a migrated database
+type backend struct {
+	name string
+	open func(t *testing.T) repositories
+}
+
+func backends() []backend {
+	return []backend{
+		{name: config.DatabaseDriverSQLite, open: openSQLite},
+		{name: config.DatabaseDriverPostgres, open: openPostgres},
+	}
+}
+
+func openSQLite(t *testing.T) repositories {
+	db, err := connection.OpenSQLite(":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	migrate(t, db, goose.DialectSQLite3, migrations.SQLiteFS)
+	connectionData := &adapter.DatabaseConnectionData{Db: db}
+	clk := clock.NewFakeClock(testClockStart)
+	return repositories{
+		clock:        clk,
+		accounts:     NewAccountSQLiteRepository(connectionData, newTestFieldCipher(t), clk, mock.NewMockLogger()),
+		transactions: NewTransactionSQLiteRepository(connectionData, clk, mock.NewMockLogger()),
+		auditLog:     NewAuditLogSQLiteRepository(connectionData, mock.NewMockLogger()),
+		unitOfWork:   NewSQLiteUnitOfWork(connectionData, mock.NewMockLogger()),
+	}
+}
+
+func openPostgres(t *testing.T) repositories {
+	url := os.Getenv(testDatabaseURLEnv)
+	if url == "" {
+		t.Skipf("%s is not set", testDatabaseURLEnv)
+	}
+	db, err := sql.Open("postgres", url)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	migrate(t, db, goose.DialectPostgres, migrations.FS)
+	connectionData := &adapter.DatabaseConnectionData{Db: db}
+	clk := clock.NewFakeClock(testClockStart)
+	return repositories{
+		clock:        clk,
+		accounts:     NewAccountPostgresRepository(connectionData, newTestFieldCipher(t), clk, mock.NewMockLogger()),
+		transactions: NewTransactionPostgresRepository(connectionData, clk, mock.NewMockLogger()),
+		auditLog:     NewAuditLogPostgresRepository(connectionData, mock.NewMockLogger()),
+		unitOfWork:   NewPostgresUnitOfWork(connectionData, mock.NewMockLogger()),
+	}
+}
+
+func migrate(t *testing.T, db *sql.DB, dialect goose.Dialect, fsys fs.FS) {
+	provider, err := goose.NewProvider(dialect, db, fsys)
+	require.NoError(t, err)
+	_, err = provider.Up(context.Background())
+	require.NoError(t, err)
+}
+
+func newTestFieldCipher(t *testing.T) *pii.AESFieldCipher {
+	fieldCipher, err := pii.NewAESFieldCipher(config.PIIConfig{
+		EncryptionKey: base64.StdEncoding.EncodeToString([]byte(strings.Repeat("e", 32))),
+		BlindIndexKey: base64.StdEncoding.EncodeToString([]byte(strings.Repeat("b", 32))),
+	})
+	require.NoError(t, err)
+	return fieldCipher
+}
+
+var documentSequence atomic.Int64
+
+// uniqueDocumentNumber returns a document number no other test run saved, the Postgres database may be reused
+func uniqueDocumentNumber() string {
+	return fmt.Sprintf("CT%d%04d", time.Now().UnixNano(), documentSequence.Add(1))
+}
+
+func saveTestAccount(t *testing.T, accounts account.AccountRepository) *account.Account {
+	saved, err := accounts.Save(context.Background(), &account.Account{
+		DocumentType:   account.DocumentTypePassport,
+		DocumentNumber: uniqueDocumentNumber(),
+		Currency:       "BRL",
+	})
+	require.NoError(t, err)
+	return saved
+}
+
+func TestAccountRepositoryContract(t *testing.T) {
+	for _, b := range backends() {
+		t.Run(b.name, func(t *testing.T) {
+			accounts := b.open(t).accounts
+			ctx := context.Background()
+
+			documentNumber := uniqueDocumentNumber()
+			saved, err := accounts.Save(ctx, &account.Account{DocumentType: account.DocumentTypePassport, DocumentNumber: documentNumber, Currency: "BRL"})
+			require.NoError(t, err)
+			assert.Positive(t, saved.AccountID)
+			assert.Equal(t, documentNumber, saved.DocumentNumber, "the saved account should hold the document number in clear text")
+			assert.Equal(t, account.StatusActive, saved.Status)
+			assert.Equal(t, int64(1), saved.Version)
+
+			found, err := accounts.FindByID(ctx, saved.AccountID)
+			require.NoError(t, err)
+			assert.Equal(t, saved, found)
+			found, err = accounts.FindByDocumentNumber(ctx, documentNumber)
+			require.NoError(t, err)
+			assert.Equal(t, saved, found)
+
+			_, err = accounts.Save(ctx, &account.Account{DocumentType: account.DocumentTypePassport, DocumentNumber: documentNumber, Currency: "BRL"})
+			assert.ErrorIs(t, err, coreerr.AccountAlreadyExistsForDocumentNumberError)
+			_, err = accounts.FindByID(ctx, math.MaxInt64)
+			assert.ErrorIs(t, err, coreerr.AccountNotFoundError)
+			_, err = accounts.FindByDocumentNumber(ctx, uniqueDocumentNumber())
+			assert.ErrorIs(t, err, coreerr.AccountNotFoundError)
+
+			next := saveTestAccount(t, accounts)
+			page, err := accounts.List(ctx, 1, saved.AccountID-1)
+			require.NoError(t, err)
+			require.Len(t, page, 1)
+			assert.Equal(t, *saved, page[0])
+			page, err = accounts.List(ctx, 1, saved.AccountID)
+			require.NoError(t, err)
+			require.Len(t, page, 1)
+			assert.Equal(t, *next, page[0], "the page should start after the cursor")
+
+			_, err = accounts.Block(ctx, saved.AccountID, saved.Version+1)
+			assert.ErrorIs(t, err, coreerr.AccountVersionConflictError)
+			blocked, err := accounts.Block(ctx, saved.AccountID, saved.Version)
+			require.NoError(t, err)
+			assert.Equal(t, account.StatusBlocked, blocked.Status)
+			assert.Equal(t, saved.Version+1, blocked.Version)
+			found, err = accounts.FindByID(ctx, saved.AccountID)
+			require.NoError(t, err)
+			assert.Equal(t, blocked, found)
+			_, err = accounts.Block(ctx, math.MaxInt64, 1)
+			assert.ErrorIs(t, err, coreerr.AccountNotFoundError)
+		})
+	}
+}
+
+func TestAuditLogContract_CreatedAt(t *testing.T) {
+	for _, b := range backends() {
+		t.Run(b.name, func(t *testing.T) {
+			repos := b.open(t)
+			ctx := context.Background()
+
+			saved := saveTestAccount(t, repos.accounts)
+			repos.clock.Advance(time.Hour)
+			_, err := repos.accounts.Block(ctx, saved.AccountID, saved.Version)
+			require.NoError(t, err)
+
+			entries, err := repos.auditLog.Search(ctx, audit.Filter{EntityType: audit.EntityAccount, EntityID: saved.AccountID, Limit: 10})
+			require.NoError(t, err)
+			require.Len(t, entries, 2)
+			assert.Equal(t, audit.ActionAccountCreate, entries[0].Action)
+			assert.True(t, testClockStart.Equal(entries[0].CreatedAt), "the entry should be created at the time of the clock")
+			assert.Equal(t, audit.ActionAccountBlock, entries[1].Action)
+			assert.True(t, testClockStart.Add(time.Hour).Equal(entries[1].CreatedAt), "the entry should be created at the time of the clock")
+			for _, entry := range entries {
+				assert.Equal(t, audit.ComputeHash(entry), entry.Hash, "the stored time should be the hashed one")
+			}
+		})
+	}
+}
+
+// TestAuditLogContract_ConcurrentRepeatableRead runs two units of work taking their snapshots before either appends
+// its entry, the entry appended last must be chained to the other one
+func TestAuditLogContract_ConcurrentRepeatableRead(t *testing.T) {
+	for _, b := range backends() {
+		t.Run(b.name, func(t *testing.T) {
+			repos := b.open(t)
+			existing := saveTestAccount(t, repos.accounts)
+
+			var snapshots sync.WaitGroup
+			snapshots.Add(2)
+			bothSnapshotsTaken := make(chan struct{})
+			go func() {
+				snapshots.Wait()
+				close(bothSnapshotsTaken)
+			}()
+			var attempts atomic.Int64
+			createAccount := func() (*account.Account, error) {
+				var created *account.Account
+				var once sync.Once
+				err := repos.unitOfWork.Do(context.Background(), sql.LevelRepeatableRead, func(ctx context.Context) error {
+					attempts.Add(1)
+					if _, err := repos.accounts.FindByID(ctx, existing.AccountID); err != nil {
+						return err
+					}
+					once.Do(func() {
+						snapshots.Done()
+						// SQLite runs one unit of work at a time, the other one has not begun
+						select {
+						case <-bothSnapshotsTaken:
+						case <-time.After(300 * time.Millisecond):
+						}
+					})
+					var err error
+					created, err = repos.accounts.Save(ctx, &account.Account{
+						DocumentType:   account.DocumentTypePassport,
+						DocumentNumber: uniqueDocumentNumber(),
+						Currency:       "BRL",
+					})
+					return err
+				})
+				return created, err
+			}
+			results := make(chan *account.Account, 2)
+			errs := make(chan error, 2)
+			for range 2 {
+				go func() {
+					created, err := createAccount()
+					results <- created
+					errs <- err
+				}()
+			}
+			require.NoError(t, <-errs)
+			require.NoError(t, <-errs)
+			first, second := <-results, <-results
+
+			ctx := context.Background()
+			var entries []*audit.Entry
+			for _, created := range []*account.Account{first, second} {
+				found, err := repos.auditLog.Search(ctx, audit.Filter{EntityType: audit.EntityAccount, EntityID: created.AccountID, Limit: 10})
+				require.NoError(t, err)
+				require.Len(t, found, 1)
+				entries = append(entries, found[0])
+			}
+			if entries[0].AuditID > entries[1].AuditID {
+				entries[0], entries[1] = entries[1], entries[0]
+			}
+			assert.Equal(t, entries[0].Hash, entries[1].PreviousHash, "the last entry should be chained to the other one")
+			if b.name == config.DatabaseDriverPostgres {
+				assert.Equal(t, int64(3), attempts.Load(), "the unit of work appending last should run again")
+			}
+		})
+	}
+}
+
+// holdUnitOfWork runs fn in a unit of work left open until release is closed, returning once fn returned
+func holdUnitOfWork(t *testing.T, unitOfWork adapter.UnitOfWork, release <-chan struct{}, fn func(ctx context.Context) error) <-chan error {
+	ran, done := make(chan error, 1), make(chan error, 1)
+	go func() {
+		done <- unitOfWork.Do(context.Background(), sql.LevelDefault, func(ctx context.Context) error {
+			err := fn(ctx)
+			ran <- err
+			<-release
+			return err
+		})
+	}()
+	require.NoError(t, <-ran)
+	return done
+}
+
+// assertWaiting asserts that nothing is received from result until the wait ends
+func assertWaiting[T any](t *testing.T, result <-chan T, msg string) {
+	select {
+	case <-result:
+		t.Fatal(msg)
+	case <-time.After(300 * time.Millisecond):
+	}
+}
+
+func TestAccountRepositoryContract_FindByIDForShare(t *testing.T) {
+	for _, b := range backends() {
+		t.Run(b.name, func(t *testing.T) {
+			repos := b.open(t)
+			ctx := context.Background()
+
+			// An account blocked by a unit of work still running is found blocked once it commits
+			saved := saveTestAccount(t, repos.accounts)
+			release := make(chan struct{})
+			blockDone := holdUnitOfWork(t, repos.unitOfWork, release, func(ctx context.Context) error {
+				_, err := repos.accounts.Block(ctx, saved.AccountID, saved.Version)
+				return err
+			})
+			found := make(chan *account.Account, 1)
+			go func() {
+				_ = repos.unitOfWork.Do(ctx, sql.LevelDefault, func(ctx context.Context) error {
+					acc, err := repos.accounts.FindByIDForShare(ctx, saved.AccountID)
+					found <- acc
+					return err
+				})
+			}()
+			assertWaiting(t, found, "the account should not be read before the block is committed")
+			close(release)
+			require.NoError(t, <-blockDone)
+			acc := <-found
+			require.NotNil(t, acc)
+			assert.True(t, acc.Blocked(), "a transaction racing a block should find the account blocked")
+
+			// An account read for share is not blocked before the unit of work reading it ends
+			saved = saveTestAccount(t, repos.accounts)
+			release = make(chan struct{})
+			readDone := holdUnitOfWork(t, repos.unitOfWork, release, func(ctx context.Context) error {
+				_, err := repos.accounts.FindByIDForShare(ctx, saved.AccountID)
+				return err
+			})
+			blocked := make(chan error, 1)
+			go func() {
+				_, err := repos.accounts.Block(ctx, saved.AccountID, saved.Version)
+				blocked <- err
+			}()
+			assertWaiting(t, blocked, "the account should not be blocked while it is read for share")
+			close(release)
+			require.NoError(t, <-readDone)
+			require.NoError(t, <-blocked)
+		})
+	}
+}
+
+func newTestTransaction(t *testing.T, accountID int64, amount float64, status string, effectiveDate time.Time) *transaction.Transaction {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	newTransaction := &transaction.Transaction{
+		AccountID:        accountID,
+		OperationTypeID:  transaction.Purchase,
+		Amount:           amount,
+		Currency:         "BRL",
+		OriginalAmount:   amount,
+		OriginalCurrency: "BRL",
+		ExchangeRate:     1,
+		Merchant:         transaction.Merchant{Name: "Padaria 100% Pão", CategoryCode: "5462", Country: "BR"},
+		Description:      "Breakfast",
+		Metadata:         map[string]string{"channel": "pos", "order.id": fmt.Sprint(amount)},
+		EventDate:        now,
+		Status:           status,
+		EffectiveDate:    effectiveDate,
+		CreatedAt:        now,
+	}
+	postings, err := ledger.Post(accountID, amount, 0, "BRL")
+	require.NoError(t, err)
+	newTransaction.Postings = postings
+	if status == transaction.StatusPosted {
+		newTransaction.PostedAt = now
+	}
+	return newTransaction
+}
+
+func assertSameTransaction(t *testing.T, expected *transaction.Transaction, actual *transaction.Transaction) {
+	t.Helper()
+	assert.Equal(t, expected.TransactionID, actual.TransactionID)
+	assert.Equal(t, expected.AccountID, actual.AccountID)
+	assert.Equal(t, expected.Amount, actual.Amount)
+	assert.Equal(t, expected.Merchant, actual.Merchant)
+	assert.Equal(t, expected.Metadata, actual.Metadata)
+	assert.Equal(t, expected.Status, actual.Status)
+	assert.True(t, expected.EventDate.Equal(actual.EventDate), "event date %s should be %s", actual.EventDate, expected.EventDate)
+	assert.True(t, expected.EffectiveDate.Equal(actual.EffectiveDate), "effective date %s should be %s", actual.EffectiveDate, expected.EffectiveDate)
+	assert.True(t, expected.PostedAt.Equal(actual.PostedAt), "posted at %s should be %s", actual.PostedAt, expected.PostedAt)
+	assert.True(t, expected.CancelledAt.Equal(actual.CancelledAt), "cancelled at %s should be %s", actual.CancelledAt, expected.CancelledAt)
+}
+
+func TestTransactionRepositoryContract(t *testing.T) {
+	for _, b := range backends() {
+		t.Run(b.name, func(t *testing.T) {
+			opened := b.open(t)
+			transactions := opened.transactions
+			ctx := context.Background()
+			accountID := saveTestAccount(t, opened.accounts).AccountID
+			now := time.Now().UTC().Truncate(time.Microsecond)
+
+			operationType, err := transactions.FindOperationTypeByID(ctx, transaction.Purchase)
+			require.NoError(t, err)
+			assert.Equal(t, int64(transaction.Purchase), operationType.OperationTypeID)
+			_, err = transactions.FindOperationTypeByID(ctx, 99)
+			assert.ErrorIs(t, err, coreerr.OperationTypeNotFoundError)
+
+			saved, err := transactions.Save(ctx, newTestTransaction(t, accountID, -10.5, transaction.StatusPosted, now))
+			require.NoError(t, err)
+			assert.Positive(t, saved.TransactionID)
+			require.Len(t, saved.Postings, 2)
+			for _, posting := range saved.Postings {
+				assert.Positive(t, posting.EntryID)
+				assert.Equal(t, saved.TransactionID, posting.TransactionID)
+			}
+			found, err := transactions.FindTransactionByID(ctx, saved.TransactionID)
+			require.NoError(t, err)
+			assertSameTransaction(t, saved, found)
+			_, err = transactions.FindTransactionByID(ctx, math.MaxInt64)
+			assert.ErrorIs(t, err, coreerr.TransactionNotFoundError)
+
+			unbalanced := newTestTransaction(t, accountID, -1, transaction.StatusPosted, now)
+			unbalanced.Postings = unbalanced.Postings[:1]
+			_, err = transactions.Save(ctx, unbalanced)
+			assert.ErrorIs(t, err, coreerr.LedgerUnbalancedError)
+
+			batch, err := transactions.SaveBatch(ctx, []*transaction.Transaction{
+				newTestTransaction(t, accountID, -1, transaction.StatusPosted, now),
+				newTestTransaction(t, accountID, -2, transaction.StatusPosted, now),
+				newTestTransaction(t, accountID, 3, transaction.StatusPosted, now),
+			})
+			require.NoError(t, err)
+			require.Len(t, batch, 3)
+			for i, amount := range []float64{-1, -2, 3} {
+				assert.Equal(t, amount, batch[i].Amount, "the batch should be returned in the order it was given")
+				assert.Greater(t, batch[i].TransactionID, saved.TransactionID)
+				assert.Len(t, batch[i].Postings, 2)
+			}
+
+			// Effective dates in the past put the pending transactions first among the due ones of a reused database
+			effectiveDate := time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+			pending, err := transactions.Save(ctx, newTestTransaction(t, accountID, -4, transaction.StatusPending, effectiveDate))
+			require.NoError(t, err)
+			assert.Empty(t, pending.Postings, "pending transactions should be posted later")
+			cancelled, err := transactions.Save(ctx, newTestTransaction(t, accountID, -5, transaction.StatusPending, effectiveDate))
+			require.NoError(t, err)
+			due, err := transactions.FindDue(ctx, effectiveDate, 1000)
+			require.NoError(t, err)
+			var dueIDs []int64
+			for _, dueTransaction := range due {
+				dueIDs = append(dueIDs, dueTransaction.TransactionID)
+			}
+			assert.Contains(t, dueIDs, pending.TransactionID)
+			assert.Contains(t, dueIDs, cancelled.TransactionID)
+			notDue, err := transactions.FindDue(ctx, effectiveDate.Add(-time.Second), 1000)
+			require.NoError(t, err)
+			for _, dueTransaction := range notDue {
+				assert.NotEqual(t, pending.TransactionID, dueTransaction.TransactionID, "transactions effective later should not be due")
+			}
+
+			pending.Status = transaction.StatusPosted
+			pending.PostedAt = now
+			pending.Postings, err = ledger.Post(accountID, pending.Amount, 0, "BRL")
+			require.NoError(t, err)
+			posted, err := transactions.Post(ctx, pending)
+			require.NoError(t, err)
+			assert.Equal(t, transaction.StatusPosted, posted.Status)
+			assert.True(t, now.Equal(posted.PostedAt))
+			assert.Len(t, posted.Postings, 2)
+			_, err = transactions.Post(ctx, pending)
+			assert.ErrorIs(t, err, coreerr.TransactionNotPendingError)
+
+			cancelledAt := now.Add(time.Minute)
+			cancelled, err = transactions.Cancel(ctx, cancelled.TransactionID, cancelledAt)
+			require.NoError(t, err)
+			assert.Equal(t, transaction.StatusCancelled, cancelled.Status)
+			assert.True(t, cancelledAt.Equal(cancelled.CancelledAt))
+			_, err = transactions.Cancel(ctx, cancelled.TransactionID, cancelledAt)
+			assert.ErrorIs(t, err, coreerr.TransactionNotPendingError)
+			_, err = transactions.Cancel(ctx, math.MaxInt64, cancelledAt)
+			assert.ErrorIs(t, err, coreerr.TransactionNotFoundError)
+
+			var streamed []int64
+			err = transactions.StreamTransactions(ctx, transaction.TransactionFilter{AccountID: accountID}, func(streamedTransaction *transaction.Transaction) error {
+				streamed = append(streamed, streamedTransaction.TransactionID)
+				readCtx, cancel := context.WithTimeout(ctx, time.Second)
+				defer cancel()
+				_, err := transactions.FindTransactionByID(readCtx, streamedTransaction.TransactionID)
+				return err
+			})
+			require.NoError(t, err, "the repository should be usable while a stream is consumed")
+			assert.Equal(t, []int64{saved.TransactionID, batch[0].TransactionID, batch[1].TransactionID, batch[2].TransactionID, posted.TransactionID}, streamed,
+				"only the posted transactions should be streamed, ordered by ID")
+			err = transactions.StreamTransactions(ctx, transaction.TransactionFilter{AccountID: accountID, From: now.Add(time.Second)}, func(*transaction.Transaction) error {
+				return assert.AnError
+			})
+			assert.NoError(t, err, "no transaction happened after the interval start")
+
+			matches, err := transactions.Search(ctx, transaction.TransactionSearch{
+				TransactionFilter: transaction.TransactionFilter{AccountID: accountID},
+				MerchantName:      "100% pão",
+				Metadata:          map[string]string{"order.id": "-2"},
+				Limit:             10,
+			})
+			require.NoError(t, err)
+			require.Len(t, matches, 1)
+			assertSameTransaction(t, batch[1], matches[0])
+			matches, err = transactions.Search(ctx, transaction.TransactionSearch{
+				TransactionFilter: transaction.TransactionFilter{AccountID: accountID},
+				Description:       "break_ast",
+				Limit:             10,
+			})
+			require.NoError(t, err)
+			assert.Empty(t, matches, "LIKE wildcards should be matched literally")
+			matches, err = transactions.Search(ctx, transaction.TransactionSearch{
+				TransactionFilter: transaction.TransactionFilter{AccountID: accountID},
+				Status:            transaction.StatusCancelled,
+				Limit:             10,
+			})
+			require.NoError(t, err)
+			require.Len(t, matches, 1)
+			assertSameTransaction(t, cancelled, matches[0])
+			matches, err = transactions.Search(ctx, transaction.TransactionSearch{
+				TransactionFilter: transaction.TransactionFilter{AccountID: accountID},
+				Cursor:            batch[0].TransactionID,
+				Limit:             2,
+			})
+			require.NoError(t, err)
+			require.Len(t, matches, 2)
+			assert.Equal(t, batch[1].TransactionID, matches[0].TransactionID, "the page should start after the cursor")
+			assert.Equal(t, batch[2].TransactionID, matches[1].TransactionID)
+		})
+	}
+}
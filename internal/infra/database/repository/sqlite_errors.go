@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"errors"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+	"strings"
+)
+
+// accountDocumentNumberIndexColumn is the column of the unique index of the document numbers, as named in the errors of SQLite
+const accountDocumentNumberIndexColumn = "accounts.document_number_index"
+
+// isSQLiteUniqueViolation reports whether err was raised by a row violating a unique constraint or index on column,
+// named as table.column. SQLite names the columns, not the index, in its errors.
+func isSQLiteUniqueViolation(err error, column string) bool {
+	var sqliteErr *sqlite.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE && strings.Contains(sqliteErr.Error(), column)
+}
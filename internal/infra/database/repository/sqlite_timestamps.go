@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+)
+
+// sqliteTimestampLayout stores times in UTC with microseconds, like Postgres. Its fixed width keeps the text order
+// of the stored times in chronological order, so they are compared and sorted as text.
+const sqliteTimestampLayout = "2006-01-02 15:04:05.000000"
+
+// sqliteTimestamp returns the stored text of a time, read back as a UTC time from the timestamp columns
+func sqliteTimestamp(value time.Time) string {
+	return value.UTC().Format(sqliteTimestampLayout)
+}
+
+// sqliteNullTimestamp returns the stored text of a nullable time, nil when it is not valid
+func sqliteNullTimestamp(value sql.NullTime) any {
+	if !value.Valid {
+		return nil
+	}
+	return sqliteTimestamp(value.Time)
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/adapter"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/contextutils"
+	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
+)
+
+// SQLiteUnitOfWork runs the calls of the SQLite repositories made with the context of Do in one database transaction.
+// SQLite transactions are always serializable, so the isolation level is not used.
+type SQLiteUnitOfWork struct {
+	connectionData *adapter.DatabaseConnectionData
+	componentName  string
+	log            logger.Logger
+}
+
+func NewSQLiteUnitOfWork(connectionData *adapter.DatabaseConnectionData, log logger.Logger) *SQLiteUnitOfWork {
+	unitOfWork := &SQLiteUnitOfWork{
+		connectionData: connectionData,
+		log:            log,
+	}
+	unitOfWork.componentName = logger.ComponentNameFromStruct(unitOfWork)
+	return unitOfWork
+}
+
+func (s *SQLiteUnitOfWork) Do(ctx context.Context, isolation sql.IsolationLevel, fn func(ctx context.Context) error) error {
+	if adapter.TxFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+	traceID := contextutils.GetTraceID(ctx)
+	s.log.Debug(s.componentName+".Do", "isolation", isolation.String(), "x_trace_id", traceID)
+	tx, err := s.connectionData.Db.BeginTx(ctx, nil)
+	if err != nil {
+		s.log.Warn(s.componentName+".Do", "error", err, "x_trace_id", traceID)
+		return coreerr.DatabaseCreateTransactionError
+	}
+	defer tx.Rollback()
+	if err = fn(adapter.ContextWithTx(ctx, tx)); err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		s.log.Warn(s.componentName+".Do", "error", err, "x_trace_id", traceID)
+		return coreerr.DatabaseFailToCommitError
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/adapter"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/contextutils"
+	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/ledger"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/transaction"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/database/mapper"
+	"github.com/lib/pq"
+	"strings"
+)
+
+// TransactionCopyWriter bulk loads posted transactions and their ledger postings with COPY, which is much faster
+// than inserting them but records nothing in the audit log. It is meant to seed demo and performance databases.
+type TransactionCopyWriter struct {
+	connectionData *adapter.DatabaseConnectionData
+	componentName  string
+	log            logger.Logger
+}
+
+func NewTransactionCopyWriter(connectionData *adapter.DatabaseConnectionData, log logger.Logger) *TransactionCopyWriter {
+	writer := &TransactionCopyWriter{
+		connectionData: connectionData,
+		log:            log,
+	}
+	writer.componentName = logger.ComponentNameFromStruct(writer)
+	return writer
+}
+
+// WriteTransactions copies the transactions and their balanced postings in one database transaction, setting the
+// transaction IDs, reserved from the transactions sequence before copying
+func (t *TransactionCopyWriter) WriteTransactions(ctx context.Context, newTransactions []*transaction.Transaction) error {
+	traceID := contextutils.GetTraceID(ctx)
+	t.log.Debug(t.componentName+".WriteTransactions", "rows", len(newTransactions), "x_trace_id", traceID)
+	if len(newTransactions) == 0 {
+		return nil
+	}
+	for _, newTransaction := range newTransactions {
+		if newTransaction.Status != transaction.StatusPosted || !ledger.Balanced(newTransaction.Postings) {
+			t.log.Warn(t.componentName+".WriteTransactions", "error", coreerr.LedgerUnbalancedError, "x_trace_id", traceID)
+			return coreerr.LedgerUnbalancedError
+		}
+	}
+	tx, err := beginTx(ctx, t.connectionData.Db, nil)
+	if err != nil {
+		t.log.Warn(t.componentName+".WriteTransactions", "error", err, "x_trace_id", traceID)
+		return coreerr.DatabaseCreateTransactionError
+	}
+	defer tx.Rollback()
+	transactionIDs, err := nextTransactionIDs(ctx, tx.Tx, len(newTransactions))
+	if err != nil {
+		t.log.Warn(t.componentName+".WriteTransactions", "error", err, "x_trace_id", traceID)
+		return coreerr.DatabaseInsertionError
+	}
+	columns := append([]string{"transaction_id"}, strings.Split(transactionInsertColumns, ", ")...)
+	var transactionRows, entryRows [][]any
+	for i, newTransaction := range newTransactions {
+		transactionModel := mapper.ToTransactionModel(newTransaction)
+		transactionModel.TransactionID = transactionIDs[i]
+		transactionRows = append(transactionRows, []any{
+			transactionModel.TransactionID,
+			transactionModel.AccountID,
+			transactionModel.OperationTypeID,
+			transactionModel.Amount,
+			transactionModel.Currency,
+			transactionModel.OriginalAmount,
+			transactionModel.OriginalCurrency,
+			transactionModel.ExchangeRate,
+			transactionModel.MerchantName,
+			transactionModel.MerchantCategory,
+			transactionModel.MerchantCountry,
+			transactionModel.Description,
+			transactionModel.Metadata,
+			transactionModel.EventDate,
+			transactionModel.Status,
+			transactionModel.EffectiveDate,
+			transactionModel.PostedAt,
+			transactionModel.CreatedAt})
+		for _, posting := range newTransaction.Postings {
+			posting.TransactionID = transactionIDs[i]
+			posting.CreatedAt = newTransaction.PostedAt
+			entryModel := mapper.ToLedgerEntryModel(&posting)
+			entryRows = append(entryRows, []any{
+				entryModel.TransactionID,
+				entryModel.LedgerAccount,
+				entryModel.Direction,
+				entryModel.Amount,
+				entryModel.Currency,
+				entryModel.CreatedAt})
+		}
+	}
+	if err = copyRows(ctx, tx.Tx, pq.CopyIn("transactions", columns...), transactionRows); err != nil {
+		t.log.Warn(t.componentName+".WriteTransactions", "error", err, "x_trace_id", traceID)
+		return coreerr.DatabaseInsertionError
+	}
+	if err = copyRows(ctx, tx.Tx, pq.CopyIn("ledger_entries", "transaction_id", "ledger_account", "direction", "amount", "currency", "created_at"), entryRows); err != nil {
+		t.log.Warn(t.componentName+".WriteTransactions", "error", err, "x_trace_id", traceID)
+		return coreerr.DatabaseInsertionError
+	}
+	if err = tx.Commit(); err != nil {
+		t.log.Warn(t.componentName+".WriteTransactions", "error", err, "x_trace_id", traceID)
+		return coreerr.DatabaseFailToCommitError
+	}
+	for i, newTransaction := range newTransactions {
+		newTransaction.TransactionID = transactionIDs[i]
+	}
+	return nil
+}
+
+// copyRows sends rows to a COPY statement of tx
+func copyRows(ctx context.Context, tx *sql.Tx, copyStatement string, rows [][]any) error {
+	stmt, err := tx.PrepareContext(ctx, copyStatement)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for _, row := range rows {
+		if _, err = stmt.ExecContext(ctx, row...); err != nil {
+			return err
+		}
+	}
+	_, err = stmt.ExecContext(ctx)
+	return err
+}
@@ -3,24 +3,43 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"fmt"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/adapter"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/clock"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/contextutils"
 	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/audit"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/ledger"
 	"github.com/kiosanim/pismo-code-assessment/internal/domains/transaction"
 	"github.com/kiosanim/pismo-code-assessment/internal/infra/database/mapper"
 	"github.com/kiosanim/pismo-code-assessment/internal/infra/database/model"
+	"strings"
+	"time"
+)
+
+// streamFetchSize is the number of rows fetched at a time by StreamTransactions and StreamEntries
+const streamFetchSize = 1000
+
+const (
+	transactionInsertColumns = "account_id, operation_type_id, amount, currency, original_amount, original_currency, exchange_rate, " +
+		"merchant_name, merchant_category_code, merchant_country, description, metadata, event_date, status, effective_date, posted_at, created_at"
+	// transactionSelectColumns must be kept in the order of transactionColumns
+	transactionSelectColumns = "transaction_id, " + transactionInsertColumns + ", cancelled_at"
 )
 
 type TransactionPostgresRepository struct {
 	connectionData *adapter.DatabaseConnectionData
+	clock          clock.Clock
 	componentName  string
 	log            logger.Logger
 }
 
-func NewTransactionPostgresRepository(connectionData *adapter.DatabaseConnectionData, log logger.Logger) *TransactionPostgresRepository {
+func NewTransactionPostgresRepository(connectionData *adapter.DatabaseConnectionData, clock clock.Clock, log logger.Logger) *TransactionPostgresRepository {
 	repository := &TransactionPostgresRepository{
 		connectionData: connectionData,
+		clock:          clock,
 		log:            log,
 	}
 	repository.componentName = logger.ComponentNameFromStruct(repository)
@@ -36,13 +55,13 @@ func (t *TransactionPostgresRepository) Save(ctx context.Context, newTransaction
 		t.log.Warn(t.componentName+".Save", "error", err, "x_trace_id", traceID)
 		return nil, err
 	}
-	tx, err := t.connectionData.Db.BeginTx(ctx, nil)
+	tx, err := beginTx(ctx, t.connectionData.Db, nil)
 	if err != nil {
 		t.log.Warn(t.componentName+".Save", "error", err, "x_trace_id", traceID)
 		return nil, coreerr.DatabaseCreateTransactionError
 	}
 	defer tx.Rollback()
-	stmt, err := tx.PrepareContext(ctx, "INSERT INTO transactions(account_id, operation_type_id, amount, event_date) VALUES($1, $2, $3, $4) RETURNING transaction_id, account_id, operation_type_id, amount, event_date")
+	stmt, err := tx.PrepareContext(ctx, "INSERT INTO transactions("+transactionInsertColumns+") VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12::jsonb, $13, $14, $15, $16, $17) RETURNING "+transactionSelectColumns)
 	if err != nil {
 		t.log.Warn(t.componentName+".Save", "error", err, "x_trace_id", traceID)
 		return nil, coreerr.DatabasePrepareStatementError
@@ -53,35 +72,412 @@ func (t *TransactionPostgresRepository) Save(ctx context.Context, newTransaction
 		transactionModel.AccountID,
 		transactionModel.OperationTypeID,
 		transactionModel.Amount,
-		transactionModel.EventDate).Scan(
-		&transactionModel.TransactionID,
-		&transactionModel.AccountID,
-		&transactionModel.OperationTypeID,
-		&transactionModel.Amount,
-		&transactionModel.EventDate)
+		transactionModel.Currency,
+		transactionModel.OriginalAmount,
+		transactionModel.OriginalCurrency,
+		transactionModel.ExchangeRate,
+		transactionModel.MerchantName,
+		transactionModel.MerchantCategory,
+		transactionModel.MerchantCountry,
+		transactionModel.Description,
+		transactionModel.Metadata,
+		transactionModel.EventDate,
+		transactionModel.Status,
+		transactionModel.EffectiveDate,
+		transactionModel.PostedAt,
+		transactionModel.CreatedAt).Scan(transactionColumns(transactionModel)...)
 	if err != nil {
 		t.log.Warn(t.componentName+".Save", "error", err, "x_trace_id", traceID)
 		return nil, coreerr.DatabaseInsertionError
 	}
+	savedTransaction := mapper.ToTransactionEntity(transactionModel)
+	if err = appendLedgerPostings(ctx, tx.Tx, []*transaction.Transaction{newTransaction}, []*transaction.Transaction{savedTransaction}); err != nil {
+		t.log.Warn(t.componentName+".Save", "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
+	if err = appendTransactionAuditEntries(ctx, tx.Tx, t.clock.Now(), savedTransaction); err != nil {
+		t.log.Warn(t.componentName+".Save", "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
 	err = tx.Commit()
 	if err != nil {
 		t.log.Warn(t.componentName+".Save", "error", err, "x_trace_id", traceID)
 		return nil, coreerr.DatabaseFailToCommitError
 	}
-	return mapper.ToTransactionEntity(transactionModel), nil
+	return savedTransaction, nil
 }
 
-func (t *TransactionPostgresRepository) FindOperationTypeByID(ctx context.Context, operationTypeID int) (*transaction.OperationType, error) {
+// SaveBatch inserts every transaction with a single multi-row statement in one database transaction.
+// The IDs of the transactions are taken from their sequence before the insert, so the inserted rows are returned in the
+// order they were given whatever the order Postgres inserts them in.
+func (t *TransactionPostgresRepository) SaveBatch(ctx context.Context, newTransactions []*transaction.Transaction) ([]*transaction.Transaction, error) {
 	traceID := contextutils.GetTraceID(ctx)
-	t.log.Debug(t.componentName+".FindOperationTypeByID", "operationTypeID", operationTypeID, "x_trace_id", traceID)
-	var selectedOperationType model.OperationTypeModel
-	tx, err := t.connectionData.Db.BeginTx(ctx, nil)
+	t.log.Debug(t.componentName+".SaveBatch", "rows", len(newTransactions), "x_trace_id", traceID)
+	if len(newTransactions) == 0 {
+		return nil, nil
+	}
+	transactionModels := make([]*model.TransactionModel, 0, len(newTransactions))
+	for _, newTransaction := range newTransactions {
+		transactionModel := mapper.ToTransactionModel(newTransaction)
+		if transactionModel == nil {
+			err := coreerr.InvalidParametersError
+			t.log.Warn(t.componentName+".SaveBatch", "error", err, "x_trace_id", traceID)
+			return nil, err
+		}
+		transactionModels = append(transactionModels, transactionModel)
+	}
+	tx, err := beginTx(ctx, t.connectionData.Db, nil)
 	if err != nil {
-		t.log.Warn(t.componentName+".FindOperationTypeByID", "error", err, "x_trace_id", traceID)
+		t.log.Warn(t.componentName+".SaveBatch", "error", err, "x_trace_id", traceID)
 		return nil, coreerr.DatabaseCreateTransactionError
 	}
 	defer tx.Rollback()
-	stmt, err := tx.PrepareContext(ctx, "SELECT operation_type_id, description FROM operation_types WHERE operation_type_id = $1")
+	transactionIDs, err := nextTransactionIDs(ctx, tx.Tx, len(newTransactions))
+	if err != nil {
+		t.log.Warn(t.componentName+".SaveBatch", "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseQueryError
+	}
+	positions := make(map[int64]int, len(newTransactions))
+	values := make([]string, 0, len(newTransactions))
+	args := make([]any, 0, len(newTransactions)*18)
+	for i, transactionModel := range transactionModels {
+		positions[transactionIDs[i]] = i
+		n := len(args)
+		values = append(values, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d::jsonb, $%d, $%d, $%d, $%d, $%d)",
+			n+1, n+2, n+3, n+4, n+5, n+6, n+7, n+8, n+9, n+10, n+11, n+12, n+13, n+14, n+15, n+16, n+17, n+18))
+		args = append(args,
+			transactionIDs[i],
+			transactionModel.AccountID,
+			transactionModel.OperationTypeID,
+			transactionModel.Amount,
+			transactionModel.Currency,
+			transactionModel.OriginalAmount,
+			transactionModel.OriginalCurrency,
+			transactionModel.ExchangeRate,
+			transactionModel.MerchantName,
+			transactionModel.MerchantCategory,
+			transactionModel.MerchantCountry,
+			transactionModel.Description,
+			transactionModel.Metadata,
+			transactionModel.EventDate,
+			transactionModel.Status,
+			transactionModel.EffectiveDate,
+			transactionModel.PostedAt,
+			transactionModel.CreatedAt)
+	}
+	query := "INSERT INTO transactions(transaction_id, " + transactionInsertColumns + ") VALUES " +
+		strings.Join(values, ", ") + " RETURNING " + transactionSelectColumns
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		t.log.Warn(t.componentName+".SaveBatch", "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseInsertionError
+	}
+	defer rows.Close()
+	saved := make([]*transaction.Transaction, len(newTransactions))
+	for rows.Next() {
+		var transactionModel model.TransactionModel
+		err = rows.Scan(transactionColumns(&transactionModel)...)
+		if err != nil {
+			t.log.Warn(t.componentName+".SaveBatch", "error", err, "x_trace_id", traceID)
+			return nil, coreerr.DatabaseInsertionError
+		}
+		i, ok := positions[transactionModel.TransactionID]
+		if !ok {
+			err = coreerr.DatabaseInsertionError
+			t.log.Warn(t.componentName+".SaveBatch", "error", err, "transaction_id", transactionModel.TransactionID, "x_trace_id", traceID)
+			return nil, err
+		}
+		saved[i] = mapper.ToTransactionEntity(&transactionModel)
+	}
+	if err = rows.Err(); err != nil {
+		t.log.Warn(t.componentName+".SaveBatch", "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseInsertionError
+	}
+	if err = appendLedgerPostings(ctx, tx.Tx, newTransactions, saved); err != nil {
+		t.log.Warn(t.componentName+".SaveBatch", "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
+	if err = appendTransactionAuditEntries(ctx, tx.Tx, t.clock.Now(), saved...); err != nil {
+		t.log.Warn(t.componentName+".SaveBatch", "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		t.log.Warn(t.componentName+".SaveBatch", "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseFailToCommitError
+	}
+	return saved, nil
+}
+
+// FindDue returns up to limit pending transactions effective until the given date, the oldest first
+func (t *TransactionPostgresRepository) FindDue(ctx context.Context, until time.Time, limit int64) ([]*transaction.Transaction, error) {
+	traceID := contextutils.GetTraceID(ctx)
+	t.log.Debug(t.componentName+".FindDue", "until", until, "limit", limit, "x_trace_id", traceID)
+	tx, err := beginTx(ctx, t.connectionData.Db, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		t.log.Warn(t.componentName+".FindDue", "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseCreateTransactionError
+	}
+	defer tx.Rollback()
+	rows, err := tx.QueryContext(ctx, "SELECT "+transactionSelectColumns+" FROM transactions WHERE status = $1 AND effective_date <= $2 "+
+		"ORDER BY effective_date, transaction_id LIMIT $3", transaction.StatusPending, until.UTC(), limit)
+	if err != nil {
+		t.log.Warn(t.componentName+".FindDue", "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseQueryError
+	}
+	defer rows.Close()
+	transactions := make([]*transaction.Transaction, 0)
+	for rows.Next() {
+		var transactionModel model.TransactionModel
+		if err = rows.Scan(transactionColumns(&transactionModel)...); err != nil {
+			t.log.Warn(t.componentName+".FindDue", "error", err, "x_trace_id", traceID)
+			return nil, coreerr.DatabaseQueryError
+		}
+		transactions = append(transactions, mapper.ToTransactionEntity(&transactionModel))
+	}
+	if err = rows.Err(); err != nil {
+		t.log.Warn(t.componentName+".FindDue", "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseQueryError
+	}
+	if err = tx.Commit(); err != nil {
+		t.log.Warn(t.componentName+".FindDue", "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseFailToCommitError
+	}
+	return transactions, nil
+}
+
+// Post marks a pending transaction as posted at dueTransaction.PostedAt and inserts its postings, which must be balanced
+func (t *TransactionPostgresRepository) Post(ctx context.Context, dueTransaction *transaction.Transaction) (*transaction.Transaction, error) {
+	t.log.Debug(t.componentName+".Post", "transactionID", dueTransaction.TransactionID, "x_trace_id", contextutils.GetTraceID(ctx))
+	return t.leavePending(ctx, "Post", dueTransaction.TransactionID, audit.ActionTransactionPost,
+		"UPDATE transactions SET status = $2, posted_at = $3 WHERE transaction_id = $1 RETURNING "+transactionSelectColumns,
+		[]any{transaction.StatusPosted, dueTransaction.PostedAt},
+		func(tx *sql.Tx, postedTransaction *transaction.Transaction) error {
+			return appendLedgerPostings(ctx, tx, []*transaction.Transaction{dueTransaction}, []*transaction.Transaction{postedTransaction})
+		})
+}
+
+// Cancel marks a pending transaction as cancelled at cancelledAt
+func (t *TransactionPostgresRepository) Cancel(ctx context.Context, transactionID int64, cancelledAt time.Time) (*transaction.Transaction, error) {
+	t.log.Debug(t.componentName+".Cancel", "transactionID", transactionID, "x_trace_id", contextutils.GetTraceID(ctx))
+	return t.leavePending(ctx, "Cancel", transactionID, audit.ActionTransactionCancel,
+		"UPDATE transactions SET status = $2, cancelled_at = $3 WHERE transaction_id = $1 RETURNING "+transactionSelectColumns,
+		[]any{transaction.StatusCancelled, cancelledAt}, nil)
+}
+
+// leavePending runs update, whose first parameter is the transaction ID, on a pending transaction locked for update.
+// The change, and whatever apply writes, is recorded in the audit log in the same database transaction.
+func (t *TransactionPostgresRepository) leavePending(ctx context.Context, method string, transactionID int64, action string, update string, args []any,
+	apply func(tx *sql.Tx, updatedTransaction *transaction.Transaction) error) (*transaction.Transaction, error) {
+	traceID := contextutils.GetTraceID(ctx)
+	tx, err := beginTx(ctx, t.connectionData.Db, nil)
+	if err != nil {
+		t.log.Warn(t.componentName+"."+method, "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseCreateTransactionError
+	}
+	defer tx.Rollback()
+	var beforeModel model.TransactionModel
+	err = tx.QueryRowContext(ctx, "SELECT "+transactionSelectColumns+" FROM transactions WHERE transaction_id = $1 FOR UPDATE", transactionID).
+		Scan(transactionColumns(&beforeModel)...)
+	if err != nil {
+		t.log.Warn(t.componentName+"."+method, "error", err, "x_trace_id", traceID)
+		if err == sql.ErrNoRows {
+			return nil, coreerr.TransactionNotFoundError
+		}
+		return nil, coreerr.DatabaseQueryError
+	}
+	if beforeModel.Status != transaction.StatusPending {
+		err = coreerr.TransactionNotPendingError
+		t.log.Warn(t.componentName+"."+method, "error", err, "status", beforeModel.Status, "x_trace_id", traceID)
+		return nil, err
+	}
+	var afterModel model.TransactionModel
+	err = tx.QueryRowContext(ctx, update, append([]any{transactionID}, args...)...).Scan(transactionColumns(&afterModel)...)
+	if err != nil {
+		t.log.Warn(t.componentName+"."+method, "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseInsertionError
+	}
+	updatedTransaction := mapper.ToTransactionEntity(&afterModel)
+	if apply != nil {
+		if err = apply(tx.Tx, updatedTransaction); err != nil {
+			t.log.Warn(t.componentName+"."+method, "error", err, "x_trace_id", traceID)
+			return nil, err
+		}
+	}
+	entry, err := newAuditEntry(ctx, t.clock.Now(), action, audit.EntityTransaction, transactionID, mapper.ToTransactionEntity(&beforeModel), updatedTransaction)
+	if err == nil {
+		err = appendAuditEntries(ctx, tx.Tx, entry)
+	}
+	if err != nil {
+		t.log.Warn(t.componentName+"."+method, "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		t.log.Warn(t.componentName+"."+method, "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseFailToCommitError
+	}
+	return updatedTransaction, nil
+}
+
+// StreamTransactions calls fn with every posted transaction selected by filter, ordered by account and transaction id.
+// Rows are fetched from a server side cursor so the result set is never loaded in memory at once.
+// Errors returned by fn stop the stream and are returned as is.
+func (t *TransactionPostgresRepository) StreamTransactions(ctx context.Context, filter transaction.TransactionFilter, fn func(*transaction.Transaction) error) error {
+	traceID := contextutils.GetTraceID(ctx)
+	t.log.Debug(t.componentName+".StreamTransactions", "filter", filter, "x_trace_id", traceID)
+	tx, err := beginTx(ctx, t.connectionData.Db, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		t.log.Warn(t.componentName+".StreamTransactions", "error", err, "x_trace_id", traceID)
+		return coreerr.DatabaseCreateTransactionError
+	}
+	defer tx.Rollback()
+	// DECLARE does not take bind parameters, the conditions are built from typed values only
+	conditions := []string{fmt.Sprintf("status = '%s'", transaction.StatusPosted)}
+	if filter.AccountID > 0 {
+		conditions = append(conditions, fmt.Sprintf("account_id = %d", filter.AccountID))
+	}
+	if !filter.From.IsZero() {
+		conditions = append(conditions, fmt.Sprintf("event_date >= '%s'", filter.From.UTC().Format(time.RFC3339Nano)))
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, fmt.Sprintf("event_date < '%s'", filter.To.UTC().Format(time.RFC3339Nano)))
+	}
+	query := "DECLARE transactions_stream NO SCROLL CURSOR FOR SELECT " + transactionSelectColumns + " FROM transactions WHERE " +
+		strings.Join(conditions, " AND ")
+	query += " ORDER BY account_id, transaction_id"
+	if _, err = tx.ExecContext(ctx, query); err != nil {
+		t.log.Warn(t.componentName+".StreamTransactions", "error", err, "x_trace_id", traceID)
+		return coreerr.DatabaseQueryError
+	}
+	fetch := fmt.Sprintf("FETCH FORWARD %d FROM transactions_stream", streamFetchSize)
+	for {
+		fetched, err := t.fetchTransactions(ctx, tx.Tx, fetch, fn)
+		if err != nil {
+			return err
+		}
+		if fetched < streamFetchSize {
+			break
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		t.log.Warn(t.componentName+".StreamTransactions", "error", err, "x_trace_id", traceID)
+		return coreerr.DatabaseFailToCommitError
+	}
+	return nil
+}
+
+func (t *TransactionPostgresRepository) fetchTransactions(ctx context.Context, tx *sql.Tx, fetch string, fn func(*transaction.Transaction) error) (int, error) {
+	traceID := contextutils.GetTraceID(ctx)
+	rows, err := tx.QueryContext(ctx, fetch)
+	if err != nil {
+		t.log.Warn(t.componentName+".fetchTransactions", "error", err, "x_trace_id", traceID)
+		return 0, coreerr.DatabaseQueryError
+	}
+	defer rows.Close()
+	fetched := 0
+	for rows.Next() {
+		var transactionModel model.TransactionModel
+		err = rows.Scan(transactionColumns(&transactionModel)...)
+		if err != nil {
+			t.log.Warn(t.componentName+".fetchTransactions", "error", err, "x_trace_id", traceID)
+			return fetched, coreerr.DatabaseQueryError
+		}
+		fetched++
+		if err = fn(mapper.ToTransactionEntity(&transactionModel)); err != nil {
+			return fetched, err
+		}
+	}
+	if err = rows.Err(); err != nil {
+		t.log.Warn(t.componentName+".fetchTransactions", "error", err, "x_trace_id", traceID)
+		return fetched, coreerr.DatabaseQueryError
+	}
+	return fetched, nil
+}
+
+// Search returns up to search.Limit transactions matching every informed criteria, ordered by transaction id
+// and starting after search.Cursor
+func (t *TransactionPostgresRepository) Search(ctx context.Context, search transaction.TransactionSearch) ([]*transaction.Transaction, error) {
+	traceID := contextutils.GetTraceID(ctx)
+	t.log.Debug(t.componentName+".Search", "search", search, "x_trace_id", traceID)
+	var conditions []string
+	var args []any
+	addCondition := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	addCondition("transaction_id > $%d", search.Cursor)
+	if search.AccountID > 0 {
+		addCondition("account_id = $%d", search.AccountID)
+	}
+	if !search.From.IsZero() {
+		addCondition("event_date >= $%d", search.From.UTC())
+	}
+	if !search.To.IsZero() {
+		addCondition("event_date < $%d", search.To.UTC())
+	}
+	if search.Status != "" {
+		addCondition("status = $%d", search.Status)
+	}
+	if search.MerchantName != "" {
+		addCondition("merchant_name ILIKE $%d", "%"+escapeLike(search.MerchantName)+"%")
+	}
+	if search.MerchantCategoryCode != "" {
+		addCondition("merchant_category_code = $%d", search.MerchantCategoryCode)
+	}
+	if search.MerchantCountry != "" {
+		addCondition("merchant_country = $%d", search.MerchantCountry)
+	}
+	if search.Description != "" {
+		addCondition("description ILIKE $%d", "%"+escapeLike(search.Description)+"%")
+	}
+	if len(search.Metadata) > 0 {
+		metadata, err := json.Marshal(search.Metadata)
+		if err != nil {
+			t.log.Warn(t.componentName+".Search", "error", err, "x_trace_id", traceID)
+			return nil, coreerr.InvalidParametersError
+		}
+		addCondition("metadata @> $%d::jsonb", string(metadata))
+	}
+	args = append(args, search.Limit)
+	query := fmt.Sprintf("SELECT %s FROM transactions WHERE %s ORDER BY transaction_id LIMIT $%d",
+		transactionSelectColumns, strings.Join(conditions, " AND "), len(args))
+	tx, err := beginTx(ctx, t.connectionData.Db, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		t.log.Warn(t.componentName+".Search", "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseCreateTransactionError
+	}
+	defer tx.Rollback()
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		t.log.Warn(t.componentName+".Search", "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseQueryError
+	}
+	defer rows.Close()
+	transactions := make([]*transaction.Transaction, 0)
+	for rows.Next() {
+		var transactionModel model.TransactionModel
+		if err = rows.Scan(transactionColumns(&transactionModel)...); err != nil {
+			t.log.Warn(t.componentName+".Search", "error", err, "x_trace_id", traceID)
+			return nil, coreerr.DatabaseQueryError
+		}
+		transactions = append(transactions, mapper.ToTransactionEntity(&transactionModel))
+	}
+	if err = rows.Err(); err != nil {
+		t.log.Warn(t.componentName+".Search", "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseQueryError
+	}
+	if err = tx.Commit(); err != nil {
+		t.log.Warn(t.componentName+".Search", "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseFailToCommitError
+	}
+	return transactions, nil
+}
+
+func (t *TransactionPostgresRepository) FindOperationTypeByID(ctx context.Context, operationTypeID int) (*transaction.OperationType, error) {
+	traceID := contextutils.GetTraceID(ctx)
+	t.log.Debug(t.componentName+".FindOperationTypeByID", "operationTypeID", operationTypeID, "x_trace_id", traceID)
+	var selectedOperationType model.OperationTypeModel
+	stmt, err := queryerFor(ctx, t.connectionData.Db).PrepareContext(ctx, "SELECT operation_type_id, description FROM operation_types WHERE operation_type_id = $1")
 	if err != nil {
 		t.log.Warn(t.componentName+".FindOperationTypeByID", "error", err, "x_trace_id", traceID)
 		return nil, coreerr.DatabasePrepareStatementError
@@ -103,13 +499,7 @@ func (t *TransactionPostgresRepository) FindTransactionByID(ctx context.Context,
 	traceID := contextutils.GetTraceID(ctx)
 	t.log.Debug(t.componentName+".FindTransactionByID", "transactionID", transactionID, "x_trace_id", traceID)
 	var transactionModel model.TransactionModel
-	tx, err := t.connectionData.Db.BeginTx(ctx, nil)
-	if err != nil {
-		t.log.Warn(t.componentName+".FindTransactionByID", "error", err, "x_trace_id", traceID)
-		return nil, coreerr.DatabaseCreateTransactionError
-	}
-	defer tx.Rollback()
-	stmt, err := tx.PrepareContext(ctx, "SELECT transaction_id, account_id, operation_type_id, amount FROM transactions WHERE transaction_id = $1")
+	stmt, err := queryerFor(ctx, t.connectionData.Db).PrepareContext(ctx, "SELECT "+transactionSelectColumns+" FROM transactions WHERE transaction_id = $1")
 	if err != nil {
 		t.log.Warn(t.componentName+".FindTransactionByID", "error", err, "x_trace_id", traceID)
 		return nil, coreerr.DatabasePrepareStatementError
@@ -117,11 +507,7 @@ func (t *TransactionPostgresRepository) FindTransactionByID(ctx context.Context,
 	defer stmt.Close()
 	err = stmt.QueryRowContext(
 		ctx,
-		transactionID).Scan(
-		&transactionModel.TransactionID,
-		&transactionModel.AccountID,
-		&transactionModel.OperationTypeID,
-		&transactionModel.Amount)
+		transactionID).Scan(transactionColumns(&transactionModel)...)
 	if err != nil {
 		t.log.Warn(t.componentName+".FindTransactionByID", "error", err, "x_trace_id", traceID)
 		if err == sql.ErrNoRows {
@@ -131,3 +517,133 @@ func (t *TransactionPostgresRepository) FindTransactionByID(ctx context.Context,
 	}
 	return mapper.ToTransactionEntity(&transactionModel), nil
 }
+
+// transactionColumns returns the scan destinations of the transaction columns, in the order they are selected
+func transactionColumns(transactionModel *model.TransactionModel) []any {
+	return []any{
+		&transactionModel.TransactionID,
+		&transactionModel.AccountID,
+		&transactionModel.OperationTypeID,
+		&transactionModel.Amount,
+		&transactionModel.Currency,
+		&transactionModel.OriginalAmount,
+		&transactionModel.OriginalCurrency,
+		&transactionModel.ExchangeRate,
+		&transactionModel.MerchantName,
+		&transactionModel.MerchantCategory,
+		&transactionModel.MerchantCountry,
+		&transactionModel.Description,
+		&transactionModel.Metadata,
+		&transactionModel.EventDate,
+		&transactionModel.Status,
+		&transactionModel.EffectiveDate,
+		&transactionModel.PostedAt,
+		&transactionModel.CreatedAt,
+		&transactionModel.CancelledAt,
+	}
+}
+
+// escapeLike escapes the LIKE wildcards of a value matched literally
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// appendTransactionAuditEntries records in tx the creation of the transactions at createdAt
+func appendTransactionAuditEntries(ctx context.Context, tx *sql.Tx, createdAt time.Time, createdTransactions ...*transaction.Transaction) error {
+	entries, err := transactionAuditEntries(ctx, createdAt, createdTransactions...)
+	if err != nil {
+		return err
+	}
+	return appendAuditEntries(ctx, tx, entries...)
+}
+
+// transactionAuditEntries describes the creation of the transactions at createdAt
+func transactionAuditEntries(ctx context.Context, createdAt time.Time, createdTransactions ...*transaction.Transaction) ([]*audit.Entry, error) {
+	entries := make([]*audit.Entry, 0, len(createdTransactions))
+	for _, createdTransaction := range createdTransactions {
+		entry, err := newAuditEntry(ctx, createdAt, audit.ActionTransactionCreate, audit.EntityTransaction, createdTransaction.TransactionID, nil, createdTransaction)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// nextTransactionIDs takes n IDs from the sequence of the transactions, to insert them with IDs known beforehand
+func nextTransactionIDs(ctx context.Context, tx *sql.Tx, n int) ([]int64, error) {
+	rows, err := tx.QueryContext(ctx, "SELECT nextval(pg_get_serial_sequence('transactions', 'transaction_id')) FROM generate_series(1, $1)", n)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	transactionIDs := make([]int64, 0, n)
+	for rows.Next() {
+		var transactionID int64
+		if err = rows.Scan(&transactionID); err != nil {
+			return nil, err
+		}
+		transactionIDs = append(transactionIDs, transactionID)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(transactionIDs) != n {
+		return nil, coreerr.DatabaseQueryError
+	}
+	return transactionIDs, nil
+}
+
+// appendLedgerPostings inserts in tx the postings of every new posted transaction, which must be balanced,
+// setting them on the saved transaction of the same index. Pending transactions are posted by Post.
+func appendLedgerPostings(ctx context.Context, tx *sql.Tx, newTransactions []*transaction.Transaction, savedTransactions []*transaction.Transaction) error {
+	if err := checkLedgerPostings(newTransactions, savedTransactions); err != nil {
+		return err
+	}
+	stmt, err := tx.PrepareContext(ctx, "INSERT INTO ledger_entries (transaction_id, ledger_account, direction, amount, currency, created_at) VALUES ($1, $2, $3, $4, $5, $6) RETURNING entry_id")
+	if err != nil {
+		return coreerr.DatabasePrepareStatementError
+	}
+	defer stmt.Close()
+	for i, savedTransaction := range savedTransactions {
+		if newTransactions[i].Status == transaction.StatusPending {
+			continue
+		}
+		savedTransaction.Postings = make([]ledger.Posting, 0, len(newTransactions[i].Postings))
+		for _, posting := range newTransactions[i].Postings {
+			posting.TransactionID = savedTransaction.TransactionID
+			posting.CreatedAt = savedTransaction.PostedAt
+			entryModel := mapper.ToLedgerEntryModel(&posting)
+			err = stmt.QueryRowContext(ctx,
+				entryModel.TransactionID,
+				entryModel.LedgerAccount,
+				entryModel.Direction,
+				entryModel.Amount,
+				entryModel.Currency,
+				entryModel.CreatedAt).Scan(&posting.EntryID)
+			if err != nil {
+				return coreerr.DatabaseInsertionError
+			}
+			savedTransaction.Postings = append(savedTransaction.Postings, posting)
+		}
+	}
+	return nil
+}
+
+// checkLedgerPostings checks that every new transaction was saved as the transaction of the same index and that the
+// postings of the posted ones are balanced
+func checkLedgerPostings(newTransactions []*transaction.Transaction, savedTransactions []*transaction.Transaction) error {
+	if len(newTransactions) != len(savedTransactions) {
+		return coreerr.DatabaseInsertionError
+	}
+	for i, newTransaction := range newTransactions {
+		savedTransaction := savedTransactions[i]
+		if savedTransaction == nil || savedTransaction.AccountID != newTransaction.AccountID || savedTransaction.Amount != newTransaction.Amount {
+			return coreerr.DatabaseInsertionError
+		}
+		if newTransaction.Status != transaction.StatusPending && !ledger.Balanced(newTransaction.Postings) {
+			return coreerr.LedgerUnbalancedError
+		}
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/transaction"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckLedgerPostings(t *testing.T) {
+	now := time.Now()
+	newTransactions := []*transaction.Transaction{
+		newTestTransaction(t, 1, -10, transaction.StatusPosted, now),
+		newTestTransaction(t, 2, 20, transaction.StatusPosted, now),
+	}
+	saved := func(accountIDs ...int64) []*transaction.Transaction {
+		savedTransactions := make([]*transaction.Transaction, len(accountIDs))
+		for i, accountID := range accountIDs {
+			savedTransactions[i] = &transaction.Transaction{TransactionID: int64(i + 1), AccountID: accountID, Amount: newTransactions[i].Amount}
+		}
+		return savedTransactions
+	}
+	assert.NoError(t, checkLedgerPostings(newTransactions, saved(1, 2)))
+	assert.ErrorIs(t, checkLedgerPostings(newTransactions, saved(2, 1)), coreerr.DatabaseInsertionError,
+		"postings should not be written for the transaction of another row")
+	assert.ErrorIs(t, checkLedgerPostings(newTransactions, saved(1)), coreerr.DatabaseInsertionError)
+	assert.ErrorIs(t, checkLedgerPostings(newTransactions, []*transaction.Transaction{saved(1)[0], nil}), coreerr.DatabaseInsertionError)
+}
@@ -0,0 +1,399 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/adapter"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/clock"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/contextutils"
+	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/audit"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/ledger"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/transaction"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/database/mapper"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/database/model"
+	"slices"
+	"strings"
+	"time"
+)
+
+// sqliteTransactionInsert inserts a transaction with the values of sqliteTransactionValues
+const sqliteTransactionInsert = "INSERT INTO transactions(" + transactionInsertColumns + ") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17) RETURNING " + transactionSelectColumns
+
+type TransactionSQLiteRepository struct {
+	connectionData *adapter.DatabaseConnectionData
+	clock          clock.Clock
+	componentName  string
+	log            logger.Logger
+}
+
+func NewTransactionSQLiteRepository(connectionData *adapter.DatabaseConnectionData, clock clock.Clock, log logger.Logger) *TransactionSQLiteRepository {
+	repository := &TransactionSQLiteRepository{
+		connectionData: connectionData,
+		clock:          clock,
+		log:            log,
+	}
+	repository.componentName = logger.ComponentNameFromStruct(repository)
+	return repository
+}
+
+func (t *TransactionSQLiteRepository) Save(ctx context.Context, newTransaction *transaction.Transaction) (*transaction.Transaction, error) {
+	t.log.Debug(t.componentName+".Save", "newTransaction", newTransaction, "x_trace_id", contextutils.GetTraceID(ctx))
+	saved, err := t.insert(ctx, "Save", []*transaction.Transaction{newTransaction})
+	if err != nil {
+		return nil, err
+	}
+	return saved[0], nil
+}
+
+// SaveBatch inserts every transaction in one database transaction. The inserted rows are returned in the order they were given.
+func (t *TransactionSQLiteRepository) SaveBatch(ctx context.Context, newTransactions []*transaction.Transaction) ([]*transaction.Transaction, error) {
+	t.log.Debug(t.componentName+".SaveBatch", "rows", len(newTransactions), "x_trace_id", contextutils.GetTraceID(ctx))
+	if len(newTransactions) == 0 {
+		return nil, nil
+	}
+	return t.insert(ctx, "SaveBatch", newTransactions)
+}
+
+// insert inserts the transactions one at a time, SQLite does not return the rows of a multi-row insert in order,
+// with their postings and audit entries in one database transaction
+func (t *TransactionSQLiteRepository) insert(ctx context.Context, method string, newTransactions []*transaction.Transaction) ([]*transaction.Transaction, error) {
+	traceID := contextutils.GetTraceID(ctx)
+	tx, err := beginTx(ctx, t.connectionData.Db, nil)
+	if err != nil {
+		t.log.Warn(t.componentName+"."+method, "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseCreateTransactionError
+	}
+	defer tx.Rollback()
+	stmt, err := tx.PrepareContext(ctx, sqliteTransactionInsert)
+	if err != nil {
+		t.log.Warn(t.componentName+"."+method, "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabasePrepareStatementError
+	}
+	defer stmt.Close()
+	saved := make([]*transaction.Transaction, 0, len(newTransactions))
+	for _, newTransaction := range newTransactions {
+		transactionModel := mapper.ToTransactionModel(newTransaction)
+		if transactionModel == nil {
+			err = coreerr.InvalidParametersError
+			t.log.Warn(t.componentName+"."+method, "error", err, "x_trace_id", traceID)
+			return nil, err
+		}
+		if err = stmt.QueryRowContext(ctx, sqliteTransactionValues(transactionModel)...).Scan(transactionColumns(transactionModel)...); err != nil {
+			t.log.Warn(t.componentName+"."+method, "error", err, "x_trace_id", traceID)
+			return nil, coreerr.DatabaseInsertionError
+		}
+		saved = append(saved, mapper.ToTransactionEntity(transactionModel))
+	}
+	if err = appendSQLiteLedgerPostings(ctx, tx.Tx, newTransactions, saved); err != nil {
+		t.log.Warn(t.componentName+"."+method, "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
+	entries, err := transactionAuditEntries(ctx, t.clock.Now(), saved...)
+	if err == nil {
+		err = appendSQLiteAuditEntries(ctx, tx.Tx, entries...)
+	}
+	if err != nil {
+		t.log.Warn(t.componentName+"."+method, "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		t.log.Warn(t.componentName+"."+method, "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseFailToCommitError
+	}
+	return saved, nil
+}
+
+// FindDue returns up to limit pending transactions effective until the given date, the oldest first
+func (t *TransactionSQLiteRepository) FindDue(ctx context.Context, until time.Time, limit int64) ([]*transaction.Transaction, error) {
+	t.log.Debug(t.componentName+".FindDue", "until", until, "limit", limit, "x_trace_id", contextutils.GetTraceID(ctx))
+	return t.query(ctx, "FindDue", "SELECT "+transactionSelectColumns+" FROM transactions WHERE status = $1 AND effective_date <= $2 "+
+		"ORDER BY effective_date, transaction_id LIMIT $3", transaction.StatusPending, sqliteTimestamp(until), limit)
+}
+
+// Post marks a pending transaction as posted at dueTransaction.PostedAt and inserts its postings, which must be balanced
+func (t *TransactionSQLiteRepository) Post(ctx context.Context, dueTransaction *transaction.Transaction) (*transaction.Transaction, error) {
+	t.log.Debug(t.componentName+".Post", "transactionID", dueTransaction.TransactionID, "x_trace_id", contextutils.GetTraceID(ctx))
+	return t.leavePending(ctx, "Post", dueTransaction.TransactionID, audit.ActionTransactionPost,
+		"UPDATE transactions SET status = $2, posted_at = $3 WHERE transaction_id = $1 RETURNING "+transactionSelectColumns,
+		[]any{transaction.StatusPosted, sqliteTimestamp(dueTransaction.PostedAt)},
+		func(tx *sql.Tx, postedTransaction *transaction.Transaction) error {
+			return appendSQLiteLedgerPostings(ctx, tx, []*transaction.Transaction{dueTransaction}, []*transaction.Transaction{postedTransaction})
+		})
+}
+
+// Cancel marks a pending transaction as cancelled at cancelledAt
+func (t *TransactionSQLiteRepository) Cancel(ctx context.Context, transactionID int64, cancelledAt time.Time) (*transaction.Transaction, error) {
+	t.log.Debug(t.componentName+".Cancel", "transactionID", transactionID, "x_trace_id", contextutils.GetTraceID(ctx))
+	return t.leavePending(ctx, "Cancel", transactionID, audit.ActionTransactionCancel,
+		"UPDATE transactions SET status = $2, cancelled_at = $3 WHERE transaction_id = $1 RETURNING "+transactionSelectColumns,
+		[]any{transaction.StatusCancelled, sqliteTimestamp(cancelledAt)}, nil)
+}
+
+// leavePending runs update, whose first parameter is the transaction ID, on a pending transaction. SQLite has a single
+// writer, so the transaction can not change between the read and the update. The change, and whatever apply writes,
+// is recorded in the audit log in the same database transaction.
+func (t *TransactionSQLiteRepository) leavePending(ctx context.Context, method string, transactionID int64, action string, update string, args []any,
+	apply func(tx *sql.Tx, updatedTransaction *transaction.Transaction) error) (*transaction.Transaction, error) {
+	traceID := contextutils.GetTraceID(ctx)
+	tx, err := beginTx(ctx, t.connectionData.Db, nil)
+	if err != nil {
+		t.log.Warn(t.componentName+"."+method, "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseCreateTransactionError
+	}
+	defer tx.Rollback()
+	var beforeModel model.TransactionModel
+	err = tx.QueryRowContext(ctx, "SELECT "+transactionSelectColumns+" FROM transactions WHERE transaction_id = $1", transactionID).
+		Scan(transactionColumns(&beforeModel)...)
+	if err != nil {
+		t.log.Warn(t.componentName+"."+method, "error", err, "x_trace_id", traceID)
+		if err == sql.ErrNoRows {
+			return nil, coreerr.TransactionNotFoundError
+		}
+		return nil, coreerr.DatabaseQueryError
+	}
+	if beforeModel.Status != transaction.StatusPending {
+		err = coreerr.TransactionNotPendingError
+		t.log.Warn(t.componentName+"."+method, "error", err, "status", beforeModel.Status, "x_trace_id", traceID)
+		return nil, err
+	}
+	var afterModel model.TransactionModel
+	err = tx.QueryRowContext(ctx, update, append([]any{transactionID}, args...)...).Scan(transactionColumns(&afterModel)...)
+	if err != nil {
+		t.log.Warn(t.componentName+"."+method, "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseInsertionError
+	}
+	updatedTransaction := mapper.ToTransactionEntity(&afterModel)
+	if apply != nil {
+		if err = apply(tx.Tx, updatedTransaction); err != nil {
+			t.log.Warn(t.componentName+"."+method, "error", err, "x_trace_id", traceID)
+			return nil, err
+		}
+	}
+	entry, err := newAuditEntry(ctx, t.clock.Now(), action, audit.EntityTransaction, transactionID, mapper.ToTransactionEntity(&beforeModel), updatedTransaction)
+	if err == nil {
+		err = appendSQLiteAuditEntries(ctx, tx.Tx, entry)
+	}
+	if err != nil {
+		t.log.Warn(t.componentName+"."+method, "error", err, "x_trace_id", traceID)
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		t.log.Warn(t.componentName+"."+method, "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseFailToCommitError
+	}
+	return updatedTransaction, nil
+}
+
+// StreamTransactions calls fn with every posted transaction selected by filter, ordered by account and transaction id.
+// Rows are read in pages of streamFetchSize after the last streamed account and transaction id, so the single
+// connection of SQLite is only held while a page is read, not while fn runs, and the result set is never loaded in
+// memory at once. Pages are not read from a single snapshot. Errors returned by fn stop the stream and are returned as is.
+func (t *TransactionSQLiteRepository) StreamTransactions(ctx context.Context, filter transaction.TransactionFilter, fn func(*transaction.Transaction) error) error {
+	t.log.Debug(t.componentName+".StreamTransactions", "filter", filter, "x_trace_id", contextutils.GetTraceID(ctx))
+	conditions, args := sqliteFilterConditions(filter)
+	args = append(args, transaction.StatusPosted)
+	conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	conditions = append(conditions, fmt.Sprintf("(account_id, transaction_id) > ($%d, $%d)", len(args)+1, len(args)+2))
+	query := fmt.Sprintf("SELECT %s FROM transactions WHERE %s ORDER BY account_id, transaction_id LIMIT $%d",
+		transactionSelectColumns, strings.Join(conditions, " AND "), len(args)+3)
+	var lastAccountID, lastTransactionID int64
+	for {
+		page, err := t.query(ctx, "StreamTransactions", query, append(slices.Clone(args), lastAccountID, lastTransactionID, streamFetchSize)...)
+		if err != nil {
+			return err
+		}
+		for _, streamedTransaction := range page {
+			if err = fn(streamedTransaction); err != nil {
+				return err
+			}
+			lastAccountID, lastTransactionID = streamedTransaction.AccountID, streamedTransaction.TransactionID
+		}
+		if len(page) < streamFetchSize {
+			return nil
+		}
+	}
+}
+
+// Search returns up to search.Limit transactions matching every informed criteria, ordered by transaction id
+// and starting after search.Cursor
+func (t *TransactionSQLiteRepository) Search(ctx context.Context, search transaction.TransactionSearch) ([]*transaction.Transaction, error) {
+	t.log.Debug(t.componentName+".Search", "search", search, "x_trace_id", contextutils.GetTraceID(ctx))
+	conditions, args := sqliteFilterConditions(search.TransactionFilter)
+	addCondition := func(condition string, values ...any) {
+		placeholders := make([]any, 0, len(values))
+		for _, value := range values {
+			args = append(args, value)
+			placeholders = append(placeholders, len(args))
+		}
+		conditions = append(conditions, fmt.Sprintf(condition, placeholders...))
+	}
+	addCondition("transaction_id > $%d", search.Cursor)
+	if search.Status != "" {
+		addCondition("status = $%d", search.Status)
+	}
+	// LIKE matches ASCII letters of any case in SQLite
+	if search.MerchantName != "" {
+		addCondition(`merchant_name LIKE $%d ESCAPE '\'`, "%"+escapeLike(search.MerchantName)+"%")
+	}
+	if search.MerchantCategoryCode != "" {
+		addCondition("merchant_category_code = $%d", search.MerchantCategoryCode)
+	}
+	if search.MerchantCountry != "" {
+		addCondition("merchant_country = $%d", search.MerchantCountry)
+	}
+	if search.Description != "" {
+		addCondition(`description LIKE $%d ESCAPE '\'`, "%"+escapeLike(search.Description)+"%")
+	}
+	// Metadata keys are letters, digits, '_', '.' and '-', quoted in the JSON path so dots are not nested keys
+	keys := make([]string, 0, len(search.Metadata))
+	for key := range search.Metadata {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		addCondition("json_extract(metadata, $%d) = $%d", `$."`+key+`"`, search.Metadata[key])
+	}
+	args = append(args, search.Limit)
+	query := fmt.Sprintf("SELECT %s FROM transactions WHERE %s ORDER BY transaction_id LIMIT $%d",
+		transactionSelectColumns, strings.Join(conditions, " AND "), len(args))
+	return t.query(ctx, "Search", query, args...)
+}
+
+func (t *TransactionSQLiteRepository) FindOperationTypeByID(ctx context.Context, operationTypeID int) (*transaction.OperationType, error) {
+	traceID := contextutils.GetTraceID(ctx)
+	t.log.Debug(t.componentName+".FindOperationTypeByID", "operationTypeID", operationTypeID, "x_trace_id", traceID)
+	var selectedOperationType model.OperationTypeModel
+	err := queryerFor(ctx, t.connectionData.Db).QueryRowContext(ctx, "SELECT operation_type_id, description FROM operation_types WHERE operation_type_id = $1", operationTypeID).
+		Scan(&selectedOperationType.OperationTypeID, &selectedOperationType.Description)
+	if err != nil {
+		t.log.Warn(t.componentName+".FindOperationTypeByID", "error", err, "x_trace_id", traceID)
+		if err == sql.ErrNoRows {
+			return nil, coreerr.OperationTypeNotFoundError
+		}
+		return nil, coreerr.DatabaseQueryError
+	}
+	return mapper.ToOperationTypeEntity(&selectedOperationType), nil
+}
+
+func (t *TransactionSQLiteRepository) FindTransactionByID(ctx context.Context, transactionID int64) (*transaction.Transaction, error) {
+	traceID := contextutils.GetTraceID(ctx)
+	t.log.Debug(t.componentName+".FindTransactionByID", "transactionID", transactionID, "x_trace_id", traceID)
+	var transactionModel model.TransactionModel
+	err := queryerFor(ctx, t.connectionData.Db).QueryRowContext(ctx, "SELECT "+transactionSelectColumns+" FROM transactions WHERE transaction_id = $1", transactionID).
+		Scan(transactionColumns(&transactionModel)...)
+	if err != nil {
+		t.log.Warn(t.componentName+".FindTransactionByID", "error", err, "x_trace_id", traceID)
+		if err == sql.ErrNoRows {
+			return nil, coreerr.TransactionNotFoundError
+		}
+		return nil, coreerr.DatabaseQueryError
+	}
+	return mapper.ToTransactionEntity(&transactionModel), nil
+}
+
+// query returns the transactions selected by query
+func (t *TransactionSQLiteRepository) query(ctx context.Context, method string, query string, args ...any) ([]*transaction.Transaction, error) {
+	traceID := contextutils.GetTraceID(ctx)
+	rows, err := queryerFor(ctx, t.connectionData.Db).QueryContext(ctx, query, args...)
+	if err != nil {
+		t.log.Warn(t.componentName+"."+method, "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseQueryError
+	}
+	defer rows.Close()
+	transactions := make([]*transaction.Transaction, 0)
+	for rows.Next() {
+		var transactionModel model.TransactionModel
+		if err = rows.Scan(transactionColumns(&transactionModel)...); err != nil {
+			t.log.Warn(t.componentName+"."+method, "error", err, "x_trace_id", traceID)
+			return nil, coreerr.DatabaseQueryError
+		}
+		transactions = append(transactions, mapper.ToTransactionEntity(&transactionModel))
+	}
+	if err = rows.Err(); err != nil {
+		t.log.Warn(t.componentName+"."+method, "error", err, "x_trace_id", traceID)
+		return nil, coreerr.DatabaseQueryError
+	}
+	return transactions, nil
+}
+
+// sqliteFilterConditions returns the conditions selecting the transactions of filter, with their values
+func sqliteFilterConditions(filter transaction.TransactionFilter) ([]string, []any) {
+	var conditions []string
+	var args []any
+	addCondition := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.AccountID > 0 {
+		addCondition("account_id = $%d", filter.AccountID)
+	}
+	if !filter.From.IsZero() {
+		addCondition("event_date >= $%d", sqliteTimestamp(filter.From))
+	}
+	if !filter.To.IsZero() {
+		addCondition("event_date < $%d", sqliteTimestamp(filter.To))
+	}
+	return conditions, args
+}
+
+// sqliteTransactionValues returns the values of transactionInsertColumns, with the times as stored by SQLite
+func sqliteTransactionValues(transactionModel *model.TransactionModel) []any {
+	return []any{
+		transactionModel.AccountID,
+		transactionModel.OperationTypeID,
+		transactionModel.Amount,
+		transactionModel.Currency,
+		transactionModel.OriginalAmount,
+		transactionModel.OriginalCurrency,
+		transactionModel.ExchangeRate,
+		transactionModel.MerchantName,
+		transactionModel.MerchantCategory,
+		transactionModel.MerchantCountry,
+		transactionModel.Description,
+		transactionModel.Metadata,
+		sqliteTimestamp(transactionModel.EventDate),
+		transactionModel.Status,
+		sqliteTimestamp(transactionModel.EffectiveDate),
+		sqliteNullTimestamp(transactionModel.PostedAt),
+		sqliteTimestamp(transactionModel.CreatedAt),
+	}
+}
+
+// appendSQLiteLedgerPostings inserts in tx the postings of every new posted transaction, which must be balanced,
+// setting them on the saved transaction of the same index. Pending transactions are posted by Post.
+func appendSQLiteLedgerPostings(ctx context.Context, tx *sql.Tx, newTransactions []*transaction.Transaction, savedTransactions []*transaction.Transaction) error {
+	if err := checkLedgerPostings(newTransactions, savedTransactions); err != nil {
+		return err
+	}
+	stmt, err := tx.PrepareContext(ctx, "INSERT INTO ledger_entries (transaction_id, ledger_account, direction, amount, currency, created_at) VALUES ($1, $2, $3, $4, $5, $6) RETURNING entry_id")
+	if err != nil {
+		return coreerr.DatabasePrepareStatementError
+	}
+	defer stmt.Close()
+	for i, savedTransaction := range savedTransactions {
+		if newTransactions[i].Status == transaction.StatusPending {
+			continue
+		}
+		savedTransaction.Postings = make([]ledger.Posting, 0, len(newTransactions[i].Postings))
+		for _, posting := range newTransactions[i].Postings {
+			posting.TransactionID = savedTransaction.TransactionID
+			posting.CreatedAt = savedTransaction.PostedAt
+			entryModel := mapper.ToLedgerEntryModel(&posting)
+			err = stmt.QueryRowContext(ctx,
+				entryModel.TransactionID,
+				entryModel.LedgerAccount,
+				entryModel.Direction,
+				entryModel.Amount,
+				entryModel.Currency,
+				sqliteTimestamp(entryModel.CreatedAt)).Scan(&posting.EntryID)
+			if err != nil {
+				return coreerr.DatabaseInsertionError
+			}
+			savedTransaction.Postings = append(savedTransaction.Postings, posting)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/adapter"
+)
+
+// scopedTx is a transaction begun by a repository method, or the transaction of the unit of work it was called in,
+// which is committed or rolled back by the unit of work only
+type scopedTx struct {
+	*sql.Tx
+	joined bool
+}
+
+func (s *scopedTx) Commit() error {
+	if s.joined {
+		return nil
+	}
+	return s.Tx.Commit()
+}
+
+func (s *scopedTx) Rollback() error {
+	if s.joined {
+		return nil
+	}
+	return s.Tx.Rollback()
+}
+
+// beginTx joins the transaction of the unit of work running in ctx or begins a new one with opts
+func beginTx(ctx context.Context, db *sql.DB, opts *sql.TxOptions) (*scopedTx, error) {
+	if tx := adapter.TxFromContext(ctx); tx != nil {
+		return &scopedTx{Tx: tx, joined: true}, nil
+	}
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &scopedTx{Tx: tx}, nil
+}
+
+// queryer runs statements on the database or in a transaction
+type queryer interface {
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// queryerFor returns the transaction of the unit of work running in ctx, or db outside of a unit of work, for
+// single statement reads that need no transaction of their own
+func queryerFor(ctx context.Context, db *sql.DB) queryer {
+	if tx := adapter.TxFromContext(ctx); tx != nil {
+		return tx
+	}
+	return db
+}
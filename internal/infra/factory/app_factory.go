@@ -2,45 +2,90 @@ package factory
 
 import (
 	"context"
+	"fmt"
 	"github.com/kiosanim/pismo-code-assessment/interfaces/http/handler"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/adapter"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/auth"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/cache"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/clock"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/config"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/currency"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/idgen"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/lock"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/pii"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/ratelimit"
 	"github.com/kiosanim/pismo-code-assessment/internal/domains/account"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/audit"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/holder"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/ledger"
 	"github.com/kiosanim/pismo-code-assessment/internal/domains/transaction"
+	infraauth "github.com/kiosanim/pismo-code-assessment/internal/infra/auth"
+	infraclock "github.com/kiosanim/pismo-code-assessment/internal/infra/clock"
 	infraconfig "github.com/kiosanim/pismo-code-assessment/internal/infra/config"
+	infracurrency "github.com/kiosanim/pismo-code-assessment/internal/infra/currency"
 	"github.com/kiosanim/pismo-code-assessment/internal/infra/database/connection"
 	"github.com/kiosanim/pismo-code-assessment/internal/infra/database/repository"
+	infraidgen "github.com/kiosanim/pismo-code-assessment/internal/infra/idgen"
 	infralock "github.com/kiosanim/pismo-code-assessment/internal/infra/lock"
 	infralogger "github.com/kiosanim/pismo-code-assessment/internal/infra/logger"
+	infrapii "github.com/kiosanim/pismo-code-assessment/internal/infra/pii"
+	infraratelimit "github.com/kiosanim/pismo-code-assessment/internal/infra/ratelimit"
 	"log"
 	"os"
 )
 
+// lockManager takes the distributed locks and administers them, reloading its timings with the configuration
+type lockManager interface {
+	lock.DistributedLockManager
+	lock.Administrator
+	config.Reloadable
+}
+
 type AppFactory struct {
-	configuration       *config.Configuration
+	configWatcher       *infraconfig.ConfigWatcher
+	databaseDriver      string
 	connectionData      *adapter.DatabaseConnectionData
 	cacheConnectionData *adapter.CacheConnectionData
+	lockManager         lockManager
+	rateProvider        currency.RateProvider
+	fieldCipher         pii.FieldCipher
+	clock               clock.Clock
+	idGenerator         idgen.Generator
 	log                 logger.Logger
 }
 
 func NewAppFactory(ctx context.Context) AppFactory {
 	appFactory := AppFactory{}
-	configuration := appFactory.setupConfiguration()
+	path, _ := os.Getwd()
+	configuration := appFactory.setupConfiguration(path)
 	sLogger := infralogger.NewSlogLogger(ctx, configuration)
+	appFactory.databaseDriver = configuration.Database.Driver
 	connectionData := appFactory.setupDatabase(configuration)
 	cacheConnectionData := appFactory.setupCache(ctx, configuration)
-	appFactory.configuration = configuration
 	appFactory.log = sLogger
 	appFactory.connectionData = connectionData
 	appFactory.cacheConnectionData = cacheConnectionData
+	appFactory.clock = infraclock.NewSystemClock()
+	appFactory.idGenerator = infraidgen.NewRandomGenerator()
+	appFactory.lockManager = appFactory.setupLockManager(configuration)
+	appFactory.rateProvider = appFactory.setupRateProvider(configuration)
+	appFactory.fieldCipher = appFactory.setupFieldCipher(configuration)
+	appFactory.configWatcher = infraconfig.NewConfigWatcher(path, configuration, sLogger)
+	appFactory.configWatcher.Register(sLogger)
+	appFactory.configWatcher.Register(appFactory.lockManager)
 	return appFactory
 }
 
+// Configuration returns the configuration in effect, including values reloaded at runtime
 func (a *AppFactory) Configuration() *config.Configuration {
-	return a.configuration
+	return a.configWatcher.Current()
+}
+
+// ConfigWatcher returns the watcher responsible for hot reloading the configuration
+func (a *AppFactory) ConfigWatcher() *infraconfig.ConfigWatcher {
+	return a.configWatcher
 }
 
 func (a *AppFactory) ConnectionData() *adapter.DatabaseConnectionData {
@@ -52,8 +97,18 @@ func (a *AppFactory) CacheConnectionData() *adapter.CacheConnectionData {
 }
 
 func (a *AppFactory) AccountRepository() account.AccountRepository {
+	if a.sqlite() {
+		return repository.NewAccountSQLiteRepository(
+			a.connectionData,
+			a.fieldCipher,
+			a.clock,
+			a.log,
+		)
+	}
 	return repository.NewAccountPostgresRepository(
 		a.connectionData,
+		a.fieldCipher,
+		a.clock,
 		a.log,
 	)
 }
@@ -68,7 +123,73 @@ func (a *AppFactory) AccountRepository() account.AccountRepository {
 //}
 
 func (a *AppFactory) TransactionRepository() transaction.TransactionRepository {
+	if a.sqlite() {
+		return repository.NewTransactionSQLiteRepository(
+			a.connectionData,
+			a.clock,
+			a.log,
+		)
+	}
 	return repository.NewTransactionPostgresRepository(
+		a.connectionData,
+		a.clock,
+		a.log,
+	)
+}
+
+func (a *AppFactory) AuditLogRepository() audit.AuditLogRepository {
+	if a.sqlite() {
+		return repository.NewAuditLogSQLiteRepository(
+			a.connectionData,
+			a.log,
+		)
+	}
+	return repository.NewAuditLogPostgresRepository(
+		a.connectionData,
+		a.log,
+	)
+}
+
+func (a *AppFactory) HolderRepository() holder.HolderRepository {
+	if a.sqlite() {
+		return repository.NewHolderSQLiteRepository(
+			a.connectionData,
+			a.fieldCipher,
+			a.clock,
+			a.log,
+		)
+	}
+	return repository.NewHolderPostgresRepository(
+		a.connectionData,
+		a.fieldCipher,
+		a.clock,
+		a.log,
+	)
+}
+
+// UnitOfWork returns the unit of work running the calls of the repositories in one database transaction
+func (a *AppFactory) UnitOfWork() adapter.UnitOfWork {
+	if a.sqlite() {
+		return repository.NewSQLiteUnitOfWork(
+			a.connectionData,
+			a.log,
+		)
+	}
+	return repository.NewPostgresUnitOfWork(
+		a.connectionData,
+		a.log,
+	)
+}
+
+// LedgerRepository returns the repository checking the ledger invariants, postings are written by the TransactionRepository
+func (a *AppFactory) LedgerRepository() ledger.LedgerRepository {
+	if a.sqlite() {
+		return repository.NewLedgerSQLiteRepository(
+			a.connectionData,
+			a.log,
+		)
+	}
+	return repository.NewLedgerPostgresRepository(
 		a.connectionData,
 		a.log,
 	)
@@ -93,6 +214,21 @@ func (a *AppFactory) AccountHandler(accountService account.Service) *handler.Acc
 func (a *AppFactory) TransactionHandler(transactionService transaction.Service) *handler.TransactionHandler {
 	return handler.NewTransactionHandler(
 		transactionService,
+		a.clock,
+		a.log,
+	)
+}
+
+func (a *AppFactory) AuditHandler(auditService audit.Service) *handler.AuditHandler {
+	return handler.NewAuditHandler(
+		auditService,
+		a.log,
+	)
+}
+
+func (a *AppFactory) HolderHandler(holderService holder.Service) *handler.HolderHandler {
+	return handler.NewHolderHandler(
+		holderService,
 		a.log,
 	)
 }
@@ -105,7 +241,64 @@ func (a *AppFactory) CacheRepository() cache.CacheRepository {
 }
 
 func (a *AppFactory) DistributedLockManager() lock.DistributedLockManager {
-	return infralock.NewRedisDistributedLockManager(a.cacheConnectionData, a.configuration, a.log)
+	return a.lockManager
+}
+
+// LockAdministrator returns the lock manager as an Administrator, to inspect and release the locks of any instance
+func (a *AppFactory) LockAdministrator() lock.Administrator {
+	return a.lockManager
+}
+
+// CachePurger returns the cache repository as a Purger, to remove cached entities in bulk
+func (a *AppFactory) CachePurger() cache.Purger {
+	return repository.NewRedisRepository(
+		a.cacheConnectionData,
+		a.log,
+	)
+}
+
+func (a *AppFactory) RateLimiter() ratelimit.RateLimiter {
+	return infraratelimit.NewRedisRateLimiter(a.cacheConnectionData, a.log)
+}
+
+func (a *AppFactory) RateProvider() currency.RateProvider {
+	return a.rateProvider
+}
+
+func (a *AppFactory) Clock() clock.Clock {
+	return a.clock
+}
+
+func (a *AppFactory) IDGenerator() idgen.Generator {
+	return a.idGenerator
+}
+
+func (a *AppFactory) FieldCipher() pii.FieldCipher {
+	return a.fieldCipher
+}
+
+// Authenticators builds the authenticators enabled in the auth configuration, none when auth is disabled
+func (a *AppFactory) Authenticators() ([]auth.Authenticator, error) {
+	authConfig := a.Configuration().Auth
+	if !authConfig.Enabled {
+		a.log.Warn("AppFactory.Authenticators", "status", "authentication disabled, all routes are public")
+		return nil, nil
+	}
+	var authenticators []auth.Authenticator
+	if authConfig.JWT.Algorithm != "" {
+		jwtAuthenticator, err := infraauth.NewJWTAuthenticator(authConfig.JWT, a.log)
+		if err != nil {
+			return nil, err
+		}
+		authenticators = append(authenticators, jwtAuthenticator)
+	}
+	if len(authConfig.APIKeys) > 0 {
+		authenticators = append(authenticators, infraauth.NewAPIKeyAuthenticator(authConfig.APIKeys, a.log))
+	}
+	if len(authenticators) == 0 {
+		return nil, fmt.Errorf("%w: auth is enabled but neither auth.jwt nor auth.api_keys are configured", errors.ConfigValidationError)
+	}
+	return authenticators, nil
 }
 
 func (a *AppFactory) Log() logger.Logger {
@@ -121,8 +314,17 @@ func (a *AppFactory) setupCache(ctx context.Context, cfg *config.Configuration)
 	return cacheConnectionData
 }
 
+// setupDatabase connects to the database of database.driver, Postgres by default
 func (a *AppFactory) setupDatabase(cfg *config.Configuration) *adapter.DatabaseConnectionData {
-	var conn adapter.DatabaseConnection = connection.NewPostgresConnection(cfg)
+	var conn adapter.DatabaseConnection
+	switch cfg.Database.Driver {
+	case "", config.DatabaseDriverPostgres:
+		conn = connection.NewPostgresConnection(cfg)
+	case config.DatabaseDriverSQLite:
+		conn = connection.NewSQLiteConnection(cfg)
+	default:
+		panic(fmt.Errorf("%w: unknown database.driver %q", errors.ConfigValidationError, cfg.Database.Driver))
+	}
 	dbConnectionData, err := conn.Connect()
 	if err != nil {
 		panic(err)
@@ -130,8 +332,46 @@ func (a *AppFactory) setupDatabase(cfg *config.Configuration) *adapter.DatabaseC
 	return dbConnectionData
 }
 
-func (a *AppFactory) setupConfiguration() *config.Configuration {
-	path, _ := os.Getwd()
+// setupLockManager creates the lock manager of distributed_lock.backend, Redis by default
+func (a *AppFactory) setupLockManager(cfg *config.Configuration) lockManager {
+	switch cfg.DistributedLock.Backend {
+	case "", config.LockBackendRedis:
+		return infralock.NewRedisDistributedLockManager(a.cacheConnectionData, cfg, a.clock, a.idGenerator, a.log)
+	case config.LockBackendPostgres:
+		if a.sqlite() {
+			panic(fmt.Errorf("%w: distributed_lock.backend %q needs database.driver %q", errors.ConfigValidationError, cfg.DistributedLock.Backend, config.DatabaseDriverPostgres))
+		}
+		return infralock.NewPostgresAdvisoryLockManager(a.connectionData, cfg, a.idGenerator, a.log)
+	default:
+		panic(fmt.Errorf("%w: unknown distributed_lock.backend %q", errors.ConfigValidationError, cfg.DistributedLock.Backend))
+	}
+}
+
+// sqlite tells whether the repositories are backed by SQLite instead of Postgres
+func (a *AppFactory) sqlite() bool {
+	return a.databaseDriver == config.DatabaseDriverSQLite
+}
+
+func (a *AppFactory) setupRateProvider(cfg *config.Configuration) currency.RateProvider {
+	if cfg.Currency.RatesFile == "" {
+		a.log.Warn("AppFactory.setupRateProvider", "status", "currency.rates_file not configured, foreign currency transactions are rejected")
+	}
+	rateProvider, err := infracurrency.NewFileRateProvider(cfg.Currency.RatesFile)
+	if err != nil {
+		panic(err)
+	}
+	return rateProvider
+}
+
+func (a *AppFactory) setupFieldCipher(cfg *config.Configuration) pii.FieldCipher {
+	fieldCipher, err := infrapii.NewAESFieldCipher(cfg.PII)
+	if err != nil {
+		panic(err)
+	}
+	return fieldCipher
+}
+
+func (a *AppFactory) setupConfiguration(path string) *config.Configuration {
 	cfg, err := infraconfig.LoadConfig(path)
 	if err != nil {
 		log.Fatal(err)
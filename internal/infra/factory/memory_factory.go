@@ -0,0 +1,202 @@
+package factory
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/kiosanim/pismo-code-assessment/interfaces/http/handler"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/adapter"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/cache"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/clock"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/config"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/currency"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/idgen"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/lock"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/pii"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/ratelimit"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/account"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/audit"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/holder"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/transaction"
+	infraclock "github.com/kiosanim/pismo-code-assessment/internal/infra/clock"
+	infracurrency "github.com/kiosanim/pismo-code-assessment/internal/infra/currency"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/database/memory"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/database/repository"
+	infraidgen "github.com/kiosanim/pismo-code-assessment/internal/infra/idgen"
+	infralock "github.com/kiosanim/pismo-code-assessment/internal/infra/lock"
+	infrapii "github.com/kiosanim/pismo-code-assessment/internal/infra/pii"
+	infraratelimit "github.com/kiosanim/pismo-code-assessment/internal/infra/ratelimit"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+// redisTick is how often the in-process Redis clock is moved forward, expiring keys
+const redisTick = 100 * time.Millisecond
+
+// MemoryFactory builds the application on the memory repositories and an in-process Redis, so the API runs without
+// any external service. Locks, rate limits and the cache run the same Redis commands as with the AppFactory.
+type MemoryFactory struct {
+	configuration         *config.Configuration
+	redis                 *miniredis.Miniredis
+	cacheConnectionData   *adapter.CacheConnectionData
+	accountRepository     *memory.AccountMemoryRepository
+	transactionRepository *memory.TransactionMemoryRepository
+	auditLogRepository    *memory.AuditLogMemoryRepository
+	holderRepository      *memory.HolderMemoryRepository
+	lockManager           *infralock.RedisDistributedLockManager
+	rateProvider          currency.RateProvider
+	clock                 clock.Clock
+	idGenerator           idgen.Generator
+	fieldCipher           pii.FieldCipher
+	log                   logger.Logger
+}
+
+// NewMemoryFactory starts the in-process Redis, which runs until ctx is done
+func NewMemoryFactory(ctx context.Context, configuration *config.Configuration, log logger.Logger) (*MemoryFactory, error) {
+	server, err := miniredis.Run()
+	if err != nil {
+		return nil, err
+	}
+	rateProvider, err := infracurrency.NewFileRateProvider(configuration.Currency.RatesFile)
+	if err != nil {
+		server.Close()
+		return nil, err
+	}
+	fieldCipher, err := newEphemeralFieldCipher()
+	if err != nil {
+		server.Close()
+		return nil, err
+	}
+	memoryFactory := &MemoryFactory{
+		configuration:         configuration,
+		redis:                 server,
+		cacheConnectionData:   &adapter.CacheConnectionData{Rdb: redis.NewClient(&redis.Options{Addr: server.Addr()})},
+		accountRepository:     memory.NewAccountMemoryRepository(log),
+		transactionRepository: memory.NewTransactionMemoryRepository(log),
+		auditLogRepository:    memory.NewAuditLogMemoryRepository(),
+		holderRepository:      memory.NewHolderMemoryRepository(log),
+		rateProvider:          rateProvider,
+		clock:                 infraclock.NewSystemClock(),
+		idGenerator:           infraidgen.NewRandomGenerator(),
+		fieldCipher:           fieldCipher,
+		log:                   log,
+	}
+	memoryFactory.lockManager = infralock.NewRedisDistributedLockManager(memoryFactory.cacheConnectionData, configuration,
+		memoryFactory.clock, memoryFactory.idGenerator, log)
+	go memoryFactory.expireKeys(ctx)
+	return memoryFactory, nil
+}
+
+// newEphemeralFieldCipher returns a field cipher with random keys, enough for the in-process cache which does not
+// outlive the factory
+func newEphemeralFieldCipher() (*infrapii.AESFieldCipher, error) {
+	keys := make([]string, 2)
+	for i := range keys {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+		keys[i] = base64.StdEncoding.EncodeToString(key)
+	}
+	return infrapii.NewAESFieldCipher(config.PIIConfig{EncryptionKey: keys[0], BlindIndexKey: keys[1]})
+}
+
+// expireKeys moves the in-process Redis clock forward, which unlike Redis does not expire keys by itself
+func (m *MemoryFactory) expireKeys(ctx context.Context) {
+	ticker := time.NewTicker(redisTick)
+	defer ticker.Stop()
+	defer m.cacheConnectionData.Rdb.Close()
+	defer m.redis.Close()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.redis.FastForward(redisTick)
+		}
+	}
+}
+
+func (m *MemoryFactory) Configuration() *config.Configuration {
+	return m.configuration
+}
+
+// ConnectionData returns nil, the memory repositories use no database
+func (m *MemoryFactory) ConnectionData() *adapter.DatabaseConnectionData {
+	return nil
+}
+
+func (m *MemoryFactory) CacheConnectionData() *adapter.CacheConnectionData {
+	return m.cacheConnectionData
+}
+
+func (m *MemoryFactory) AccountRepository() account.AccountRepository {
+	return m.accountRepository
+}
+
+func (m *MemoryFactory) TransactionRepository() transaction.TransactionRepository {
+	return m.transactionRepository
+}
+
+func (m *MemoryFactory) AuditLogRepository() audit.AuditLogRepository {
+	return m.auditLogRepository
+}
+
+func (m *MemoryFactory) HolderRepository() holder.HolderRepository {
+	return m.holderRepository
+}
+
+func (m *MemoryFactory) UnitOfWork() adapter.UnitOfWork {
+	return memory.NewMemoryUnitOfWork()
+}
+
+func (m *MemoryFactory) AccountHandler(accountService account.Service) *handler.AccountHandler {
+	return handler.NewAccountHandler(accountService, m.log)
+}
+
+func (m *MemoryFactory) TransactionHandler(transactionService transaction.Service) *handler.TransactionHandler {
+	return handler.NewTransactionHandler(transactionService, m.clock, m.log)
+}
+
+func (m *MemoryFactory) AuditHandler(auditService audit.Service) *handler.AuditHandler {
+	return handler.NewAuditHandler(auditService, m.log)
+}
+
+func (m *MemoryFactory) HolderHandler(holderService holder.Service) *handler.HolderHandler {
+	return handler.NewHolderHandler(holderService, m.log)
+}
+
+func (m *MemoryFactory) CacheRepository() cache.CacheRepository {
+	return repository.NewRedisRepository(m.cacheConnectionData, m.log)
+}
+
+func (m *MemoryFactory) DistributedLockManager() lock.DistributedLockManager {
+	return m.lockManager
+}
+
+func (m *MemoryFactory) RateLimiter() ratelimit.RateLimiter {
+	return infraratelimit.NewRedisRateLimiter(m.cacheConnectionData, m.log)
+}
+
+func (m *MemoryFactory) RateProvider() currency.RateProvider {
+	return m.rateProvider
+}
+
+func (m *MemoryFactory) Clock() clock.Clock {
+	return m.clock
+}
+
+func (m *MemoryFactory) IDGenerator() idgen.Generator {
+	return m.idGenerator
+}
+
+// FieldCipher returns the cipher of the cached accounts, the memory repositories store document numbers in clear text
+func (m *MemoryFactory) FieldCipher() pii.FieldCipher {
+	return m.fieldCipher
+}
+
+func (m *MemoryFactory) Log() logger.Logger {
+	return m.log
+}
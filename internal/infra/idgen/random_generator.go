@@ -0,0 +1,25 @@
+package idgen
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// idSize is the number of random bytes of an ID, 128 bits make collisions negligible
+const idSize = 16
+
+// RandomGenerator returns hex encoded random IDs read from crypto/rand
+type RandomGenerator struct{}
+
+func NewRandomGenerator() *RandomGenerator {
+	return &RandomGenerator{}
+}
+
+// NewID returns 32 hex characters. crypto/rand never fails on supported platforms, it panics otherwise.
+func (r *RandomGenerator) NewID() string {
+	id := make([]byte, idSize)
+	if _, err := rand.Read(id); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,18 @@
+package idgen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRandomGeneratorNewID(t *testing.T) {
+	generator := NewRandomGenerator()
+	seen := make(map[string]bool)
+	for range 100 {
+		id := generator.NewID()
+		assert.Len(t, id, 2*idSize, "IDs should be hex encoded")
+		assert.False(t, seen[id], "IDs should not repeat")
+		seen[id] = true
+	}
+}
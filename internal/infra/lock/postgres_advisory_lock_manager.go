@@ -0,0 +1,246 @@
+package lock
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/adapter"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/config"
+	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/idgen"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/lock"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
+	"github.com/lib/pq"
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// lockNotAvailable is the Postgres error code of a lock not acquired within lock_timeout
+const lockNotAvailable = "55P03"
+
+// PostgresAdvisoryLockManager takes Postgres transaction level advisory locks (pg_advisory_xact_lock), released when
+// the transaction holding them ends. A lock taken with the context of a unit of work is held by its transaction, so it
+// covers the changes made in it and is released by their commit or rollback. Other locks begin their own transaction,
+// released on Unlock, when the context they were taken with is done or when their connection is closed. Locks have
+// no ttl: they never expire while their holder is alive.
+type PostgresAdvisoryLockManager struct {
+	connectionData *adapter.DatabaseConnectionData
+	timings        atomic.Pointer[config.DistributedLock]
+	idGenerator    idgen.Generator
+	componentName  string
+	log            logger.Logger
+}
+
+func NewPostgresAdvisoryLockManager(
+	connectionData *adapter.DatabaseConnectionData,
+	configuration *config.Configuration,
+	idGenerator idgen.Generator,
+	log logger.Logger) *PostgresAdvisoryLockManager {
+	manager := &PostgresAdvisoryLockManager{
+		connectionData: connectionData,
+		idGenerator:    idGenerator,
+		log:            log,
+	}
+	manager.ApplyConfiguration(configuration)
+	manager.componentName = logger.ComponentNameFromStruct(manager)
+	return manager
+}
+
+// ApplyConfiguration replaces the default lock timings used by WaitToLockUsingDefaultTimeConfiguration
+func (p *PostgresAdvisoryLockManager) ApplyConfiguration(cfg *config.Configuration) {
+	timings := cfg.DistributedLock
+	p.timings.Store(&timings)
+}
+
+// Lock tries to acquire a lock once, failing with DistributedLockFailToAcquire when another transaction holds it.
+// Advisory locks do not expire, so ttl is not used.
+func (p *PostgresAdvisoryLockManager) Lock(ctx context.Context, key string, ttl time.Duration) (*lock.Lock, error) {
+	return p.acquire(ctx, key, 0)
+}
+
+// WaitToLock waits up to waitingTime for the lock in the queue of Postgres, so retryInterval is not used, nor ttl
+func (p *PostgresAdvisoryLockManager) WaitToLock(ctx context.Context, key string, ttl time.Duration, waitingTime time.Duration, retryInterval time.Duration) (*lock.Lock, error) {
+	p.log.Debug(p.componentName+".WaitToLock", "status", "Trying to acquire lock...", "key", key)
+	if waitingTime <= 0 {
+		return nil, coreerr.DistributedLockFailToAcquire
+	}
+	return p.acquire(ctx, key, waitingTime)
+}
+
+func (p *PostgresAdvisoryLockManager) WaitToLockUsingDefaultTimeConfiguration(ctx context.Context, key string) (*lock.Lock, error) {
+	p.log.Debug(p.componentName + ".WaitToLockUsingDefaultTimeConfiguration")
+	timings := p.timings.Load()
+	waitingTime := time.Duration(timings.WaitingTime) * time.Millisecond
+	retryInterval := time.Duration(timings.RetryInterval) * time.Millisecond
+	ttl := time.Duration(timings.TTL) * time.Millisecond
+	return p.WaitToLock(ctx, key, ttl, waitingTime, retryInterval)
+}
+
+// Unlock commits the transaction of a lock. Locks held by a unit of work are left to its commit or rollback, and
+// locks already released are ignored, like Redis locks that expired.
+func (p *PostgresAdvisoryLockManager) Unlock(ctx context.Context, acquiredLock *lock.Lock) error {
+	if acquiredLock.Tx == nil {
+		return coreerr.InvalidParametersError
+	}
+	if acquiredLock.Joined {
+		p.log.Debug(p.componentName+".Unlock", "lock released by its unit of work:", acquiredLock.Key)
+		return nil
+	}
+	err := acquiredLock.Tx.Commit()
+	if errors.Is(err, sql.ErrTxDone) {
+		p.log.Debug(p.componentName+".Unlock", "lock already released:", acquiredLock.Key)
+		return nil
+	}
+	if err != nil {
+		p.log.Debug(p.componentName+".Unlock", "failed to release lock:", acquiredLock.Key, "err", err)
+		return err
+	}
+	p.log.Debug(p.componentName+".Unlock", "releasing lock:", acquiredLock.Key)
+	return nil
+}
+
+// Refresh checks that the transaction of a lock is still running, failing with DistributedLockNotHeldError when it
+// already ended. Advisory locks do not expire, so ttl is not used.
+func (p *PostgresAdvisoryLockManager) Refresh(ctx context.Context, acquiredLock *lock.Lock, ttl time.Duration) error {
+	if acquiredLock.Tx == nil {
+		return coreerr.DistributedLockNotHeldError
+	}
+	if _, err := acquiredLock.Tx.ExecContext(ctx, "SELECT 1"); err != nil {
+		p.log.Debug(p.componentName+".Refresh", "lock not held:", acquiredLock.Key, "err", err)
+		return coreerr.DistributedLockNotHeldError
+	}
+	return nil
+}
+
+// List returns the advisory locks held in the database. Locks are named by lock.Keys, the others by their number.
+// Their TTL is negative, they never expire.
+func (p *PostgresAdvisoryLockManager) List(ctx context.Context) ([]lock.HeldLock, error) {
+	rows, err := p.connectionData.Db.QueryContext(ctx, `SELECT (classid::bigint << 32) | objid::bigint, pid FROM pg_locks
+		WHERE locktype = 'advisory' AND granted AND objsubid = 1 AND database = (SELECT oid FROM pg_database WHERE datname = current_database())`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	keys := make(map[int64]string, len(lock.Keys))
+	for _, key := range lock.Keys {
+		keys[advisoryLockID(key)] = key
+	}
+	var heldLocks []lock.HeldLock
+	for rows.Next() {
+		var lockID, pid int64
+		if err = rows.Scan(&lockID, &pid); err != nil {
+			return nil, err
+		}
+		key, ok := keys[lockID]
+		if !ok {
+			key = strconv.FormatInt(lockID, 10)
+		}
+		heldLocks = append(heldLocks, lock.HeldLock{Key: key, Value: fmt.Sprintf("pid %d", pid), TTL: -1})
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	slices.SortFunc(heldLocks, func(a, b lock.HeldLock) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	return heldLocks, nil
+}
+
+// ForceRelease terminates the database connection holding a lock, which rolls back its transaction
+func (p *PostgresAdvisoryLockManager) ForceRelease(ctx context.Context, key string) (bool, error) {
+	if !strings.HasPrefix(key, lock.KeyPrefix) {
+		return false, coreerr.InvalidParametersError
+	}
+	lockID := advisoryLockID(key)
+	var released bool
+	err := p.connectionData.Db.QueryRowContext(ctx, `SELECT coalesce(bool_or(pg_terminate_backend(pid)), false) FROM pg_locks
+		WHERE locktype = 'advisory' AND granted AND objsubid = 1 AND classid = $1 AND objid = $2`,
+		uint32(uint64(lockID)>>32), uint32(lockID)).Scan(&released)
+	if err != nil {
+		p.log.Debug(p.componentName+".ForceRelease", "failed to release lock:", key, "err", err)
+		return false, err
+	}
+	p.log.Debug(p.componentName+".ForceRelease", "released lock:", key, "released", released)
+	return released, nil
+}
+
+// acquire takes the lock in the transaction of the unit of work running in ctx, or else in a transaction of its
+// own, waiting up to waitingTime for it when it is greater than zero
+func (p *PostgresAdvisoryLockManager) acquire(ctx context.Context, key string, waitingTime time.Duration) (*lock.Lock, error) {
+	tx := adapter.TxFromContext(ctx)
+	joined := tx != nil
+	if !joined {
+		var err error
+		if tx, err = p.connectionData.Db.BeginTx(ctx, nil); err != nil {
+			p.log.Debug(p.componentName+".Lock", "err", err)
+			return nil, coreerr.DatabaseCreateTransactionError
+		}
+	}
+	// A joined transaction is rolled back by its unit of work, which gets the error
+	rollback := func() {
+		if !joined {
+			_ = tx.Rollback()
+		}
+	}
+	acquired, err := p.tryLock(ctx, tx, key, waitingTime)
+	if err != nil {
+		rollback()
+		p.log.Debug(p.componentName+".Lock", "err", err)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	if !acquired {
+		rollback()
+		p.log.Debug(p.componentName+".Lock", "lock not acquired:", key)
+		return nil, coreerr.DistributedLockFailToAcquire
+	}
+	p.log.Debug(p.componentName+".Lock", "Lock acquired:", key, "joined", joined)
+	return &lock.Lock{Key: key, Value: p.idGenerator.NewID(), Tx: tx, Joined: joined}, nil
+}
+
+// tryLock takes the advisory lock of key in tx, waiting up to waitingTime for it when it is greater than zero. The
+// lock_timeout of tx is restored once the lock is taken, so it does not apply to the next statements of a unit of
+// work. A lock not taken in time aborts tx.
+func (p *PostgresAdvisoryLockManager) tryLock(ctx context.Context, tx *sql.Tx, key string, waitingTime time.Duration) (bool, error) {
+	if waitingTime <= 0 {
+		var acquired bool
+		err := tx.QueryRowContext(ctx, "SELECT pg_try_advisory_xact_lock($1)", advisoryLockID(key)).Scan(&acquired)
+		return acquired, err
+	}
+	var lockTimeout string
+	if err := tx.QueryRowContext(ctx, "SELECT current_setting('lock_timeout')").Scan(&lockTimeout); err != nil {
+		return false, err
+	}
+	if err := setLocal(ctx, tx, "lock_timeout", strconv.FormatInt(waitingTime.Milliseconds(), 10)); err != nil {
+		return false, err
+	}
+	_, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", advisoryLockID(key))
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == lockNotAvailable {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, setLocal(ctx, tx, "lock_timeout", lockTimeout)
+}
+
+// setLocal sets a setting of Postgres until the end of tx
+func setLocal(ctx context.Context, tx *sql.Tx, setting string, value string) error {
+	_, err := tx.ExecContext(ctx, "SELECT set_config($1, $2, true)", setting, value)
+	return err
+}
+
+// advisoryLockID returns the number identifying the advisory lock of a key, the 64 bits FNV-1a hash of the key
+func advisoryLockID(key string) int64 {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(key))
+	return int64(hash.Sum64())
+}
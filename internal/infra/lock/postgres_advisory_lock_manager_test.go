@@ -0,0 +1,96 @@
+package lock
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/kiosanim/pismo-code-assessment/internal/core/adapter"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/config"
+	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/idgen"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/lock"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/logger/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testDatabaseURLEnv names the variable with the URL of the Postgres database of the tests taking advisory locks
+const testDatabaseURLEnv = "TEST_DATABASE_URL"
+
+func TestAdvisoryLockID(t *testing.T) {
+	assert.Equal(t, advisoryLockID(lock.TransactionCreationLockKey), advisoryLockID(lock.TransactionCreationLockKey),
+		"every instance should take the same advisory lock for a key")
+	seen := make(map[int64]string)
+	for _, key := range lock.Keys {
+		id := advisoryLockID(key)
+		assert.NotContains(t, seen, id, "%s should not share its advisory lock with %s", key, seen[id])
+		seen[id] = key
+	}
+}
+
+func TestPostgresAdvisoryLockManagerWithoutTransaction(t *testing.T) {
+	manager := NewPostgresAdvisoryLockManager(&adapter.DatabaseConnectionData{}, &config.Configuration{},
+		idgen.NewFakeGenerator("holder"), mock.NewMockLogger())
+	ctx := context.Background()
+	notHeld := &lock.Lock{Key: testLockKey, Value: "holder-1"}
+	assert.ErrorIs(t, manager.Refresh(ctx, notHeld, time.Minute), coreerr.DistributedLockNotHeldError)
+	assert.ErrorIs(t, manager.Unlock(ctx, notHeld), coreerr.InvalidParametersError, "a lock without its transaction can not be released")
+	_, err := manager.ForceRelease(ctx, "transactions")
+	assert.ErrorIs(t, err, coreerr.InvalidParametersError, "only lock keys should be released")
+	_, err = manager.WaitToLock(ctx, testLockKey, time.Minute, 0, time.Second)
+	assert.ErrorIs(t, err, coreerr.DistributedLockFailToAcquire, "no lock should be acquired without waiting time")
+}
+
+func TestPostgresAdvisoryLockManagerInUnitOfWork(t *testing.T) {
+	url := os.Getenv(testDatabaseURLEnv)
+	if url == "" {
+		t.Skipf("%s is not set", testDatabaseURLEnv)
+	}
+	db, err := sql.Open("postgres", url)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	manager := NewPostgresAdvisoryLockManager(&adapter.DatabaseConnectionData{Db: db},
+		&config.Configuration{DistributedLock: config.DistributedLock{WaitingTime: 5000}},
+		idgen.NewFakeGenerator("holder"), mock.NewMockLogger())
+	ctx := context.Background()
+	var defaultLockTimeout string
+	require.NoError(t, db.QueryRowContext(ctx, "SHOW lock_timeout").Scan(&defaultLockTimeout))
+
+	first, err := db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	defer first.Rollback()
+	held, err := manager.WaitToLockUsingDefaultTimeConfiguration(adapter.ContextWithTx(ctx, first), testLockKey)
+	require.NoError(t, err)
+	assert.True(t, held.Joined, "the lock should be held by the transaction of the unit of work")
+	assert.Same(t, first, held.Tx)
+
+	second, err := db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	defer second.Rollback()
+	acquired := make(chan error, 1)
+	go func() {
+		_, err := manager.WaitToLockUsingDefaultTimeConfiguration(adapter.ContextWithTx(ctx, second), testLockKey)
+		acquired <- err
+	}()
+	require.NoError(t, manager.Unlock(ctx, held), "unlocking a joined lock should leave it to its unit of work")
+	select {
+	case err = <-acquired:
+		t.Fatalf("the second holder should wait for the first to commit, got %v", err)
+	case <-time.After(300 * time.Millisecond):
+	}
+	require.NoError(t, first.Commit())
+	select {
+	case err = <-acquired:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the second holder should get the lock once the first committed")
+	}
+	var lockTimeout string
+	require.NoError(t, second.QueryRowContext(ctx, "SHOW lock_timeout").Scan(&lockTimeout))
+	assert.Equal(t, defaultLockTimeout, lockTimeout, "the lock_timeout of the unit of work should be restored once the lock is taken")
+	_, err = manager.Lock(ctx, testLockKey, time.Minute)
+	assert.ErrorIs(t, err, coreerr.DistributedLockFailToAcquire, "the lock should be held until the second transaction ends")
+}
@@ -4,17 +4,24 @@ import (
 	"context"
 	"errors"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/adapter"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/clock"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/config"
 	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/idgen"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/lock"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
 	"github.com/redis/go-redis/v9"
+	"slices"
+	"strings"
+	"sync/atomic"
 	"time"
 )
 
 type RedisDistributedLockManager struct {
 	cacheConnectionData *adapter.CacheConnectionData
-	configuration       *config.Configuration
+	timings             atomic.Pointer[config.DistributedLock]
+	clock               clock.Clock
+	idGenerator         idgen.Generator
 	componentName       string
 	log                 logger.Logger
 }
@@ -22,17 +29,27 @@ type RedisDistributedLockManager struct {
 func NewRedisDistributedLockManager(
 	cacheConnectionData *adapter.CacheConnectionData,
 	configuration *config.Configuration,
+	clock clock.Clock,
+	idGenerator idgen.Generator,
 	log logger.Logger) *RedisDistributedLockManager {
 	manager := &RedisDistributedLockManager{
 		cacheConnectionData: cacheConnectionData,
-		configuration:       configuration,
+		clock:               clock,
+		idGenerator:         idGenerator,
 		componentName:       "RedisDistributedLockManager",
 		log:                 log,
 	}
+	manager.ApplyConfiguration(configuration)
 	manager.componentName = logger.ComponentNameFromStruct(manager)
 	return manager
 }
 
+// ApplyConfiguration replaces the default lock timings used by WaitToLockUsingDefaultTimeConfiguration
+func (r *RedisDistributedLockManager) ApplyConfiguration(cfg *config.Configuration) {
+	timings := cfg.DistributedLock
+	r.timings.Store(&timings)
+}
+
 // Lock Trying to acquire a lock
 func (r *RedisDistributedLockManager) Lock(ctx context.Context, key string, ttl time.Duration) (*lock.Lock, error) {
 	lockValue := r.createLockValue()
@@ -55,9 +72,9 @@ func (r *RedisDistributedLockManager) Lock(ctx context.Context, key string, ttl
 
 // WaitToLock Waits until waitingTime for acquire a lock, it will retry in intervals of RetryInterval (see config file) until timeout
 func (r *RedisDistributedLockManager) WaitToLock(ctx context.Context, key string, ttl time.Duration, waitingTimeMilliseconds time.Duration, retryMilliseconds time.Duration) (*lock.Lock, error) {
-	timeout := time.Now().Add(waitingTimeMilliseconds)
+	timeout := r.clock.Now().Add(waitingTimeMilliseconds)
 	r.log.Debug(r.componentName+".WaitToLock", "status", "Trying to acquire lock...")
-	for time.Now().Before(timeout) {
+	for r.clock.Now().Before(timeout) {
 		acquiredLock, err := r.Lock(ctx, key, ttl)
 		if err == nil {
 			r.log.Debug(r.componentName+".WaitToLock", "Lock acquired:", acquiredLock.Key)
@@ -79,9 +96,10 @@ func (r *RedisDistributedLockManager) WaitToLock(ctx context.Context, key string
 
 func (r *RedisDistributedLockManager) WaitToLockUsingDefaultTimeConfiguration(ctx context.Context, key string) (*lock.Lock, error) {
 	r.log.Debug(r.componentName + ".WaitToLockUsingDefaultTimeConfiguration")
-	waitingTimeMilliseconds := time.Duration(r.configuration.DistributedLock.WaitingTime) * time.Millisecond
-	retryMilliseconds := time.Duration(r.configuration.DistributedLock.RetryInterval) * time.Millisecond
-	ttl := time.Duration(r.configuration.DistributedLock.TTL) * time.Millisecond
+	timings := r.timings.Load()
+	waitingTimeMilliseconds := time.Duration(timings.WaitingTime) * time.Millisecond
+	retryMilliseconds := time.Duration(timings.RetryInterval) * time.Millisecond
+	ttl := time.Duration(timings.TTL) * time.Millisecond
 	lck, err := r.WaitToLock(ctx, key, ttl, waitingTimeMilliseconds, retryMilliseconds)
 	return lck, err
 }
@@ -105,7 +123,71 @@ func (r *RedisDistributedLockManager) Unlock(ctx context.Context, acquiredLock *
 	return nil
 }
 
-// createLockValue Generate a Lock value based on time.RFC3339Nano
+// Refresh extends the ttl of a lock only while it still holds the value set when it was acquired
+func (r *RedisDistributedLockManager) Refresh(ctx context.Context, acquiredLock *lock.Lock, ttl time.Duration) error {
+	refreshScript := `if redis.call("get",KEYS[1]) == ARGV[1]
+					then
+						return redis.call("pexpire",KEYS[1],ARGV[2])
+					else
+						return 0
+					end`
+	script := redis.NewScript(refreshScript)
+
+	refreshed, err := script.Run(ctx, r.cacheConnectionData.Rdb, []string{acquiredLock.Key}, acquiredLock.Value, ttl.Milliseconds()).Int()
+	if err != nil {
+		r.log.Debug(r.componentName+".Refresh", "failed to refresh lock:", acquiredLock.Key, "err", err)
+		return err
+	}
+	if refreshed == 0 {
+		r.log.Debug(r.componentName+".Refresh", "lock not held:", acquiredLock.Key)
+		return coreerr.DistributedLockNotHeldError
+	}
+	return nil
+}
+
+// List returns the held locks, found by scanning the keys starting with lock.KeyPrefix
+func (r *RedisDistributedLockManager) List(ctx context.Context) ([]lock.HeldLock, error) {
+	var heldLocks []lock.HeldLock
+	iterator := r.cacheConnectionData.Rdb.Scan(ctx, 0, lock.KeyPrefix+"*", 100).Iterator()
+	for iterator.Next(ctx) {
+		key := iterator.Val()
+		value, err := r.cacheConnectionData.Rdb.Get(ctx, key).Result()
+		if errors.Is(err, redis.Nil) {
+			continue // Released while scanning
+		}
+		if err != nil {
+			return nil, err
+		}
+		ttl, err := r.cacheConnectionData.Rdb.PTTL(ctx, key).Result()
+		if err != nil {
+			return nil, err
+		}
+		heldLocks = append(heldLocks, lock.HeldLock{Key: key, Value: value, TTL: ttl})
+	}
+	if err := iterator.Err(); err != nil {
+		return nil, err
+	}
+	slices.SortFunc(heldLocks, func(a, b lock.HeldLock) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	return heldLocks, nil
+}
+
+// ForceRelease releases a lock whatever its holder, for locks left behind by crashed instances
+func (r *RedisDistributedLockManager) ForceRelease(ctx context.Context, key string) (bool, error) {
+	if !strings.HasPrefix(key, lock.KeyPrefix) {
+		return false, coreerr.InvalidParametersError
+	}
+	removed, err := r.cacheConnectionData.Rdb.Del(ctx, key).Result()
+	if err != nil {
+		r.log.Debug(r.componentName+".ForceRelease", "failed to release lock:", key, "err", err)
+		return false, err
+	}
+	r.log.Debug(r.componentName+".ForceRelease", "released lock:", key, "released", removed > 0)
+	return removed > 0, nil
+}
+
+// createLockValue Generate a Lock value unique to its holder, so a lock is never released or refreshed by another one
 func (r *RedisDistributedLockManager) createLockValue() string {
-	return time.Now().Format(time.RFC3339Nano)
+	return r.idGenerator.NewID()
 }
@@ -0,0 +1,154 @@
+package lock
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/adapter"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/clock"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/config"
+	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/idgen"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/logger/mock"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testLockKey = "lock-test"
+
+func newTestLockManager(t *testing.T, clk clock.Clock) (*RedisDistributedLockManager, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	manager := NewRedisDistributedLockManager(&adapter.CacheConnectionData{Rdb: rdb}, &config.Configuration{}, clk,
+		idgen.NewFakeGenerator("holder"), mock.NewMockLogger())
+	return manager, server
+}
+
+// steppingClock moves forward by step every time it is read, so waiting loops end without sleeping
+type steppingClock struct {
+	mu   sync.Mutex
+	now  time.Time
+	step time.Duration
+}
+
+func (s *steppingClock) Now() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = s.now.Add(s.step)
+	return s.now
+}
+
+func TestRedisDistributedLockManagerLock(t *testing.T) {
+	manager, server := newTestLockManager(t, clock.NewFakeClock(time.Now()))
+	ctx := context.Background()
+
+	acquired, err := manager.Lock(ctx, testLockKey, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "holder-1", acquired.Value, "the lock value should come from the ID generator")
+	value, err := server.Get(testLockKey)
+	require.NoError(t, err)
+	assert.Equal(t, "holder-1", value)
+	assert.Equal(t, time.Minute, server.TTL(testLockKey))
+
+	_, err = manager.Lock(ctx, testLockKey, time.Minute)
+	assert.ErrorIs(t, err, coreerr.DistributedLockFailToAcquire, "a held lock should not be acquired again")
+}
+
+func TestRedisDistributedLockManagerLockExpires(t *testing.T) {
+	manager, server := newTestLockManager(t, clock.NewFakeClock(time.Now()))
+	ctx := context.Background()
+
+	first, err := manager.Lock(ctx, testLockKey, time.Second)
+	require.NoError(t, err)
+	server.FastForward(time.Second)
+	second, err := manager.Lock(ctx, testLockKey, time.Second)
+	require.NoError(t, err, "an expired lock should be acquired by another holder")
+	assert.Equal(t, "holder-2", second.Value)
+
+	require.NoError(t, manager.Unlock(ctx, first))
+	value, err := server.Get(testLockKey)
+	require.NoError(t, err)
+	assert.Equal(t, "holder-2", value, "the first holder should not release the lock of the second one")
+}
+
+func TestRedisDistributedLockManagerRefresh(t *testing.T) {
+	manager, server := newTestLockManager(t, clock.NewFakeClock(time.Now()))
+	ctx := context.Background()
+
+	acquired, err := manager.Lock(ctx, testLockKey, time.Second)
+	require.NoError(t, err)
+	server.FastForward(800 * time.Millisecond)
+	require.NoError(t, manager.Refresh(ctx, acquired, time.Second))
+	server.FastForward(800 * time.Millisecond)
+	assert.True(t, server.Exists(testLockKey), "a refreshed lock should outlive its first ttl")
+
+	server.FastForward(time.Second)
+	err = manager.Refresh(ctx, acquired, time.Second)
+	assert.ErrorIs(t, err, coreerr.DistributedLockNotHeldError, "an expired lock should not be refreshed")
+
+	other, err := manager.Lock(ctx, testLockKey, time.Second)
+	require.NoError(t, err)
+	err = manager.Refresh(ctx, acquired, time.Minute)
+	assert.ErrorIs(t, err, coreerr.DistributedLockNotHeldError, "the lock of another holder should not be refreshed")
+	assert.Equal(t, time.Second, server.TTL(other.Key))
+}
+
+func TestRedisDistributedLockManagerWaitToLockTimesOut(t *testing.T) {
+	clk := &steppingClock{now: time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC), step: 10 * time.Millisecond}
+	manager, _ := newTestLockManager(t, clk)
+	ctx := context.Background()
+
+	_, err := manager.Lock(ctx, testLockKey, time.Minute)
+	require.NoError(t, err)
+	_, err = manager.WaitToLock(ctx, testLockKey, time.Minute, 50*time.Millisecond, time.Millisecond)
+	assert.ErrorIs(t, err, coreerr.DistributedLockFailToAcquire, "waiting should end when the clock passes the waiting time")
+}
+
+func TestRedisDistributedLockManagerWaitToLockAfterExpiry(t *testing.T) {
+	manager, server := newTestLockManager(t, clock.NewFakeClock(time.Now()))
+	ctx := context.Background()
+
+	_, err := manager.Lock(ctx, testLockKey, time.Second)
+	require.NoError(t, err)
+	server.FastForward(time.Second)
+	acquired, err := manager.WaitToLock(ctx, testLockKey, time.Minute, time.Second, time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, "holder-2", acquired.Value)
+}
+
+func TestRedisDistributedLockManagerListAndForceRelease(t *testing.T) {
+	manager, server := newTestLockManager(t, clock.NewFakeClock(time.Now()))
+	ctx := context.Background()
+
+	_, err := manager.Lock(ctx, "lock-b", time.Minute)
+	require.NoError(t, err)
+	_, err = manager.Lock(ctx, "lock-a", time.Second)
+	require.NoError(t, err)
+	require.NoError(t, server.Set("cache:account:1", "{}"))
+
+	heldLocks, err := manager.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, heldLocks, 2, "only the lock keys should be listed")
+	assert.Equal(t, "lock-a", heldLocks[0].Key, "locks should be sorted by key")
+	assert.Equal(t, "holder-2", heldLocks[0].Value)
+	assert.Equal(t, time.Second, heldLocks[0].TTL)
+	assert.Equal(t, "lock-b", heldLocks[1].Key)
+
+	released, err := manager.ForceRelease(ctx, "lock-b")
+	require.NoError(t, err)
+	assert.True(t, released, "a held lock should be released")
+	assert.False(t, server.Exists("lock-b"))
+	released, err = manager.ForceRelease(ctx, "lock-b")
+	require.NoError(t, err)
+	assert.False(t, released, "a lock not held should not be released")
+
+	_, err = manager.ForceRelease(ctx, "cache:account:1")
+	assert.ErrorIs(t, err, coreerr.InvalidParametersError, "keys other than locks should be rejected")
+	assert.True(t, server.Exists("cache:account:1"))
+}
@@ -6,39 +6,84 @@ import (
 	"github.com/kiosanim/pismo-code-assessment/internal/core/contextkeys"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/contextutils"
 	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
+	"io"
 	"log/slog"
 	"os"
 	"strings"
+	"sync/atomic"
 )
 
+// RedactedValue replaces the value of every attribute listed in pii.redacted_log_attributes
+const RedactedValue = "[REDACTED]"
+
 type SlogLogger struct {
-	l *slog.Logger
+	l          *slog.Logger
+	level      *slog.LevelVar
+	redactions *atomic.Pointer[map[string]bool]
 }
 
 func NewSlogLogger(ctx context.Context, cfg *config.Configuration) *SlogLogger {
-	var logLevel slog.Level
-	logLevelFromConfig := cfg.App.LogLevel
-	switch strings.ToLower(logLevelFromConfig) {
-	case "info":
-		logLevel = slog.LevelInfo
-	case "warn":
-		logLevel = slog.LevelWarn
-	case "error":
-		logLevel = slog.LevelError
-	case "debug":
-		logLevel = slog.LevelDebug
-	default:
-		logLevel = slog.LevelInfo
-	}
+	return newSlogLogger(ctx, os.Stdout, cfg)
+}
+
+func newSlogLogger(ctx context.Context, w io.Writer, cfg *config.Configuration) *SlogLogger {
+	logLevel, _ := ParseLevel(cfg.App.LogLevel)
+	level := new(slog.LevelVar)
+	level.Set(logLevel)
+	redactions := new(atomic.Pointer[map[string]bool])
+	redactions.Store(redactedAttributes(cfg.PII.RedactedLogAttributes))
 	options := &slog.HandlerOptions{
-		Level: logLevel,
+		Level: level,
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if (*redactions.Load())[strings.ToLower(attr.Key)] {
+				return slog.String(attr.Key, RedactedValue)
+			}
+			return attr
+		},
 	}
 	traceID := contextutils.GetTraceID(ctx)
-	sLogger := slog.New(slog.NewJSONHandler(os.Stdout, options))
+	sLogger := slog.New(slog.NewJSONHandler(w, options))
 	sLogger.With(
 		contextkeys.TraceIDKey, traceID,
 	)
-	return &SlogLogger{l: sLogger}
+	return &SlogLogger{l: sLogger, level: level, redactions: redactions}
+}
+
+// redactedAttributes builds the case-insensitive set of attribute names whose values are never logged
+func redactedAttributes(names []string) *map[string]bool {
+	redactions := make(map[string]bool, len(names))
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			redactions[strings.ToLower(name)] = true
+		}
+	}
+	return &redactions
+}
+
+// ParseLevel converts the app.log_level value into a slog.Level, an empty value means info
+func ParseLevel(name string) (slog.Level, bool) {
+	switch strings.ToLower(name) {
+	case "info", "":
+		return slog.LevelInfo, true
+	case "warn":
+		return slog.LevelWarn, true
+	case "error":
+		return slog.LevelError, true
+	case "debug":
+		return slog.LevelDebug, true
+	default:
+		return slog.LevelInfo, false
+	}
+}
+
+// ApplyConfiguration changes the log level and the redacted attributes at runtime, including for loggers derived by With
+func (s *SlogLogger) ApplyConfiguration(cfg *config.Configuration) {
+	s.redactions.Store(redactedAttributes(cfg.PII.RedactedLogAttributes))
+	logLevel, ok := ParseLevel(cfg.App.LogLevel)
+	if !ok {
+		return
+	}
+	s.level.Set(logLevel)
 }
 
 func (s *SlogLogger) Info(msg string, args ...any) {
@@ -58,5 +103,5 @@ func (s *SlogLogger) Error(msg string, args ...any) {
 }
 
 func (s *SlogLogger) With(args ...any) logger.Logger {
-	return &SlogLogger{l: s.l.With(args...)}
+	return &SlogLogger{l: s.l.With(args...), level: s.level, redactions: s.redactions}
 }
@@ -0,0 +1,40 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/config"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func redactingConfiguration(attributes ...string) *config.Configuration {
+	return &config.Configuration{
+		App: config.AppConfig{LogLevel: "debug"},
+		PII: config.PIIConfig{RedactedLogAttributes: attributes},
+	}
+}
+
+func TestSlogLoggerRedactsConfiguredAttributes(t *testing.T) {
+	var output bytes.Buffer
+	log := newSlogLogger(context.Background(), &output, redactingConfiguration("document_number", "DocumentNumber"))
+	log.Info("account", "document_number", "11987408098", "documentnumber", "11987408098", "accountID", 1)
+	assert.NotContains(t, output.String(), "11987408098", "redacted attributes must not be logged")
+	assert.Contains(t, output.String(), `"document_number":"[REDACTED]"`)
+	assert.Contains(t, output.String(), `"accountID":1`, "other attributes should be logged")
+}
+
+func TestSlogLoggerRedactsAttributesOfDerivedLoggers(t *testing.T) {
+	var output bytes.Buffer
+	log := newSlogLogger(context.Background(), &output, redactingConfiguration("document_number"))
+	log.With("document_number", "11987408098").Debug("account")
+	assert.NotContains(t, output.String(), "11987408098", "attributes added by With must be redacted")
+}
+
+func TestSlogLoggerApplyConfigurationReloadsRedactions(t *testing.T) {
+	var output bytes.Buffer
+	log := newSlogLogger(context.Background(), &output, redactingConfiguration())
+	log.ApplyConfiguration(redactingConfiguration("document_number"))
+	log.Info("account", "document_number", "11987408098")
+	assert.NotContains(t, output.String(), "11987408098", "reloaded redactions should be applied")
+}
@@ -0,0 +1,109 @@
+package pii
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/config"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"os"
+	"strings"
+)
+
+// ciphertextPrefix identifies the values encrypted by AESFieldCipher and the version of their format
+const ciphertextPrefix = "enc:v1:"
+
+const keySize = 32
+
+// AESFieldCipher encrypts values with AES-256-GCM and indexes them with HMAC-SHA256.
+// Ciphertexts are the prefix followed by the base64 encoded nonce and sealed value.
+type AESFieldCipher struct {
+	aead          cipher.AEAD
+	blindIndexKey []byte
+}
+
+// NewAESFieldCipher creates a cipher with the keys of the pii configuration. Both keys are required and must differ.
+func NewAESFieldCipher(cfg config.PIIConfig) (*AESFieldCipher, error) {
+	encryptionKey, err := loadKey("pii.encryption_key", cfg.EncryptionKey, cfg.EncryptionKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	blindIndexKey, err := loadKey("pii.blind_index_key", cfg.BlindIndexKey, cfg.BlindIndexKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	if hmac.Equal(encryptionKey, blindIndexKey) {
+		return nil, fmt.Errorf("%w: pii.encryption_key and pii.blind_index_key must be different", errors.ConfigValidationError)
+	}
+	block, err := aes.NewCipher(encryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ConfigValidationError, err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ConfigValidationError, err)
+	}
+	return &AESFieldCipher{aead: aead, blindIndexKey: blindIndexKey}, nil
+}
+
+func (a *AESFieldCipher) Encrypt(plaintext string) (string, error) {
+	nonce := make([]byte, a.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := a.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return ciphertextPrefix + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt returns values without the ciphertext prefix as they are, so rows written before encryption are still readable
+func (a *AESFieldCipher) Decrypt(ciphertext string) (string, error) {
+	encoded, ok := strings.CutPrefix(ciphertext, ciphertextPrefix)
+	if !ok {
+		return ciphertext, nil
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < a.aead.NonceSize() {
+		return "", errors.PIIDecryptionError
+	}
+	nonce, sealed := sealed[:a.aead.NonceSize()], sealed[a.aead.NonceSize():]
+	plaintext, err := a.aead.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return "", errors.PIIDecryptionError
+	}
+	return string(plaintext), nil
+}
+
+func (a *AESFieldCipher) BlindIndex(plaintext string) string {
+	mac := hmac.New(sha256.New, a.blindIndexKey)
+	mac.Write([]byte(plaintext))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// IsEncrypted checks if a stored value was encrypted by an AESFieldCipher
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, ciphertextPrefix)
+}
+
+// loadKey decodes the inline key or, when it is empty, the content of the key file
+func loadKey(name string, value string, path string) ([]byte, error) {
+	if value == "" && path != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", errors.ConfigValidationError, name, err)
+		}
+		value = string(content)
+	}
+	if value == "" {
+		return nil, fmt.Errorf("%w: %s is not configured", errors.ConfigValidationError, name)
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value))
+	if err != nil || len(key) != keySize {
+		return nil, fmt.Errorf("%w: %s must be %d base64 encoded bytes", errors.ConfigValidationError, name, keySize)
+	}
+	return key, nil
+}
@@ -0,0 +1,86 @@
+package pii
+
+import (
+	"encoding/base64"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/config"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var (
+	encryptionKey = base64.StdEncoding.EncodeToString([]byte(strings.Repeat("e", keySize)))
+	blindIndexKey = base64.StdEncoding.EncodeToString([]byte(strings.Repeat("b", keySize)))
+)
+
+func newTestCipher(t *testing.T) *AESFieldCipher {
+	fieldCipher, err := NewAESFieldCipher(config.PIIConfig{EncryptionKey: encryptionKey, BlindIndexKey: blindIndexKey})
+	require.NoError(t, err)
+	return fieldCipher
+}
+
+func TestAESFieldCipher_EncryptDecrypt(t *testing.T) {
+	fieldCipher := newTestCipher(t)
+	first, err := fieldCipher.Encrypt("12345678909")
+	require.NoError(t, err)
+	second, err := fieldCipher.Encrypt("12345678909")
+	require.NoError(t, err)
+	assert.True(t, IsEncrypted(first))
+	assert.NotContains(t, first, "12345678909")
+	assert.NotEqual(t, first, second, "ciphertexts should use random nonces")
+	plaintext, err := fieldCipher.Decrypt(first)
+	require.NoError(t, err)
+	assert.Equal(t, "12345678909", plaintext)
+}
+
+func TestAESFieldCipher_DecryptPlaintext(t *testing.T) {
+	plaintext, err := newTestCipher(t).Decrypt("12345678909")
+	require.NoError(t, err)
+	assert.Equal(t, "12345678909", plaintext, "values written before encryption should be returned as they are")
+}
+
+func TestAESFieldCipher_DecryptTampered(t *testing.T) {
+	fieldCipher := newTestCipher(t)
+	ciphertext, err := fieldCipher.Encrypt("12345678909")
+	require.NoError(t, err)
+	tampered := ciphertext[:len(ciphertext)-2] + "AA"
+	_, err = fieldCipher.Decrypt(tampered)
+	assert.ErrorIs(t, err, errors.PIIDecryptionError)
+	_, err = fieldCipher.Decrypt(ciphertextPrefix + "!")
+	assert.ErrorIs(t, err, errors.PIIDecryptionError)
+}
+
+func TestAESFieldCipher_BlindIndex(t *testing.T) {
+	fieldCipher := newTestCipher(t)
+	index := fieldCipher.BlindIndex("12345678909")
+	assert.Len(t, index, 64)
+	assert.Equal(t, index, fieldCipher.BlindIndex("12345678909"), "blind index should be deterministic")
+	assert.NotEqual(t, index, fieldCipher.BlindIndex("12345678900"))
+}
+
+func TestNewAESFieldCipher_KeyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "encryption.key")
+	require.NoError(t, os.WriteFile(path, []byte(encryptionKey+"\n"), 0o600))
+	_, err := NewAESFieldCipher(config.PIIConfig{EncryptionKeyFile: path, BlindIndexKey: blindIndexKey})
+	assert.NoError(t, err)
+}
+
+func TestNewAESFieldCipher_InvalidKeys(t *testing.T) {
+	for name, cfg := range map[string]config.PIIConfig{
+		"missing encryption key":  {BlindIndexKey: blindIndexKey},
+		"missing blind index key": {EncryptionKey: encryptionKey},
+		"short key":               {EncryptionKey: base64.StdEncoding.EncodeToString([]byte("short")), BlindIndexKey: blindIndexKey},
+		"not base64":              {EncryptionKey: "not base64!", BlindIndexKey: blindIndexKey},
+		"same keys":               {EncryptionKey: encryptionKey, BlindIndexKey: encryptionKey},
+		"missing key file":        {EncryptionKeyFile: filepath.Join(t.TempDir(), "missing.key"), BlindIndexKey: blindIndexKey},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewAESFieldCipher(cfg)
+			assert.ErrorIs(t, err, errors.ConfigValidationError)
+		})
+	}
+}
@@ -0,0 +1,2 @@
+// Package pii implements the field encryption of personally identifiable information with AES-GCM and HMAC blind indexes
+package pii
@@ -0,0 +1,81 @@
+package ratelimit
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/adapter"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/logger"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/ratelimit"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+// slidingWindowScript keeps the timestamps of the accepted requests in a sorted set.
+// The Redis clock is used so every pod shares the same time reference.
+// Returns {allowed, remaining, reset_after_ms, retry_after_ms}
+var slidingWindowScript = redis.NewScript(`
+local key = KEYS[1]
+local window = tonumber(ARGV[1])
+local limit = tonumber(ARGV[2])
+local member = ARGV[3]
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+redis.call("ZREMRANGEBYSCORE", key, "-inf", now - window)
+local count = redis.call("ZCARD", key)
+local allowed = 0
+if count < limit then
+	redis.call("ZADD", key, now, member)
+	redis.call("PEXPIRE", key, window)
+	count = count + 1
+	allowed = 1
+end
+local resetAfter = window
+local oldest = redis.call("ZRANGE", key, 0, 0, "WITHSCORES")
+if oldest[2] then
+	resetAfter = tonumber(oldest[2]) + window - now
+end
+local retryAfter = 0
+if allowed == 0 then
+	retryAfter = resetAfter
+end
+return {allowed, limit - count, resetAfter, retryAfter}
+`)
+
+// RedisRateLimiter is a sliding window log rate limiter executed atomically in Redis
+type RedisRateLimiter struct {
+	cacheConnectionData *adapter.CacheConnectionData
+	componentName       string
+	log                 logger.Logger
+}
+
+func NewRedisRateLimiter(cacheConnectionData *adapter.CacheConnectionData, log logger.Logger) *RedisRateLimiter {
+	limiter := &RedisRateLimiter{
+		cacheConnectionData: cacheConnectionData,
+		log:                 log,
+	}
+	limiter.componentName = logger.ComponentNameFromStruct(limiter)
+	return limiter
+}
+
+// Allow registers a request for key and checks if it fits in the limit
+func (r *RedisRateLimiter) Allow(ctx context.Context, key string, limit ratelimit.Limit) (*ratelimit.Result, error) {
+	values, err := slidingWindowScript.Run(
+		ctx,
+		r.cacheConnectionData.Rdb,
+		[]string{key},
+		limit.Window.Milliseconds(),
+		limit.Requests,
+		uuid.NewString(),
+	).Int64Slice()
+	if err != nil {
+		r.log.Warn(r.componentName+".Allow", "key", key, "error", err)
+		return nil, err
+	}
+	return &ratelimit.Result{
+		Allowed:    values[0] == 1,
+		Limit:      limit.Requests,
+		Remaining:  values[1],
+		ResetAfter: time.Duration(values[2]) * time.Millisecond,
+		RetryAfter: time.Duration(values[3]) * time.Millisecond,
+	}, nil
+}
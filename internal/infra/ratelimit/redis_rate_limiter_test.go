@@ -0,0 +1,57 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/adapter"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/ratelimit"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/logger/mock"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRateLimiter(t *testing.T) (*RedisRateLimiter, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	return NewRedisRateLimiter(&adapter.CacheConnectionData{Rdb: rdb}, mock.NewMockLogger()), server
+}
+
+func TestRedisRateLimiterAllow(t *testing.T) {
+	limiter, _ := newTestRateLimiter(t)
+	ctx := context.Background()
+	limit := ratelimit.Limit{Requests: 2, Window: time.Minute}
+
+	first, err := limiter.Allow(ctx, "ratelimit:test", limit)
+	require.NoError(t, err)
+	assert.True(t, first.Allowed, "first request should be allowed")
+	assert.Equal(t, int64(1), first.Remaining)
+
+	second, err := limiter.Allow(ctx, "ratelimit:test", limit)
+	require.NoError(t, err)
+	assert.True(t, second.Allowed, "second request should be allowed")
+	assert.Equal(t, int64(0), second.Remaining)
+
+	third, err := limiter.Allow(ctx, "ratelimit:test", limit)
+	require.NoError(t, err)
+	assert.False(t, third.Allowed, "third request should exceed the limit")
+	assert.Equal(t, int64(2), third.Limit)
+	assert.Greater(t, third.RetryAfter, time.Duration(0), "retry after should be set")
+	assert.LessOrEqual(t, third.RetryAfter, time.Minute, "retry after should not exceed the window")
+
+	other, err := limiter.Allow(ctx, "ratelimit:other", limit)
+	require.NoError(t, err)
+	assert.True(t, other.Allowed, "keys should be limited independently")
+}
+
+func TestRedisRateLimiterUnavailable(t *testing.T) {
+	limiter, server := newTestRateLimiter(t)
+	server.Close()
+	_, err := limiter.Allow(context.Background(), "ratelimit:test", ratelimit.Limit{Requests: 1, Window: time.Second})
+	assert.Error(t, err)
+}
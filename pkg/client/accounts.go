@@ -0,0 +1,35 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"github.com/kiosanim/pismo-code-assessment/application/account/dto"
+	"net/http"
+)
+
+func (c *Client) CreateAccount(ctx context.Context, request dto.CreateAccountRequest) (*dto.CreateAccountResponse, error) {
+	var response dto.CreateAccountResponse
+	if err := c.do(ctx, http.MethodPost, "/accounts", nil, request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (c *Client) GetAccount(ctx context.Context, accountID int64) (*dto.FindAccountByIdResponse, error) {
+	var response dto.FindAccountByIdResponse
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/accounts/%d", accountID), nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ListAccounts returns a page of up to limit accounts, the response Cursor being the last account ID of the page
+func (c *Client) ListAccounts(ctx context.Context, cursor int64, limit int64) (*dto.ListAccountsResponse, error) {
+	var response struct {
+		Accounts dto.ListAccountsResponse `json:"accounts"`
+	}
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/accounts/list/%d/%d", cursor, limit), nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response.Accounts, nil
+}
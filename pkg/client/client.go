@@ -0,0 +1,229 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/contextkeys"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/contextutils"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	TraceIDHeader        = contextkeys.TraceIDKey
+	IdempotencyKeyHeader = "Idempotency-Key"
+	APIKeyHeader         = "X-API-Key"
+)
+
+// RetryPolicy sets how requests the API did not process are retried: requests rejected by the rate limit or for
+// failing to acquire the transaction lock and, for GET requests only, requests that failed to be sent or were
+// answered by 502, 503 or 504
+type RetryPolicy struct {
+	MaxAttempts    int           // Attempts of a request, including the first one. 1 disables retries.
+	InitialBackoff time.Duration // Wait before the first retry, doubled on each retry
+	MaxBackoff     time.Duration // Longest wait between attempts, unless the API asks for a longer one with Retry-After
+}
+
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 3, InitialBackoff: 100 * time.Millisecond, MaxBackoff: 2 * time.Second}
+
+// Client calls the API at its base URL. It is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	headers    http.Header // Headers sent with every request
+	retry      RetryPolicy
+}
+
+type Option func(c *Client)
+
+// WithHTTPClient sends the requests with httpClient instead of a new http.Client
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithTimeout limits the time of each attempt of a request, 30 seconds by default
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		httpClient := *c.httpClient
+		httpClient.Timeout = timeout
+		c.httpClient = &httpClient
+	}
+}
+
+// WithRetryPolicy replaces the DefaultRetryPolicy
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// WithHeader sends a header with every request
+func WithHeader(name string, value string) Option {
+	return func(c *Client) {
+		c.headers.Set(name, value)
+	}
+}
+
+// WithAPIKey authenticates the requests with an API key
+func WithAPIKey(apiKey string) Option {
+	return WithHeader(APIKeyHeader, apiKey)
+}
+
+// WithBearerToken authenticates the requests with a JWT
+func WithBearerToken(token string) Option {
+	return WithHeader("Authorization", "Bearer "+token)
+}
+
+func New(baseURL string, options ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		headers:    make(http.Header),
+		retry:      DefaultRetryPolicy,
+	}
+	for _, option := range options {
+		option(c)
+	}
+	c.retry.MaxAttempts = max(c.retry.MaxAttempts, 1)
+	return c
+}
+
+type idempotencyKeyContextKey struct{}
+
+// WithTraceID sets the x-trace-id of the requests sent with the returned context. Requests sent with the context of
+// a request of the API already carry its trace ID; other requests get a new one.
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, contextkeys.TraceIDKey, traceID)
+}
+
+// WithIdempotencyKey sets the Idempotency-Key of the POST requests sent with the returned context, a new one being
+// generated for each request by default. Reusing the key of a request retried by the caller identifies it as a retry.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+// do sends a request with a JSON payload, when not nil, decoding the response into out, when not nil, and
+// retrying it according to the retry policy
+func (c *Client) do(ctx context.Context, method string, path string, query url.Values, payload any, out any) error {
+	return c.doWithHeaders(ctx, method, path, query, nil, payload, out)
+}
+
+// doWithHeaders is do sending the request headers besides the ones sent with every request
+func (c *Client) doWithHeaders(ctx context.Context, method string, path string, query url.Values, requestHeaders http.Header, payload any, out any) error {
+	var body []byte
+	if payload != nil {
+		var err error
+		if body, err = json.Marshal(payload); err != nil {
+			return err
+		}
+	}
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	headers := c.headers.Clone()
+	for name, values := range requestHeaders {
+		headers[name] = values
+	}
+	traceID := contextutils.GetTraceID(ctx)
+	if traceID == "" {
+		traceID = uuid.NewString()
+	}
+	headers.Set(TraceIDHeader, traceID)
+	if method != http.MethodGet {
+		key, _ := ctx.Value(idempotencyKeyContextKey{}).(string)
+		if key == "" {
+			key = uuid.NewString()
+		}
+		headers.Set(IdempotencyKeyHeader, key)
+	}
+	if payload != nil {
+		headers.Set("Content-Type", "application/json")
+	}
+	for attempt := 1; ; attempt++ {
+		retryAfter, err := c.send(ctx, method, target, headers, body, out)
+		if err == nil || attempt >= c.retry.MaxAttempts || ctx.Err() != nil || !c.retryable(method, err) {
+			return err
+		}
+		timer := time.NewTimer(max(c.backoff(attempt), retryAfter))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// send makes an attempt of a request, returning the wait asked by the Retry-After header of error responses
+func (c *Client) send(ctx context.Context, method string, target string, headers http.Header, body []byte, out any) (time.Duration, error) {
+	request, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	request.Header = headers.Clone()
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		return 0, err
+	}
+	if response.StatusCode >= http.StatusBadRequest {
+		var retryAfter time.Duration
+		if seconds, err := strconv.Atoi(response.Header.Get("Retry-After")); err == nil {
+			retryAfter = time.Duration(seconds) * time.Second
+		}
+		return retryAfter, newAPIError(response.StatusCode, content, headers.Get(TraceIDHeader))
+	}
+	if out == nil {
+		return 0, nil
+	}
+	return 0, json.Unmarshal(content, out)
+}
+
+// retryable checks if the API did not process a failed request, so sending it again has no side effects
+func (c *Client) retryable(method string, err error) bool {
+	var apiError *APIError
+	if !errors.As(err, &apiError) {
+		// http.Client fails with *url.Error when the request could not be sent or answered
+		var transportError *url.Error
+		return method == http.MethodGet && errors.As(err, &transportError)
+	}
+	switch {
+	case apiError.StatusCode == http.StatusTooManyRequests, errors.Is(apiError, ErrDistributedLockFailToAcquire):
+		return true
+	case apiError.StatusCode == http.StatusBadGateway, apiError.StatusCode == http.StatusServiceUnavailable, apiError.StatusCode == http.StatusGatewayTimeout:
+		return method == http.MethodGet
+	}
+	return false
+}
+
+// backoff returns the wait before retrying the attempt, doubling the initial backoff on each attempt with a
+// random jitter of up to half of it
+func (c *Client) backoff(attempt int) time.Duration {
+	wait := c.retry.InitialBackoff
+	for range attempt - 1 {
+		if wait >= c.retry.MaxBackoff {
+			break
+		}
+		wait *= 2
+	}
+	wait = min(wait, c.retry.MaxBackoff)
+	if wait <= 0 {
+		return 0
+	}
+	return wait/2 + rand.N(wait/2+1)
+}
@@ -0,0 +1,381 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	accountdto "github.com/kiosanim/pismo-code-assessment/application/account/dto"
+	holderdto "github.com/kiosanim/pismo-code-assessment/application/holder/dto"
+	"github.com/kiosanim/pismo-code-assessment/application/transaction/dto"
+	"github.com/kiosanim/pismo-code-assessment/interfaces/http/router"
+	"github.com/kiosanim/pismo-code-assessment/internal/core/config"
+	"github.com/kiosanim/pismo-code-assessment/internal/domains/transaction"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/factory"
+	"github.com/kiosanim/pismo-code-assessment/internal/infra/logger/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var noBackoff = RetryPolicy{MaxAttempts: 3}
+
+// newTestClient returns a client of the API served on the memory repositories
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+	gin.SetMode(gin.ReleaseMode)
+	gin.DefaultWriter = io.Discard
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	configuration := &config.Configuration{
+		DistributedLock: config.DistributedLock{TTL: 1000, RetryInterval: 1, WaitingTime: 2000},
+		Currency:        config.CurrencyConfig{Default: "BRL"},
+	}
+	log := mock.NewMockLogger()
+	memoryFactory, err := factory.NewMemoryFactory(ctx, configuration, log)
+	require.NoError(t, err)
+	server := httptest.NewServer(router.NewRouter(memoryFactory, nil, log))
+	t.Cleanup(server.Close)
+	return New(server.URL, WithHTTPClient(server.Client()), WithRetryPolicy(noBackoff))
+}
+
+// recordedRequest is an attempt seen by a scripted API
+type recordedRequest struct {
+	method         string
+	traceID        string
+	idempotencyKey string
+	apiKey         string
+}
+
+// newScriptedAPI answers the nth request with the nth response, the last one being repeated
+func newScriptedAPI(t *testing.T, responses ...func(w http.ResponseWriter)) (*httptest.Server, func() []recordedRequest) {
+	t.Helper()
+	var mu sync.Mutex
+	var recorded []recordedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		recorded = append(recorded, recordedRequest{
+			method:         r.Method,
+			traceID:        r.Header.Get(TraceIDHeader),
+			idempotencyKey: r.Header.Get(IdempotencyKeyHeader),
+			apiKey:         r.Header.Get(APIKeyHeader),
+		})
+		respond := responses[min(len(recorded), len(responses))-1]
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		respond(w)
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []recordedRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]recordedRequest{}, recorded...)
+	}
+}
+
+func respond(status int, body string) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}
+}
+
+func TestAccounts(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+
+	created, err := c.CreateAccount(ctx, accountdto.CreateAccountRequest{DocumentNumber: "52998224725"})
+	require.NoError(t, err)
+	assert.Positive(t, created.AccountID)
+	assert.Equal(t, "CPF", created.DocumentType)
+	assert.Equal(t, "BRL", created.Currency)
+
+	found, err := c.GetAccount(ctx, created.AccountID)
+	require.NoError(t, err)
+	assert.Equal(t, created.AccountID, found.AccountID)
+
+	page, err := c.ListAccounts(ctx, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, page.Accounts, 1)
+	assert.Equal(t, created.AccountID, page.Accounts[0].AccountID)
+	assert.Equal(t, created.AccountID, page.Cursor)
+
+	_, err = c.GetAccount(ctx, created.AccountID+1)
+	assert.ErrorIs(t, err, ErrAccountNotFound)
+	var apiError *APIError
+	require.ErrorAs(t, err, &apiError)
+	assert.Equal(t, http.StatusNotFound, apiError.StatusCode)
+	assert.Equal(t, "AccountNotFoundError", apiError.Code)
+	assert.NotEmpty(t, apiError.TraceID)
+
+	_, err = c.CreateAccount(ctx, accountdto.CreateAccountRequest{DocumentNumber: "52998224725"})
+	assert.Error(t, err)
+}
+
+func TestAccountETag(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+	created, err := c.CreateAccount(ctx, accountdto.CreateAccountRequest{DocumentNumber: "52998224725"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), created.Version)
+
+	get := func(ifNoneMatch string) *http.Response {
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/accounts/"+strconv.FormatInt(created.AccountID, 10), nil)
+		require.NoError(t, err)
+		if ifNoneMatch != "" {
+			request.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		response, err := c.httpClient.Do(request)
+		require.NoError(t, err)
+		response.Body.Close()
+		return response
+	}
+	response := get("")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, `"1-m"`, response.Header.Get("ETag"), "the ETag should tell the document number is masked")
+	assert.Equal(t, "Authorization, X-API-Key", response.Header.Get("Vary"))
+	response = get(`"1-m"`)
+	assert.Equal(t, http.StatusNotModified, response.StatusCode)
+	assert.Equal(t, "Authorization, X-API-Key", response.Header.Get("Vary"))
+	assert.Equal(t, http.StatusNotModified, get(`"0", W/"1-m"`).StatusCode, "If-None-Match should use the weak comparison")
+	assert.Equal(t, http.StatusOK, get(`"1"`).StatusCode, "the ETag of the unmasked account should not match the masked one")
+	assert.Equal(t, http.StatusOK, get(`"0"`).StatusCode)
+}
+
+func TestHolders(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+	created, err := c.CreateAccount(ctx, accountdto.CreateAccountRequest{DocumentNumber: "52998224725"})
+	require.NoError(t, err)
+
+	empty, err := c.GetHolder(ctx, created.AccountID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), empty.Version)
+	assert.Nil(t, empty.UpdatedAt)
+
+	email, city := " Ana@Example.com ", "Sao Paulo"
+	updated, err := c.UpdateHolder(ctx, created.AccountID, empty.Version, holderdto.UpdateHolderRequest{
+		Email:   &email,
+		Address: &holderdto.AddressPatchDTO{City: &city},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), updated.Version)
+	assert.Equal(t, "***********.com", updated.Email, "personal data should be masked without the accounts:pii scope")
+	assert.Equal(t, city, updated.Address.City)
+	assert.NotNil(t, updated.UpdatedAt)
+
+	phone := "+55 (11) 98765-4321"
+	_, err = c.UpdateHolder(ctx, created.AccountID, empty.Version, holderdto.UpdateHolderRequest{Phone: &phone})
+	assert.ErrorIs(t, err, ErrAccountHolderVersionMismatch, "a stale version should be rejected")
+	var apiError *APIError
+	require.ErrorAs(t, err, &apiError)
+	assert.Equal(t, http.StatusPreconditionFailed, apiError.StatusCode)
+
+	invalidPhone := "12345"
+	_, err = c.UpdateHolder(ctx, created.AccountID, updated.Version, holderdto.UpdateHolderRequest{Phone: &invalidPhone})
+	assert.ErrorIs(t, err, ErrAccountHolderInvalid)
+
+	updated, err = c.UpdateHolder(ctx, created.AccountID, updated.Version, holderdto.UpdateHolderRequest{Phone: &phone})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), updated.Version)
+
+	history, err := c.GetHolderHistory(ctx, created.AccountID)
+	require.NoError(t, err)
+	require.Len(t, history.Changes, 2)
+	assert.Equal(t, int64(2), history.Changes[0].Version)
+	assert.Equal(t, []string{"phone"}, history.Changes[0].ChangedFields)
+	assert.Equal(t, []string{"email", "address.city"}, history.Changes[1].ChangedFields)
+
+	_, err = c.GetHolder(ctx, created.AccountID+1)
+	assert.ErrorIs(t, err, ErrAccountNotFound)
+}
+
+func TestHolderETag(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+	created, err := c.CreateAccount(ctx, accountdto.CreateAccountRequest{DocumentNumber: "52998224725"})
+	require.NoError(t, err)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/accounts/"+strconv.FormatInt(created.AccountID, 10)+"/holder", nil)
+	require.NoError(t, err)
+	response, err := c.httpClient.Do(request)
+	require.NoError(t, err)
+	response.Body.Close()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, `"0-m"`, response.Header.Get("ETag"), "the ETag should tell the personal data is masked")
+	assert.Equal(t, "Authorization, X-API-Key", response.Header.Get("Vary"))
+}
+
+func TestUpdateHolderRequiresIfMatch(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+	created, err := c.CreateAccount(ctx, accountdto.CreateAccountRequest{DocumentNumber: "52998224725"})
+	require.NoError(t, err)
+	name := "Ana"
+	path := "/accounts/" + strconv.FormatInt(created.AccountID, 10) + "/holder"
+
+	err = c.do(ctx, http.MethodPatch, path, nil, holderdto.UpdateHolderRequest{Name: &name}, nil)
+	assert.ErrorIs(t, err, ErrPreconditionRequired)
+	var apiError *APIError
+	require.ErrorAs(t, err, &apiError)
+	assert.Equal(t, http.StatusPreconditionRequired, apiError.StatusCode)
+
+	err = c.doWithHeaders(ctx, http.MethodPatch, path, nil, http.Header{"If-Match": {`W/"0"`}}, holderdto.UpdateHolderRequest{Name: &name}, nil)
+	assert.ErrorIs(t, err, ErrAccountHolderVersionMismatch, "weak ETags should never match")
+	var updated holderdto.HolderResponse
+	err = c.doWithHeaders(ctx, http.MethodPatch, path, nil, http.Header{"If-Match": {`"0-m"`}}, holderdto.UpdateHolderRequest{Name: &name}, &updated)
+	require.NoError(t, err, "the masked ETag of the holder should match its version")
+	assert.Equal(t, int64(1), updated.Version)
+}
+
+func TestTransactions(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+	account, err := c.CreateAccount(ctx, accountdto.CreateAccountRequest{DocumentNumber: "52998224725"})
+	require.NoError(t, err)
+
+	var transactionIDs []int64
+	for _, request := range []dto.CreateTransactionRequest{
+		{AccountID: account.AccountID, OperationTypeID: transaction.Purchase, Amount: 50, MerchantName: "Padaria"},
+		{AccountID: account.AccountID, OperationTypeID: transaction.Withdrawal, Amount: 20},
+		{AccountID: account.AccountID, OperationTypeID: transaction.Payment, Amount: 60, Metadata: map[string]string{"channel": "pix"}},
+	} {
+		created, err := c.CreateTransaction(ctx, request)
+		require.NoError(t, err)
+		transactionIDs = append(transactionIDs, created.Transaction.TransactionID)
+	}
+	assert.Len(t, transactionIDs, 3)
+
+	found, err := c.GetTransaction(ctx, transactionIDs[0])
+	require.NoError(t, err)
+	assert.Equal(t, -50.0, found.Transaction.Amount)
+	assert.Equal(t, "Padaria", found.Transaction.MerchantName)
+
+	page, err := c.ListTransactions(ctx, dto.ListTransactionsRequest{AccountID: account.AccountID, Metadata: map[string]string{"channel": "pix"}})
+	require.NoError(t, err)
+	require.Len(t, page.Transactions, 1)
+	assert.Equal(t, transactionIDs[2], page.Transactions[0].TransactionID)
+
+	var listed []int64
+	err = c.EachTransaction(ctx, dto.ListTransactionsRequest{AccountID: account.AccountID, Limit: 1, Location: time.UTC}, func(transaction dto.TransactionDTO) error {
+		listed = append(listed, transaction.TransactionID)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, transactionIDs, listed)
+
+	_, err = c.CreateTransaction(ctx, dto.CreateTransactionRequest{AccountID: account.AccountID, OperationTypeID: 9, Amount: 10})
+	assert.ErrorIs(t, err, ErrTransactionInvalidOperationType)
+
+	_, err = c.CancelTransaction(ctx, transactionIDs[0])
+	assert.ErrorIs(t, err, ErrTransactionNotPending)
+	var apiError *APIError
+	require.ErrorAs(t, err, &apiError)
+	assert.Equal(t, http.StatusConflict, apiError.StatusCode)
+
+	effectiveDate := time.Now().Add(24 * time.Hour)
+	scheduled, err := c.CreateTransaction(ctx, dto.CreateTransactionRequest{AccountID: account.AccountID, OperationTypeID: transaction.Purchase, Amount: 10, EffectiveDate: &effectiveDate})
+	require.NoError(t, err)
+	cancelled, err := c.CancelTransaction(ctx, scheduled.Transaction.TransactionID)
+	require.NoError(t, err)
+	assert.Equal(t, transaction.StatusCancelled, cancelled.Transaction.Status)
+}
+
+func TestTraceIDPropagation(t *testing.T) {
+	c := newTestClient(t)
+
+	_, err := c.GetTransaction(WithTraceID(context.Background(), "trace-42"), 1)
+	var apiError *APIError
+	require.ErrorAs(t, err, &apiError)
+	assert.Equal(t, "trace-42", apiError.TraceID)
+	assert.ErrorIs(t, err, ErrTransactionNotFound)
+}
+
+func TestRetriesKeepTheTraceIDAndIdempotencyKey(t *testing.T) {
+	server, requests := newScriptedAPI(t,
+		respond(http.StatusTooManyRequests, `{"error":"rate limit exceeded"}`),
+		respond(http.StatusBadRequest, `{"error":"distributed lock fail to acquire"}`),
+		respond(http.StatusCreated, `{"transaction":{"transaction_id":7}}`),
+	)
+	c := New(server.URL, WithRetryPolicy(noBackoff), WithAPIKey("secret"))
+
+	response, err := c.CreateTransaction(context.Background(), dto.CreateTransactionRequest{AccountID: 1, OperationTypeID: transaction.Purchase, Amount: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), response.Transaction.TransactionID)
+	recorded := requests()
+	require.Len(t, recorded, 3)
+	for _, request := range recorded {
+		assert.Equal(t, recorded[0].traceID, request.traceID)
+		assert.Equal(t, recorded[0].idempotencyKey, request.idempotencyKey)
+		assert.Equal(t, "secret", request.apiKey)
+	}
+	assert.NotEmpty(t, recorded[0].traceID)
+	assert.NotEmpty(t, recorded[0].idempotencyKey)
+
+	_, err = c.CreateTransaction(WithIdempotencyKey(context.Background(), "key-1"), dto.CreateTransactionRequest{})
+	require.NoError(t, err)
+	recorded = requests()
+	assert.Equal(t, "key-1", recorded[len(recorded)-1].idempotencyKey)
+	assert.NotEqual(t, recorded[0].traceID, recorded[len(recorded)-1].traceID)
+}
+
+func TestRetries(t *testing.T) {
+	t.Run("gives up after the maximum attempts", func(t *testing.T) {
+		server, requests := newScriptedAPI(t, respond(http.StatusTooManyRequests, `{"error":"rate limit exceeded"}`))
+		c := New(server.URL, WithRetryPolicy(noBackoff))
+		_, err := c.GetAccount(context.Background(), 1)
+		assert.ErrorIs(t, err, ErrRateLimitExceeded)
+		assert.Len(t, requests(), 3)
+	})
+	t.Run("retries GET requests answered by 503", func(t *testing.T) {
+		server, requests := newScriptedAPI(t,
+			respond(http.StatusServiceUnavailable, ``),
+			respond(http.StatusOK, `{"account_id":1}`),
+		)
+		c := New(server.URL, WithRetryPolicy(noBackoff))
+		found, err := c.GetAccount(context.Background(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), found.AccountID)
+		assert.Len(t, requests(), 2)
+	})
+	t.Run("does not retry POST requests the API may have processed", func(t *testing.T) {
+		server, requests := newScriptedAPI(t, respond(http.StatusServiceUnavailable, ``))
+		c := New(server.URL, WithRetryPolicy(noBackoff))
+		_, err := c.CreateAccount(context.Background(), accountdto.CreateAccountRequest{DocumentNumber: "52998224725"})
+		var apiError *APIError
+		require.ErrorAs(t, err, &apiError)
+		assert.Equal(t, http.StatusServiceUnavailable, apiError.StatusCode)
+		assert.Empty(t, apiError.Code)
+		assert.Nil(t, errors.Unwrap(apiError))
+		assert.Len(t, requests(), 1)
+	})
+	t.Run("does not retry other errors", func(t *testing.T) {
+		server, requests := newScriptedAPI(t, respond(http.StatusNotFound, `{"error":"account not found"}`))
+		c := New(server.URL, WithRetryPolicy(noBackoff))
+		_, err := c.GetAccount(context.Background(), 1)
+		assert.ErrorIs(t, err, ErrAccountNotFound)
+		assert.Len(t, requests(), 1)
+	})
+	t.Run("retries GET requests that could not be sent", func(t *testing.T) {
+		server, _ := newScriptedAPI(t, respond(http.StatusOK, `{}`))
+		server.Close()
+		c := New(server.URL, WithRetryPolicy(RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}))
+		_, err := c.GetAccount(context.Background(), 1)
+		assert.Error(t, err)
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	c := New("http://localhost", WithRetryPolicy(RetryPolicy{MaxAttempts: 5, InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}))
+	for attempt, limit := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 400 * time.Millisecond, 5: time.Second, 10: time.Second} {
+		wait := c.backoff(attempt)
+		assert.GreaterOrEqual(t, wait, limit/2)
+		assert.LessOrEqual(t, wait, limit)
+	}
+	assert.Zero(t, New("http://localhost", WithRetryPolicy(noBackoff)).backoff(1))
+}
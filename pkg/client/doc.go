@@ -0,0 +1,4 @@
+// Package client is the Go client of the API. It sends the x-trace-id of the context, or a new one, with every request,
+// retries requests the API did not process with backoff, keeping their trace ID and Idempotency-Key, and returns the
+// errors of the API as *APIError values matching the Err variables with errors.Is.
+package client
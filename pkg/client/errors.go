@@ -0,0 +1,68 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	coreerr "github.com/kiosanim/pismo-code-assessment/internal/core/errors"
+	"net/http"
+)
+
+// Errors the API responds with, matched by errors.Is against the errors returned by the client
+var (
+	ErrAccountNotFound                       = coreerr.AccountNotFoundError
+	ErrAccountAlreadyExistsForDocumentNumber = coreerr.AccountAlreadyExistsForDocumentNumberError
+	ErrAccountBlocked                        = coreerr.AccountBlockedError
+	ErrAccountHolderInvalid                  = coreerr.AccountHolderInvalidError
+	ErrAccountHolderVersionMismatch          = coreerr.AccountHolderVersionMismatchError
+	ErrAuthenticationRequired                = coreerr.AuthenticationRequiredError
+	ErrAuthInsufficientScope                 = coreerr.AuthInsufficientScopeError
+	ErrAuthInvalidCredentials                = coreerr.AuthInvalidCredentialsError
+	ErrCurrencyInvalid                       = coreerr.CurrencyInvalidError
+	ErrCurrencyRateNotFound                  = coreerr.CurrencyRateNotFoundError
+	ErrDistributedLockFailToAcquire          = coreerr.DistributedLockFailToAcquire
+	ErrDocumentTypeInvalid                   = coreerr.DocumentTypeInvalidError
+	ErrInvalidParameters                     = coreerr.InvalidParametersError
+	ErrOperationTypeNotFound                 = coreerr.OperationTypeNotFoundError
+	ErrPreconditionRequired                  = coreerr.PreconditionRequiredError
+	ErrRateLimitExceeded                     = coreerr.RateLimitExceededError
+	ErrTransactionInvalidAccountID           = coreerr.TransactionInvalidAccountIDError
+	ErrTransactionInvalidAmountNegative      = coreerr.TransactionInvalidAmountNegativeError
+	ErrTransactionInvalidDetails             = coreerr.TransactionInvalidDetailsError
+	ErrTransactionInvalidEffectiveDate       = coreerr.TransactionInvalidEffectiveDateError
+	ErrTransactionInvalidEventDate           = coreerr.TransactionInvalidEventDateError
+	ErrTransactionInvalidOperationType       = coreerr.TransactionInvalidOperationTypeError
+	ErrTransactionNotFound                   = coreerr.TransactionNotFoundError
+	ErrTransactionNotPending                 = coreerr.TransactionNotPendingError
+)
+
+// APIError is an error response of the API
+type APIError struct {
+	StatusCode int
+	Code       string // Code of the error, e.g. "AccountNotFoundError", empty when the API error is unknown
+	Message    string // Error message of the response, or its status text when it has none
+	TraceID    string // x-trace-id of the request
+	err        error
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%d %s (x-trace-id %s)", e.StatusCode, e.Message, e.TraceID)
+}
+
+// Unwrap returns the error of the API, nil when it is unknown
+func (e *APIError) Unwrap() error {
+	return e.err
+}
+
+// newAPIError builds the error of a response with an error status from its {"error": "..."} body
+func newAPIError(statusCode int, body []byte, traceID string) *APIError {
+	apiError := &APIError{StatusCode: statusCode, Message: http.StatusText(statusCode), TraceID: traceID}
+	var response struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(body, &response) == nil && response.Error != "" {
+		apiError.Message = response.Error
+		apiError.err = coreerr.FromMessage(response.Error)
+		apiError.Code = coreerr.Code(apiError.err)
+	}
+	return apiError
+}
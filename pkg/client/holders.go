@@ -0,0 +1,38 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"github.com/kiosanim/pismo-code-assessment/application/holder/dto"
+	"net/http"
+	"strconv"
+)
+
+// GetHolder returns the holder of an account, its Version being the version UpdateHolder expects
+func (c *Client) GetHolder(ctx context.Context, accountID int64) (*dto.HolderResponse, error) {
+	var response dto.HolderResponse
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/accounts/%d/holder", accountID), nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// UpdateHolder sets the non nil values of the request when the holder is still at version, failing with
+// ErrAccountHolderVersionMismatch when it was changed since it was read
+func (c *Client) UpdateHolder(ctx context.Context, accountID int64, version int64, request dto.UpdateHolderRequest) (*dto.HolderResponse, error) {
+	var response dto.HolderResponse
+	headers := http.Header{"If-Match": {`"` + strconv.FormatInt(version, 10) + `"`}}
+	if err := c.doWithHeaders(ctx, http.MethodPatch, fmt.Sprintf("/accounts/%d/holder", accountID), nil, headers, request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetHolderHistory returns the changes of the holder of an account, newest first
+func (c *Client) GetHolderHistory(ctx context.Context, accountID int64) (*dto.HolderHistoryResponse, error) {
+	var response dto.HolderHistoryResponse
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/accounts/%d/holder/history", accountID), nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
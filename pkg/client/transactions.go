@@ -0,0 +1,103 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"github.com/kiosanim/pismo-code-assessment/application/transaction/dto"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// CreateTransaction creates a transaction, retried with the same Idempotency-Key when the API did not process it
+func (c *Client) CreateTransaction(ctx context.Context, request dto.CreateTransactionRequest) (*dto.CreateTransactionResponse, error) {
+	var response dto.CreateTransactionResponse
+	if err := c.do(ctx, http.MethodPost, "/transactions", nil, request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (c *Client) GetTransaction(ctx context.Context, transactionID int64) (*dto.FindTransactionByIdResponse, error) {
+	var response dto.FindTransactionByIdResponse
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/transactions/%d", transactionID), nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// CancelTransaction cancels a PENDING transaction
+func (c *Client) CancelTransaction(ctx context.Context, transactionID int64) (*dto.CancelTransactionResponse, error) {
+	var response dto.CancelTransactionResponse
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/transactions/%d/cancel", transactionID), nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ListTransactions returns a page of the transactions matching the request, see dto.ListTransactionsRequest. The
+// response Cursor is the Cursor of the request of the next page, zero on the last page.
+func (c *Client) ListTransactions(ctx context.Context, request dto.ListTransactionsRequest) (*dto.ListTransactionsResponse, error) {
+	var response dto.ListTransactionsResponse
+	if err := c.do(ctx, http.MethodGet, "/transactions", listTransactionsQuery(request), nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// EachTransaction calls fn with every transaction matching the request, following the pages from request.Cursor
+// until the last page or fn returns an error
+func (c *Client) EachTransaction(ctx context.Context, request dto.ListTransactionsRequest, fn func(transaction dto.TransactionDTO) error) error {
+	for {
+		page, err := c.ListTransactions(ctx, request)
+		if err != nil {
+			return err
+		}
+		for _, transaction := range page.Transactions {
+			if err = fn(transaction); err != nil {
+				return err
+			}
+		}
+		if page.Cursor == 0 {
+			return nil
+		}
+		request.Cursor = page.Cursor
+	}
+}
+
+func listTransactionsQuery(request dto.ListTransactionsRequest) url.Values {
+	query := make(url.Values)
+	setInt := func(name string, value int64) {
+		if value > 0 {
+			query.Set(name, strconv.FormatInt(value, 10))
+		}
+	}
+	setString := func(name string, value string) {
+		if value != "" {
+			query.Set(name, value)
+		}
+	}
+	setTime := func(name string, value time.Time) {
+		if !value.IsZero() {
+			query.Set(name, value.Format(time.RFC3339Nano))
+		}
+	}
+	setInt("account_id", request.AccountID)
+	setInt("cursor", request.Cursor)
+	setInt("limit", request.Limit)
+	setString("status", request.Status)
+	setString("merchant_name", request.MerchantName)
+	setString("mcc", request.MCC)
+	setString("merchant_country", request.MerchantCountry)
+	setString("description", request.Description)
+	for key, value := range request.Metadata {
+		query.Set("metadata["+key+"]", value)
+	}
+	setTime("from", request.From)
+	setTime("to", request.To)
+	if request.Location != nil {
+		query.Set("tz", request.Location.String())
+	}
+	return query
+}